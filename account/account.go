@@ -3,6 +3,7 @@ package account
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/prebid/go-gdpr/consentconstants"
 
@@ -78,9 +79,44 @@ func GetAccount(ctx context.Context, cfg *config.Configuration, fetcher stored_r
 		account.Privacy.IPv4Config.AnonKeepBits = iputil.IPv4DefaultMaskingBitSize
 	}
 
+	if section, validationErrs := ValidateAccount(account); len(validationErrs) > 0 {
+		return nil, malformedAccountConfig(accountID, section, validationErrs)
+	}
+
 	return account, nil
 }
 
+// ValidateAccount validates the account config sections which make the account malformed when invalid and returns
+// the first invalid section with its errors. Invalid IP anonymization settings are not reported, the auctions fall
+// back to the default masking for them.
+func ValidateAccount(account *config.Account) (section string, errs []error) {
+	for _, s := range []struct {
+		name     string
+		validate func([]error) []error
+	}{
+		{"privacy.usprivacy", account.Privacy.USPrivacy.Validate},
+		{"price_clearing", account.PriceClearing.Validate},
+		{"stored_request_versions", account.StoredRequestVersions.Validate},
+		{"bidder_transforms", account.BidderTransforms.Validate},
+	} {
+		if errs := s.validate(nil); len(errs) > 0 {
+			return s.name, errs
+		}
+	}
+	return "", nil
+}
+
+// malformedAccountConfig returns the error reported for an account config section failing validation
+func malformedAccountConfig(accountID, section string, errs []error) []error {
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	return []error{&errortypes.MalformedAcct{
+		Message: fmt.Sprintf("The prebid-server account config %s for account id \"%s\" is malformed: %s. Please reach out to the prebid server host.", section, accountID, strings.Join(messages, "; ")),
+	}}
+}
+
 // TCF2Enforcements maps enforcement algo string values to their integer representation and is
 // used to limit string compares
 var TCF2Enforcements = map[string]config.TCF2EnforcementAlgo{
@@ -23,14 +23,19 @@ var (
 )
 
 var mockAccountData = map[string]json.RawMessage{
-	"valid_acct":                json.RawMessage(`{"disabled":false}`),
-	"valid_acct_dsa":            json.RawMessage(`{"disabled":false, "privacy": {"dsa": {"default": "` + validDSA + `"}}}`),
-	"invalid_acct_dsa":          json.RawMessage(`{"disabled":false, "privacy": {"dsa": {"default": "` + invalidDSA + `"}}}`),
-	"invalid_acct_ipv6_ipv4":    json.RawMessage(`{"disabled":false, "privacy": {"ipv6": {"anon_keep_bits": -32}, "ipv4": {"anon_keep_bits": -16}}}`),
-	"disabled_acct":             json.RawMessage(`{"disabled":true}`),
-	"malformed_acct":            json.RawMessage(`{"disabled":"invalid type"}`),
-	"gdpr_channel_enabled_acct": json.RawMessage(`{"disabled":false,"gdpr":{"channel_enabled":{"amp":true}}}`),
-	"ccpa_channel_enabled_acct": json.RawMessage(`{"disabled":false,"ccpa":{"channel_enabled":{"amp":true}}}`),
+	"valid_acct":                      json.RawMessage(`{"disabled":false}`),
+	"valid_acct_dsa":                  json.RawMessage(`{"disabled":false, "privacy": {"dsa": {"default": "` + validDSA + `"}}}`),
+	"invalid_acct_dsa":                json.RawMessage(`{"disabled":false, "privacy": {"dsa": {"default": "` + invalidDSA + `"}}}`),
+	"invalid_acct_ipv6_ipv4":          json.RawMessage(`{"disabled":false, "privacy": {"ipv6": {"anon_keep_bits": -32}, "ipv4": {"anon_keep_bits": -16}}}`),
+	"disabled_acct":                   json.RawMessage(`{"disabled":true}`),
+	"malformed_acct":                  json.RawMessage(`{"disabled":"invalid type"}`),
+	"gdpr_channel_enabled_acct":       json.RawMessage(`{"disabled":false,"gdpr":{"channel_enabled":{"amp":true}}}`),
+	"ccpa_channel_enabled_acct":       json.RawMessage(`{"disabled":false,"ccpa":{"channel_enabled":{"amp":true}}}`),
+	"invalid_price_clearing":          json.RawMessage(`{"disabled":false, "price_clearing": {"mode": "third_price"}}`),
+	"invalid_stored_request_versions": json.RawMessage(`{"disabled":false, "stored_request_versions": {"candidate": "v2", "candidate_percent": 101}}`),
+	"invalid_us_privacy":              json.RawMessage(`{"disabled":false, "privacy": {"usprivacy": {"enabled": true, "sections": {"usxx": {}}}}}`),
+	"valid_transforms_acct":           json.RawMessage(`{"bidder_transforms":{"rules":[{"bidders":["appnexus"],"op":"cap_imps","max_imps":2}]}}`),
+	"invalid_transforms_acct":         json.RawMessage(`{"bidder_transforms":{"rules":[{"bidders":["appnexus"],"op":"cap_imps","max_imps":2},{"bidders":["appnexus"],"op":"delete","path":"site"}]}}`),
 }
 
 type mockAccountFetcher struct {
@@ -92,6 +97,9 @@ func TestGetAccount(t *testing.T) {
 
 		{accountID: "invalid_acct_ipv6_ipv4", required: true, disabled: false, err: nil, wantDefaultIP: true},
 		{accountID: "invalid_acct_dsa", required: false, disabled: false, err: &errortypes.MalformedAcct{}},
+		{accountID: "invalid_price_clearing", required: false, disabled: false, err: &errortypes.MalformedAcct{}},
+		{accountID: "invalid_stored_request_versions", required: false, disabled: false, err: &errortypes.MalformedAcct{}},
+		{accountID: "invalid_us_privacy", required: false, disabled: false, err: &errortypes.MalformedAcct{}},
 
 		// pubID given and matches a host account explicitly disabled (Disabled: true on account json)
 		{accountID: "disabled_acct", required: false, disabled: false, err: &errortypes.AccountDisabled{}},
@@ -147,6 +155,62 @@ func TestGetAccount(t *testing.T) {
 	}
 }
 
+func TestGetAccountBidderTransforms(t *testing.T) {
+	cfg := &config.Configuration{}
+	assert.NoError(t, cfg.MarshalAccountDefaults())
+	metrics := &metrics.MetricsEngineMock{}
+
+	account, errs := GetAccount(context.Background(), cfg, &mockAccountFetcher{}, "valid_transforms_acct", metrics)
+	assert.Empty(t, errs)
+	assert.Equal(t, []config.BidderTransformRule{{Bidders: []string{"appnexus"}, Op: config.BidderTransformCapImps, MaxImps: 2}}, account.BidderTransforms.Rules)
+
+	account, errs = GetAccount(context.Background(), cfg, &mockAccountFetcher{}, "invalid_transforms_acct", metrics)
+	assert.Nil(t, account)
+	if assert.Len(t, errs, 1) {
+		assert.IsType(t, &errortypes.MalformedAcct{}, errs[0])
+		assert.Contains(t, errs[0].Error(), "bidder_transforms")
+	}
+}
+
+func TestValidateAccount(t *testing.T) {
+	tests := []struct {
+		name            string
+		account         config.Account
+		expectedSection string
+		expectedErrs    int
+	}{
+		{
+			name:    "valid",
+			account: config.Account{PriceClearing: config.AccountPriceClearing{Mode: config.ClearingModeSecondPrice}},
+		},
+		{
+			name:    "invalid_ip_masking_falls_back",
+			account: config.Account{Privacy: config.AccountPrivacy{IPv6Config: config.IPv6{AnonKeepBits: -32}}},
+		},
+		{
+			name:            "invalid_price_clearing",
+			account:         config.Account{PriceClearing: config.AccountPriceClearing{Mode: "third_price"}},
+			expectedSection: "price_clearing",
+			expectedErrs:    1,
+		},
+		{
+			name: "invalid_bidder_transforms",
+			account: config.Account{BidderTransforms: config.AccountBidderTransforms{Rules: []config.BidderTransformRule{
+				{Op: config.BidderTransformCapImps, MaxImps: 2},
+			}}},
+			expectedSection: "bidder_transforms",
+			expectedErrs:    1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			section, errs := ValidateAccount(&test.account)
+			assert.Equal(t, test.expectedSection, section)
+			assert.Len(t, errs, test.expectedErrs)
+		})
+	}
+}
+
 func TestSetDerivedConfig(t *testing.T) {
 	tests := []struct {
 		description              string
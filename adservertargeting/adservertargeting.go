@@ -16,6 +16,7 @@ const (
 	SourceBidRequest  DataSource = "bidrequest"
 	SourceStatic      DataSource = "static"
 	SourceBidResponse DataSource = "bidresponse"
+	SourceExpression  DataSource = "expression"
 )
 
 const (
@@ -39,9 +40,19 @@ type ResponseTargetingData struct {
 	Path     string
 }
 
+// ExpressionTargetingData holds a parsed expression, evaluated against every bid in the response
+type ExpressionTargetingData struct {
+	Key        string
+	HasMacro   bool
+	Expression *expression
+}
+
 type adServerTargetingData struct {
-	RequestTargetingData  map[string]RequestTargetingData
-	ResponseTargetingData []ResponseTargetingData
+	RequestTargetingData    map[string]RequestTargetingData
+	ResponseTargetingData   []ResponseTargetingData
+	ExpressionTargetingData []ExpressionTargetingData
+	requestCache            *requestCache
+	queryParams             url.Values
 }
 
 func Apply(
@@ -81,6 +92,7 @@ func collect(
 
 	requestTargetingData := map[string]RequestTargetingData{}
 	responseTargetingData := []ResponseTargetingData{}
+	expressionTargetingData := []ExpressionTargetingData{}
 
 	impsCache := requestCache{resolvedReq: resolvedRequest}
 
@@ -106,12 +118,27 @@ func collect(
 			bidResponseTargeting.Path = targetingObj.Value
 			bidResponseTargeting.HasMacro = strings.Contains(strings.ToUpper(targetingObj.Key), bidderMacro)
 			responseTargetingData = append(responseTargetingData, bidResponseTargeting)
+		case SourceExpression:
+			//causes PBS to treat 'value' as an expression evaluated against the request and each bid
+			expr, err := parseExpression(targetingObj.Value)
+			if err != nil {
+				warnings = append(warnings, createWarning(err.Error()))
+				continue
+			}
+			expressionTargetingData = append(expressionTargetingData, ExpressionTargetingData{
+				Key:        targetingObj.Key,
+				HasMacro:   strings.Contains(strings.ToUpper(targetingObj.Key), bidderMacro),
+				Expression: expr,
+			})
 		}
 	}
 
 	adServerTargetingData := &adServerTargetingData{
-		RequestTargetingData:  requestTargetingData,
-		ResponseTargetingData: responseTargetingData,
+		RequestTargetingData:    requestTargetingData,
+		ResponseTargetingData:   responseTargetingData,
+		ExpressionTargetingData: expressionTargetingData,
+		requestCache:            &impsCache,
+		queryParams:             queryParams,
 	}
 
 	return adServerTargetingData, warnings
@@ -134,6 +161,10 @@ func resolve(
 			if len(respWarnings) > 0 {
 				warnings = append(warnings, respWarnings...)
 			}
+			exprWarnings := processExpressionTargetingData(adServerTargetingData, targetingData, bidderName, bid, bidCache, response, seat.Ext)
+			if len(exprWarnings) > 0 {
+				warnings = append(warnings, exprWarnings...)
+			}
 			seat.Bid[i].Ext = buildBidExt(targetingData, bid, warnings, truncateTargetAttribute)
 		}
 	}
@@ -0,0 +1,584 @@
+package adservertargeting
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/buger/jsonparser"
+	"github.com/prebid/openrtb/v20/openrtb2"
+)
+
+// An expression derives a targeting value from one or more request or response fields.
+//
+//	expression := term ('+' term)*
+//	term       := operand ('|' transform)*
+//	operand    := 'literal' | source ':' path
+//	transform  := name ['(' arg (',' arg)* ')']
+//
+// Sources are bidrequest and bidresponse. Path segments may select array elements
+// either by index, e.g. imp[0].id, or by field value, e.g. user.data[name=seg].segment[0].id.
+// Terms joined by '+' are concatenated.
+type expression struct {
+	raw   string
+	terms []expressionTerm
+}
+
+type expressionTerm struct {
+	operand    expressionOperand
+	transforms []expressionTransform
+}
+
+type expressionOperand struct {
+	source  DataSource
+	path    string
+	steps   []pathStep
+	literal string
+}
+
+// expressionTransform receives the value of the previous step, or the error raised
+// while resolving it, so transforms like default can recover from missing data.
+type expressionTransform func(value string, err error) (string, error)
+
+type pathStep struct {
+	key       string
+	selectors []pathSelector
+}
+
+type pathSelector struct {
+	index int
+	field string
+	value string
+}
+
+// expressionContext holds the request and bid data an expression is evaluated against
+type expressionContext struct {
+	reqCache    *requestCache
+	queryParams url.Values
+	bidderName  string
+	bid         openrtb2.Bid
+	bidsCache   bidsCache
+	response    *openrtb2.BidResponse
+	seatExt     json.RawMessage
+}
+
+const (
+	transformDefault     = "default"
+	transformLower       = "lower"
+	transformHash        = "hash"
+	transformTruncate    = "truncate"
+	transformPriceBucket = "pricebucket"
+
+	priceBucketPrecision = 2
+)
+
+func parseExpression(raw string) (*expression, error) {
+	tokens, err := tokenizeExpression(raw)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New("expression is empty")
+	}
+
+	p := &expressionParser{tokens: tokens}
+	expr := &expression{raw: raw}
+	for {
+		term, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		expr.terms = append(expr.terms, term)
+
+		if p.done() {
+			return expr, nil
+		}
+		if tok := p.next(); tok.kind != tokenPlus {
+			return nil, fmt.Errorf("expected '+', got %s", tok.describe())
+		}
+	}
+}
+
+func (expr *expression) evaluate(ctx *expressionContext) (string, error) {
+	var sb strings.Builder
+	for _, term := range expr.terms {
+		value, err := term.operand.resolve(ctx)
+		for _, transform := range term.transforms {
+			value, err = transform(value, err)
+		}
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(value)
+	}
+	return sb.String(), nil
+}
+
+func (op expressionOperand) resolve(ctx *expressionContext) (string, error) {
+	switch op.source {
+	case SourceBidRequest:
+		return resolveRequestOperand(op, ctx)
+	case SourceBidResponse:
+		return resolveResponseOperand(op, ctx)
+	default:
+		return op.literal, nil
+	}
+}
+
+func resolveRequestOperand(op expressionOperand, ctx *expressionContext) (string, error) {
+	if ampKey, hasPrefix := verifyPrefixAndTrim(op.path, "ext.prebid.amp.data."); hasPrefix {
+		if val := ctx.queryParams.Get(ampKey); val != "" {
+			return val, nil
+		}
+		return "", fmt.Errorf("value not found for path: %s", op.path)
+	}
+
+	// as with the bidrequest source, imp refers to the imp the bid was made for
+	if op.steps[0].key == "imp" && len(op.steps[0].selectors) == 0 && len(op.steps) > 1 {
+		impsData, err := ctx.reqCache.GetImpsData()
+		if err != nil {
+			return "", err
+		}
+		for _, impData := range impsData {
+			id, _, _, err := jsonparser.Get(impData, "id")
+			if err == nil && string(id) == ctx.bid.ImpID {
+				return lookupPath(impData, op.path, op.steps[1:])
+			}
+		}
+		return "", fmt.Errorf("value not found for path: %s", op.path)
+	}
+
+	return lookupPath(ctx.reqCache.GetReqJson(), op.path, op.steps)
+}
+
+func resolveResponseOperand(op expressionOperand, ctx *expressionContext) (string, error) {
+	steps := op.steps
+	switch {
+	case len(steps) > 2 && steps[0].key == "seatbid" && steps[1].key == "bid" && len(steps[0].selectors) == 0 && len(steps[1].selectors) == 0:
+		bidBytes, err := ctx.bidsCache.GetBid(ctx.bidderName, ctx.bid.ID, ctx.bid)
+		if err != nil {
+			return "", err
+		}
+		return lookupPath(bidBytes, op.path, steps[2:])
+	case len(steps) > 2 && steps[0].key == "seatbid" && steps[1].key == "ext" && len(steps[0].selectors) == 0:
+		return lookupPath(ctx.seatExt, op.path, steps[2:])
+	case len(steps) > 1 && steps[0].key == "ext":
+		return lookupPath(ctx.response.Ext, op.path, steps[1:])
+	default:
+		return getRespData(ctx.response, op.path)
+	}
+}
+
+// lookupPath walks data along the compiled path steps and returns the string or number found at the end.
+func lookupPath(data []byte, path string, steps []pathStep) (string, error) {
+	value := data
+	dataType := jsonparser.Object
+	var err error
+	for _, step := range steps {
+		if step.key != "" {
+			if value, dataType, _, err = jsonparser.Get(value, step.key); err != nil {
+				return "", lookupError(path, err)
+			}
+		}
+		for _, sel := range step.selectors {
+			if dataType != jsonparser.Array {
+				return "", fmt.Errorf("value is not an array for path: %s", path)
+			}
+			if value, dataType, err = selectElement(value, sel); err != nil {
+				return "", lookupError(path, err)
+			}
+		}
+	}
+	if !verifyType(dataType) {
+		return "", fmt.Errorf("incorrect value type for path: %s, value can only be string or number", path)
+	}
+	return string(value), nil
+}
+
+func selectElement(array []byte, sel pathSelector) ([]byte, jsonparser.ValueType, error) {
+	if sel.field == "" {
+		value, dataType, _, err := jsonparser.Get(array, "["+strconv.Itoa(sel.index)+"]")
+		return value, dataType, err
+	}
+
+	var (
+		found     []byte
+		foundType jsonparser.ValueType
+	)
+	_, err := jsonparser.ArrayEach(array, func(elem []byte, elemType jsonparser.ValueType, _ int, _ error) {
+		if found != nil || elemType != jsonparser.Object {
+			return
+		}
+		if fieldValue, _, _, err := jsonparser.Get(elem, sel.field); err == nil && string(fieldValue) == sel.value {
+			found, foundType = elem, elemType
+		}
+	})
+	if err != nil {
+		return nil, jsonparser.NotExist, err
+	}
+	if found == nil {
+		return nil, jsonparser.NotExist, jsonparser.KeyPathNotFoundError
+	}
+	return found, foundType, nil
+}
+
+func lookupError(path string, err error) error {
+	if err == jsonparser.KeyPathNotFoundError {
+		return fmt.Errorf("value not found for path: %s", path)
+	}
+	return err
+}
+
+type expressionParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *expressionParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *expressionParser) peek() token {
+	if p.done() {
+		return token{kind: tokenEnd, pos: -1}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *expressionParser) next() token {
+	tok := p.peek()
+	p.pos++
+	return tok
+}
+
+func (p *expressionParser) expect(kind tokenKind, what string) (token, error) {
+	tok := p.next()
+	if tok.kind != kind {
+		return tok, fmt.Errorf("expected %s, got %s", what, tok.describe())
+	}
+	return tok, nil
+}
+
+func (p *expressionParser) parseTerm() (expressionTerm, error) {
+	var term expressionTerm
+
+	tok := p.next()
+	switch tok.kind {
+	case tokenLiteral:
+		term.operand = expressionOperand{literal: tok.text}
+	case tokenWord:
+		operand, err := parseOperand(tok)
+		if err != nil {
+			return term, err
+		}
+		term.operand = operand
+	default:
+		return term, fmt.Errorf("expected operand, got %s", tok.describe())
+	}
+
+	for p.peek().kind == tokenPipe {
+		p.next()
+		transform, err := p.parseTransform()
+		if err != nil {
+			return term, err
+		}
+		term.transforms = append(term.transforms, transform)
+	}
+	return term, nil
+}
+
+func (p *expressionParser) parseTransform() (expressionTransform, error) {
+	nameTok, err := p.expect(tokenWord, "transform name")
+	if err != nil {
+		return nil, err
+	}
+
+	var args []string
+	if p.peek().kind == tokenOpenParen {
+		p.next()
+		for {
+			tok := p.next()
+			if tok.kind != tokenLiteral && tok.kind != tokenWord {
+				return nil, fmt.Errorf("expected argument for transform %s, got %s", nameTok.text, tok.describe())
+			}
+			args = append(args, tok.text)
+
+			sep := p.next()
+			if sep.kind == tokenCloseParen {
+				break
+			}
+			if sep.kind != tokenComma {
+				return nil, fmt.Errorf("expected ',' or ')' for transform %s, got %s", nameTok.text, sep.describe())
+			}
+		}
+	}
+
+	return buildTransform(strings.ToLower(nameTok.text), args)
+}
+
+func parseOperand(tok token) (expressionOperand, error) {
+	sourceName, path, found := strings.Cut(tok.text, ":")
+	if !found {
+		return expressionOperand{}, fmt.Errorf("operand %q at position %d must be a quoted literal or source:path", tok.text, tok.pos)
+	}
+
+	source := DataSource(strings.ToLower(sourceName))
+	if source != SourceBidRequest && source != SourceBidResponse {
+		return expressionOperand{}, fmt.Errorf("unsupported source %q at position %d", sourceName, tok.pos)
+	}
+
+	steps, err := parsePath(path)
+	if err != nil {
+		return expressionOperand{}, fmt.Errorf("invalid path %q at position %d: %s", path, tok.pos, err.Error())
+	}
+	return expressionOperand{source: source, path: path, steps: steps}, nil
+}
+
+func parsePath(path string) ([]pathStep, error) {
+	if path == "" {
+		return nil, errors.New("path is empty")
+	}
+
+	var steps []pathStep
+	for _, segment := range splitPath(path) {
+		key, rest, _ := strings.Cut(segment, "[")
+		step := pathStep{key: key}
+		if rest != "" {
+			rest = "[" + rest
+		}
+		for rest != "" {
+			end := strings.IndexByte(rest, ']')
+			if rest[0] != '[' || end < 0 {
+				return nil, fmt.Errorf("malformed selector in segment %q", segment)
+			}
+			sel, err := parseSelector(rest[1:end])
+			if err != nil {
+				return nil, err
+			}
+			step.selectors = append(step.selectors, sel)
+			rest = rest[end+1:]
+		}
+		if step.key == "" && len(step.selectors) == 0 {
+			return nil, errors.New("path contains an empty segment")
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+func parseSelector(selector string) (pathSelector, error) {
+	if field, value, found := strings.Cut(selector, "="); found {
+		if field == "" {
+			return pathSelector{}, fmt.Errorf("filter %q has no field", selector)
+		}
+		return pathSelector{field: field, value: value}, nil
+	}
+
+	index, err := strconv.Atoi(selector)
+	if err != nil || index < 0 {
+		return pathSelector{}, fmt.Errorf("selector %q must be a non-negative index or a field=value filter", selector)
+	}
+	return pathSelector{index: index}, nil
+}
+
+// splitPath splits on the path delimiter, ignoring delimiters inside array selectors
+func splitPath(path string) []string {
+	var segments []string
+	depth, start := 0, 0
+	for i := 0; i < len(path); i++ {
+		switch path[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case pathDelimiter[0]:
+			if depth == 0 {
+				segments = append(segments, path[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(segments, path[start:])
+}
+
+func buildTransform(name string, args []string) (expressionTransform, error) {
+	switch name {
+	case transformDefault:
+		if len(args) != 1 {
+			return nil, errors.New("transform default requires exactly one argument")
+		}
+		defaultValue := args[0]
+		return func(value string, err error) (string, error) {
+			if err != nil || value == "" {
+				return defaultValue, nil
+			}
+			return value, nil
+		}, nil
+	case transformLower:
+		if len(args) != 0 {
+			return nil, errors.New("transform lower takes no arguments")
+		}
+		return valueTransform(func(value string) (string, error) {
+			return strings.ToLower(value), nil
+		}), nil
+	case transformHash:
+		if len(args) != 0 {
+			return nil, errors.New("transform hash takes no arguments")
+		}
+		return valueTransform(func(value string) (string, error) {
+			sum := sha256.Sum256([]byte(value))
+			return hex.EncodeToString(sum[:]), nil
+		}), nil
+	case transformTruncate:
+		if len(args) != 1 {
+			return nil, errors.New("transform truncate requires exactly one argument")
+		}
+		length, err := strconv.Atoi(args[0])
+		if err != nil || length <= 0 {
+			return nil, fmt.Errorf("transform truncate requires a positive length, got %q", args[0])
+		}
+		return valueTransform(func(value string) (string, error) {
+			if utf8.RuneCountInString(value) <= length {
+				return value, nil
+			}
+			return string([]rune(value)[:length]), nil
+		}), nil
+	case transformPriceBucket:
+		return buildPriceBucketTransform(args)
+	default:
+		return nil, fmt.Errorf("unknown transform %q", name)
+	}
+}
+
+// buildPriceBucketTransform rounds a numeric value down to a multiple of the increment,
+// capped at the optional max, matching the formatting of the hb_pb targeting key.
+func buildPriceBucketTransform(args []string) (expressionTransform, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, errors.New("transform pricebucket requires an increment and an optional max")
+	}
+	increment, err := strconv.ParseFloat(args[0], 64)
+	if err != nil || increment <= 0 {
+		return nil, fmt.Errorf("transform pricebucket requires a positive increment, got %q", args[0])
+	}
+	maxPrice := math.Inf(1)
+	if len(args) == 2 {
+		if maxPrice, err = strconv.ParseFloat(args[1], 64); err != nil || maxPrice < increment {
+			return nil, fmt.Errorf("transform pricebucket requires a max no lower than the increment, got %q", args[1])
+		}
+	}
+
+	return valueTransform(func(value string) (string, error) {
+		price, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return "", fmt.Errorf("value %q is not numeric", value)
+		}
+		if price < 0 {
+			price = 0
+		}
+		if price > maxPrice {
+			price = maxPrice
+		}
+		// nudge up before flooring so values already on a bucket boundary are not rounded down
+		bucket := math.Floor(price/increment+1e-9) * increment
+		return strconv.FormatFloat(bucket, 'f', priceBucketPrecision, 64), nil
+	}), nil
+}
+
+// valueTransform wraps fn so that it is skipped when an earlier step failed
+func valueTransform(fn func(value string) (string, error)) expressionTransform {
+	return func(value string, err error) (string, error) {
+		if err != nil {
+			return "", err
+		}
+		return fn(value)
+	}
+}
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenWord
+	tokenLiteral
+	tokenPlus
+	tokenPipe
+	tokenOpenParen
+	tokenCloseParen
+	tokenComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (tok token) describe() string {
+	if tok.kind == tokenEnd {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q at position %d", tok.text, tok.pos)
+}
+
+var expressionPunctuation = map[byte]tokenKind{
+	'+': tokenPlus,
+	'|': tokenPipe,
+	'(': tokenOpenParen,
+	')': tokenCloseParen,
+	',': tokenComma,
+}
+
+func tokenizeExpression(raw string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(raw); {
+		c := raw[i]
+		switch {
+		case unicode.IsSpace(rune(c)):
+			i++
+		case c == '\'':
+			var sb strings.Builder
+			start := i
+			i++
+			for ; i < len(raw) && raw[i] != '\''; i++ {
+				if raw[i] == '\\' && i+1 < len(raw) {
+					i++
+				}
+				sb.WriteByte(raw[i])
+			}
+			if i >= len(raw) {
+				return nil, fmt.Errorf("unterminated literal at position %d", start)
+			}
+			i++
+			tokens = append(tokens, token{kind: tokenLiteral, text: sb.String(), pos: start})
+		case expressionPunctuation[c] != tokenEnd:
+			tokens = append(tokens, token{kind: expressionPunctuation[c], text: string(c), pos: i})
+			i++
+		default:
+			start := i
+			depth := 0
+			for ; i < len(raw); i++ {
+				c = raw[i]
+				if c == '[' {
+					depth++
+				} else if c == ']' {
+					depth--
+				} else if depth == 0 && (unicode.IsSpace(rune(c)) || c == '\'' || expressionPunctuation[c] != tokenEnd) {
+					break
+				}
+			}
+			if depth != 0 {
+				return nil, fmt.Errorf("unbalanced brackets at position %d", start)
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: raw[start:i], pos: start})
+		}
+	}
+	return tokens, nil
+}
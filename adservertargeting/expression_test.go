@@ -0,0 +1,266 @@
+package adservertargeting
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseExpression(t *testing.T) {
+	testCases := []struct {
+		description   string
+		inputExpr     string
+		expectedTerms int
+		expectedError string
+	}{
+		{
+			description:   "single path",
+			inputExpr:     "bidrequest:site.domain",
+			expectedTerms: 1,
+		},
+		{
+			description:   "concatenation with literal and transforms",
+			inputExpr:     "bidrequest:site.domain | lower + '_' + bidresponse:seatbid.bid.price | pricebucket(0.5, 20)",
+			expectedTerms: 3,
+		},
+		{
+			description:   "array filter with spaces and delimiters in value",
+			inputExpr:     "bidrequest:user.data[name=seg provider.com].segment[0].id | default('none')",
+			expectedTerms: 1,
+		},
+		{
+			description:   "empty expression",
+			inputExpr:     "  ",
+			expectedError: "expression is empty",
+		},
+		{
+			description:   "unknown source",
+			inputExpr:     "static:value",
+			expectedError: `unsupported source "static" at position 0`,
+		},
+		{
+			description:   "bare word operand",
+			inputExpr:     "site.domain",
+			expectedError: `operand "site.domain" at position 0 must be a quoted literal or source:path`,
+		},
+		{
+			description:   "unknown transform",
+			inputExpr:     "bidrequest:site.domain | upper",
+			expectedError: `unknown transform "upper"`,
+		},
+		{
+			description:   "missing operand after concatenation",
+			inputExpr:     "bidrequest:site.domain +",
+			expectedError: "expected operand, got end of expression",
+		},
+		{
+			description:   "missing concatenation operator",
+			inputExpr:     "bidrequest:site.domain 'x'",
+			expectedError: `expected '+', got "x" at position 23`,
+		},
+		{
+			description:   "unterminated literal",
+			inputExpr:     "'abc",
+			expectedError: "unterminated literal at position 0",
+		},
+		{
+			description:   "unbalanced selector",
+			inputExpr:     "bidrequest:imp[0.id",
+			expectedError: "unbalanced brackets at position 0",
+		},
+		{
+			description:   "invalid selector",
+			inputExpr:     "bidrequest:imp[first].id",
+			expectedError: `invalid path "imp[first].id" at position 0: selector "first" must be a non-negative index or a field=value filter`,
+		},
+		{
+			description:   "empty path segment",
+			inputExpr:     "bidrequest:site..domain",
+			expectedError: `invalid path "site..domain" at position 0: path contains an empty segment`,
+		},
+		{
+			description:   "truncate without length",
+			inputExpr:     "bidrequest:site.domain | truncate",
+			expectedError: "transform truncate requires exactly one argument",
+		},
+		{
+			description:   "truncate with invalid length",
+			inputExpr:     "bidrequest:site.domain | truncate(0)",
+			expectedError: `transform truncate requires a positive length, got "0"`,
+		},
+		{
+			description:   "pricebucket with invalid increment",
+			inputExpr:     "bidresponse:seatbid.bid.price | pricebucket(abc)",
+			expectedError: `transform pricebucket requires a positive increment, got "abc"`,
+		},
+		{
+			description:   "pricebucket with max below increment",
+			inputExpr:     "bidresponse:seatbid.bid.price | pricebucket(1, 0.5)",
+			expectedError: `transform pricebucket requires a max no lower than the increment, got "0.5"`,
+		},
+		{
+			description:   "unclosed transform arguments",
+			inputExpr:     "bidrequest:site.domain | default('x'",
+			expectedError: "expected ',' or ')' for transform default, got end of expression",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			expr, err := parseExpression(test.inputExpr)
+			if len(test.expectedError) > 0 {
+				assert.EqualError(t, err, test.expectedError)
+				assert.Nil(t, expr)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, expr.terms, test.expectedTerms)
+			}
+		})
+	}
+}
+
+func TestEvaluateExpression(t *testing.T) {
+	reqJson := json.RawMessage(`{
+		"site": {"domain": "WWW.Example.COM"},
+		"imp": [
+			{"id": "imp1", "tagid": "top-banner", "ext": {"gpid": "/123/home"}},
+			{"id": "imp2", "tagid": "sidebar", "ext": {"gpid": "/123/side"}}
+		],
+		"user": {"data": [
+			{"name": "other.com", "segment": [{"id": "x1"}]},
+			{"name": "segs.com", "segment": [{"id": "s1"}, {"id": "s2"}]}
+		]}
+	}`)
+
+	response := &openrtb2.BidResponse{
+		ID:  "resp1",
+		Cur: "USD",
+		Ext: json.RawMessage(`{"prebid": {"auctiontimestamp": 1000}}`),
+	}
+	bid := openrtb2.Bid{ID: "bid1", ImpID: "imp2", Price: 3.87, DealID: "deal-1", Ext: json.RawMessage(`{"meta": {"advertiserDomains": ["adv.com"]}}`)}
+
+	testCases := []struct {
+		description   string
+		inputExpr     string
+		expectedValue string
+		expectedError string
+	}{
+		{
+			description:   "request path with lower",
+			inputExpr:     "bidrequest:site.domain | lower",
+			expectedValue: "www.example.com",
+		},
+		{
+			description:   "imp path resolves against the imp of the bid",
+			inputExpr:     "bidrequest:imp.ext.gpid",
+			expectedValue: "/123/side",
+		},
+		{
+			description:   "imp index selector",
+			inputExpr:     "bidrequest:imp[0].tagid",
+			expectedValue: "top-banner",
+		},
+		{
+			description:   "array filter",
+			inputExpr:     "bidrequest:user.data[name=segs.com].segment[1].id",
+			expectedValue: "s2",
+		},
+		{
+			description:   "array filter without match falls back to default",
+			inputExpr:     "bidrequest:user.data[name=missing.com].segment[0].id | default('none')",
+			expectedValue: "none",
+		},
+		{
+			description:   "amp query param",
+			inputExpr:     "bidrequest:ext.prebid.amp.data.page | truncate(3)",
+			expectedValue: "art",
+		},
+		{
+			description:   "bid field with price bucket",
+			inputExpr:     "bidresponse:seatbid.bid.price | pricebucket(0.5)",
+			expectedValue: "3.50",
+		},
+		{
+			description:   "price bucket capped at max",
+			inputExpr:     "bidresponse:seatbid.bid.price | pricebucket(0.1, 2)",
+			expectedValue: "2.00",
+		},
+		{
+			description:   "price bucket on boundary",
+			inputExpr:     "'0.3' | pricebucket(0.1)",
+			expectedValue: "0.30",
+		},
+		{
+			description:   "bid ext array element",
+			inputExpr:     "bidresponse:seatbid.bid.ext.meta.advertiserDomains[0]",
+			expectedValue: "adv.com",
+		},
+		{
+			description:   "seat ext, response ext and response field",
+			inputExpr:     "bidresponse:seatbid.ext.tier + '-' + bidresponse:ext.prebid.auctiontimestamp + '-' + bidresponse:cur",
+			expectedValue: "gold-1000-USD",
+		},
+		{
+			description:   "concatenation of transformed fields",
+			inputExpr:     "bidrequest:imp.tagid + '_' + bidresponse:seatbid.bid.dealid | default('nodeal')",
+			expectedValue: "sidebar_deal-1",
+		},
+		{
+			description:   "hash then truncate",
+			inputExpr:     "bidrequest:site.domain | lower | hash | truncate(8)",
+			expectedValue: "80fc0fb9",
+		},
+		{
+			description:   "transform after default is applied to the default value",
+			inputExpr:     "bidrequest:site.page | default('UNKNOWN') | lower",
+			expectedValue: "unknown",
+		},
+		{
+			description:   "missing value",
+			inputExpr:     "bidrequest:site.page | lower",
+			expectedError: "value not found for path: site.page",
+		},
+		{
+			description:   "object value",
+			inputExpr:     "bidrequest:site",
+			expectedError: "incorrect value type for path: site, value can only be string or number",
+		},
+		{
+			description:   "selector on non-array",
+			inputExpr:     "bidrequest:site.domain[0]",
+			expectedError: "value is not an array for path: site.domain[0]",
+		},
+		{
+			description:   "non numeric price bucket",
+			inputExpr:     "bidrequest:site.domain | pricebucket(0.1)",
+			expectedError: `value "WWW.Example.COM" is not numeric`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			expr, err := parseExpression(test.inputExpr)
+			assert.NoError(t, err, "unexpected parse error")
+
+			ctx := &expressionContext{
+				reqCache:    &requestCache{resolvedReq: reqJson},
+				queryParams: url.Values{"page": []string{"article"}},
+				bidderName:  "appnexus",
+				bid:         bid,
+				bidsCache:   bidsCache{bids: make(map[string]map[string][]byte)},
+				response:    response,
+				seatExt:     json.RawMessage(`{"tier": "gold"}`),
+			}
+			value, err := expr.evaluate(ctx)
+			if len(test.expectedError) > 0 {
+				assert.EqualError(t, err, test.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expectedValue, value)
+			}
+		})
+	}
+}
@@ -44,11 +44,19 @@ func validateAdServerTargeting(adServerTargeting []openrtb_ext.AdServerTarget) (
 		targetingObjSource := DataSource(strings.ToLower(targetingObj.Source))
 		if targetingObjSource != SourceStatic &&
 			targetingObjSource != SourceBidRequest &&
-			targetingObjSource != SourceBidResponse {
+			targetingObjSource != SourceBidResponse &&
+			targetingObjSource != SourceExpression {
 			isDataCorrect = false
 			warnings = append(warnings, createWarning(fmt.Sprintf("Incorrect source for the ad server targeting object at index %d", i)))
 		}
 
+		if targetingObjSource == SourceExpression && len(targetingObj.Value) > 0 {
+			if _, err := parseExpression(targetingObj.Value); err != nil {
+				isDataCorrect = false
+				warnings = append(warnings, createWarning(fmt.Sprintf("Invalid expression for the ad server targeting object at index %d: %s", i, err.Error())))
+			}
+		}
+
 		if isDataCorrect {
 			validatedAdServerTargeting = append(validatedAdServerTargeting, targetingObj)
 		}
@@ -113,6 +113,28 @@ func TestValidateAdServerTargeting(t *testing.T) {
 				{Code: 10007, Message: "Value is empty for the ad server targeting object at index 0"},
 			},
 		},
+		{
+			description: "valid expression targeting object",
+			inputTargeting: []openrtb_ext.AdServerTarget{
+				{Key: "adt_key", Source: "expression", Value: "bidrequest:site.domain | lower + '_' + bidresponse:seatbid.bid.price | pricebucket(0.5)"},
+			},
+			expectedTargeting: []openrtb_ext.AdServerTarget{
+				{Key: "adt_key", Source: "expression", Value: "bidrequest:site.domain | lower + '_' + bidresponse:seatbid.bid.price | pricebucket(0.5)"},
+			},
+			expectedWarnings: []openrtb_ext.ExtBidderMessage(nil),
+		},
+		{
+			description: "invalid targeting object: expression",
+			inputTargeting: []openrtb_ext.AdServerTarget{
+				{Key: "adt_key1", Source: "expression", Value: "bidrequest:site.domain | upper"},
+				{Key: "adt_key2", Source: "expression", Value: "bidrequest:site.domain | truncate(-1)"},
+			},
+			expectedTargeting: []openrtb_ext.AdServerTarget(nil),
+			expectedWarnings: []openrtb_ext.ExtBidderMessage{
+				{Code: 10007, Message: `Invalid expression for the ad server targeting object at index 0: unknown transform "upper"`},
+				{Code: 10007, Message: `Invalid expression for the ad server targeting object at index 1: transform truncate requires a positive length, got "-1"`},
+			},
+		},
 		{
 			description: "valid and invalid targeting object",
 			inputTargeting: []openrtb_ext.AdServerTarget{
@@ -67,6 +67,47 @@ func processResponseTargetingData(
 	return warnings
 }
 
+func processExpressionTargetingData(
+	adServerTargetingData *adServerTargetingData,
+	targetingData map[string]string,
+	bidderName string,
+	bid openrtb2.Bid,
+	bidsHolder bidsCache,
+	response *openrtb2.BidResponse,
+	seatExt json.RawMessage) []openrtb_ext.ExtBidderMessage {
+
+	var warnings []openrtb_ext.ExtBidderMessage
+	if len(adServerTargetingData.ExpressionTargetingData) == 0 {
+		return warnings
+	}
+
+	ctx := &expressionContext{
+		reqCache:    adServerTargetingData.requestCache,
+		queryParams: adServerTargetingData.queryParams,
+		bidderName:  bidderName,
+		bid:         bid,
+		bidsCache:   bidsHolder,
+		response:    response,
+		seatExt:     seatExt,
+	}
+
+	for _, exprTargetingData := range adServerTargetingData.ExpressionTargetingData {
+		key := exprTargetingData.Key
+		if exprTargetingData.HasMacro {
+			key = strings.Replace(key, bidderMacro, bidderName, -1)
+		}
+
+		value, err := exprTargetingData.Expression.evaluate(ctx)
+		if err != nil {
+			message := fmt.Sprintf("%s in expression for key: %s, bidder: %s, bid id: %s", err.Error(), exprTargetingData.Key, bidderName, bid.ID)
+			warnings = append(warnings, createWarning(message))
+		} else {
+			targetingData[key] = value
+		}
+	}
+	return warnings
+}
+
 func buildBidExt(targetingData map[string]string,
 	bid openrtb2.Bid,
 	warnings []openrtb_ext.ExtBidderMessage,
@@ -230,6 +230,65 @@ func TestProcessResponseTargetingData(t *testing.T) {
 	}
 }
 
+func TestProcessExpressionTargetingData(t *testing.T) {
+	lowerDomain, _ := parseExpression("bidrequest:site.domain | lower")
+	bucketedPrice, _ := parseExpression("bidresponse:seatbid.bid.price | pricebucket(0.1, 10)")
+	missingPage, _ := parseExpression("bidrequest:site.page")
+
+	testCases := []struct {
+		description            string
+		inputAdServerTargeting adServerTargetingData
+		inputTargetingData     map[string]string
+		expectedTargetingData  map[string]string
+		expectedWarnings       []openrtb_ext.ExtBidderMessage
+	}{
+		{
+			description:            "no expressions",
+			inputAdServerTargeting: adServerTargetingData{},
+			inputTargetingData:     map[string]string{"inKey1": "inVal1"},
+			expectedTargetingData:  map[string]string{"inKey1": "inVal1"},
+			expectedWarnings:       []openrtb_ext.ExtBidderMessage(nil),
+		},
+		{
+			description: "expressions with bidder macro",
+			inputAdServerTargeting: adServerTargetingData{
+				ExpressionTargetingData: []ExpressionTargetingData{
+					{Key: "domain", Expression: lowerDomain},
+					{Key: "{{BIDDER}}_pb", HasMacro: true, Expression: bucketedPrice},
+				},
+			},
+			inputTargetingData: map[string]string{"inKey1": "inVal1"},
+			expectedTargetingData: map[string]string{
+				"inKey1": "inVal1", "domain": "test.com", "bidderA_pb": "1.20",
+			},
+			expectedWarnings: []openrtb_ext.ExtBidderMessage(nil),
+		},
+		{
+			description: "expression error is reported per key",
+			inputAdServerTargeting: adServerTargetingData{
+				ExpressionTargetingData: []ExpressionTargetingData{
+					{Key: "page", Expression: missingPage},
+					{Key: "domain", Expression: lowerDomain},
+				},
+			},
+			inputTargetingData:    map[string]string{},
+			expectedTargetingData: map[string]string{"domain": "test.com"},
+			expectedWarnings: []openrtb_ext.ExtBidderMessage{
+				{Code: 10007, Message: "value not found for path: site.page in expression for key: page, bidder: bidderA, bid id: testBidId"},
+			},
+		},
+	}
+
+	bid := openrtb2.Bid{ID: "testBidId", ImpID: "testBidImpId1", Price: 1.25}
+	for _, test := range testCases {
+		test.inputAdServerTargeting.requestCache = &requestCache{resolvedReq: json.RawMessage(`{"site": {"domain": "TEST.com"}}`)}
+		bidsCache := bidsCache{bids: make(map[string]map[string][]byte)}
+		actualWarnings := processExpressionTargetingData(&test.inputAdServerTargeting, test.inputTargetingData, "bidderA", bid, bidsCache, &openrtb2.BidResponse{}, nil)
+		assert.Equal(t, test.expectedTargetingData, test.inputTargetingData, "incorrect targeting data")
+		assert.Equal(t, test.expectedWarnings, actualWarnings, "incorrect warnings")
+	}
+}
+
 func TestBuildBidExt(t *testing.T) {
 
 	testCases := []struct {
@@ -149,8 +149,9 @@ func evaluateActivities(rw *openrtb_ext.RequestWrapper, ac privacy.ActivityContr
 	}
 	blockUserFPD := !ac.Allow(privacy.ActivityTransmitUserFPD, component, privacy.ActivityRequest{})
 	blockPreciseGeo := !ac.Allow(privacy.ActivityTransmitPreciseGeo, component, privacy.ActivityRequest{})
+	deniedIDs := ac.DeniedIDs(rw, component, privacy.ActivityRequest{})
 
-	if !blockUserFPD && !blockPreciseGeo {
+	if !blockUserFPD && !blockPreciseGeo && !deniedIDs.Any() {
 		return true, nil
 	}
 
@@ -165,6 +166,7 @@ func evaluateActivities(rw *openrtb_ext.RequestWrapper, ac privacy.ActivityContr
 		ipConf := privacy.IPConf{IPV6: ac.IPv6Config, IPV4: ac.IPv4Config}
 		privacy.ScrubGeoAndDeviceIP(cloneReq, ipConf)
 	}
+	privacy.ScrubDeniedIDs(cloneReq, deniedIDs)
 
 	cloneReq.RebuildRequest()
 	return true, cloneReq
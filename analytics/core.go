@@ -31,7 +31,11 @@ type AuctionObject struct {
 	StartTime            time.Time
 	HookExecutionOutcome []hookexecution.StageOutcome
 	SeatNonBid           []openrtb_ext.SeatNonBid
+	PrivacyAudit         []openrtb_ext.PrivacyAuditRecord
 	RequestWrapper       *openrtb_ext.RequestWrapper
+	DSA                  []openrtb_ext.DSARecord
+	// StoredRequestVersion is the version of the Stored Request data which served the auction
+	StoredRequestVersion string
 }
 
 // Loggable object of a transaction at /openrtb2/amp endpoint
@@ -44,7 +48,11 @@ type AmpObject struct {
 	StartTime            time.Time
 	HookExecutionOutcome []hookexecution.StageOutcome
 	SeatNonBid           []openrtb_ext.SeatNonBid
+	PrivacyAudit         []openrtb_ext.PrivacyAuditRecord
 	RequestWrapper       *openrtb_ext.RequestWrapper
+	DSA                  []openrtb_ext.DSARecord
+	// StoredRequestVersion is the version of the Stored Request data which served the auction
+	StoredRequestVersion string
 }
 
 // Loggable object of a transaction at /openrtb2/video endpoint
@@ -56,7 +64,11 @@ type VideoObject struct {
 	VideoResponse  *openrtb_ext.BidResponseVideo
 	StartTime      time.Time
 	SeatNonBid     []openrtb_ext.SeatNonBid
+	PrivacyAudit   []openrtb_ext.PrivacyAuditRecord
 	RequestWrapper *openrtb_ext.RequestWrapper
+	DSA            []openrtb_ext.DSARecord
+	// StoredRequestVersion is the version of the Stored Request data which served the auction
+	StoredRequestVersion string
 }
 
 // Loggable object of a transaction at /setuid
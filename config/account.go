@@ -2,13 +2,16 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"strings"
+	"time"
 
 	"github.com/prebid/go-gdpr/consentconstants"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/util/iputil"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
 )
 
 // ChannelType enumerates the values of integrations Prebid Server can configure for an account
@@ -32,6 +35,14 @@ const (
 	RoundingModeUp        BidRoundingMode = "up"
 )
 
+// ClearingMode enumerates the pricing modes the exchange can apply to the winning bid of an impression
+type ClearingMode string
+
+const (
+	ClearingModeFirstPrice  ClearingMode = "first_price"
+	ClearingModeSecondPrice ClearingMode = "second_price"
+)
+
 // Account represents a publisher account configuration
 type Account struct {
 	ID                      string                                      `mapstructure:"id" json:"id"`
@@ -54,6 +65,13 @@ type Account struct {
 	Privacy                 AccountPrivacy                              `mapstructure:"privacy" json:"privacy"`
 	PreferredMediaType      openrtb_ext.PreferredMediaType              `mapstructure:"preferredmediatype" json:"preferredmediatype"`
 	TargetingPrefix         string                                      `mapstructure:"targeting_prefix" json:"targeting_prefix"`
+	AuctionMacros           AccountAuctionMacros                        `mapstructure:"auction_macros" json:"auction_macros"`
+	PriceClearing           AccountPriceClearing                        `mapstructure:"price_clearing" json:"price_clearing"`
+	StoredRequestVersions   AccountStoredRequestVersions                `mapstructure:"stored_request_versions" json:"stored_request_versions"`
+	BidderTransforms        AccountBidderTransforms                     `mapstructure:"bidder_transforms" json:"bidder_transforms"`
+	// ReturnCreativeOnCacheFailure keeps the adm of the bids which couldn't be cached, even when the request
+	// asks for the creatives not to be returned
+	ReturnCreativeOnCacheFailure bool `mapstructure:"return_creative_on_cache_failure" json:"return_creative_on_cache_failure"`
 
 	BidPriceThreshold float64 `mapstructure:"bidpricethreshold" json:"bidpricethreshold"`
 }
@@ -66,6 +84,191 @@ type CookieSync struct {
 	PriorityGroups  [][]string `mapstructure:"priority_groups" json:"priority_groups"`
 }
 
+// AccountAuctionMacros represents account-specific configuration for resolving the standard OpenRTB
+// auction macros (${AUCTION_PRICE}, ${AUCTION_ID}, ...) in the adm, nurl, burl and lurl of the bids
+type AccountAuctionMacros struct {
+	Enabled bool `mapstructure:"enabled" json:"enabled"`
+	// PriceEncoding is the name of the registered macros.PriceEncoder used for ${AUCTION_PRICE}
+	PriceEncoding string `mapstructure:"price_encoding" json:"price_encoding"`
+}
+
+// AccountPriceClearing represents account-specific configuration of the price winning bids clear at
+type AccountPriceClearing struct {
+	Mode ClearingMode `mapstructure:"mode" json:"mode"`
+	// Increment is added to the second highest price (or the soft floor) to compute the clearing price
+	Increment float64 `mapstructure:"increment" json:"increment"`
+	// SoftFloorMultiplier derives the soft floor of an impression from its hard floor (imp.bidfloor).
+	// Winning bids between the hard and the soft floor clear at the bid price.
+	SoftFloorMultiplier float64 `mapstructure:"soft_floor_multiplier" json:"soft_floor_multiplier"`
+}
+
+// Validate checks the price clearing mode and its parameters
+func (pc *AccountPriceClearing) Validate(errs []error) []error {
+	if pc.Mode != "" && pc.Mode != ClearingModeFirstPrice && pc.Mode != ClearingModeSecondPrice {
+		errs = append(errs, fmt.Errorf("price_clearing.mode must be %s or %s, got %s", ClearingModeFirstPrice, ClearingModeSecondPrice, pc.Mode))
+	}
+	if pc.Increment < 0 {
+		errs = append(errs, fmt.Errorf("price_clearing.increment must be >= 0, got %v", pc.Increment))
+	}
+	if pc.SoftFloorMultiplier != 0 && pc.SoftFloorMultiplier < 1 {
+		errs = append(errs, fmt.Errorf("price_clearing.soft_floor_multiplier must be 0 or >= 1, got %v", pc.SoftFloorMultiplier))
+	}
+	return errs
+}
+
+// IsSecondPrice indicates whether winning bids clear at the second highest price
+func (pc *AccountPriceClearing) IsSecondPrice() bool {
+	return pc.Mode == ClearingModeSecondPrice
+}
+
+// AccountStoredRequestVersions represents the staged rollout of a version of the Stored Requests and Imps
+// of the account. Versions tag the Stored Request data, see stored_requests.VersionedID.
+type AccountStoredRequestVersions struct {
+	// Current is the version serving the auctions outside of the rollout, empty for the untagged data
+	Current string `mapstructure:"current" json:"current"`
+	// Candidate is the version serving CandidatePercent percent of the auctions
+	Candidate        string `mapstructure:"candidate" json:"candidate"`
+	CandidatePercent int    `mapstructure:"candidate_percent" json:"candidate_percent"`
+	// Rollback serves the current version to all the auctions without discarding the rollout
+	Rollback bool `mapstructure:"rollback" json:"rollback"`
+}
+
+// Validate checks the rollout percentage of the candidate version
+func (v *AccountStoredRequestVersions) Validate(errs []error) []error {
+	if v.CandidatePercent < 0 || v.CandidatePercent > 100 {
+		errs = append(errs, fmt.Errorf("stored_request_versions.candidate_percent must be between 0 and 100, got %d", v.CandidatePercent))
+	}
+	return errs
+}
+
+// Select returns the version serving the auctions of the users in the given bucket in [0,100)
+func (v *AccountStoredRequestVersions) Select(bucket int) string {
+	if !v.Rollback && bucket < v.CandidatePercent {
+		return v.Candidate
+	}
+	return v.Current
+}
+
+// BidderTransformOp enumerates the operations of the bidder request transformation rules
+type BidderTransformOp string
+
+const (
+	BidderTransformSet          BidderTransformOp = "set"
+	BidderTransformDelete       BidderTransformOp = "delete"
+	BidderTransformCopy         BidderTransformOp = "copy"
+	BidderTransformRename       BidderTransformOp = "rename"
+	BidderTransformCapImps      BidderTransformOp = "cap_imps"
+	BidderTransformStripFormats BidderTransformOp = "strip_formats"
+	BidderTransformMapParams    BidderTransformOp = "map_params"
+)
+
+// BidderTransformAllBidders matches every bidder in the bidders of a transformation rule
+const BidderTransformAllBidders = "*"
+
+// AccountBidderTransforms represents the declarative rules rewriting the request of each bidder before its
+// adapter builds the HTTP requests. Rules apply in order.
+type AccountBidderTransforms struct {
+	Rules []BidderTransformRule `mapstructure:"rules" json:"rules"`
+}
+
+// BidderTransformRule is one operation on the OpenRTB request of the bidders it lists
+type BidderTransformRule struct {
+	Bidders []string          `mapstructure:"bidders" json:"bidders"`
+	Op      BidderTransformOp `mapstructure:"op" json:"op"`
+	// Path is the JSON pointer of the field set, deleted or written by copy and rename. The "*" token matches
+	// every element of an array.
+	Path string `mapstructure:"path" json:"path"`
+	// From is the JSON pointer of the field copied or renamed. Its "*" tokens bind, in order, to the ones of Path.
+	From  string `mapstructure:"from" json:"from"`
+	Value any    `mapstructure:"value" json:"value"`
+	// MaxImps is the number of imps kept by cap_imps
+	MaxImps int `mapstructure:"max_imps" json:"max_imps"`
+	// MediaTypes lists the imp formats removed by strip_formats, imps left without any format are removed
+	MediaTypes []openrtb_ext.BidType `mapstructure:"media_types" json:"media_types"`
+	// Params renames the bidder params of the imps (imp.ext.bidder) to the names the bidder expects
+	Params map[string]string `mapstructure:"params" json:"params"`
+}
+
+// Validate checks the operation, pointers and parameters of each rule
+func (bt *AccountBidderTransforms) Validate(errs []error) []error {
+	for i, rule := range bt.Rules {
+		if err := rule.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("bidder_transforms.rules[%d]: %s", i, err))
+		}
+	}
+	return errs
+}
+
+// AppliesTo indicates whether the rule lists the bidder
+func (r *BidderTransformRule) AppliesTo(bidder string) bool {
+	for _, b := range r.Bidders {
+		if b == bidder || b == BidderTransformAllBidders {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *BidderTransformRule) validate() error {
+	if len(r.Bidders) == 0 {
+		return errors.New("bidders must not be empty")
+	}
+
+	switch r.Op {
+	case BidderTransformSet:
+		if _, err := jsonutil.ParsePointer(r.Path); err != nil {
+			return fmt.Errorf("invalid path: %s", err)
+		}
+		if r.Value == nil {
+			return errors.New("value is required")
+		}
+	case BidderTransformDelete:
+		if _, err := jsonutil.ParsePointer(r.Path); err != nil {
+			return fmt.Errorf("invalid path: %s", err)
+		}
+	case BidderTransformCopy, BidderTransformRename:
+		path, err := jsonutil.ParsePointer(r.Path)
+		if err != nil {
+			return fmt.Errorf("invalid path: %s", err)
+		}
+		from, err := jsonutil.ParsePointer(r.From)
+		if err != nil {
+			return fmt.Errorf("invalid from: %s", err)
+		}
+		if path.Wildcards() != from.Wildcards() {
+			return errors.New("path and from must have the same number of * tokens")
+		}
+		if r.Path == r.From || strings.HasPrefix(r.Path, r.From+"/") {
+			return errors.New("path must not be from or one of its fields")
+		}
+	case BidderTransformCapImps:
+		if r.MaxImps < 1 {
+			return fmt.Errorf("max_imps must be >= 1, got %d", r.MaxImps)
+		}
+	case BidderTransformStripFormats:
+		if len(r.MediaTypes) == 0 {
+			return errors.New("media_types must not be empty")
+		}
+		for _, mediaType := range r.MediaTypes {
+			if _, err := openrtb_ext.ParseBidType(string(mediaType)); err != nil {
+				return fmt.Errorf("invalid media type %s", mediaType)
+			}
+		}
+	case BidderTransformMapParams:
+		if len(r.Params) == 0 {
+			return errors.New("params must not be empty")
+		}
+		for from, to := range r.Params {
+			if from == "" || to == "" {
+				return errors.New("params must not map empty names")
+			}
+		}
+	default:
+		return fmt.Errorf("unknown op %s", r.Op)
+	}
+	return nil
+}
+
 // AccountCCPA represents account-specific CCPA configuration
 type AccountCCPA struct {
 	Enabled        *bool          `mapstructure:"enabled" json:"enabled,omitempty"`
@@ -81,6 +284,8 @@ type AccountPriceFloors struct {
 	MaxRule                int               `mapstructure:"max_rules" json:"max_rules"`
 	MaxSchemaDims          int               `mapstructure:"max_schema_dims" json:"max_schema_dims"`
 	Fetcher                AccountFloorFetch `mapstructure:"fetch" json:"fetch"`
+	// Timezone is the IANA time zone of the publisher, used to resolve the hourOfDay floor schema field
+	Timezone string `mapstructure:"timezone" json:"timezone"`
 }
 
 // AccountFloorFetch defines the configuration for dynamic floors fetching.
@@ -137,6 +342,10 @@ func (pf *AccountPriceFloors) validate(errs []error) []error {
 		errs = append(errs, fmt.Errorf(`account_defaults.price_floors.fetch.max_schema_dims should not be less than 0 and greater than 20`))
 	}
 
+	if _, err := time.LoadLocation(pf.Timezone); err != nil {
+		errs = append(errs, fmt.Errorf(`account_defaults.price_floors.timezone %s is not a valid time zone`, pf.Timezone))
+	}
+
 	return errs
 }
 
@@ -355,11 +564,25 @@ type AccountPrivacy struct {
 	IPv6Config      IPv6             `mapstructure:"ipv6" json:"ipv6"`
 	IPv4Config      IPv4             `mapstructure:"ipv4" json:"ipv4"`
 	PrivacySandbox  PrivacySandbox   `mapstructure:"privacysandbox" json:"privacysandbox"`
+	USPrivacy       AccountUSPrivacy `mapstructure:"usprivacy" json:"usprivacy"`
 }
 
 type PrivacySandbox struct {
 	TopicsDomain      string            `mapstructure:"topicsdomain"`
 	CookieDeprecation CookieDeprecation `mapstructure:"cookiedeprecation"`
+	PAAPI             AccountPAAPI      `mapstructure:"paapi" json:"paapi"`
+}
+
+// AccountPAAPI controls the Protected Audience signals of the imps and the auction configs returned by the bidders.
+// They're allowed unless explicitly disabled, in which case imp[].ext.ae and imp[].ext.igs aren't sent to the
+// bidders and their auction configs are dropped.
+type AccountPAAPI struct {
+	Enabled *bool `mapstructure:"enabled" json:"enabled,omitempty"`
+}
+
+// Disabled returns true when the account explicitly disables the Protected Audience signals
+func (p AccountPAAPI) Disabled() bool {
+	return p.Enabled != nil && !*p.Enabled
 }
 
 type CookieDeprecation struct {
@@ -367,6 +590,68 @@ type CookieDeprecation struct {
 	TTLSec  int  `mapstructure:"ttl_sec"`
 }
 
+// AccountUSPrivacy configures the enforcement of the US National and US state GPP sections on privacy activities
+type AccountUSPrivacy struct {
+	Enabled  bool                               `mapstructure:"enabled" json:"enabled"`
+	Sections map[string]AccountUSPrivacySection `mapstructure:"sections" json:"sections"`
+}
+
+// AccountUSPrivacySection overrides the enforcement of a single US GPP section, sections are keyed by name (usnat,
+// usca, usva, usco, usut or usct)
+type AccountUSPrivacySection struct {
+	Enabled *bool           `mapstructure:"enabled" json:"enabled"`
+	Rules   []USPrivacyRule `mapstructure:"rules" json:"rules"`
+}
+
+// USPrivacyRule allows or denies activities for the matching components regardless of the signals of the section
+type USPrivacyRule struct {
+	Activities []string          `mapstructure:"activities" json:"activities"`
+	Condition  ActivityCondition `mapstructure:"condition" json:"condition"`
+	Allow      bool              `mapstructure:"allow" json:"allow"`
+}
+
+// usPrivacySections are the names of the US GPP sections which can be overridden
+var usPrivacySections = map[string]struct{}{
+	"usnat": {},
+	"usca":  {},
+	"usva":  {},
+	"usco":  {},
+	"usut":  {},
+	"usct":  {},
+}
+
+// usPrivacyActivities are the names of the activities US privacy rules can apply to
+var usPrivacyActivities = map[string]struct{}{
+	"syncUser":                 {},
+	"fetchBids":                {},
+	"enrichUfpd":               {},
+	"reportAnalytics":          {},
+	"transmitUfpd":             {},
+	"transmitPreciseGeo":       {},
+	"transmitUniqueRequestIds": {},
+	"transmitTid":              {},
+	"transmitEids":             {},
+	"transmitDeviceIds":        {},
+	"transmitPaapi":            {},
+}
+
+func (up *AccountUSPrivacy) Validate(errs []error) []error {
+	for name, section := range up.Sections {
+		if _, ok := usPrivacySections[name]; !ok {
+			errs = append(errs, fmt.Errorf("account_defaults.privacy.usprivacy.sections %s is not a US GPP section", name))
+			continue
+		}
+		for _, rule := range section.Rules {
+			for _, activity := range rule.Activities {
+				if _, ok := usPrivacyActivities[activity]; !ok {
+					errs = append(errs, fmt.Errorf("account_defaults.privacy.usprivacy.sections.%s.rules activity %s is not supported", name, activity))
+				}
+			}
+		}
+	}
+	return errs
+}
+
 // AccountDSA represents DSA configuration
 type AccountDSA struct {
 	Default         string `mapstructure:"default" json:"default"`
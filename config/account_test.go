@@ -7,6 +7,7 @@ import (
 
 	"github.com/prebid/go-gdpr/consentconstants"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -956,6 +957,31 @@ func TestAccountPriceFloorsValidate(t *testing.T) {
 			},
 			want: []error{errors.New("account_defaults.price_floors.fetch.max_schema_dims should not be less than 0 and greater than 20")},
 		},
+		{
+			description: "Valid timezone",
+			pf: &AccountPriceFloors{
+				EnforceFloorsRate: 100,
+				Fetcher: AccountFloorFetch{
+					Period:  300,
+					MaxAge:  600,
+					Timeout: 12,
+				},
+				Timezone: "America/New_York",
+			},
+		},
+		{
+			description: "Invalid timezone",
+			pf: &AccountPriceFloors{
+				EnforceFloorsRate: 100,
+				Fetcher: AccountFloorFetch{
+					Period:  300,
+					MaxAge:  600,
+					Timeout: 12,
+				},
+				Timezone: "Mars/Olympus_Mons",
+			},
+			want: []error{errors.New("account_defaults.price_floors.timezone Mars/Olympus_Mons is not a valid time zone")},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.description, func(t *testing.T) {
@@ -1011,3 +1037,247 @@ func TestIPMaskingValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestAccountPriceClearingValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		clearing AccountPriceClearing
+		want     []error
+	}{
+		{
+			name:     "empty",
+			clearing: AccountPriceClearing{},
+		},
+		{
+			name:     "valid_second_price",
+			clearing: AccountPriceClearing{Mode: ClearingModeSecondPrice, Increment: 0.01, SoftFloorMultiplier: 1.5},
+		},
+		{
+			name:     "invalid",
+			clearing: AccountPriceClearing{Mode: "third_price", Increment: -1, SoftFloorMultiplier: 0.5},
+			want: []error{
+				errors.New("price_clearing.mode must be first_price or second_price, got third_price"),
+				errors.New("price_clearing.increment must be >= 0, got -1"),
+				errors.New("price_clearing.soft_floor_multiplier must be 0 or >= 1, got 0.5"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errs []error
+			errs = tt.clearing.Validate(errs)
+			assert.ElementsMatch(t, errs, tt.want)
+		})
+	}
+}
+
+func TestAccountStoredRequestVersionsValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions AccountStoredRequestVersions
+		want     []error
+	}{
+		{
+			name:     "empty",
+			versions: AccountStoredRequestVersions{},
+		},
+		{
+			name:     "valid",
+			versions: AccountStoredRequestVersions{Current: "v1", Candidate: "v2", CandidatePercent: 100},
+		},
+		{
+			name:     "negative_percent",
+			versions: AccountStoredRequestVersions{Candidate: "v2", CandidatePercent: -1},
+			want:     []error{errors.New("stored_request_versions.candidate_percent must be between 0 and 100, got -1")},
+		},
+		{
+			name:     "percent_above_100",
+			versions: AccountStoredRequestVersions{Candidate: "v2", CandidatePercent: 101},
+			want:     []error{errors.New("stored_request_versions.candidate_percent must be between 0 and 100, got 101")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errs []error
+			errs = tt.versions.Validate(errs)
+			assert.ElementsMatch(t, errs, tt.want)
+		})
+	}
+}
+
+func TestAccountStoredRequestVersionsSelect(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions AccountStoredRequestVersions
+		bucket   int
+		want     string
+	}{
+		{
+			name:     "no_rollout",
+			versions: AccountStoredRequestVersions{},
+			bucket:   0,
+			want:     "",
+		},
+		{
+			name:     "in_rollout",
+			versions: AccountStoredRequestVersions{Current: "v1", Candidate: "v2", CandidatePercent: 10},
+			bucket:   9,
+			want:     "v2",
+		},
+		{
+			name:     "outside_rollout",
+			versions: AccountStoredRequestVersions{Current: "v1", Candidate: "v2", CandidatePercent: 10},
+			bucket:   10,
+			want:     "v1",
+		},
+		{
+			name:     "full_rollout",
+			versions: AccountStoredRequestVersions{Current: "v1", Candidate: "v2", CandidatePercent: 100},
+			bucket:   99,
+			want:     "v2",
+		},
+		{
+			name:     "rollback",
+			versions: AccountStoredRequestVersions{Current: "v1", Candidate: "v2", CandidatePercent: 100, Rollback: true},
+			bucket:   0,
+			want:     "v1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.versions.Select(tt.bucket))
+		})
+	}
+}
+
+func TestAccountUSPrivacyValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		usPrivacy AccountUSPrivacy
+		want      []error
+	}{
+		{
+			name:      "empty",
+			usPrivacy: AccountUSPrivacy{},
+		},
+		{
+			name: "valid",
+			usPrivacy: AccountUSPrivacy{
+				Enabled: true,
+				Sections: map[string]AccountUSPrivacySection{
+					"usnat": {Enabled: ptrutil.ToPtr(false)},
+					"usca":  {Rules: []USPrivacyRule{{Activities: []string{"syncUser", "transmitPreciseGeo"}, Allow: true}}},
+				},
+			},
+		},
+		{
+			name: "invalid",
+			usPrivacy: AccountUSPrivacy{
+				Enabled: true,
+				Sections: map[string]AccountUSPrivacySection{
+					"usfl": {},
+					"usva": {Rules: []USPrivacyRule{{Activities: []string{"syncUser", "transmitEverything"}}}},
+				},
+			},
+			want: []error{
+				errors.New("account_defaults.privacy.usprivacy.sections usfl is not a US GPP section"),
+				errors.New("account_defaults.privacy.usprivacy.sections.usva.rules activity transmitEverything is not supported"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errs []error
+			errs = tt.usPrivacy.Validate(errs)
+			assert.ElementsMatch(t, errs, tt.want)
+		})
+	}
+}
+
+func TestAccountBidderTransformsValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		rule BidderTransformRule
+		want []error
+	}{
+		{
+			name: "valid_set",
+			rule: BidderTransformRule{Bidders: []string{"*"}, Op: BidderTransformSet, Path: "/imp/*/ext/bidder/siteId", Value: "123"},
+		},
+		{
+			name: "valid_rename",
+			rule: BidderTransformRule{Bidders: []string{"appnexus"}, Op: BidderTransformRename, Path: "/imp/*/ext/bidder/tag", From: "/imp/*/tagid"},
+		},
+		{
+			name: "valid_strip_formats",
+			rule: BidderTransformRule{Bidders: []string{"appnexus"}, Op: BidderTransformStripFormats, MediaTypes: []openrtb_ext.BidType{openrtb_ext.BidTypeVideo}},
+		},
+		{
+			name: "no_bidders",
+			rule: BidderTransformRule{Op: BidderTransformDelete, Path: "/site/keywords"},
+			want: []error{errors.New("bidder_transforms.rules[0]: bidders must not be empty")},
+		},
+		{
+			name: "unknown_op",
+			rule: BidderTransformRule{Bidders: []string{"appnexus"}, Op: "move"},
+			want: []error{errors.New("bidder_transforms.rules[0]: unknown op move")},
+		},
+		{
+			name: "set_invalid_path",
+			rule: BidderTransformRule{Bidders: []string{"appnexus"}, Op: BidderTransformSet, Path: "imp", Value: 1},
+			want: []error{errors.New("bidder_transforms.rules[0]: invalid path: pointer must start with /")},
+		},
+		{
+			name: "set_without_value",
+			rule: BidderTransformRule{Bidders: []string{"appnexus"}, Op: BidderTransformSet, Path: "/tmax"},
+			want: []error{errors.New("bidder_transforms.rules[0]: value is required")},
+		},
+		{
+			name: "copy_wildcard_mismatch",
+			rule: BidderTransformRule{Bidders: []string{"appnexus"}, Op: BidderTransformCopy, Path: "/ext/tagid", From: "/imp/*/tagid"},
+			want: []error{errors.New("bidder_transforms.rules[0]: path and from must have the same number of * tokens")},
+		},
+		{
+			name: "rename_into_from",
+			rule: BidderTransformRule{Bidders: []string{"appnexus"}, Op: BidderTransformRename, Path: "/site/ext/site", From: "/site"},
+			want: []error{errors.New("bidder_transforms.rules[0]: path must not be from or one of its fields")},
+		},
+		{
+			name: "cap_imps_zero",
+			rule: BidderTransformRule{Bidders: []string{"appnexus"}, Op: BidderTransformCapImps},
+			want: []error{errors.New("bidder_transforms.rules[0]: max_imps must be >= 1, got 0")},
+		},
+		{
+			name: "strip_formats_invalid_media_type",
+			rule: BidderTransformRule{Bidders: []string{"appnexus"}, Op: BidderTransformStripFormats, MediaTypes: []openrtb_ext.BidType{"display"}},
+			want: []error{errors.New("bidder_transforms.rules[0]: invalid media type display")},
+		},
+		{
+			name: "map_params_empty_name",
+			rule: BidderTransformRule{Bidders: []string{"appnexus"}, Op: BidderTransformMapParams, Params: map[string]string{"placementId": ""}},
+			want: []error{errors.New("bidder_transforms.rules[0]: params must not map empty names")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transforms := AccountBidderTransforms{Rules: []BidderTransformRule{tt.rule}}
+			var errs []error
+			errs = transforms.Validate(errs)
+			assert.ElementsMatch(t, errs, tt.want)
+		})
+	}
+}
+
+func TestBidderTransformRuleAppliesTo(t *testing.T) {
+	rule := BidderTransformRule{Bidders: []string{"appnexus", "rubicon"}}
+	assert.True(t, rule.AppliesTo("appnexus"))
+	assert.False(t, rule.AppliesTo("pubmatic"))
+
+	rule = BidderTransformRule{Bidders: []string{BidderTransformAllBidders}}
+	assert.True(t, rule.AppliesTo("pubmatic"))
+}
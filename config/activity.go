@@ -9,6 +9,9 @@ type AllowActivities struct {
 	TransmitPreciseGeo       Activity `mapstructure:"transmitPreciseGeo" json:"transmitPreciseGeo"`
 	TransmitUniqueRequestIds Activity `mapstructure:"transmitUniqueRequestIds" json:"transmitUniqueRequestIds"`
 	TransmitTids             Activity `mapstructure:"transmitTid" json:"transmitTid"`
+	TransmitEids             Activity `mapstructure:"transmitEids" json:"transmitEids"`
+	TransmitDeviceIds        Activity `mapstructure:"transmitDeviceIds" json:"transmitDeviceIds"`
+	TransmitPAAPI            Activity `mapstructure:"transmitPaapi" json:"transmitPaapi"`
 }
 
 type Activity struct {
@@ -24,4 +27,6 @@ type ActivityRule struct {
 type ActivityCondition struct {
 	ComponentName []string `mapstructure:"componentName" json:"componentName"`
 	ComponentType []string `mapstructure:"componentType" json:"componentType"`
+	// EIDSource restricts the rule to the EIDs of the sources, it only applies to the transmitEids activity
+	EIDSource []string `mapstructure:"eidSource" json:"eidSource"`
 }
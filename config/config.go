@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"os"
 	"reflect"
 	"strings"
 	"time"
@@ -58,6 +59,8 @@ type Configuration struct {
 	StoredResponses StoredRequests `mapstructure:"stored_responses"`
 	// StoredRequestsTimeout defines the number of milliseconds before a timeout occurs with stored requests fetch
 	StoredRequestsTimeout int `mapstructure:"stored_requests_timeout_ms"`
+	// StoredDataAPI configures the admin endpoint managing the stored data kept in writable backends
+	StoredDataAPI StoredDataAPI `mapstructure:"stored_data_api"`
 
 	MaxRequestSize       int64             `mapstructure:"max_request_size"`
 	Analytics            Analytics         `mapstructure:"analytics"`
@@ -97,6 +100,8 @@ type Configuration struct {
 	// GenerateRequestID overrides the bidrequest.id in an AMP Request or an App Stored Request with a generated UUID if set to true. The default is false.
 	GenerateRequestID bool                      `mapstructure:"generate_request_id"`
 	HostSChainNode    *openrtb2.SupplyChainNode `mapstructure:"host_schain_node"`
+	// SChainValidation configures the validation of the supply chains of the incoming requests
+	SChainValidation SChainValidation `mapstructure:"schain_validation"`
 	// Experiment configures non-production ready features.
 	Experiment Experiment `mapstructure:"experiment"`
 	DataCenter string     `mapstructure:"datacenter"`
@@ -113,8 +118,81 @@ type Admin struct {
 	Enabled bool `mapstructure:"enabled"`
 }
 type PriceFloors struct {
-	Enabled bool              `mapstructure:"enabled"`
-	Fetcher PriceFloorFetcher `mapstructure:"fetcher"`
+	Enabled     bool                  `mapstructure:"enabled"`
+	Fetcher     PriceFloorFetcher     `mapstructure:"fetcher"`
+	Suggestions PriceFloorSuggestions `mapstructure:"suggestions"`
+}
+
+type FloorSuggestionStrategy string
+
+const (
+	// FloorSuggestionStrategyPercentile suggests the configured percentile of the observed top bid prices
+	FloorSuggestionStrategyPercentile FloorSuggestionStrategy = "percentile"
+	// FloorSuggestionStrategyTargetFill suggests the highest floor that the configured share of observed top bids still clears
+	FloorSuggestionStrategyTargetFill FloorSuggestionStrategy = "target_fill"
+)
+
+// PriceFloorSuggestions configures the derivation of candidate floor rules from the observed bid landscape
+type PriceFloorSuggestions struct {
+	Enabled bool `mapstructure:"enabled"`
+	// PeriodSec is the interval at which suggestions are regenerated and the observed landscape is reset
+	PeriodSec int                     `mapstructure:"period_sec"`
+	Strategy  FloorSuggestionStrategy `mapstructure:"strategy"`
+	// Percentile of the observed top bid prices used by the percentile strategy, in the range [0, 100]
+	Percentile float64 `mapstructure:"percentile"`
+	// TargetFillRate is the share of observed top bids, in the range (0, 1], expected to clear the suggested floor
+	TargetFillRate float64 `mapstructure:"target_fill_rate"`
+	// Fields are the floor schema dimensions bids are aggregated by
+	Fields []string `mapstructure:"fields"`
+	// Currency of the suggested floor rules
+	Currency string `mapstructure:"currency"`
+	// MinSamples is the number of observed top bids needed before a rule is suggested
+	MinSamples int `mapstructure:"min_samples"`
+	// MaxSamples caps the number of top bids retained per rule, older observations are reservoir sampled
+	MaxSamples int `mapstructure:"max_samples"`
+	// MaxRules caps the number of rules tracked per account
+	MaxRules int `mapstructure:"max_rules"`
+	// MaxAccounts caps the number of accounts whose landscape is tracked within a period
+	MaxAccounts int `mapstructure:"max_accounts"`
+	// OutputDir, if set, is the directory suggestions are written to as <account>.json in floors fetch format
+	OutputDir string `mapstructure:"output_dir"`
+}
+
+func (cfg *PriceFloorSuggestions) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.PeriodSec <= 0 {
+		errs = append(errs, fmt.Errorf("price_floors.suggestions.period_sec must be > 0. Got %d", cfg.PeriodSec))
+	}
+	switch cfg.Strategy {
+	case FloorSuggestionStrategyPercentile:
+		if cfg.Percentile < 0 || cfg.Percentile > 100 {
+			errs = append(errs, fmt.Errorf("price_floors.suggestions.percentile must be in the range [0, 100]. Got %g", cfg.Percentile))
+		}
+	case FloorSuggestionStrategyTargetFill:
+		if cfg.TargetFillRate <= 0 || cfg.TargetFillRate > 1 {
+			errs = append(errs, fmt.Errorf("price_floors.suggestions.target_fill_rate must be in the range (0, 1]. Got %g", cfg.TargetFillRate))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("price_floors.suggestions.strategy must be one of %s, %s. Got %s", FloorSuggestionStrategyPercentile, FloorSuggestionStrategyTargetFill, cfg.Strategy))
+	}
+	if len(cfg.Fields) == 0 {
+		errs = append(errs, errors.New("price_floors.suggestions.fields must not be empty"))
+	}
+	if cfg.MinSamples <= 0 {
+		errs = append(errs, fmt.Errorf("price_floors.suggestions.min_samples must be > 0. Got %d", cfg.MinSamples))
+	}
+	if cfg.MaxSamples < cfg.MinSamples {
+		errs = append(errs, fmt.Errorf("price_floors.suggestions.max_samples must be >= min_samples. Got %d", cfg.MaxSamples))
+	}
+	if cfg.MaxRules <= 0 {
+		errs = append(errs, fmt.Errorf("price_floors.suggestions.max_rules must be > 0. Got %d", cfg.MaxRules))
+	}
+	if cfg.MaxAccounts <= 0 {
+		errs = append(errs, fmt.Errorf("price_floors.suggestions.max_accounts must be > 0. Got %d", cfg.MaxAccounts))
+	}
+	return errs
 }
 
 const MIN_COOKIE_SIZE_BYTES = 500
@@ -160,6 +238,7 @@ func (cfg *Configuration) validate(v *viper.Viper) []error {
 	errs = cfg.Accounts.validate(errs)
 	errs = cfg.CategoryMapping.validate(errs)
 	errs = cfg.StoredVideo.validate(errs)
+	errs = cfg.StoredDataAPI.validate(cfg, errs)
 	errs = cfg.Metrics.validate(errs)
 	if cfg.MaxRequestSize < 0 {
 		errs = append(errs, fmt.Errorf("cfg.max_request_size must be >= 0. Got %d", cfg.MaxRequestSize))
@@ -168,6 +247,9 @@ func (cfg *Configuration) validate(v *viper.Viper) []error {
 	errs = cfg.CurrencyConverter.validate(errs)
 	errs = cfg.Debug.validate(errs)
 	errs = cfg.ExtCacheURL.validate(errs)
+	errs = cfg.CacheURL.Embedded.validate(errs)
+	errs = cfg.CacheURL.WriteBatching.validate(errs)
+	errs = cfg.CacheURL.WriteRetries.validate(errs)
 	errs = cfg.AccountDefaults.PriceFloors.validate(errs)
 	if cfg.AccountDefaults.Disabled {
 		logger.Warnf(`With account_defaults.disabled=true, host-defined accounts must exist and have "disabled":false. All other requests will be rejected.`)
@@ -181,6 +263,12 @@ func (cfg *Configuration) validate(v *viper.Viper) []error {
 	errs = cfg.BidderInfos.validate(errs)
 	errs = cfg.AccountDefaults.Privacy.IPv6Config.Validate(errs)
 	errs = cfg.AccountDefaults.Privacy.IPv4Config.Validate(errs)
+	errs = cfg.AccountDefaults.Privacy.USPrivacy.Validate(errs)
+	errs = cfg.AccountDefaults.PriceClearing.Validate(errs)
+	errs = cfg.AccountDefaults.StoredRequestVersions.Validate(errs)
+	errs = cfg.AccountDefaults.BidderTransforms.Validate(errs)
+	errs = cfg.PriceFloors.Suggestions.validate(errs)
+	errs = cfg.SChainValidation.validate(errs)
 
 	return errs
 }
@@ -274,6 +362,15 @@ type GDPR struct {
 	// to DefaultValue
 	EEACountries    []string `mapstructure:"eea_countries"`
 	EEACountriesMap map[string]struct{}
+	VendorListCache GDPRVendorListCache `mapstructure:"vendorlist_cache"`
+}
+
+// GDPRVendorListCache configures the on disk cache of the global vendor lists. Vendor lists of the snapshot are
+// copied to the cache directory, both are read on startup before any vendor list is fetched and fetched vendor
+// lists are persisted to the cache directory.
+type GDPRVendorListCache struct {
+	Dir         string `mapstructure:"dir"`
+	SnapshotDir string `mapstructure:"snapshot_dir"`
 }
 
 func (cfg *GDPR) validate(v *viper.Viper, errs []error) []error {
@@ -481,8 +578,32 @@ type CurrencyConverter struct {
 	FetchTimeoutMilliseconds int    `mapstructure:"fetch_timeout_ms"`
 	FetchIntervalSeconds     int    `mapstructure:"fetch_interval_seconds"`
 	StaleRatesSeconds        int    `mapstructure:"stale_rates_seconds"`
+	// Sources are tried in order until the rates are fetched from one of them. The fetch URL is the only
+	// source when they are not set.
+	Sources []CurrencyRateSource `mapstructure:"sources"`
+	// MaxRateDeviationPercent rejects the fetched rates deviating by more than this percentage from their
+	// previous value, the previous value is kept. The check is disabled when it is 0.
+	MaxRateDeviationPercent float64 `mapstructure:"max_rate_deviation_percent"`
+	// RateDeviationConfirmations is the number of consecutive fetches returning a deviating rate after which the
+	// rate is accepted. A deviating rate is also accepted when another source agrees on it. When it is 0, deviating
+	// rates are only accepted when another source agrees on them.
+	RateDeviationConfirmations int `mapstructure:"rate_deviation_confirmations"`
+}
+
+// CurrencyRateSource is a source of currency rates: JSON in the format of fetch_url or ECB XML served at the
+// URL, or a JSON file at the path
+type CurrencyRateSource struct {
+	Type string `mapstructure:"type"`
+	URL  string `mapstructure:"url"`
+	Path string `mapstructure:"path"`
 }
 
+const (
+	CurrencyRateSourceJSON = "json"
+	CurrencyRateSourceECB  = "ecb_xml"
+	CurrencyRateSourceFile = "file"
+)
+
 func (cfg *CurrencyConverter) validate(errs []error) []error {
 	if cfg.FetchIntervalSeconds < 0 {
 		errs = append(errs, fmt.Errorf("currency_converter.fetch_interval_seconds must be in the range [0, %d]. Got %d", 0xffff, cfg.FetchIntervalSeconds))
@@ -490,9 +611,90 @@ func (cfg *CurrencyConverter) validate(errs []error) []error {
 	if cfg.FetchTimeoutMilliseconds < 0 {
 		errs = append(errs, fmt.Errorf("currency_converter.fetch_timeout_ms must be 0 or greater. Got %d", cfg.FetchTimeoutMilliseconds))
 	}
+	for i, source := range cfg.Sources {
+		switch source.Type {
+		case CurrencyRateSourceJSON, CurrencyRateSourceECB:
+			if source.URL == "" {
+				errs = append(errs, fmt.Errorf("currency_converter.sources[%d].url must be set for the %s type", i, source.Type))
+			}
+		case CurrencyRateSourceFile:
+			if source.Path == "" {
+				errs = append(errs, fmt.Errorf("currency_converter.sources[%d].path must be set for the %s type", i, source.Type))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("currency_converter.sources[%d].type must be one of %s, %s, %s. Got %s", i, CurrencyRateSourceJSON, CurrencyRateSourceECB, CurrencyRateSourceFile, source.Type))
+		}
+	}
+	if cfg.MaxRateDeviationPercent < 0 {
+		errs = append(errs, fmt.Errorf("currency_converter.max_rate_deviation_percent must be 0 or greater. Got %v", cfg.MaxRateDeviationPercent))
+	}
+	if cfg.RateDeviationConfirmations < 0 {
+		errs = append(errs, fmt.Errorf("currency_converter.rate_deviation_confirmations must be 0 or greater. Got %d", cfg.RateDeviationConfirmations))
+	}
 	return errs
 }
 
+// SChainValidation configures the validation of the inbound supply chains, request.source.schain and
+// request.ext.prebid.schains, before they are forwarded to the bidders
+type SChainValidation struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Enforcement is repair to remove the invalid nodes of a chain and mark it incomplete, or reject to drop the chain
+	Enforcement string `mapstructure:"enforcement"`
+	// MaxDepth is the maximum number of nodes of a chain, the host node excluded
+	MaxDepth int `mapstructure:"max_depth"`
+	// SellersJSON are the sellers.json files of the advertising systems, the nodes of their chains with an unknown
+	// or mismatched seller ID are flagged
+	SellersJSON []SellersJSONFile `mapstructure:"sellers_json"`
+}
+
+// SellersJSONFile is the sellers.json file of an advertising system, identified by its domain as in the schain asi
+type SellersJSONFile struct {
+	ASI  string `mapstructure:"asi"`
+	Path string `mapstructure:"path"`
+}
+
+const (
+	SChainEnforcementRepair = "repair"
+	SChainEnforcementReject = "reject"
+)
+
+func (cfg *SChainValidation) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.Enforcement != SChainEnforcementRepair && cfg.Enforcement != SChainEnforcementReject {
+		errs = append(errs, fmt.Errorf("schain_validation.enforcement must be one of %s, %s. Got %s", SChainEnforcementRepair, SChainEnforcementReject, cfg.Enforcement))
+	}
+	if cfg.MaxDepth <= 0 {
+		errs = append(errs, fmt.Errorf("schain_validation.max_depth must be greater than 0. Got %d", cfg.MaxDepth))
+	}
+	for i, file := range cfg.SellersJSON {
+		if file.ASI == "" || file.Path == "" {
+			errs = append(errs, fmt.Errorf("schain_validation.sellers_json[%d] must set the asi and the path", i))
+			continue
+		}
+		if err := file.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("schain_validation.sellers_json[%d] %v", i, err))
+		}
+	}
+	return errs
+}
+
+// validate checks that the sellers.json file can be read and parsed, so that a server doesn't start without it
+func (file SellersJSONFile) validate() error {
+	data, err := os.ReadFile(file.Path)
+	if err != nil {
+		return fmt.Errorf("could not be read: %v", err)
+	}
+	var parsed struct {
+		Sellers []json.RawMessage `json:"sellers"`
+	}
+	if err := jsonutil.UnmarshalValid(data, &parsed); err != nil {
+		return fmt.Errorf("could not be parsed: %v", err)
+	}
+	return nil
+}
+
 type AgmaAnalytics struct {
 	Enabled  bool                      `mapstructure:"enabled"`
 	Endpoint AgmaAnalyticsHttpEndpoint `mapstructure:"endpoint"`
@@ -670,6 +872,104 @@ type Cache struct {
 	ExpectedTimeMillis int `mapstructure:"expected_millis"`
 
 	DefaultTTLs DefaultTTLs `mapstructure:"default_ttl_seconds"`
+
+	// Embedded configures the cache served by Prebid Server itself, used instead of the Prebid Cache service
+	Embedded EmbeddedCache `mapstructure:"embedded"`
+
+	// WriteBatching coalesces the writes of concurrent auctions into shared requests to Prebid Cache
+	WriteBatching CacheWriteBatching `mapstructure:"write_batching"`
+	// WriteRetries retries the failed requests to Prebid Cache within the auction timeout
+	WriteRetries CacheWriteRetries `mapstructure:"write_retries"`
+}
+
+// CacheWriteBatching configures the coalescing of the Prebid Cache writes. A batch is sent when its window
+// has elapsed since its first write, or as soon as it holds max_values values.
+type CacheWriteBatching struct {
+	Enabled   bool `mapstructure:"enabled"`
+	WindowMS  int  `mapstructure:"window_ms"`
+	MaxValues int  `mapstructure:"max_values"`
+}
+
+func (cfg *CacheWriteBatching) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.WindowMS <= 0 {
+		errs = append(errs, fmt.Errorf("cache.write_batching.window_ms must be > 0. Got %d", cfg.WindowMS))
+	}
+	if cfg.MaxValues <= 0 {
+		errs = append(errs, fmt.Errorf("cache.write_batching.max_values must be > 0. Got %d", cfg.MaxValues))
+	}
+	return errs
+}
+
+// CacheWriteRetries configures the retries of the requests to Prebid Cache which failed to connect or
+// returned a server error. The delay before the nth retry is a random duration between half and all of
+// backoff_ms * 2^(n-1), capped at max_backoff_ms. A retry is skipped when the auction can't wait for it.
+type CacheWriteRetries struct {
+	MaxRetries   int `mapstructure:"max_retries"`
+	BackoffMS    int `mapstructure:"backoff_ms"`
+	MaxBackoffMS int `mapstructure:"max_backoff_ms"`
+}
+
+func (cfg *CacheWriteRetries) validate(errs []error) []error {
+	if cfg.MaxRetries < 0 {
+		errs = append(errs, fmt.Errorf("cache.write_retries.max_retries must be >= 0. Got %d", cfg.MaxRetries))
+	}
+	if cfg.MaxRetries > 0 && cfg.BackoffMS <= 0 {
+		errs = append(errs, fmt.Errorf("cache.write_retries.backoff_ms must be > 0. Got %d", cfg.BackoffMS))
+	}
+	if cfg.MaxBackoffMS < cfg.BackoffMS {
+		errs = append(errs, fmt.Errorf("cache.write_retries.max_backoff_ms must be >= backoff_ms. Got %d", cfg.MaxBackoffMS))
+	}
+	return errs
+}
+
+// EmbeddedCache configures the bid cache stored by Prebid Server and served on its GET /cache route.
+type EmbeddedCache struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Storage is memory, for a cache local to the instance, or redis, for a cache shared by the instances
+	Storage string `mapstructure:"storage"`
+	// DefaultTTLSeconds applies to the values cached without ttlseconds, MaxTTLSeconds caps all the values
+	DefaultTTLSeconds int `mapstructure:"default_ttl_seconds"`
+	MaxTTLSeconds     int `mapstructure:"max_ttl_seconds"`
+	// MaxEntries limits the number of values of the memory storage, the values expiring first are evicted while it is full
+	MaxEntries int `mapstructure:"max_entries"`
+	// Redis configures the redis storage, the invalidation settings don't apply
+	Redis RedisConfig `mapstructure:"redis"`
+}
+
+const (
+	EmbeddedCacheStorageMemory = "memory"
+	EmbeddedCacheStorageRedis  = "redis"
+)
+
+func (cfg *EmbeddedCache) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	switch cfg.Storage {
+	case EmbeddedCacheStorageMemory:
+		if cfg.MaxEntries <= 0 {
+			errs = append(errs, fmt.Errorf("cache.embedded.max_entries must be > 0. Got %d", cfg.MaxEntries))
+		}
+	case EmbeddedCacheStorageRedis:
+		if cfg.Redis.Address == "" {
+			errs = append(errs, errors.New("cache.embedded.redis.address must be set for the redis storage"))
+		}
+		if cfg.Redis.Timeout <= 0 {
+			errs = append(errs, fmt.Errorf("cache.embedded.redis.timeout_ms must be > 0. Got %d", cfg.Redis.Timeout))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("cache.embedded.storage must be %s or %s. Got %s", EmbeddedCacheStorageMemory, EmbeddedCacheStorageRedis, cfg.Storage))
+	}
+	if cfg.DefaultTTLSeconds <= 0 {
+		errs = append(errs, fmt.Errorf("cache.embedded.default_ttl_seconds must be > 0. Got %d", cfg.DefaultTTLSeconds))
+	}
+	if cfg.MaxTTLSeconds < cfg.DefaultTTLSeconds {
+		errs = append(errs, fmt.Errorf("cache.embedded.max_ttl_seconds must be >= default_ttl_seconds. Got %d", cfg.MaxTTLSeconds))
+	}
+	return errs
 }
 
 // Default TTLs to use to cache bids for different types of imps.
@@ -959,6 +1259,18 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("cache.default_ttl_seconds.video", 0)
 	v.SetDefault("cache.default_ttl_seconds.native", 0)
 	v.SetDefault("cache.default_ttl_seconds.audio", 0)
+	v.SetDefault("cache.embedded.enabled", false)
+	v.SetDefault("cache.embedded.storage", "memory")
+	v.SetDefault("cache.embedded.default_ttl_seconds", 300)
+	v.SetDefault("cache.embedded.max_ttl_seconds", 3600)
+	v.SetDefault("cache.embedded.max_entries", 100000)
+	setStoredRedisDefaults(v, "cache.embedded")
+	v.SetDefault("cache.write_batching.enabled", false)
+	v.SetDefault("cache.write_batching.window_ms", 5)
+	v.SetDefault("cache.write_batching.max_values", 100)
+	v.SetDefault("cache.write_retries.max_retries", 0)
+	v.SetDefault("cache.write_retries.backoff_ms", 10)
+	v.SetDefault("cache.write_retries.max_backoff_ms", 50)
 	v.SetDefault("external_cache.scheme", "")
 	v.SetDefault("external_cache.host", "")
 	v.SetDefault("external_cache.path", "")
@@ -973,6 +1285,9 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("host_cookie.ttl_days", 90)
 	v.SetDefault("host_cookie.max_cookie_size_bytes", 0)
 	v.SetDefault("host_schain_node", nil)
+	v.SetDefault("schain_validation.enabled", false)
+	v.SetDefault("schain_validation.enforcement", SChainEnforcementRepair)
+	v.SetDefault("schain_validation.max_depth", 10)
 	v.SetDefault("validations.banner_creative_max_size", ValidationSkip)
 	v.SetDefault("validations.secure_markup", ValidationSkip)
 	v.SetDefault("validations.max_creative_size.height", 0)
@@ -1021,6 +1336,8 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("category_mapping.filesystem.directorypath", "./static/category-mapping")
 	v.SetDefault("category_mapping.http.endpoint", "")
 	v.SetDefault("stored_requests_timeout_ms", 50)
+	v.SetDefault("stored_data_api.enabled", false)
+	v.SetDefault("stored_data_api.timeout_ms", 1000)
 	v.SetDefault("stored_requests.database.connection.driver", "")
 	v.SetDefault("stored_requests.database.connection.dbname", "")
 	v.SetDefault("stored_requests.database.connection.host", "")
@@ -1144,6 +1461,10 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("accounts.http_events.refresh_rate_seconds", 0)
 	v.SetDefault("accounts.http_events.timeout_ms", 0)
 
+	for _, section := range []string{"stored_requests", "stored_video_req", "stored_responses", "accounts", "category_mapping"} {
+		setStoredRedisDefaults(v, section)
+	}
+
 	v.BindEnv("user_sync.external_url")
 	v.BindEnv("user_sync.coop_sync.default")
 
@@ -1197,6 +1518,8 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("gdpr.tcf2.purpose9.vendor_exceptions", []string{})
 	v.SetDefault("gdpr.tcf2.purpose10.vendor_exceptions", []string{})
 	v.SetDefault("gdpr.amp_exception", false)
+	v.SetDefault("gdpr.vendorlist_cache.dir", "")
+	v.SetDefault("gdpr.vendorlist_cache.snapshot_dir", "./static/vendorlist-snapshot")
 	v.SetDefault("gdpr.eea_countries", []string{"ALA", "AUT", "BEL", "BGR", "HRV", "CYP", "CZE", "DNK", "EST",
 		"FIN", "FRA", "GUF", "DEU", "GIB", "GRC", "GLP", "GGY", "HUN", "ISL", "IRL", "IMN", "ITA", "JEY", "LVA",
 		"LIE", "LTU", "LUX", "MLT", "MTQ", "MYT", "NLD", "NOR", "POL", "PRT", "REU", "ROU", "BLM", "MAF", "SPM",
@@ -1207,6 +1530,8 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("currency_converter.fetch_timeout_ms", 60000)      // 60 seconds
 	v.SetDefault("currency_converter.fetch_interval_seconds", 1800) // fetch currency rates every 30 minutes
 	v.SetDefault("currency_converter.stale_rates_seconds", 0)
+	v.SetDefault("currency_converter.max_rate_deviation_percent", 0)
+	v.SetDefault("currency_converter.rate_deviation_confirmations", 3)
 	v.SetDefault("default_request.type", "")
 	v.SetDefault("default_request.file.name", "")
 	v.SetDefault("default_request.alias_info", false)
@@ -1222,6 +1547,7 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("account_defaults.price_floors.use_dynamic_data", false)
 	v.SetDefault("account_defaults.price_floors.max_rules", 100)
 	v.SetDefault("account_defaults.price_floors.max_schema_dims", 3)
+	v.SetDefault("account_defaults.price_floors.timezone", "UTC")
 	v.SetDefault("account_defaults.price_floors.fetch.enabled", false)
 	v.SetDefault("account_defaults.price_floors.fetch.url", "")
 	v.SetDefault("account_defaults.events_enabled", false)
@@ -1234,12 +1560,23 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("account_defaults.privacy.privacysandbox.topicsdomain", "")
 	v.SetDefault("account_defaults.privacy.privacysandbox.cookiedeprecation.enabled", false)
 	v.SetDefault("account_defaults.privacy.privacysandbox.cookiedeprecation.ttl_sec", 604800)
+	v.SetDefault("account_defaults.auction_macros.enabled", false)
+	v.SetDefault("account_defaults.auction_macros.price_encoding", "clear")
+	v.SetDefault("account_defaults.price_clearing.mode", "first_price")
+	v.SetDefault("account_defaults.price_clearing.increment", 0.01)
+	v.SetDefault("account_defaults.price_clearing.soft_floor_multiplier", 0)
+	v.SetDefault("account_defaults.stored_request_versions.current", "")
+	v.SetDefault("account_defaults.stored_request_versions.candidate", "")
+	v.SetDefault("account_defaults.stored_request_versions.candidate_percent", 0)
+	v.SetDefault("account_defaults.stored_request_versions.rollback", false)
+	v.SetDefault("account_defaults.return_creative_on_cache_failure", false)
 
 	v.SetDefault("account_defaults.events_enabled", false)
 	v.BindEnv("account_defaults.privacy.dsa.default")
 	v.BindEnv("account_defaults.privacy.dsa.gdpr_only")
 	v.SetDefault("account_defaults.privacy.ipv6.anon_keep_bits", 56)
 	v.SetDefault("account_defaults.privacy.ipv4.anon_keep_bits", 24)
+	v.SetDefault("account_defaults.privacy.usprivacy.enabled", false)
 
 	//Defaults for Price floor fetcher
 	v.SetDefault("price_floors.fetcher.worker", 20)
@@ -1331,6 +1668,18 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("gdpr.tcf2.special_feature1.enforce", true)
 	v.SetDefault("gdpr.tcf2.special_feature1.vendor_exceptions", []openrtb_ext.BidderName{})
 	v.SetDefault("price_floors.enabled", false)
+	v.SetDefault("price_floors.suggestions.enabled", false)
+	v.SetDefault("price_floors.suggestions.period_sec", 3600)
+	v.SetDefault("price_floors.suggestions.strategy", "percentile")
+	v.SetDefault("price_floors.suggestions.percentile", 25)
+	v.SetDefault("price_floors.suggestions.target_fill_rate", 0.8)
+	v.SetDefault("price_floors.suggestions.fields", []string{"mediaType", "size", "domain"})
+	v.SetDefault("price_floors.suggestions.currency", "USD")
+	v.SetDefault("price_floors.suggestions.min_samples", 100)
+	v.SetDefault("price_floors.suggestions.max_samples", 1000)
+	v.SetDefault("price_floors.suggestions.max_rules", 1000)
+	v.SetDefault("price_floors.suggestions.max_accounts", 10000)
+	v.SetDefault("price_floors.suggestions.output_dir", "")
 
 	// Defaults for account_defaults.events.default_url
 	v.SetDefault("account_defaults.events.default_url", "https://PBS_HOST/event?t=##PBS-EVENTTYPE##&vtype=##PBS-VASTEVENT##&b=##PBS-BIDID##&f=i&a=##PBS-ACCOUNTID##&ts=##PBS-TIMESTAMP##&bidder=##PBS-BIDDER##&int=##PBS-INTEGRATION##&mt=##PBS-MEDIATYPE##&ch=##PBS-CHANNEL##&aid=##PBS-AUCTIONID##&l=##PBS-LINEID##")
@@ -1365,6 +1714,17 @@ func isConfigInfoPresent(v *viper.Viper, prefix string, fields []string) bool {
 	return false
 }
 
+func setStoredRedisDefaults(v *viper.Viper, section string) {
+	v.SetDefault(section+".redis.address", "")
+	v.SetDefault(section+".redis.password", "")
+	v.SetDefault(section+".redis.db", 0)
+	v.SetDefault(section+".redis.key_prefix", "")
+	v.SetDefault(section+".redis.timeout_ms", 50)
+	v.SetDefault(section+".redis.pool_size", 10)
+	v.SetDefault(section+".redis.invalidation_channel", "")
+	v.SetDefault(section+".redis.retry_delay_ms", 1000)
+}
+
 func setBidderDefaults(v *viper.Viper, bidder string) {
 	adapterCfgPrefix := "adapters." + bidder
 	v.BindEnv(adapterCfgPrefix + ".disabled")
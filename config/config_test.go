@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -151,6 +152,9 @@ func TestDefaults(t *testing.T) {
 	cmpInts(t, "host_cookie.ttl_days", 90, int(cfg.HostCookie.TTL))
 	cmpInts(t, "host_cookie.max_cookie_size_bytes", 0, cfg.HostCookie.MaxCookieSizeBytes)
 	cmpInts(t, "currency_converter.fetch_interval_seconds", 1800, cfg.CurrencyConverter.FetchIntervalSeconds)
+	assert.Empty(t, cfg.CurrencyConverter.Sources)
+	assert.Equal(t, float64(0), cfg.CurrencyConverter.MaxRateDeviationPercent)
+	cmpInts(t, "currency_converter.rate_deviation_confirmations", 3, cfg.CurrencyConverter.RateDeviationConfirmations)
 	cmpStrings(t, "currency_converter.fetch_url", "https://cdn.jsdelivr.net/gh/prebid/currency-file@1/latest.json", cfg.CurrencyConverter.FetchURL)
 	cmpBools(t, "account_required", false, cfg.AccountRequired)
 	cmpInts(t, "metrics.influxdb.collection_rate_seconds", 20, cfg.Metrics.Influxdb.MetricSendInterval)
@@ -163,6 +167,8 @@ func TestDefaults(t *testing.T) {
 	cmpBools(t, "adapter_gdpr_request_blocked", false, cfg.Metrics.Disabled.AdapterGDPRRequestBlocked)
 	cmpStrings(t, "certificates_file", "", cfg.PemCertsFile)
 	cmpInts(t, "stored_requests_timeout_ms", 50, cfg.StoredRequestsTimeout)
+	cmpBools(t, "stored_data_api.enabled", false, cfg.StoredDataAPI.Enabled)
+	cmpInts(t, "stored_data_api.timeout_ms", 1000, cfg.StoredDataAPI.Timeout)
 	cmpBools(t, "stored_requests.filesystem.enabled", false, cfg.StoredRequests.Files.Enabled)
 	cmpStrings(t, "stored_requests.filesystem.directorypath", "./stored_requests/data/by_id", cfg.StoredRequests.Files.Path)
 	cmpStrings(t, "stored_requests.http.endpoint", "", cfg.StoredRequests.HTTP.Endpoint)
@@ -178,6 +184,25 @@ func TestDefaults(t *testing.T) {
 	cmpBools(t, "accounts.cache_events.enabled", false, cfg.Accounts.CacheEvents.Enabled)
 	cmpStrings(t, "accounts.cache_events.endpoint", "", cfg.Accounts.CacheEvents.Endpoint)
 	cmpStrings(t, "accounts.http_events.endpoint", "", cfg.Accounts.HTTPEvents.Endpoint)
+	cmpStrings(t, "stored_requests.redis.address", "", cfg.StoredRequests.Redis.Address)
+	cmpStrings(t, "stored_requests.redis.key_prefix", "", cfg.StoredRequests.Redis.KeyPrefix)
+	cmpInts(t, "stored_requests.redis.timeout_ms", 50, cfg.StoredRequests.Redis.Timeout)
+	cmpInts(t, "stored_requests.redis.pool_size", 10, cfg.StoredRequests.Redis.PoolSize)
+	cmpStrings(t, "stored_requests.redis.invalidation_channel", "", cfg.StoredRequests.Redis.InvalidationChannel)
+	cmpInts(t, "stored_requests.redis.retry_delay_ms", 1000, cfg.StoredRequests.Redis.RetryDelay)
+	cmpInts(t, "accounts.redis.timeout_ms", 50, cfg.Accounts.Redis.Timeout)
+	cmpBools(t, "cache.embedded.enabled", false, cfg.CacheURL.Embedded.Enabled)
+	cmpStrings(t, "cache.embedded.storage", "memory", cfg.CacheURL.Embedded.Storage)
+	cmpInts(t, "cache.embedded.default_ttl_seconds", 300, cfg.CacheURL.Embedded.DefaultTTLSeconds)
+	cmpInts(t, "cache.embedded.max_ttl_seconds", 3600, cfg.CacheURL.Embedded.MaxTTLSeconds)
+	cmpInts(t, "cache.embedded.max_entries", 100000, cfg.CacheURL.Embedded.MaxEntries)
+	cmpInts(t, "cache.embedded.redis.timeout_ms", 50, cfg.CacheURL.Embedded.Redis.Timeout)
+	cmpBools(t, "cache.write_batching.enabled", false, cfg.CacheURL.WriteBatching.Enabled)
+	cmpInts(t, "cache.write_batching.window_ms", 5, cfg.CacheURL.WriteBatching.WindowMS)
+	cmpInts(t, "cache.write_batching.max_values", 100, cfg.CacheURL.WriteBatching.MaxValues)
+	cmpInts(t, "cache.write_retries.max_retries", 0, cfg.CacheURL.WriteRetries.MaxRetries)
+	cmpInts(t, "cache.write_retries.backoff_ms", 10, cfg.CacheURL.WriteRetries.BackoffMS)
+	cmpInts(t, "cache.write_retries.max_backoff_ms", 50, cfg.CacheURL.WriteRetries.MaxBackoffMS)
 	cmpInts(t, "accounts.http_events.refresh_rate_seconds", 0, int(cfg.Accounts.HTTPEvents.RefreshRate))
 	cmpInts(t, "accounts.http_events.timeout_ms", 0, int(cfg.Accounts.HTTPEvents.Timeout))
 	cmpBools(t, "auto_gen_source_tid", true, cfg.AutoGenSourceTID)
@@ -190,6 +215,9 @@ func TestDefaults(t *testing.T) {
 	cmpStrings(t, "experiment.adscert.remote.url", "", cfg.Experiment.AdCerts.Remote.Url)
 	cmpInts(t, "experiment.adscert.remote.signing_timeout_ms", 5, cfg.Experiment.AdCerts.Remote.SigningTimeoutMs)
 	cmpNils(t, "host_schain_node", cfg.HostSChainNode)
+	cmpBools(t, "schain_validation.enabled", false, cfg.SChainValidation.Enabled)
+	cmpStrings(t, "schain_validation.enforcement", "repair", cfg.SChainValidation.Enforcement)
+	cmpInts(t, "schain_validation.max_depth", 10, cfg.SChainValidation.MaxDepth)
 	cmpStrings(t, "datacenter", "", cfg.DataCenter)
 
 	//Assert the price floor default values
@@ -202,6 +230,18 @@ func TestDefaults(t *testing.T) {
 	cmpInts(t, "price_floors.fetcher.http_client.max_idle_connections_per_host", 2, cfg.PriceFloors.Fetcher.HttpClient.MaxIdleConnsPerHost)
 	cmpInts(t, "price_floors.fetcher.http_client.idle_connection_timeout_seconds", 60, cfg.PriceFloors.Fetcher.HttpClient.IdleConnTimeout)
 	cmpInts(t, "price_floors.fetcher.max_retries", 10, cfg.PriceFloors.Fetcher.MaxRetries)
+	cmpBools(t, "price_floors.suggestions.enabled", false, cfg.PriceFloors.Suggestions.Enabled)
+	cmpInts(t, "price_floors.suggestions.period_sec", 3600, cfg.PriceFloors.Suggestions.PeriodSec)
+	cmpStrings(t, "price_floors.suggestions.strategy", "percentile", string(cfg.PriceFloors.Suggestions.Strategy))
+	assert.Equal(t, 25.0, cfg.PriceFloors.Suggestions.Percentile, "price_floors.suggestions.percentile")
+	assert.Equal(t, 0.8, cfg.PriceFloors.Suggestions.TargetFillRate, "price_floors.suggestions.target_fill_rate")
+	assert.Equal(t, []string{"mediaType", "size", "domain"}, cfg.PriceFloors.Suggestions.Fields, "price_floors.suggestions.fields")
+	cmpStrings(t, "price_floors.suggestions.currency", "USD", cfg.PriceFloors.Suggestions.Currency)
+	cmpInts(t, "price_floors.suggestions.min_samples", 100, cfg.PriceFloors.Suggestions.MinSamples)
+	cmpInts(t, "price_floors.suggestions.max_samples", 1000, cfg.PriceFloors.Suggestions.MaxSamples)
+	cmpInts(t, "price_floors.suggestions.max_rules", 1000, cfg.PriceFloors.Suggestions.MaxRules)
+	cmpInts(t, "price_floors.suggestions.max_accounts", 10000, cfg.PriceFloors.Suggestions.MaxAccounts)
+	cmpStrings(t, "price_floors.suggestions.output_dir", "", cfg.PriceFloors.Suggestions.OutputDir)
 
 	// Assert compression related defaults
 	cmpBools(t, "compression.request.enable_gzip", false, cfg.Compression.Request.GZIP)
@@ -214,6 +254,7 @@ func TestDefaults(t *testing.T) {
 	cmpBools(t, "account_defaults.price_floors.use_dynamic_data", false, cfg.AccountDefaults.PriceFloors.UseDynamicData)
 	cmpInts(t, "account_defaults.price_floors.max_rules", 100, cfg.AccountDefaults.PriceFloors.MaxRule)
 	cmpInts(t, "account_defaults.price_floors.max_schema_dims", 3, cfg.AccountDefaults.PriceFloors.MaxSchemaDims)
+	cmpStrings(t, "account_defaults.price_floors.timezone", "UTC", cfg.AccountDefaults.PriceFloors.Timezone)
 	cmpBools(t, "account_defaults.price_floors.fetch.enabled", false, cfg.AccountDefaults.PriceFloors.Fetcher.Enabled)
 	cmpStrings(t, "account_defaults.price_floors.fetch.url", "", cfg.AccountDefaults.PriceFloors.Fetcher.URL)
 	cmpInts(t, "account_defaults.price_floors.fetch.timeout_ms", 3000, cfg.AccountDefaults.PriceFloors.Fetcher.Timeout)
@@ -225,6 +266,14 @@ func TestDefaults(t *testing.T) {
 	cmpStrings(t, "account_defaults.privacy.topicsdomain", "", cfg.AccountDefaults.Privacy.PrivacySandbox.TopicsDomain)
 	cmpBools(t, "account_defaults.privacy.privacysandbox.cookiedeprecation.enabled", false, cfg.AccountDefaults.Privacy.PrivacySandbox.CookieDeprecation.Enabled)
 	cmpInts(t, "account_defaults.privacy.privacysandbox.cookiedeprecation.ttl_sec", 604800, cfg.AccountDefaults.Privacy.PrivacySandbox.CookieDeprecation.TTLSec)
+	assert.Nil(t, cfg.AccountDefaults.Privacy.PrivacySandbox.PAAPI.Enabled, "account_defaults.privacy.privacysandbox.paapi.enabled")
+	cmpBools(t, "account_defaults.auction_macros.enabled", false, cfg.AccountDefaults.AuctionMacros.Enabled)
+	cmpStrings(t, "account_defaults.auction_macros.price_encoding", "clear", cfg.AccountDefaults.AuctionMacros.PriceEncoding)
+	cmpStrings(t, "account_defaults.price_clearing.mode", "first_price", string(cfg.AccountDefaults.PriceClearing.Mode))
+	cmpStrings(t, "account_defaults.stored_request_versions.current", "", cfg.AccountDefaults.StoredRequestVersions.Current)
+	cmpInts(t, "account_defaults.stored_request_versions.candidate_percent", 0, cfg.AccountDefaults.StoredRequestVersions.CandidatePercent)
+	cmpBools(t, "account_defaults.stored_request_versions.rollback", false, cfg.AccountDefaults.StoredRequestVersions.Rollback)
+	cmpBools(t, "account_defaults.return_creative_on_cache_failure", false, cfg.AccountDefaults.ReturnCreativeOnCacheFailure)
 
 	cmpBools(t, "account_defaults.events.enabled", false, cfg.AccountDefaults.Events.Enabled)
 	cmpInts(t, "price_floor_fetcher.worker", 20, cfg.PriceFloorFetcher.Worker)
@@ -246,6 +295,7 @@ func TestDefaults(t *testing.T) {
 
 	cmpInts(t, "account_defaults.privacy.ipv6.anon_keep_bits", 56, cfg.AccountDefaults.Privacy.IPv6Config.AnonKeepBits)
 	cmpInts(t, "account_defaults.privacy.ipv4.anon_keep_bits", 24, cfg.AccountDefaults.Privacy.IPv4Config.AnonKeepBits)
+	cmpBools(t, "account_defaults.privacy.usprivacy.enabled", false, cfg.AccountDefaults.Privacy.USPrivacy.Enabled)
 
 	//Assert purpose VendorExceptionMap hash tables were built correctly
 	cmpBools(t, "analytics.agma.enabled", false, cfg.Analytics.Agma.Enabled)
@@ -361,6 +411,9 @@ func TestDefaults(t *testing.T) {
 		10: &expectedTCF2.Purpose10,
 	}
 	assert.Equal(t, expectedTCF2, cfg.GDPR.TCF2, "gdpr.tcf2")
+
+	cmpStrings(t, "gdpr.vendorlist_cache.dir", "", cfg.GDPR.VendorListCache.Dir)
+	cmpStrings(t, "gdpr.vendorlist_cache.snapshot_dir", "./static/vendorlist-snapshot", cfg.GDPR.VendorListCache.SnapshotDir)
 }
 
 // When adding a new field, make sure the indentations are spaces not tabs otherwise read config may fail to parse the new field value.
@@ -471,6 +524,15 @@ http_client_cache:
 currency_converter:
   fetch_url: https://currency.prebid.org
   fetch_interval_seconds: 1800
+  sources:
+    - type: json
+      url: https://currency.prebid.org
+    - type: ecb_xml
+      url: https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml
+    - type: file
+      path: /etc/pbs/currency.json
+  max_rate_deviation_percent: 10
+  rate_deviation_confirmations: 5
 recaptcha_secret: asdfasdfasdfasdf
 metrics:
   influxdb:
@@ -503,6 +565,13 @@ host_schain_node:
     sid: "00001"
     rid: "BidRequest"
     hp: 1
+schain_validation:
+    enabled: true
+    enforcement: "reject"
+    max_depth: 5
+    sellers_json:
+        - asi: "pbshostcompany.com"
+          path: "./test/sellers.json"
 validations:
     banner_creative_max_size: "skip"
     secure_markup: "skip"
@@ -581,6 +650,8 @@ account_defaults:
             cookiedeprecation:
                 enabled: true
                 ttl_sec: 86400
+            paapi:
+                enabled: true
 price_floor_fetcher:
   worker: 10
   capacity: 20
@@ -695,6 +766,10 @@ func TestFullConfig(t *testing.T) {
 	cmpStrings(t, "host_schain_node.sid", "00001", cfg.HostSChainNode.SID)
 	cmpStrings(t, "host_schain_node.rid", "BidRequest", cfg.HostSChainNode.RID)
 	cmpInt8s(t, "host_schain_node.hp", &int8One, cfg.HostSChainNode.HP)
+	cmpBools(t, "schain_validation.enabled", true, cfg.SChainValidation.Enabled)
+	cmpStrings(t, "schain_validation.enforcement", "reject", cfg.SChainValidation.Enforcement)
+	cmpInts(t, "schain_validation.max_depth", 5, cfg.SChainValidation.MaxDepth)
+	assert.Equal(t, []SellersJSONFile{{ASI: "pbshostcompany.com", Path: "./test/sellers.json"}}, cfg.SChainValidation.SellersJSON)
 	cmpStrings(t, "datacenter", "1", cfg.DataCenter)
 	cmpStrings(t, "validations.banner_creative_max_size", "skip", cfg.Validations.BannerCreativeMaxSize)
 	cmpStrings(t, "validations.secure_markup", "skip", cfg.Validations.SecureMarkup)
@@ -760,6 +835,7 @@ func TestFullConfig(t *testing.T) {
 	cmpStrings(t, "account_defaults.privacy.topicsdomain", "test.com", cfg.AccountDefaults.Privacy.PrivacySandbox.TopicsDomain)
 	cmpBools(t, "account_defaults.privacy.cookiedeprecation.enabled", true, cfg.AccountDefaults.Privacy.PrivacySandbox.CookieDeprecation.Enabled)
 	cmpInts(t, "account_defaults.privacy.cookiedeprecation.ttl_sec", 86400, cfg.AccountDefaults.Privacy.PrivacySandbox.CookieDeprecation.TTLSec)
+	assert.Equal(t, ptrutil.ToPtr(true), cfg.AccountDefaults.Privacy.PrivacySandbox.PAAPI.Enabled, "account_defaults.privacy.privacysandbox.paapi.enabled")
 
 	// Assert compression related defaults
 	cmpBools(t, "compression.request.enable_gzip", true, cfg.Compression.Request.GZIP)
@@ -910,6 +986,13 @@ func TestFullConfig(t *testing.T) {
 
 	cmpStrings(t, "currency_converter.fetch_url", "https://currency.prebid.org", cfg.CurrencyConverter.FetchURL)
 	cmpInts(t, "currency_converter.fetch_interval_seconds", 1800, cfg.CurrencyConverter.FetchIntervalSeconds)
+	assert.Equal(t, []CurrencyRateSource{
+		{Type: "json", URL: "https://currency.prebid.org"},
+		{Type: "ecb_xml", URL: "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"},
+		{Type: "file", Path: "/etc/pbs/currency.json"},
+	}, cfg.CurrencyConverter.Sources)
+	assert.Equal(t, float64(10), cfg.CurrencyConverter.MaxRateDeviationPercent)
+	cmpInts(t, "currency_converter.rate_deviation_confirmations", 5, cfg.CurrencyConverter.RateDeviationConfirmations)
 	cmpStrings(t, "recaptcha_secret", "asdfasdfasdfasdf", cfg.RecaptchaSecret)
 	cmpStrings(t, "metrics.influxdb.host", "upstream:8232", cfg.Metrics.Influxdb.Host)
 	cmpStrings(t, "metrics.influxdb.database", "metricsdb", cfg.Metrics.Influxdb.Database)
@@ -1351,6 +1434,60 @@ func TestNegativeCurrencyConverterFetchInterval(t *testing.T) {
 	assert.NotNil(t, err, "cfg.currency_converter.fetch_interval_seconds should prevent negative values, but it doesn't")
 }
 
+func TestCurrencyConverterSourcesValidate(t *testing.T) {
+	cfg := CurrencyConverter{
+		Sources: []CurrencyRateSource{
+			{Type: CurrencyRateSourceJSON, URL: "https://currency.prebid.org"},
+			{Type: CurrencyRateSourceECB},
+			{Type: CurrencyRateSourceFile},
+			{Type: "csv", Path: "rates.csv"},
+		},
+		MaxRateDeviationPercent:    -1,
+		RateDeviationConfirmations: -1,
+	}
+
+	errs := cfg.validate(nil)
+	assert.Equal(t, []error{
+		errors.New("currency_converter.sources[1].url must be set for the ecb_xml type"),
+		errors.New("currency_converter.sources[2].path must be set for the file type"),
+		errors.New("currency_converter.sources[3].type must be one of json, ecb_xml, file. Got csv"),
+		errors.New("currency_converter.max_rate_deviation_percent must be 0 or greater. Got -1"),
+		errors.New("currency_converter.rate_deviation_confirmations must be 0 or greater. Got -1"),
+	}, errs)
+}
+
+func TestSChainValidationValidate(t *testing.T) {
+	dir := t.TempDir()
+	invalidPath := filepath.Join(dir, "invalid.json")
+	assert.NoError(t, os.WriteFile(invalidPath, []byte(`{"sellers":`), 0600))
+
+	cfg := SChainValidation{
+		Enabled:     true,
+		Enforcement: "drop",
+		MaxDepth:    0,
+		SellersJSON: []SellersJSONFile{
+			{ASI: "pbshostcompany.com", Path: "./test/sellers.json"},
+			{ASI: "exchange.com"},
+			{ASI: "invalid.com", Path: invalidPath},
+			{ASI: "missing.com", Path: filepath.Join(dir, "missing.json")},
+		},
+	}
+
+	errs := cfg.validate(nil)
+	if assert.Len(t, errs, 5) {
+		assert.Equal(t, []error{
+			errors.New("schain_validation.enforcement must be one of repair, reject. Got drop"),
+			errors.New("schain_validation.max_depth must be greater than 0. Got 0"),
+			errors.New("schain_validation.sellers_json[1] must set the asi and the path"),
+		}, errs[:3])
+		assert.ErrorContains(t, errs[3], "schain_validation.sellers_json[2] could not be parsed")
+		assert.ErrorContains(t, errs[4], "schain_validation.sellers_json[3] could not be read")
+	}
+
+	cfg.Enabled = false
+	assert.Empty(t, cfg.validate(nil))
+}
+
 func TestOverflowedCurrencyConverterFetchInterval(t *testing.T) {
 	v := viper.New()
 	v.Set("gdpr.default_value", "0")
@@ -2029,3 +2166,212 @@ func TestUnpackDSADefault(t *testing.T) {
 		})
 	}
 }
+
+func TestPriceFloorSuggestionsValidate(t *testing.T) {
+	valid := PriceFloorSuggestions{
+		Enabled:     true,
+		PeriodSec:   3600,
+		Strategy:    FloorSuggestionStrategyPercentile,
+		Percentile:  25,
+		Fields:      []string{"mediaType"},
+		MinSamples:  10,
+		MaxSamples:  100,
+		MaxRules:    100,
+		MaxAccounts: 100,
+	}
+
+	tests := []struct {
+		name           string
+		modify         func(cfg *PriceFloorSuggestions)
+		expectedErrors []error
+	}{
+		{
+			name:   "valid_percentile",
+			modify: func(cfg *PriceFloorSuggestions) {},
+		},
+		{
+			name: "valid_target_fill",
+			modify: func(cfg *PriceFloorSuggestions) {
+				cfg.Strategy = FloorSuggestionStrategyTargetFill
+				cfg.TargetFillRate = 0.9
+			},
+		},
+		{
+			name: "disabled_not_validated",
+			modify: func(cfg *PriceFloorSuggestions) {
+				*cfg = PriceFloorSuggestions{}
+			},
+		},
+		{
+			name: "invalid_percentile",
+			modify: func(cfg *PriceFloorSuggestions) {
+				cfg.Percentile = 101
+			},
+			expectedErrors: []error{errors.New("price_floors.suggestions.percentile must be in the range [0, 100]. Got 101")},
+		},
+		{
+			name: "invalid_target_fill_rate",
+			modify: func(cfg *PriceFloorSuggestions) {
+				cfg.Strategy = FloorSuggestionStrategyTargetFill
+				cfg.TargetFillRate = 0
+			},
+			expectedErrors: []error{errors.New("price_floors.suggestions.target_fill_rate must be in the range (0, 1]. Got 0")},
+		},
+		{
+			name: "invalid_strategy",
+			modify: func(cfg *PriceFloorSuggestions) {
+				cfg.Strategy = "invalid"
+			},
+			expectedErrors: []error{errors.New("price_floors.suggestions.strategy must be one of percentile, target_fill. Got invalid")},
+		},
+		{
+			name: "invalid_limits",
+			modify: func(cfg *PriceFloorSuggestions) {
+				cfg.PeriodSec = 0
+				cfg.Fields = nil
+				cfg.MinSamples = 200
+				cfg.MaxRules = 0
+				cfg.MaxAccounts = 0
+			},
+			expectedErrors: []error{
+				errors.New("price_floors.suggestions.period_sec must be > 0. Got 0"),
+				errors.New("price_floors.suggestions.fields must not be empty"),
+				errors.New("price_floors.suggestions.max_samples must be >= min_samples. Got 100"),
+				errors.New("price_floors.suggestions.max_rules must be > 0. Got 0"),
+				errors.New("price_floors.suggestions.max_accounts must be > 0. Got 0"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid
+			tt.modify(&cfg)
+			errs := cfg.validate(nil)
+			assert.Equal(t, tt.expectedErrors, errs)
+		})
+	}
+}
+
+func TestEmbeddedCacheValidate(t *testing.T) {
+	valid := EmbeddedCache{
+		Enabled:           true,
+		Storage:           EmbeddedCacheStorageMemory,
+		DefaultTTLSeconds: 300,
+		MaxTTLSeconds:     3600,
+		MaxEntries:        1000,
+	}
+
+	tests := []struct {
+		name           string
+		modify         func(cfg *EmbeddedCache)
+		expectedErrors []error
+	}{
+		{
+			name:   "valid_memory",
+			modify: func(cfg *EmbeddedCache) {},
+		},
+		{
+			name: "valid_redis",
+			modify: func(cfg *EmbeddedCache) {
+				cfg.Storage = EmbeddedCacheStorageRedis
+				cfg.MaxEntries = 0
+				cfg.Redis = RedisConfig{Address: "localhost:6379", Timeout: 50}
+			},
+		},
+		{
+			name: "disabled_not_validated",
+			modify: func(cfg *EmbeddedCache) {
+				*cfg = EmbeddedCache{}
+			},
+		},
+		{
+			name: "invalid_storage",
+			modify: func(cfg *EmbeddedCache) {
+				cfg.Storage = "disk"
+			},
+			expectedErrors: []error{errors.New("cache.embedded.storage must be memory or redis. Got disk")},
+		},
+		{
+			name: "invalid_memory_max_entries",
+			modify: func(cfg *EmbeddedCache) {
+				cfg.MaxEntries = 0
+			},
+			expectedErrors: []error{errors.New("cache.embedded.max_entries must be > 0. Got 0")},
+		},
+		{
+			name: "invalid_redis",
+			modify: func(cfg *EmbeddedCache) {
+				cfg.Storage = EmbeddedCacheStorageRedis
+			},
+			expectedErrors: []error{
+				errors.New("cache.embedded.redis.address must be set for the redis storage"),
+				errors.New("cache.embedded.redis.timeout_ms must be > 0. Got 0"),
+			},
+		},
+		{
+			name: "invalid_ttls",
+			modify: func(cfg *EmbeddedCache) {
+				cfg.DefaultTTLSeconds = 0
+				cfg.MaxTTLSeconds = -1
+			},
+			expectedErrors: []error{
+				errors.New("cache.embedded.default_ttl_seconds must be > 0. Got 0"),
+				errors.New("cache.embedded.max_ttl_seconds must be >= default_ttl_seconds. Got -1"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid
+			tt.modify(&cfg)
+			errs := cfg.validate(nil)
+			assert.Equal(t, tt.expectedErrors, errs)
+		})
+	}
+}
+
+func TestCacheWriteBatchingAndRetriesValidate(t *testing.T) {
+	tests := []struct {
+		name           string
+		batching       CacheWriteBatching
+		retries        CacheWriteRetries
+		expectedErrors []error
+	}{
+		{
+			name:     "valid",
+			batching: CacheWriteBatching{Enabled: true, WindowMS: 5, MaxValues: 100},
+			retries:  CacheWriteRetries{MaxRetries: 2, BackoffMS: 10, MaxBackoffMS: 50},
+		},
+		{
+			name:     "disabled_not_validated",
+			batching: CacheWriteBatching{},
+			retries:  CacheWriteRetries{},
+		},
+		{
+			name:     "invalid",
+			batching: CacheWriteBatching{Enabled: true},
+			retries:  CacheWriteRetries{MaxRetries: 1, MaxBackoffMS: -1},
+			expectedErrors: []error{
+				errors.New("cache.write_batching.window_ms must be > 0. Got 0"),
+				errors.New("cache.write_batching.max_values must be > 0. Got 0"),
+				errors.New("cache.write_retries.backoff_ms must be > 0. Got 0"),
+				errors.New("cache.write_retries.max_backoff_ms must be >= backoff_ms. Got -1"),
+			},
+		},
+		{
+			name:           "negative_retries",
+			retries:        CacheWriteRetries{MaxRetries: -1},
+			expectedErrors: []error{errors.New("cache.write_retries.max_retries must be >= 0. Got -1")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.batching.validate(nil)
+			errs = tt.retries.validate(errs)
+			assert.Equal(t, tt.expectedErrors, errs)
+		})
+	}
+}
@@ -71,6 +71,33 @@ type StoredRequests struct {
 	// HTTPEvents configures an instance of stored_requests/events/http/http.go.
 	// If non-nil, the server will use those endpoints to populate and update the cache.
 	HTTPEvents HTTPEventsConfig `mapstructure:"http_events"`
+	// Redis configures an instance of stored_requests/backends/redis_fetcher/fetcher.go and of
+	// stored_requests/events/redis/redis.go. If the address is set, the data will be fetched from a
+	// server speaking the Redis protocol.
+	Redis RedisConfig `mapstructure:"redis"`
+}
+
+// RedisConfig configures stored_requests/backends/redis_fetcher/fetcher.go and stored_requests/events/redis/redis.go
+type RedisConfig struct {
+	// Address is the host:port of the server
+	Address  string `mapstructure:"address"`
+	Password string `mapstructure:"password"`
+	DB       int    `mapstructure:"db"`
+	// KeyPrefix namespaces the keys, the data is stored at <key_prefix><type>:<id>
+	KeyPrefix string `mapstructure:"key_prefix"`
+	Timeout   int    `mapstructure:"timeout_ms"`
+	PoolSize  int    `mapstructure:"pool_size"`
+	// InvalidationChannel is the pub/sub channel on which the invalidated IDs are published
+	InvalidationChannel string `mapstructure:"invalidation_channel"`
+	RetryDelay          int    `mapstructure:"retry_delay_ms"`
+}
+
+func (cfg RedisConfig) TimeoutDuration() time.Duration {
+	return time.Duration(cfg.Timeout) * time.Millisecond
+}
+
+func (cfg RedisConfig) RetryDelayDuration() time.Duration {
+	return time.Duration(cfg.RetryDelay) * time.Millisecond
 }
 
 // HTTPEventsConfig configures stored_requests/events/http/http.go
@@ -89,6 +116,33 @@ func (cfg HTTPEventsConfig) RefreshRateDuration() time.Duration {
 	return time.Duration(cfg.RefreshRate) * time.Second
 }
 
+// StoredDataAPI configures endpoints/stored_data.go, served on the admin port. Stored Requests and Imps are
+// written to the stored_requests backend, Stored Responses and Accounts to the stored_responses and accounts
+// backends. Redis is the only writable backend.
+type StoredDataAPI struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Timeout is the maximum number of milliseconds an operation on a backend may take
+	Timeout int `mapstructure:"timeout_ms"`
+}
+
+// TimeoutDuration returns the Timeout as a time.Duration
+func (cfg StoredDataAPI) TimeoutDuration() time.Duration {
+	return time.Duration(cfg.Timeout) * time.Millisecond
+}
+
+func (cfg *StoredDataAPI) validate(parent *Configuration, errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.Timeout <= 0 {
+		errs = append(errs, fmt.Errorf("stored_data_api.timeout_ms must be positive"))
+	}
+	if parent.StoredRequests.Redis.Address == "" && parent.StoredResponses.Redis.Address == "" && parent.Accounts.Redis.Address == "" {
+		errs = append(errs, fmt.Errorf("stored_data_api requires a writable backend: stored_requests.redis, stored_responses.redis or accounts.redis"))
+	}
+	return errs
+}
+
 // CacheEventsConfig configured stored_requests/events/api/api.go
 type CacheEventsConfig struct {
 	// Enabled should be true to enable the events api endpoint
@@ -164,6 +218,15 @@ func (cfg *StoredRequests) validate(errs []error) []error {
 		if cfg.Database.CacheInitialization.Query != "" {
 			errs = append(errs, fmt.Errorf("%s: database.initialize_caches.query must be empty if in_memory_cache=none", cfg.Section()))
 		}
+		if cfg.Redis.InvalidationChannel != "" {
+			errs = append(errs, fmt.Errorf("%s: redis.invalidation_channel must be empty if in_memory_cache=none", cfg.Section()))
+		}
+	}
+	if cfg.Redis.Address != "" && cfg.Redis.Timeout <= 0 {
+		errs = append(errs, fmt.Errorf("%s: redis.timeout_ms must be positive", cfg.Section()))
+	}
+	if cfg.Redis.InvalidationChannel != "" && cfg.Redis.RetryDelay <= 0 {
+		errs = append(errs, fmt.Errorf("%s: redis.retry_delay_ms must be positive", cfg.Section()))
 	}
 	errs = cfg.InMemoryCache.validate(cfg.DataType(), errs)
 	return errs
@@ -290,3 +290,30 @@ func TestResolveConfig(t *testing.T) {
 	assertStringsEqual(t, amp.HTTPEvents.Endpoint, cfg.StoredRequests.HTTPEvents.AmpEndpoint)
 	assertStringsEqual(t, amp.CacheEvents.Endpoint, "/storedrequests/amp")
 }
+
+func TestRedisConfigValidation(t *testing.T) {
+	redisCfg := func(redis RedisConfig, cacheType string) *StoredRequests {
+		return &StoredRequests{
+			dataType:      RequestDataType,
+			InMemoryCache: InMemoryCache{Type: cacheType},
+			Redis:         redis,
+		}
+	}
+
+	assertNoErrs(t, redisCfg(RedisConfig{}, "none").validate(nil))
+	assertNoErrs(t, redisCfg(RedisConfig{Address: "localhost:6379", Timeout: 50}, "none").validate(nil))
+	assertNoErrs(t, redisCfg(RedisConfig{Address: "localhost:6379", Timeout: 50, InvalidationChannel: "channel", RetryDelay: 1000}, "unbounded").validate(nil))
+	assertErrsExist(t, redisCfg(RedisConfig{Address: "localhost:6379"}, "none").validate(nil))
+	assertErrsExist(t, redisCfg(RedisConfig{Address: "localhost:6379", Timeout: 50, InvalidationChannel: "channel", RetryDelay: 1000}, "none").validate(nil))
+	assertErrsExist(t, redisCfg(RedisConfig{Address: "localhost:6379", Timeout: 50, InvalidationChannel: "channel"}, "unbounded").validate(nil))
+	assertErrsExist(t, redisCfg(RedisConfig{Address: "localhost:6379", Timeout: 50, InvalidationChannel: "channel", RetryDelay: -1}, "unbounded").validate(nil))
+}
+
+func TestStoredDataAPIValidation(t *testing.T) {
+	withRedis := &Configuration{Accounts: StoredRequests{Redis: RedisConfig{Address: "localhost:6379"}}}
+
+	assertNoErrs(t, (&StoredDataAPI{}).validate(&Configuration{}, nil))
+	assertNoErrs(t, (&StoredDataAPI{Enabled: true, Timeout: 1000}).validate(withRedis, nil))
+	assertErrsExist(t, (&StoredDataAPI{Enabled: true}).validate(withRedis, nil))
+	assertErrsExist(t, (&StoredDataAPI{Enabled: true, Timeout: 1000}).validate(&Configuration{}, nil))
+}
@@ -1,29 +1,59 @@
 package currency
 
 import (
-	"context"
-	"fmt"
-	"io"
+	"errors"
+	"math"
 	"net/http"
+	"sort"
+	"sync"
 	"sync/atomic"
 	"time"
 
-	"github.com/prebid/prebid-server/v3/errortypes"
 	"github.com/prebid/prebid-server/v3/logger"
-	"github.com/prebid/prebid-server/v3/util/jsonutil"
 	"github.com/prebid/prebid-server/v3/util/timeutil"
 )
 
 // RateConverter holds the currencies conversion rates dictionary
 type RateConverter struct {
-	httpClient          httpClient
-	httpTimeout         time.Duration
+	sources             []RateSource
 	staleRatesThreshold time.Duration
-	syncSourceURL       string
-	rates               atomic.Value // Should only hold Rates struct
-	lastUpdated         atomic.Value // Should only hold time.Time
-	constantRates       Conversions
-	time                timeutil.Time
+	maxRateDeviation    float64
+	// rateConfirmations is the number of consecutive fetches which must return a deviating rate for it to be accepted
+	rateConfirmations int
+	rates             atomic.Value // Should only hold Rates struct
+	lastUpdated       atomic.Value // Should only hold time.Time
+	constantRates     Conversions
+	time              timeutil.Time
+
+	statusMutex    sync.Mutex
+	sourceStatuses []RateSourceStatus
+	activeSource   int
+
+	deviationMutex sync.Mutex
+	// deviatingRates holds the deviating rates being confirmed by consecutive fetches, keyed by from/to currencies
+	deviatingRates map[string]deviatingRate
+}
+
+// deviatingRate is a rejected rate and the number of consecutive fetches which returned it
+type deviatingRate struct {
+	rate    float64
+	fetches int
+}
+
+// RateSourceStatus is the outcome of the last fetches from a rate source
+type RateSourceStatus struct {
+	Source string `json:"source"`
+	// Active is set for the source of the current rates
+	Active      bool      `json:"active"`
+	LastFetched time.Time `json:"lastFetched"`
+	LastError   string    `json:"lastError,omitempty"`
+	// RejectedRates lists the rates of the last fetch which deviated too much from the previous ones
+	RejectedRates []string `json:"rejectedRates,omitempty"`
+}
+
+// RateSourcesInfo is the additional info of the converter, with the status of every rate source
+type RateSourcesInfo struct {
+	Sources []RateSourceStatus `json:"sources"`
 }
 
 // NewRateConverter returns a new RateConverter
@@ -33,58 +63,160 @@ func NewRateConverter(
 	syncSourceURL string,
 	staleRatesThreshold time.Duration,
 ) *RateConverter {
+	return NewRateConverterWithSources([]RateSource{NewHTTPRateSource(httpClient, httpTimeout, syncSourceURL)}, staleRatesThreshold, 0, 0)
+}
+
+// NewRateConverterWithSources returns a new RateConverter updating the rates from the first source which
+// can be fetched, in order. When maxRateDeviation is positive, a fetched rate deviating by more than this
+// percentage from the previous value is rejected and the previous value is kept, unless another source agrees
+// on the new rate or rateConfirmations consecutive fetches return it.
+func NewRateConverterWithSources(sources []RateSource, staleRatesThreshold time.Duration, maxRateDeviation float64, rateConfirmations int) *RateConverter {
+	sourceStatuses := make([]RateSourceStatus, len(sources))
+	for i, source := range sources {
+		sourceStatuses[i].Source = source.Name()
+	}
 	return &RateConverter{
-		httpClient:          httpClient,
-		httpTimeout:         httpTimeout,
+		sources:             sources,
 		staleRatesThreshold: staleRatesThreshold,
-		syncSourceURL:       syncSourceURL,
+		maxRateDeviation:    maxRateDeviation,
+		rateConfirmations:   rateConfirmations,
 		rates:               atomic.Value{},
 		lastUpdated:         atomic.Value{},
 		constantRates:       NewConstantRates(),
 		time:                &timeutil.RealTime{},
+		sourceStatuses:      sourceStatuses,
+		activeSource:        -1,
 	}
 }
 
-// fetch allows to retrieve the currencies rates from the syncSourceURL provided
+// fetch retrieves the currencies rates from the first source which can be fetched
 func (rc *RateConverter) fetch() (*Rates, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), rc.httpTimeout)
-	defer cancel()
-
-	request, err := http.NewRequestWithContext(ctx, "GET", rc.syncSourceURL, nil)
-	if err != nil {
-		return nil, err
+	var errs []error
+	for i, source := range rc.sources {
+		rates, err := source.Fetch()
+		if err != nil {
+			rc.setSourceError(i, err)
+			errs = append(errs, err)
+			continue
+		}
+		rejectedRates := rc.rejectDeviatingRates(i, rates)
+		rc.setSourceFetched(i, rejectedRates)
+		return rates, nil
 	}
+	if len(errs) == 0 {
+		return nil, errors.New("no currency rate sources are configured")
+	}
+	return nil, errors.Join(errs...)
+}
 
-	response, err := rc.httpClient.Do(request)
-	if err != nil {
-		return nil, err
+// rejectDeviatingRates restores the previous value of the rates fetched from the source deviating too much from it. A
+// deviating rate is accepted when another source agrees on it or when it was returned by enough consecutive fetches.
+func (rc *RateConverter) rejectDeviatingRates(sourceIndex int, rates *Rates) []string {
+	if rc.maxRateDeviation <= 0 {
+		return nil
 	}
-	defer func() {
-		// read the entire response body to ensure full connection reuse if there's an
-		// invalid status code
-		if _, err := io.Copy(io.Discard, response.Body); err != nil {
-			logger.Errorf("error draining conversion rates response body: %v", err)
-		}
-		response.Body.Close()
-	}()
+	previousRates, ok := rc.rates.Load().(*Rates)
+	if !ok || previousRates == nil {
+		return nil
+	}
+
+	rc.deviationMutex.Lock()
+	defer rc.deviationMutex.Unlock()
+
+	deviatingRates := make(map[string]deviatingRate)
+	var otherSourcesRates []*Rates
+	var otherSourcesFetched bool
+	var rejectedRates []string
+	for from, conversions := range rates.Conversions {
+		for to, rate := range conversions {
+			previousRate, ok := previousRates.Conversions[from][to]
+			if !ok || previousRate == 0 {
+				continue
+			}
+			deviation := rateDeviation(rate, previousRate)
+			if deviation <= rc.maxRateDeviation {
+				continue
+			}
+
+			key := from + "/" + to
+			confirmed := rc.deviatingRates[key]
+			if confirmed.fetches > 0 && rateDeviation(rate, confirmed.rate) <= rc.maxRateDeviation {
+				confirmed.fetches++
+			} else {
+				confirmed = deviatingRate{rate: rate, fetches: 1}
+			}
+			if rc.rateConfirmations > 0 && confirmed.fetches >= rc.rateConfirmations {
+				logger.Infof("Accepting the %s to %s conversion rate %v returned by %d consecutive fetches", from, to, rate, confirmed.fetches)
+				continue
+			}
 
-	if response.StatusCode >= 400 {
-		message := fmt.Sprintf("the currency rates request failed with status code %d", response.StatusCode)
-		return nil, &errortypes.BadServerResponse{Message: message}
+			if !otherSourcesFetched {
+				otherSourcesRates = rc.fetchOtherSources(sourceIndex)
+				otherSourcesFetched = true
+			}
+			if ratesAgree(otherSourcesRates, from, to, rate, rc.maxRateDeviation) {
+				logger.Infof("Accepting the %s to %s conversion rate %v another source agrees on", from, to, rate)
+				continue
+			}
+
+			logger.Errorf("Rejecting the %s to %s conversion rate %v which deviates by %.2f%% from the previous rate %v", from, to, rate, deviation, previousRate)
+			conversions[to] = previousRate
+			deviatingRates[key] = confirmed
+			rejectedRates = append(rejectedRates, key)
+		}
 	}
+	rc.deviatingRates = deviatingRates
+	sort.Strings(rejectedRates)
+	return rejectedRates
+}
 
-	bytesJSON, err := io.ReadAll(response.Body)
-	if err != nil {
-		return nil, fmt.Errorf("the currency rates request failed: %v", err)
+// fetchOtherSources returns the rates of the sources other than the one at sourceIndex which can be fetched
+func (rc *RateConverter) fetchOtherSources(sourceIndex int) []*Rates {
+	var otherRates []*Rates
+	for i, source := range rc.sources {
+		if i == sourceIndex {
+			continue
+		}
+		rates, err := source.Fetch()
+		if err != nil {
+			logger.Warnf("Error fetching currency rates from %s to confirm the deviating rates: %v", source.Name(), err)
+			continue
+		}
+		otherRates = append(otherRates, rates)
 	}
+	return otherRates
+}
 
-	updatedRates := &Rates{}
-	err = jsonutil.UnmarshalValid(bytesJSON, updatedRates)
-	if err != nil {
-		return nil, fmt.Errorf("the currency rates request failed to parse json: %v", err)
+// ratesAgree returns true if one of the rates has a from to conversion rate which deviates by at most maxDeviation
+// percent from the rate
+func ratesAgree(otherRates []*Rates, from, to string, rate, maxDeviation float64) bool {
+	for _, rates := range otherRates {
+		if otherRate, ok := rates.Conversions[from][to]; ok && otherRate != 0 && rateDeviation(rate, otherRate) <= maxDeviation {
+			return true
+		}
 	}
+	return false
+}
 
-	return updatedRates, err
+// rateDeviation returns the deviation of the rate from the reference rate, in percent
+func rateDeviation(rate, reference float64) float64 {
+	return math.Abs(rate-reference) / reference * 100
+}
+
+func (rc *RateConverter) setSourceError(index int, err error) {
+	rc.statusMutex.Lock()
+	defer rc.statusMutex.Unlock()
+	rc.sourceStatuses[index].LastError = err.Error()
+	rc.sourceStatuses[index].RejectedRates = nil
+}
+
+func (rc *RateConverter) setSourceFetched(index int, rejectedRates []string) {
+	rc.statusMutex.Lock()
+	defer rc.statusMutex.Unlock()
+	rc.sourceStatuses[index].LastFetched = rc.time.Now()
+	rc.sourceStatuses[index].LastError = ""
+	rc.sourceStatuses[index].RejectedRates = rejectedRates
+	rc.activeSource = index
 }
 
 // Update updates the internal currencies rates from remote sources
@@ -131,6 +263,10 @@ func (rc *RateConverter) Rates() Conversions {
 func (rc *RateConverter) clearRates() {
 	// atomic.Value field rates must be of type *Rates so we cast nil to that type
 	rc.rates.Store((*Rates)(nil))
+
+	rc.statusMutex.Lock()
+	defer rc.statusMutex.Unlock()
+	rc.activeSource = -1
 }
 
 // checkStaleRates checks if loaded third party conversion rates are stale
@@ -149,13 +285,30 @@ func (rc *RateConverter) checkStaleRates() bool {
 	return false
 }
 
-// GetInfo returns setup information about the converter
+// GetInfo returns setup information about the converter. The source is the one of the current rates, or the
+// first source when the rates have not been fetched.
 func (rc *RateConverter) GetInfo() ConverterInfo {
 	var rates *map[string]map[string]float64 = rc.Rates().GetRates()
+
+	rc.statusMutex.Lock()
+	defer rc.statusMutex.Unlock()
+	var source string
+	if rc.activeSource >= 0 {
+		source = rc.sourceStatuses[rc.activeSource].Source
+	} else if len(rc.sourceStatuses) > 0 {
+		source = rc.sourceStatuses[0].Source
+	}
+	sourceStatuses := make([]RateSourceStatus, len(rc.sourceStatuses))
+	copy(sourceStatuses, rc.sourceStatuses)
+	for i := range sourceStatuses {
+		sourceStatuses[i].Active = i == rc.activeSource
+	}
+
 	return converterInfo{
-		source:      rc.syncSourceURL,
-		lastUpdated: rc.LastUpdated(),
-		rates:       rates,
+		source:         source,
+		lastUpdated:    rc.LastUpdated(),
+		rates:          rates,
+		additionalInfo: RateSourcesInfo{Sources: sourceStatuses},
 	}
 }
 
@@ -1,6 +1,7 @@
 package currency
 
 import (
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -381,3 +382,123 @@ func (m *mockHttpClient) Do(req *http.Request) (*http.Response, error) {
 		Body:       io.NopCloser(strings.NewReader(m.responseBody)),
 	}, nil
 }
+
+func TestRateSourcesFallback(t *testing.T) {
+	fakeTime := &FakeTime{time: time.Date(2018, time.September, 12, 30, 0, 0, 0, time.UTC)}
+	primary := &mockRateSource{name: "primary", err: errors.New("connection refused")}
+	secondary := &mockRateSource{name: "secondary", rates: map[string]map[string]float64{"USD": {"GBP": 0.77}}}
+	currencyConverter := NewRateConverterWithSources([]RateSource{primary, secondary}, 24*time.Hour, 0, 0)
+	currencyConverter.time = fakeTime
+
+	assert.NoError(t, currencyConverter.Run())
+	assert.Equal(t, map[string]map[string]float64{"USD": {"GBP": 0.77}}, currencyConverter.Rates().(*Rates).Conversions)
+
+	info := currencyConverter.GetInfo()
+	assert.Equal(t, "secondary", info.Source())
+	assert.Equal(t, RateSourcesInfo{Sources: []RateSourceStatus{
+		{Source: "primary", LastError: "connection refused"},
+		{Source: "secondary", Active: true, LastFetched: fakeTime.time},
+	}}, info.AdditionalInfo())
+
+	// The primary source is used again as soon as it can be fetched
+	primary.err = nil
+	primary.rates = map[string]map[string]float64{"USD": {"GBP": 0.78}}
+	assert.NoError(t, currencyConverter.Run())
+	assert.Equal(t, map[string]map[string]float64{"USD": {"GBP": 0.78}}, currencyConverter.Rates().(*Rates).Conversions)
+	assert.Equal(t, "primary", currencyConverter.GetInfo().Source())
+
+	// The rates are kept while they are not stale when every source fails
+	primary.err = errors.New("connection refused")
+	secondary.err = errors.New("timeout")
+	assert.EqualError(t, currencyConverter.Run(), "connection refused\ntimeout")
+	assert.Equal(t, map[string]map[string]float64{"USD": {"GBP": 0.78}}, currencyConverter.Rates().(*Rates).Conversions)
+	info = currencyConverter.GetInfo()
+	assert.Equal(t, "primary", info.Source())
+	assert.Equal(t, RateSourcesInfo{Sources: []RateSourceStatus{
+		{Source: "primary", Active: true, LastFetched: fakeTime.time, LastError: "connection refused"},
+		{Source: "secondary", LastFetched: fakeTime.time, LastError: "timeout"},
+	}}, info.AdditionalInfo())
+}
+
+func TestRateSourcesInfoWithoutRates(t *testing.T) {
+	currencyConverter := NewRateConverterWithSources([]RateSource{
+		&mockRateSource{name: "primary", err: errors.New("connection refused")},
+		&mockRateSource{name: "secondary", err: errors.New("timeout")},
+	}, 24*time.Hour, 0, 0)
+
+	assert.Error(t, currencyConverter.Run())
+	assert.Equal(t, &ConstantRates{}, currencyConverter.Rates())
+	info := currencyConverter.GetInfo()
+	assert.Equal(t, "primary", info.Source())
+	assert.Equal(t, RateSourcesInfo{Sources: []RateSourceStatus{
+		{Source: "primary", LastError: "connection refused"},
+		{Source: "secondary", LastError: "timeout"},
+	}}, info.AdditionalInfo())
+}
+
+func TestRejectDeviatingRates(t *testing.T) {
+	source := &mockRateSource{name: "primary", rates: map[string]map[string]float64{"USD": {"GBP": 0.8, "EUR": 0.9}}}
+	currencyConverter := NewRateConverterWithSources([]RateSource{source}, 24*time.Hour, 10, 0)
+	currencyConverter.time = &FakeTime{time: time.Date(2018, time.September, 12, 30, 0, 0, 0, time.UTC)}
+
+	assert.NoError(t, currencyConverter.Run())
+
+	source.rates = map[string]map[string]float64{"USD": {"GBP": 0.84, "EUR": 9, "JPY": 110}, "GBP": {"USD": 1.25}}
+	assert.NoError(t, currencyConverter.Run())
+	assert.Equal(t, map[string]map[string]float64{"USD": {"GBP": 0.84, "EUR": 0.9, "JPY": 110}, "GBP": {"USD": 1.25}},
+		currencyConverter.Rates().(*Rates).Conversions, "only the EUR rate deviates by more than 10%")
+	info := currencyConverter.GetInfo().AdditionalInfo().(RateSourcesInfo)
+	assert.Equal(t, []string{"USD/EUR"}, info.Sources[0].RejectedRates)
+}
+
+func TestRejectDeviatingRatesDisabled(t *testing.T) {
+	source := &mockRateSource{name: "primary", rates: map[string]map[string]float64{"USD": {"EUR": 0.9}}}
+	currencyConverter := NewRateConverterWithSources([]RateSource{source}, 24*time.Hour, 0, 0)
+
+	assert.NoError(t, currencyConverter.Run())
+	source.rates = map[string]map[string]float64{"USD": {"EUR": 9}}
+	assert.NoError(t, currencyConverter.Run())
+	assert.Equal(t, map[string]map[string]float64{"USD": {"EUR": 9}}, currencyConverter.Rates().(*Rates).Conversions)
+}
+
+func TestAcceptDeviatingRatesConfirmedByConsecutiveFetches(t *testing.T) {
+	source := &mockRateSource{name: "primary", rates: map[string]map[string]float64{"USD": {"EUR": 0.9}}}
+	currencyConverter := NewRateConverterWithSources([]RateSource{source}, 24*time.Hour, 10, 3)
+
+	assert.NoError(t, currencyConverter.Run())
+
+	source.rates = map[string]map[string]float64{"USD": {"EUR": 1.8}}
+	assert.NoError(t, currencyConverter.Run())
+	assert.Equal(t, map[string]map[string]float64{"USD": {"EUR": 0.9}}, currencyConverter.Rates().(*Rates).Conversions, "first fetch of the deviating rate")
+
+	// a fetch returning a different deviating rate starts the confirmation again
+	source.rates = map[string]map[string]float64{"USD": {"EUR": 3}}
+	assert.NoError(t, currencyConverter.Run())
+	source.rates = map[string]map[string]float64{"USD": {"EUR": 1.8}}
+	assert.NoError(t, currencyConverter.Run())
+	source.rates = map[string]map[string]float64{"USD": {"EUR": 1.85}}
+	assert.NoError(t, currencyConverter.Run())
+	assert.Equal(t, map[string]map[string]float64{"USD": {"EUR": 0.9}}, currencyConverter.Rates().(*Rates).Conversions, "second fetch of the deviating rate")
+
+	source.rates = map[string]map[string]float64{"USD": {"EUR": 1.8}}
+	assert.NoError(t, currencyConverter.Run())
+	assert.Equal(t, map[string]map[string]float64{"USD": {"EUR": 1.8}}, currencyConverter.Rates().(*Rates).Conversions, "third fetch of the deviating rate")
+	info := currencyConverter.GetInfo().AdditionalInfo().(RateSourcesInfo)
+	assert.Empty(t, info.Sources[0].RejectedRates)
+}
+
+func TestAcceptDeviatingRatesOtherSourcesAgreeOn(t *testing.T) {
+	primary := &mockRateSource{name: "primary", rates: map[string]map[string]float64{"USD": {"EUR": 0.9, "GBP": 0.8}}}
+	secondary := &mockRateSource{name: "secondary", err: errors.New("timeout")}
+	tertiary := &mockRateSource{name: "tertiary", rates: map[string]map[string]float64{"USD": {"EUR": 1.75, "GBP": 0.8}}}
+	currencyConverter := NewRateConverterWithSources([]RateSource{primary, secondary, tertiary}, 24*time.Hour, 10, 0)
+
+	assert.NoError(t, currencyConverter.Run())
+
+	primary.rates = map[string]map[string]float64{"USD": {"EUR": 1.8, "GBP": 1.6}}
+	assert.NoError(t, currencyConverter.Run())
+	assert.Equal(t, map[string]map[string]float64{"USD": {"EUR": 1.8, "GBP": 0.8}}, currencyConverter.Rates().(*Rates).Conversions,
+		"only the EUR rate is confirmed by another source")
+	info := currencyConverter.GetInfo().AdditionalInfo().(RateSourcesInfo)
+	assert.Equal(t, []string{"USD/GBP"}, info.Sources[0].RejectedRates)
+}
@@ -0,0 +1,188 @@
+package currency
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/logger"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// RateSource fetches the currency rates from one origin
+type RateSource interface {
+	// Name identifies the source in the converter info, like its URL or path
+	Name() string
+	Fetch() (*Rates, error)
+}
+
+// NewRateSources returns the rate sources of the config, in the order they are tried. The fetch URL is the
+// only source when no sources are listed.
+func NewRateSources(httpClient httpClient, httpTimeout time.Duration, cfg config.CurrencyConverter) []RateSource {
+	if len(cfg.Sources) == 0 {
+		return []RateSource{NewHTTPRateSource(httpClient, httpTimeout, cfg.FetchURL)}
+	}
+
+	sources := make([]RateSource, 0, len(cfg.Sources))
+	for _, source := range cfg.Sources {
+		switch source.Type {
+		case config.CurrencyRateSourceJSON:
+			sources = append(sources, NewHTTPRateSource(httpClient, httpTimeout, source.URL))
+		case config.CurrencyRateSourceECB:
+			sources = append(sources, NewECBRateSource(httpClient, httpTimeout, source.URL))
+		case config.CurrencyRateSourceFile:
+			sources = append(sources, NewFileRateSource(source.Path))
+		}
+	}
+	return sources
+}
+
+// NewHTTPRateSource returns a source of the rates served as JSON, in the format of
+// https://cdn.jsdelivr.net/gh/prebid/currency-file@1/latest.json
+func NewHTTPRateSource(httpClient httpClient, httpTimeout time.Duration, url string) RateSource {
+	return &httpRateSource{
+		httpClient:  httpClient,
+		httpTimeout: httpTimeout,
+		url:         url,
+	}
+}
+
+type httpRateSource struct {
+	httpClient  httpClient
+	httpTimeout time.Duration
+	url         string
+}
+
+func (s *httpRateSource) Name() string {
+	return s.url
+}
+
+func (s *httpRateSource) Fetch() (*Rates, error) {
+	body, err := fetchRates(s.httpClient, s.httpTimeout, s.url)
+	if err != nil {
+		return nil, err
+	}
+	return parseJSONRates(body)
+}
+
+// NewECBRateSource returns a source of the euro reference rates served as XML by the European Central Bank,
+// like https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml. The rates are converted from EUR.
+func NewECBRateSource(httpClient httpClient, httpTimeout time.Duration, url string) RateSource {
+	return &ecbRateSource{
+		httpClient:  httpClient,
+		httpTimeout: httpTimeout,
+		url:         url,
+	}
+}
+
+type ecbRateSource struct {
+	httpClient  httpClient
+	httpTimeout time.Duration
+	url         string
+}
+
+// ecbEnvelope holds the rates of the ECB XML, the days are listed from the latest
+type ecbEnvelope struct {
+	Days []struct {
+		Time  string `xml:"time,attr"`
+		Rates []struct {
+			Currency string  `xml:"currency,attr"`
+			Rate     float64 `xml:"rate,attr"`
+		} `xml:"Cube"`
+	} `xml:"Cube>Cube"`
+}
+
+func (s *ecbRateSource) Name() string {
+	return s.url
+}
+
+func (s *ecbRateSource) Fetch() (*Rates, error) {
+	body, err := fetchRates(s.httpClient, s.httpTimeout, s.url)
+	if err != nil {
+		return nil, err
+	}
+
+	var envelope ecbEnvelope
+	if err := xml.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("the currency rates request failed to parse xml: %v", err)
+	}
+	if len(envelope.Days) == 0 || len(envelope.Days[0].Rates) == 0 {
+		return nil, errors.New("the currency rates response has no rates")
+	}
+
+	eurRates := make(map[string]float64, len(envelope.Days[0].Rates))
+	for _, rate := range envelope.Days[0].Rates {
+		eurRates[rate.Currency] = rate.Rate
+	}
+	return NewRates(map[string]map[string]float64{"EUR": eurRates}), nil
+}
+
+// NewFileRateSource returns a source of the rates stored in a local file, in the JSON format of the HTTP source
+func NewFileRateSource(path string) RateSource {
+	return &fileRateSource{path: path}
+}
+
+type fileRateSource struct {
+	path string
+}
+
+func (s *fileRateSource) Name() string {
+	return s.path
+}
+
+func (s *fileRateSource) Fetch() (*Rates, error) {
+	body, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("the currency rates file could not be read: %v", err)
+	}
+	return parseJSONRates(body)
+}
+
+func fetchRates(httpClient httpClient, httpTimeout time.Duration, url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), httpTimeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		// read the entire response body to ensure full connection reuse if there's an
+		// invalid status code
+		if _, err := io.Copy(io.Discard, response.Body); err != nil {
+			logger.Errorf("error draining conversion rates response body: %v", err)
+		}
+		response.Body.Close()
+	}()
+
+	if response.StatusCode >= 400 {
+		message := fmt.Sprintf("the currency rates request failed with status code %d", response.StatusCode)
+		return nil, &errortypes.BadServerResponse{Message: message}
+	}
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("the currency rates request failed: %v", err)
+	}
+	return body, nil
+}
+
+func parseJSONRates(body []byte) (*Rates, error) {
+	rates := &Rates{}
+	if err := jsonutil.UnmarshalValid(body, rates); err != nil {
+		return nil, fmt.Errorf("the currency rates request failed to parse json: %v", err)
+	}
+	return rates, nil
+}
@@ -0,0 +1,148 @@
+package currency
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func getMockECBRates() []byte {
+	return []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<Cube>
+		<Cube time="2018-09-12">
+			<Cube currency="USD" rate="1.1617"/>
+			<Cube currency="GBP" rate="0.89183"/>
+		</Cube>
+		<Cube time="2018-09-11">
+			<Cube currency="USD" rate="1.1589"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`)
+}
+
+func newMockRatesServer(response []byte, status int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(status)
+		rw.Write(response)
+	}))
+}
+
+// mockRateSource returns the rates, or the error when set
+type mockRateSource struct {
+	name  string
+	rates map[string]map[string]float64
+	err   error
+}
+
+func (s *mockRateSource) Name() string {
+	return s.name
+}
+
+func (s *mockRateSource) Fetch() (*Rates, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	conversions := make(map[string]map[string]float64, len(s.rates))
+	for from, rates := range s.rates {
+		conversions[from] = make(map[string]float64, len(rates))
+		for to, rate := range rates {
+			conversions[from][to] = rate
+		}
+	}
+	return NewRates(conversions), nil
+}
+
+func TestECBRateSource(t *testing.T) {
+	tests := []struct {
+		description     string
+		giveResponse    []byte
+		giveStatus      int
+		wantErr         string
+		wantConversions map[string]map[string]float64
+	}{
+		{
+			description:     "The rates of the latest day are converted from EUR",
+			giveResponse:    getMockECBRates(),
+			giveStatus:      http.StatusOK,
+			wantConversions: map[string]map[string]float64{"EUR": {"USD": 1.1617, "GBP": 0.89183}},
+		},
+		{
+			description:  "The response has no rates",
+			giveResponse: []byte(`<Envelope><Cube></Cube></Envelope>`),
+			giveStatus:   http.StatusOK,
+			wantErr:      "the currency rates response has no rates",
+		},
+		{
+			description:  "The response is not XML",
+			giveResponse: []byte(`{"conversions":{}}`),
+			giveStatus:   http.StatusOK,
+			wantErr:      "the currency rates request failed to parse xml: EOF",
+		},
+		{
+			description:  "The server responds with an error",
+			giveResponse: []byte(`Service Unavailable`),
+			giveStatus:   http.StatusServiceUnavailable,
+			wantErr:      "the currency rates request failed with status code 503",
+		},
+	}
+
+	for _, tt := range tests {
+		server := newMockRatesServer(tt.giveResponse, tt.giveStatus)
+		defer server.Close()
+
+		rates, err := NewECBRateSource(&http.Client{}, time.Second, server.URL).Fetch()
+		if len(tt.wantErr) > 0 {
+			assert.EqualError(t, err, tt.wantErr, tt.description)
+			assert.Nil(t, rates, tt.description)
+		} else {
+			assert.NoError(t, err, tt.description)
+			assert.Equal(t, tt.wantConversions, rates.Conversions, tt.description)
+		}
+	}
+}
+
+func TestFileRateSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	assert.NoError(t, os.WriteFile(path, getMockRates(), 0600))
+
+	source := NewFileRateSource(path)
+	assert.Equal(t, path, source.Name())
+	rates, err := source.Fetch()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]map[string]float64{"USD": {"GBP": 0.77208}, "GBP": {"USD": 1.2952}}, rates.Conversions)
+
+	_, err = NewFileRateSource(filepath.Join(t.TempDir(), "missing.json")).Fetch()
+	assert.ErrorContains(t, err, "the currency rates file could not be read")
+}
+
+func TestNewRateSources(t *testing.T) {
+	sources := NewRateSources(&http.Client{}, time.Second, config.CurrencyConverter{
+		FetchURL: "https://currency.prebid.org",
+	})
+	assert.Len(t, sources, 1)
+	assert.IsType(t, &httpRateSource{}, sources[0])
+	assert.Equal(t, "https://currency.prebid.org", sources[0].Name())
+
+	sources = NewRateSources(&http.Client{}, time.Second, config.CurrencyConverter{
+		FetchURL: "https://currency.prebid.org",
+		Sources: []config.CurrencyRateSource{
+			{Type: config.CurrencyRateSourceECB, URL: "https://ecb.example.com/rates.xml"},
+			{Type: config.CurrencyRateSourceJSON, URL: "https://rates.example.com/latest.json"},
+			{Type: config.CurrencyRateSourceFile, Path: "/etc/pbs/currency.json"},
+		},
+	})
+	assert.Len(t, sources, 3)
+	assert.IsType(t, &ecbRateSource{}, sources[0])
+	assert.IsType(t, &httpRateSource{}, sources[1])
+	assert.IsType(t, &fileRateSource{}, sources[2])
+	assert.Equal(t, []string{"https://ecb.example.com/rates.xml", "https://rates.example.com/latest.json", "/etc/pbs/currency.json"},
+		[]string{sources[0].Name(), sources[1].Name(), sources[2].Name()})
+}
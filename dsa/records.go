@@ -0,0 +1,35 @@
+package dsa
+
+import (
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// Records returns the DSA transparency information of the bids of the response, the bids without a DSA object
+// are skipped
+func Records(bidResponse *openrtb2.BidResponse) []openrtb_ext.DSARecord {
+	if bidResponse == nil {
+		return nil
+	}
+
+	var records []openrtb_ext.DSARecord
+	for _, seatBid := range bidResponse.SeatBid {
+		for _, bid := range seatBid.Bid {
+			if len(bid.Ext) == 0 {
+				continue
+			}
+			var bidExt openrtb_ext.ExtBid
+			if err := jsonutil.Unmarshal(bid.Ext, &bidExt); err != nil || bidExt.DSA == nil {
+				continue
+			}
+			records = append(records, openrtb_ext.DSARecord{
+				Seat:  seatBid.Seat,
+				ImpID: bid.ImpID,
+				BidID: bid.ID,
+				DSA:   *bidExt.DSA,
+			})
+		}
+	}
+	return records
+}
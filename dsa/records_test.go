@@ -0,0 +1,73 @@
+package dsa
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecords(t *testing.T) {
+	tests := []struct {
+		name         string
+		giveResponse *openrtb2.BidResponse
+		wantRecords  []openrtb_ext.DSARecord
+	}{
+		{
+			name:         "nil",
+			giveResponse: nil,
+			wantRecords:  nil,
+		},
+		{
+			name: "bids_without_dsa",
+			giveResponse: &openrtb2.BidResponse{
+				SeatBid: []openrtb2.SeatBid{
+					{Seat: "appnexus", Bid: []openrtb2.Bid{{ID: "bid-1"}, {ID: "bid-2", Ext: json.RawMessage(`{"origbidcpm":1}`)}}},
+				},
+			},
+			wantRecords: nil,
+		},
+		{
+			name: "bids_with_dsa",
+			giveResponse: &openrtb2.BidResponse{
+				SeatBid: []openrtb2.SeatBid{
+					{Seat: "appnexus", Bid: []openrtb2.Bid{
+						{ID: "bid-1", ImpID: "imp-1", Ext: json.RawMessage(`{"dsa":{"adrender":1,"behalf":"advertiser","paid":"buyer","transparency":[{"domain":"dsp.com","dsaparams":[1,2]}]}}`)},
+						{ID: "bid-2", ImpID: "imp-2"},
+					}},
+					{Seat: "rubicon", Bid: []openrtb2.Bid{
+						{ID: "bid-3", ImpID: "imp-1", Ext: json.RawMessage(`{"dsa":{"paid":"buyer"}}`)},
+					}},
+				},
+			},
+			wantRecords: []openrtb_ext.DSARecord{
+				{
+					Seat:  "appnexus",
+					ImpID: "imp-1",
+					BidID: "bid-1",
+					DSA: openrtb_ext.ExtBidDSA{
+						AdRender:     ptrutil.ToPtr[int8](1),
+						Behalf:       "advertiser",
+						Paid:         "buyer",
+						Transparency: []openrtb_ext.ExtBidDSATransparency{{Domain: "dsp.com", Params: []int{1, 2}}},
+					},
+				},
+				{
+					Seat:  "rubicon",
+					ImpID: "imp-1",
+					BidID: "bid-3",
+					DSA:   openrtb_ext.ExtBidDSA{Paid: "buyer"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantRecords, Records(tt.giveResponse))
+		})
+	}
+}
@@ -33,11 +33,23 @@ var (
 	ErrPaidTooLong       = errors.New("DSA paid exceeds limit of 100 chars")
 	ErrNeitherWillRender = errors.New("DSA publisher and buyer both signal will not render")
 	ErrBothWillRender    = errors.New("DSA publisher and buyer both signal will render")
+
+	ErrTransparencyDomainInvalid     = errors.New("DSA transparency domain missing or exceeds limit of 75 chars")
+	ErrTransparencyParamsInvalid     = errors.New("DSA transparency dsaparams must be 1, 2 or 3")
+	ErrTransparencyParamsNotDeclared = errors.New("DSA transparency dsaparams not declared by the publisher")
+)
+
+// Transparency parameter values of the data used to target the ad
+const (
+	ParamProfiling          = 1 // profiling
+	ParamBasicAdvertising   = 2 // basic advertising
+	ParamPreciseGeolocation = 3 // precise geolocation
 )
 
 const (
-	behalfMaxLength = 100
-	paidMaxLength   = 100
+	behalfMaxLength             = 100
+	paidMaxLength               = 100
+	transparencyDomainMaxLength = 75
 )
 
 // Validate determines whether a given bid is valid from a DSA perspective.
@@ -46,7 +58,7 @@ const (
 // DSA object contents are invalid
 func Validate(req *openrtb_ext.RequestWrapper, bid *entities.PbsOrtbBid) error {
 	reqDSA := getReqDSA(req)
-	bidDSA := getBidDSA(bid)
+	bidDSA := GetBidDSA(bid)
 
 	if dropDSA(reqDSA, bidDSA) {
 		bid.Bid.Ext = jsonparser.Delete(bid.Bid.Ext, "dsa")
@@ -64,6 +76,9 @@ func Validate(req *openrtb_ext.RequestWrapper, bid *entities.PbsOrtbBid) error {
 	if len(bidDSA.Paid) > paidMaxLength {
 		return ErrPaidTooLong
 	}
+	if err := validateTransparency(reqDSA, bidDSA.Transparency); err != nil {
+		return err
+	}
 	if reqDSA != nil && reqDSA.PubRender != nil && bidDSA.AdRender != nil {
 		if *reqDSA.PubRender == PubRenderCannotRender && *bidDSA.AdRender != AdRenderWillRender {
 			return ErrNeitherWillRender
@@ -75,6 +90,34 @@ func Validate(req *openrtb_ext.RequestWrapper, bid *entities.PbsOrtbBid) error {
 	return nil
 }
 
+// validateTransparency checks the transparency entries of the bid. When the publisher declares transparency
+// params in the request, the bid may only use those.
+func validateTransparency(reqDSA *openrtb_ext.ExtRegsDSA, transparency []openrtb_ext.ExtBidDSATransparency) error {
+	declaredParams := make(map[int]struct{})
+	if reqDSA != nil {
+		for _, reqTransparency := range reqDSA.Transparency {
+			for _, param := range reqTransparency.Params {
+				declaredParams[param] = struct{}{}
+			}
+		}
+	}
+
+	for _, entry := range transparency {
+		if len(entry.Domain) == 0 || len(entry.Domain) > transparencyDomainMaxLength {
+			return ErrTransparencyDomainInvalid
+		}
+		for _, param := range entry.Params {
+			if param < ParamProfiling || param > ParamPreciseGeolocation {
+				return ErrTransparencyParamsInvalid
+			}
+			if _, ok := declaredParams[param]; len(declaredParams) > 0 && !ok {
+				return ErrTransparencyParamsNotDeclared
+			}
+		}
+	}
+	return nil
+}
+
 // dsaRequired examines the bid request to determine if the dsarequired field indicates
 // that bid responses include a dsa object
 func dsaRequired(dsa *openrtb_ext.ExtRegsDSA) bool {
@@ -96,8 +139,8 @@ func getReqDSA(req *openrtb_ext.RequestWrapper) *openrtb_ext.ExtRegsDSA {
 	return regExt.GetDSA()
 }
 
-// getBidDSA retrieves the DSA object from the bid
-func getBidDSA(bid *entities.PbsOrtbBid) *openrtb_ext.ExtBidDSA {
+// GetBidDSA retrieves the DSA object from the bid
+func GetBidDSA(bid *entities.PbsOrtbBid) *openrtb_ext.ExtBidDSA {
 	if bid == nil || bid.Bid == nil {
 		return nil
 	}
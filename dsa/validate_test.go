@@ -194,6 +194,86 @@ func TestValidate(t *testing.T) {
 			},
 			wantError: nil,
 		},
+		{
+			name: "required_and_bid_transparency_is_valid",
+			giveRequest: &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{
+					Regs: &openrtb2.Regs{
+						Ext: json.RawMessage(`{"dsa": {"dsarequired": 2,"transparency":[{"domain":"ssp.com","dsaparams":[1,2]}]}}`),
+					},
+				},
+			},
+			giveBid: &entities.PbsOrtbBid{
+				Bid: &openrtb2.Bid{
+					Ext: json.RawMessage(`{"dsa":{"transparency":[{"domain":"dsp.com","dsaparams":[2]}]}}`),
+				},
+			},
+			wantError: nil,
+		},
+		{
+			name: "required_and_bid_transparency_domain_is_missing",
+			giveRequest: &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{
+					Regs: &openrtb2.Regs{
+						Ext: json.RawMessage(`{"dsa": {"dsarequired": 2}}`),
+					},
+				},
+			},
+			giveBid: &entities.PbsOrtbBid{
+				Bid: &openrtb2.Bid{
+					Ext: json.RawMessage(`{"dsa":{"transparency":[{"dsaparams":[1]}]}}`),
+				},
+			},
+			wantError: ErrTransparencyDomainInvalid,
+		},
+		{
+			name: "required_and_bid_transparency_domain_is_too_long",
+			giveRequest: &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{
+					Regs: &openrtb2.Regs{
+						Ext: json.RawMessage(`{"dsa": {"dsarequired": 2}}`),
+					},
+				},
+			},
+			giveBid: &entities.PbsOrtbBid{
+				Bid: &openrtb2.Bid{
+					Ext: json.RawMessage(`{"dsa":{"transparency":[{"domain":"` + strings.Repeat("a", 72) + `.com","dsaparams":[1]}]}}`),
+				},
+			},
+			wantError: ErrTransparencyDomainInvalid,
+		},
+		{
+			name: "required_and_bid_transparency_params_are_invalid",
+			giveRequest: &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{
+					Regs: &openrtb2.Regs{
+						Ext: json.RawMessage(`{"dsa": {"dsarequired": 2}}`),
+					},
+				},
+			},
+			giveBid: &entities.PbsOrtbBid{
+				Bid: &openrtb2.Bid{
+					Ext: json.RawMessage(`{"dsa":{"transparency":[{"domain":"dsp.com","dsaparams":[4]}]}}`),
+				},
+			},
+			wantError: ErrTransparencyParamsInvalid,
+		},
+		{
+			name: "required_and_bid_transparency_params_are_not_declared",
+			giveRequest: &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{
+					Regs: &openrtb2.Regs{
+						Ext: json.RawMessage(`{"dsa": {"dsarequired": 2,"transparency":[{"domain":"ssp.com","dsaparams":[1]}]}}`),
+					},
+				},
+			},
+			giveBid: &entities.PbsOrtbBid{
+				Bid: &openrtb2.Bid{
+					Ext: json.RawMessage(`{"dsa":{"transparency":[{"domain":"dsp.com","dsaparams":[1,3]}]}}`),
+				},
+			},
+			wantError: ErrTransparencyParamsNotDeclared,
+		},
 	}
 
 	for _, tt := range tests {
@@ -389,7 +469,7 @@ func TestGetBidDSA(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			dsa := getBidDSA(tt.bid)
+			dsa := GetBidDSA(tt.bid)
 			assert.Equal(t, tt.expectedDSA, dsa)
 		})
 	}
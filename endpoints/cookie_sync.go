@@ -259,6 +259,7 @@ func extractPrivacyPolicies(request cookieSyncRequest, usersyncDefaultGDPRValue
 
 	privacyPolicies := privacy.Policies{
 		GPPSID: gppSID,
+		GPP:    request.GPP,
 	}
 
 	return privacyMacros, gdprSignal, privacyPolicies, nil
@@ -56,10 +56,10 @@ func newCurrencyRatesInfo(rateConverter rateConverter, fetchingInterval time.Dur
 }
 
 // NewCurrencyRatesEndpoint returns current currency rates applied by the PBS server.
+// The info is read on every request to report the current status of the rate sources.
 func NewCurrencyRatesEndpoint(rateConverter rateConverter, fetchingInterval time.Duration) http.HandlerFunc {
-	currencyRateInfo := newCurrencyRatesInfo(rateConverter, fetchingInterval)
-
 	return func(w http.ResponseWriter, _ *http.Request) {
+		currencyRateInfo := newCurrencyRatesInfo(rateConverter, fetchingInterval)
 		jsonOutput, err := jsonutil.Marshal(currencyRateInfo)
 		if err != nil {
 			logger.Errorf("/currency/rates Critical error when trying to marshal currencyRateInfo: %v", err)
@@ -0,0 +1,38 @@
+package endpoints
+
+import (
+	"net/http"
+
+	"github.com/prebid/prebid-server/v3/logger"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// FloorSuggestionsEndpoint is the admin path pattern the floor suggestions endpoint handles
+const FloorSuggestionsEndpoint = "GET /floors/suggestions/{account}"
+
+type floorSuggester interface {
+	GetSuggestion(accountID string) (*openrtb_ext.PriceFloorData, bool)
+}
+
+// NewFloorSuggestionsEndpoint returns the floors data suggested for the account in the floors fetch format, so the
+// admin endpoint can be configured as an account's price_floors.fetch.url
+func NewFloorSuggestionsEndpoint(suggester floorSuggester) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		data, found := suggester.GetSuggestion(r.PathValue("account"))
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		jsonOutput, err := jsonutil.Marshal(data)
+		if err != nil {
+			logger.Errorf("/floors/suggestions Critical error when trying to marshal floor data: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(jsonOutput)
+	}
+}
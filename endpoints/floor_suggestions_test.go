@@ -0,0 +1,64 @@
+package endpoints
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+type floorSuggesterMock struct {
+	suggestions map[string]*openrtb_ext.PriceFloorData
+}
+
+func (m floorSuggesterMock) GetSuggestion(accountID string) (*openrtb_ext.PriceFloorData, bool) {
+	data, ok := m.suggestions[accountID]
+	return data, ok
+}
+
+func TestFloorSuggestionsEndpoint(t *testing.T) {
+	suggester := floorSuggesterMock{suggestions: map[string]*openrtb_ext.PriceFloorData{
+		"acc1": {
+			Currency: "USD",
+			ModelGroups: []openrtb_ext.PriceFloorModelGroup{{
+				Schema: openrtb_ext.PriceFloorSchema{Fields: []string{"mediaType"}, Delimiter: "|"},
+				Values: map[string]float64{"banner": 1.5},
+			}},
+		},
+	}}
+
+	tests := []struct {
+		name         string
+		account      string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "suggestion_found",
+			account:      "acc1",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"currency":"USD","modelgroups":[{"schema":{"fields":["mediaType"],"delimiter":"|"},"values":{"banner":1.5}}]}`,
+		},
+		{
+			name:         "suggestion_not_found",
+			account:      "acc2",
+			expectedCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc(FloorSuggestionsEndpoint, NewFloorSuggestionsEndpoint(suggester))
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest("GET", "/floors/suggestions/"+tt.account, nil))
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+				assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
@@ -164,7 +164,7 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 
 	// There is no body for AMP requests, so we pass a nil body and ignore the return value.
 	_, rejectErr := hookExecutor.ExecuteEntrypointStage(r, nilBody)
-	reqWrapper, storedAuctionResponses, storedBidResponses, bidderImpReplaceImp, errL := deps.parseAmpRequest(r, labels)
+	reqWrapper, storedAuctionResponses, storedBidResponses, bidderImpReplaceImp, errL := deps.parseAmpRequest(r, labels, "")
 	ao.Errors = append(ao.Errors, errL...)
 	// Process reject after parsing amp request, so we can use reqWrapper.
 	// There is no body for AMP requests, so we pass a nil body and ignore the return value.
@@ -235,6 +235,23 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 		return
 	}
 
+	// The account rolling out a version of the Stored Request data is only known once the untagged data is loaded
+	if storedRequestVersion := selectStoredRequestVersion(account, storedRequestUserKey(reqWrapper.BidRequest)); len(storedRequestVersion) > 0 {
+		var versionErrs []error
+		reqWrapper, storedAuctionResponses, storedBidResponses, bidderImpReplaceImp, versionErrs = deps.parseAmpRequest(r, labels, storedRequestVersion)
+		if errortypes.ContainsFatalError(versionErrs) {
+			w.WriteHeader(http.StatusBadRequest)
+			for _, err := range errortypes.FatalOnly(versionErrs) {
+				fmt.Fprintf(w, "Invalid request: %s\n", err.Error())
+			}
+			labels.RequestStatus = metrics.RequestStatusBadInput
+			ao.Errors = append(ao.Errors, versionErrs...)
+			return
+		}
+		ao.RequestWrapper = reqWrapper
+		ao.StoredRequestVersion = storedRequestVersion
+	}
+
 	// Populate any "missing" OpenRTB fields with info from other sources, (e.g. HTTP request headers).
 	if errs := deps.setFieldsImplicitly(r, reqWrapper, account); len(errs) > 0 {
 		errL = append(errL, errs...)
@@ -294,6 +311,8 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 	if auctionResponse != nil {
 		response = auctionResponse.BidResponse
 		seatNonBid.Append(auctionResponse.SeatNonBid)
+		ao.PrivacyAudit = auctionResponse.PrivacyAudit
+		ao.DSA = auctionResponse.DSA
 	}
 	ao.AuctionResponse = response
 	rejectErr, isRejectErr := hookexecution.CastRejectErr(err)
@@ -525,9 +544,9 @@ func getExtBidResponse(
 // possible, it will return errors with messages that suggest improvements.
 //
 // If the errors list has at least one element, then no guarantees are made about the returned request.
-func (deps *endpointDeps) parseAmpRequest(httpRequest *http.Request, labels metrics.Labels) (req *openrtb_ext.RequestWrapper, storedAuctionResponses stored_responses.ImpsWithBidResponses, storedBidResponses stored_responses.ImpBidderStoredResp, bidderImpReplaceImp stored_responses.BidderImpReplaceImpID, errs []error) {
+func (deps *endpointDeps) parseAmpRequest(httpRequest *http.Request, labels metrics.Labels, storedRequestVersion string) (req *openrtb_ext.RequestWrapper, storedAuctionResponses stored_responses.ImpsWithBidResponses, storedBidResponses stored_responses.ImpBidderStoredResp, bidderImpReplaceImp stored_responses.BidderImpReplaceImpID, errs []error) {
 	// Load the stored request for the AMP ID.
-	reqNormal, storedAuctionResponses, storedBidResponses, bidderImpReplaceImp, e := deps.loadRequestJSONForAmp(httpRequest, labels)
+	reqNormal, storedAuctionResponses, storedBidResponses, bidderImpReplaceImp, e := deps.loadRequestJSONForAmp(httpRequest, labels, storedRequestVersion)
 	if errs = append(errs, e...); errortypes.ContainsFatalError(errs) {
 		return
 	}
@@ -557,8 +576,8 @@ func (deps *endpointDeps) parseAmpRequest(httpRequest *http.Request, labels metr
 	return
 }
 
-// Load the stored OpenRTB request for an incoming AMP request, or return the errors found.
-func (deps *endpointDeps) loadRequestJSONForAmp(httpRequest *http.Request, labels metrics.Labels) (req *openrtb2.BidRequest, storedAuctionResponses stored_responses.ImpsWithBidResponses, storedBidResponses stored_responses.ImpBidderStoredResp, bidderImpReplaceImp stored_responses.BidderImpReplaceImpID, errs []error) {
+// Load the given version of the stored OpenRTB request for an incoming AMP request, or return the errors found.
+func (deps *endpointDeps) loadRequestJSONForAmp(httpRequest *http.Request, labels metrics.Labels, storedRequestVersion string) (req *openrtb2.BidRequest, storedAuctionResponses stored_responses.ImpsWithBidResponses, storedBidResponses stored_responses.ImpBidderStoredResp, bidderImpReplaceImp stored_responses.BidderImpReplaceImpID, errs []error) {
 	req = &openrtb2.BidRequest{}
 	errs = nil
 
@@ -578,6 +597,9 @@ func (deps *endpointDeps) loadRequestJSONForAmp(httpRequest *http.Request, label
 		errs = []error{fmt.Errorf("No AMP config found for tag_id '%s'", ampParams.StoredRequestID)}
 		return
 	}
+	if storedRequests, _, errs = stored_requests.FetchVersion(ctx, deps.storedReqFetcher, storedRequestVersion, storedRequests, nil); len(errs) > 0 {
+		return nil, nil, nil, nil, errs
+	}
 
 	// The fetched config becomes the entire OpenRTB request
 	requestJSON := storedRequests[ampParams.StoredRequestID]
@@ -741,6 +741,65 @@ func TestCCPAConsent(t *testing.T) {
 	}
 }
 
+func TestAmpStoredRequestVersion(t *testing.T) {
+	stored := map[string]json.RawMessage{
+		"1":    json.RawMessage(`{"id":"req-1","site":{"page":"prebid.org"},"imp":[{"id":"imp-1","banner":{"format":[{"w":300,"h":250}]},"ext":{"appnexus":{"placementId":12883451}}}]}`),
+		"1@v2": json.RawMessage(`{"id":"req-1","site":{"page":"prebid.org"},"imp":[{"id":"imp-1","banner":{"format":[{"w":728,"h":90}]},"ext":{"appnexus":{"placementId":12883451}}}]}`),
+	}
+
+	testCases := []struct {
+		name           string
+		versions       config.AccountStoredRequestVersions
+		expectedFormat openrtb2.Format
+	}{
+		{
+			name:           "no_rollout",
+			expectedFormat: openrtb2.Format{W: 300, H: 250},
+		},
+		{
+			name:           "candidate_version",
+			versions:       config.AccountStoredRequestVersions{Candidate: "v2", CandidatePercent: 100},
+			expectedFormat: openrtb2.Format{W: 728, H: 90},
+		},
+		{
+			name:           "version_without_data",
+			versions:       config.AccountStoredRequestVersions{Candidate: "v3", CandidatePercent: 100},
+			expectedFormat: openrtb2.Format{W: 300, H: 250},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			mockExchange := &mockAmpExchange{}
+			endpoint, _ := NewAmpEndpoint(
+				fakeUUIDGenerator{},
+				mockExchange,
+				ortb.NewRequestValidator(openrtb_ext.BuildBidderMap(), map[string]string{}, newParamsValidator(t)),
+				&mockVersionedStoredReqFetcher{data: stored},
+				empty_fetcher.EmptyFetcher{},
+				&config.Configuration{MaxRequestSize: maxSize, AccountDefaults: config.Account{StoredRequestVersions: test.versions}},
+				&metricsConfig.NilMetricsEngine{},
+				analyticsBuild.New(&config.Analytics{}),
+				map[string]string{},
+				[]byte{},
+				openrtb_ext.BuildBidderMap(),
+				empty_fetcher.EmptyFetcher{},
+				hooks.EmptyPlanBuilder{},
+				nil,
+			)
+
+			request := httptest.NewRequest("GET", "/openrtb2/auction/amp?tag_id=1", nil)
+			recorder := httptest.NewRecorder()
+			endpoint(recorder, request, nil)
+
+			assert.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+			if assert.NotNil(t, mockExchange.lastRequest) && assert.Len(t, mockExchange.lastRequest.Imp, 1) {
+				assert.Equal(t, []openrtb2.Format{test.expectedFormat}, mockExchange.lastRequest.Imp[0].Banner.Format)
+			}
+		})
+	}
+}
+
 func TestConsentWarnings(t *testing.T) {
 	type inputTest struct {
 		regs              *openrtb2.Regs
@@ -7,9 +7,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
 	"net/url"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -200,7 +202,7 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 	w.Header().Set("X-Prebid", version.BuildXPrebidHeader(version.Ver))
 	setBrowsingTopicsHeader(w, r)
 
-	req, impExtInfoMap, storedAuctionResponses, storedBidResponses, bidderImpReplaceImp, account, errL := deps.parseRequest(r, &labels, hookExecutor)
+	req, impExtInfoMap, storedAuctionResponses, storedBidResponses, bidderImpReplaceImp, account, storedRequestVersion, errL := deps.parseRequest(r, &labels, hookExecutor)
 	if errortypes.ContainsFatalError(errL) && writeError(errL, w, &labels) {
 		return
 	}
@@ -280,10 +282,13 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 	}()
 	ao.RequestWrapper = req
 	ao.Account = account
+	ao.StoredRequestVersion = storedRequestVersion
 	var response *openrtb2.BidResponse
 	if auctionResponse != nil {
 		response = auctionResponse.BidResponse
 		seatNonBid.Append(auctionResponse.SeatNonBid)
+		ao.PrivacyAudit = auctionResponse.PrivacyAudit
+		ao.DSA = auctionResponse.DSA
 	}
 	ao.Response = response
 	rejectErr, isRejectErr := hookexecution.CastRejectErr(err)
@@ -455,7 +460,7 @@ func setBrowsingTopicsHeader(w http.ResponseWriter, r *http.Request) {
 // possible, it will return errors with messages that suggest improvements.
 //
 // If the errors list has at least one element, then no guarantees are made about the returned request.
-func (deps *endpointDeps) parseRequest(httpRequest *http.Request, labels *metrics.Labels, hookExecutor hookexecution.HookStageExecutor) (req *openrtb_ext.RequestWrapper, impExtInfoMap map[string]exchange.ImpExtInfo, storedAuctionResponses stored_responses.ImpsWithBidResponses, storedBidResponses stored_responses.ImpBidderStoredResp, bidderImpReplaceImpId stored_responses.BidderImpReplaceImpID, account *config.Account, errs []error) {
+func (deps *endpointDeps) parseRequest(httpRequest *http.Request, labels *metrics.Labels, hookExecutor hookexecution.HookStageExecutor) (req *openrtb_ext.RequestWrapper, impExtInfoMap map[string]exchange.ImpExtInfo, storedAuctionResponses stored_responses.ImpsWithBidResponses, storedBidResponses stored_responses.ImpBidderStoredResp, bidderImpReplaceImpId stored_responses.BidderImpReplaceImpID, account *config.Account, storedRequestVersion string, errs []error) {
 	errs = nil
 	var err error
 	var errL []error
@@ -516,7 +521,7 @@ func (deps *endpointDeps) parseRequest(httpRequest *http.Request, labels *metric
 
 	impInfo, errs := parseImpInfo(requestJson)
 	if len(errs) > 0 {
-		return nil, nil, nil, nil, nil, nil, errs
+		return nil, nil, nil, nil, nil, nil, "", errs
 	}
 	storedBidRequestId, hasStoredBidRequest, storedRequests, storedImps, errs := deps.getStoredRequests(ctx, requestJson, impInfo)
 	if len(errs) > 0 {
@@ -561,7 +566,7 @@ func (deps *endpointDeps) parseRequest(httpRequest *http.Request, labels *metric
 	if hasPayloadUpdatesAt(hooks.StageRawAuctionRequest.String(), hookExecutor.GetOutcomes()) {
 		impInfo, errs = parseImpInfo(requestJson)
 		if len(errs) > 0 {
-			return nil, nil, nil, nil, nil, nil, errs
+			return nil, nil, nil, nil, nil, nil, "", errs
 		}
 		storedBidRequestId, hasStoredBidRequest, storedRequests, storedImps, errs = deps.getStoredRequests(ctx, requestJson, impInfo)
 		if len(errs) > 0 {
@@ -569,6 +574,12 @@ func (deps *endpointDeps) parseRequest(httpRequest *http.Request, labels *metric
 		}
 	}
 
+	// Serve the version of the Stored Request data selected by the staged rollout of the account
+	storedRequestVersion = selectStoredRequestVersion(account, storedRequestUserKeyJSON(requestJson))
+	if storedRequestVersion, storedRequests, storedImps, errs = deps.fetchStoredRequestVersion(ctx, account, storedRequestVersion, storedRequests, storedImps); len(errs) > 0 {
+		return
+	}
+
 	// Fetch the Stored Request data and merge it into the HTTP request.
 	if requestJson, impExtInfoMap, errs = deps.processStoredRequests(requestJson, impInfo, storedRequests, storedImps, storedBidRequestId, hasStoredBidRequest); len(errs) > 0 {
 		return
@@ -617,7 +628,7 @@ func (deps *endpointDeps) parseRequest(httpRequest *http.Request, labels *metric
 	storedAuctionResponses, storedBidResponses, bidderImpReplaceImpId, errL = stored_responses.ProcessStoredResponses(ctx, req, deps.storedRespFetcher)
 	if len(errL) > 0 {
 		errs = append(errs, errL...)
-		return nil, nil, nil, nil, nil, nil, errs
+		return nil, nil, nil, nil, nil, nil, "", errs
 	}
 
 	hasStoredAuctionResponses := len(storedAuctionResponses) > 0
@@ -1213,6 +1224,28 @@ func validateTargeting(t *openrtb_ext.ExtRequestTargeting) error {
 		}
 	}
 
+	if err := validatePriceGranularityMap("bidderpricegranularity", t.BidderPriceGranularity); err != nil {
+		return err
+	}
+	if err := validatePriceGranularityMap("dealpricegranularity", t.DealPriceGranularity); err != nil {
+		return err
+	}
+	if err := validatePriceGranularityMap("currencypricegranularity", t.CurrencyPriceGranularity); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func validatePriceGranularityMap(field string, pgs map[string]*openrtb_ext.PriceGranularity) error {
+	for _, key := range slices.Sorted(maps.Keys(pgs)) {
+		if pgs[key] == nil {
+			return fmt.Errorf("Price granularity error: ext.prebid.targeting.%s.%s must be an object", field, key)
+		}
+		if err := validatePriceGranularity(pgs[key]); err != nil {
+			return fmt.Errorf("%s (ext.prebid.targeting.%s.%s)", err.Error(), field, key)
+		}
+	}
 	return nil
 }
 
@@ -1225,6 +1258,13 @@ func validatePriceGranularity(pg *openrtb_ext.PriceGranularity) error {
 		return fmt.Errorf("Price granularity error: precision of more than %d significant figures is not supported", openrtb_ext.MaxDecimalFigures)
 	}
 
+	switch pg.Rounding {
+	case "", openrtb_ext.PriceGranularityRoundingDown, openrtb_ext.PriceGranularityRoundingUp,
+		openrtb_ext.PriceGranularityRoundingNearest, openrtb_ext.PriceGranularityRoundingTimeSplit:
+	default:
+		return errors.New(`Price granularity error: rounding must be one of "down", "up", "nearest" or "timesplit"`)
+	}
+
 	var prevMax float64 = 0
 	for _, gr := range pg.Ranges {
 		if gr.Max <= prevMax {
@@ -1234,6 +1274,16 @@ func validatePriceGranularity(pg *openrtb_ext.PriceGranularity) error {
 		if gr.Increment <= 0.0 {
 			return errors.New("Price granularity error: increment must be a nonzero positive number")
 		}
+
+		switch gr.Scale {
+		case "", openrtb_ext.GranularityScaleLinear:
+		case openrtb_ext.GranularityScaleLog:
+			if gr.Increment <= 1.0 {
+				return errors.New("Price granularity error: increment of a log scale range must be greater than 1")
+			}
+		default:
+			return errors.New(`Price granularity error: scale must be "linear" or "log"`)
+		}
 		prevMax = gr.Max
 	}
 	return nil
@@ -1732,6 +1782,52 @@ func (deps *endpointDeps) getStoredRequests(ctx context.Context, requestJson []b
 	return storedBidRequestId, hasStoredBidRequest, storedRequests, storedImps, errs
 }
 
+// selectStoredRequestVersion selects the version of the Stored Request data the staged rollout of the account
+// serves to the user. The user is bucketed by its key so it is served the same version in all its auctions.
+func selectStoredRequestVersion(account *config.Account, userKey string) string {
+	return account.StoredRequestVersions.Select(stored_requests.VersionBucket(userKey))
+}
+
+// fetchStoredRequestVersion fetches the version of the Stored Request data selected for the auction and returns the
+// version served. The auction falls back to the current version of the account when the candidate version cannot be
+// fetched.
+func (deps *endpointDeps) fetchStoredRequestVersion(ctx context.Context, account *config.Account, version string, storedRequests, storedImps map[string]json.RawMessage) (string, map[string]json.RawMessage, map[string]json.RawMessage, []error) {
+	versionedRequests, versionedImps, errs := stored_requests.FetchVersion(ctx, deps.storedReqFetcher, version, storedRequests, storedImps)
+	if len(errs) == 0 || version == account.StoredRequestVersions.Current {
+		return version, versionedRequests, versionedImps, errs
+	}
+
+	logger.Warnf("Error fetching the version %s of the Stored Request data of account %s, falling back to the current version %q: %v", version, account.ID, account.StoredRequestVersions.Current, errors.Join(errs...))
+	deps.metricsEngine.RecordStoredRequestVersionFallback()
+	version = account.StoredRequestVersions.Current
+	versionedRequests, versionedImps, errs = stored_requests.FetchVersion(ctx, deps.storedReqFetcher, version, storedRequests, storedImps)
+	return version, versionedRequests, versionedImps, errs
+}
+
+// storedRequestUserKey identifies the user of the request, falling back to the request ID for unknown users.
+func storedRequestUserKey(req *openrtb2.BidRequest) string {
+	if req.User != nil && len(req.User.ID) > 0 {
+		return req.User.ID
+	}
+	if req.User != nil && len(req.User.BuyerUID) > 0 {
+		return req.User.BuyerUID
+	}
+	if req.Device != nil && len(req.Device.IFA) > 0 {
+		return req.Device.IFA
+	}
+	return req.ID
+}
+
+// storedRequestUserKeyJSON is storedRequestUserKey for the request before the Stored Request data is merged into it.
+func storedRequestUserKeyJSON(requestJson []byte) string {
+	for _, path := range [][]string{{"user", "id"}, {"user", "buyeruid"}, {"device", "ifa"}, {"id"}} {
+		if key, err := jsonparser.GetString(requestJson, path...); err == nil && len(key) > 0 {
+			return key
+		}
+	}
+	return ""
+}
+
 func (deps *endpointDeps) processStoredRequests(requestJson []byte, impInfo []ImpExtPrebidData, storedRequests map[string]json.RawMessage, storedImps map[string]json.RawMessage, storedBidRequestId string, hasStoredBidRequest bool) ([]byte, map[string]exchange.ImpExtInfo, []error) {
 	bidRequestID, err := getBidRequestID(storedRequests[storedBidRequestId])
 	if err != nil {
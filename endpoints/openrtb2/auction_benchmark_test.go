@@ -99,6 +99,8 @@ func BenchmarkOpenrtbEndpoint(b *testing.B) {
 		&adscert.NilSigner{},
 		macros.NewStringIndexBasedReplacer(),
 		nil,
+		nil,
+		nil,
 		singleFormatBidders,
 	)
 
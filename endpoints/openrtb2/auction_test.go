@@ -6,6 +6,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"io/fs"
 	"net"
@@ -37,6 +38,7 @@ import (
 	metricsConfig "github.com/prebid/prebid-server/v3/metrics/config"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/ortb"
+	"github.com/prebid/prebid-server/v3/stored_requests"
 	"github.com/prebid/prebid-server/v3/stored_requests/backends/empty_fetcher"
 	"github.com/prebid/prebid-server/v3/stored_responses"
 	"github.com/prebid/prebid-server/v3/util/iputil"
@@ -2154,6 +2156,39 @@ func TestValidateTargeting(t *testing.T) {
 			},
 			expectedError: errors.New("Price granularity error: range list must be ordered with increasing \"max\""),
 		},
+		{
+			name: "bidderpricegranularity-ok",
+			givenTargeting: &openrtb_ext.ExtRequestTargeting{
+				BidderPriceGranularity: map[string]*openrtb_ext.PriceGranularity{
+					"appnexus": {
+						Precision: ptrutil.ToPtr(2),
+						Ranges:    []openrtb_ext.GranularityRange{{Min: 0.0, Max: 10.0, Increment: 0.5}},
+					},
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name: "dealpricegranularity-invalid",
+			givenTargeting: &openrtb_ext.ExtRequestTargeting{
+				DealPriceGranularity: map[string]*openrtb_ext.PriceGranularity{
+					"deal-1": {
+						Precision: ptrutil.ToPtr(2),
+						Ranges:    []openrtb_ext.GranularityRange{{Min: 0.0, Max: 10.0, Increment: 0}},
+					},
+				},
+			},
+			expectedError: errors.New("Price granularity error: increment must be a nonzero positive number (ext.prebid.targeting.dealpricegranularity.deal-1)"),
+		},
+		{
+			name: "currencypricegranularity-nil-entry",
+			givenTargeting: &openrtb_ext.ExtRequestTargeting{
+				CurrencyPriceGranularity: map[string]*openrtb_ext.PriceGranularity{
+					"EUR": nil,
+				},
+			},
+			expectedError: errors.New("Price granularity error: ext.prebid.targeting.currencypricegranularity.EUR must be an object"),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -2211,6 +2246,46 @@ func TestValidatePriceGranularity(t *testing.T) {
 			},
 			expectedError: errors.New("Price granularity error: increment must be a nonzero positive number"),
 		},
+		{
+			description: "price granularity invalid rounding",
+			givenPriceGranularity: &openrtb_ext.PriceGranularity{
+				Precision: ptrutil.ToPtr(2),
+				Rounding:  "sideways",
+			},
+			expectedError: errors.New(`Price granularity error: rounding must be one of "down", "up", "nearest" or "timesplit"`),
+		},
+		{
+			description: "price granularity invalid scale",
+			givenPriceGranularity: &openrtb_ext.PriceGranularity{
+				Precision: ptrutil.ToPtr(2),
+				Ranges: []openrtb_ext.GranularityRange{
+					{Min: 0.0, Max: 1.0, Increment: 0.1, Scale: "exp"},
+				},
+			},
+			expectedError: errors.New(`Price granularity error: scale must be "linear" or "log"`),
+		},
+		{
+			description: "price granularity log scale increment not above 1",
+			givenPriceGranularity: &openrtb_ext.PriceGranularity{
+				Precision: ptrutil.ToPtr(2),
+				Ranges: []openrtb_ext.GranularityRange{
+					{Min: 0.0, Max: 1.0, Increment: 0.5, Scale: "log"},
+				},
+			},
+			expectedError: errors.New("Price granularity error: increment of a log scale range must be greater than 1"),
+		},
+		{
+			description: "price granularity with log scale and rounding correct",
+			givenPriceGranularity: &openrtb_ext.PriceGranularity{
+				Precision: ptrutil.ToPtr(2),
+				Rounding:  "nearest",
+				Ranges: []openrtb_ext.GranularityRange{
+					{Min: 0.0, Max: 5.0, Increment: 0.1},
+					{Min: 5.0, Max: 80.0, Increment: 2, Scale: "log"},
+				},
+			},
+			expectedError: nil,
+		},
 		{
 			description: "price granularity correct",
 			givenPriceGranularity: &openrtb_ext.PriceGranularity{
@@ -3777,7 +3852,7 @@ func TestParseRequestParseImpInfoError(t *testing.T) {
 
 	req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(reqBody))
 
-	resReq, impExtInfoMap, _, _, _, _, errL := deps.parseRequest(req, &metrics.Labels{}, hookExecutor)
+	resReq, impExtInfoMap, _, _, _, _, _, errL := deps.parseRequest(req, &metrics.Labels{}, hookExecutor)
 
 	assert.Nil(t, resReq, "Result request should be nil due to incorrect imp")
 	assert.Nil(t, impExtInfoMap, "Impression info map should be nil due to incorrect imp")
@@ -3785,6 +3860,207 @@ func TestParseRequestParseImpInfoError(t *testing.T) {
 	assert.Contains(t, errL[0].Error(), "cannot unmarshal openrtb_ext.Options.EchoVideoAttrs", "Incorrect error message")
 }
 
+func TestParseRequestStoredRequestVersion(t *testing.T) {
+	reqBody := `{"id":"req-1","site":{"page":"prebid.org","publisher":{"id":"acc-1"}},"imp":[{"ext":{"prebid":{"storedrequest":{"id":"imp-1"}}}}]}`
+	storedData := map[string]json.RawMessage{
+		"imp-1":    json.RawMessage(`{"id":"imp-1","banner":{"format":[{"w":300,"h":250}]},"ext":{"appnexus":{"placementId":1}}}`),
+		"imp-1@v2": json.RawMessage(`{"id":"imp-1","banner":{"format":[{"w":728,"h":90}]},"ext":{"appnexus":{"placementId":1}}}`),
+	}
+
+	testCases := []struct {
+		name            string
+		accountConfig   string
+		expectedVersion string
+		expectedFormat  openrtb2.Format
+	}{
+		{
+			name:            "no_rollout",
+			accountConfig:   `{}`,
+			expectedVersion: "",
+			expectedFormat:  openrtb2.Format{W: 300, H: 250},
+		},
+		{
+			name:            "candidate_version",
+			accountConfig:   `{"stored_request_versions":{"candidate":"v2","candidate_percent":100}}`,
+			expectedVersion: "v2",
+			expectedFormat:  openrtb2.Format{W: 728, H: 90},
+		},
+		{
+			name:            "candidate_version_rolled_back",
+			accountConfig:   `{"stored_request_versions":{"candidate":"v2","candidate_percent":100,"rollback":true}}`,
+			expectedVersion: "",
+			expectedFormat:  openrtb2.Format{W: 300, H: 250},
+		},
+		{
+			name:            "version_without_data",
+			accountConfig:   `{"stored_request_versions":{"candidate":"v3","candidate_percent":100}}`,
+			expectedVersion: "v3",
+			expectedFormat:  openrtb2.Format{W: 300, H: 250},
+		},
+		{
+			name:            "candidate_version_fetch_error_falls_back_to_untagged_current",
+			accountConfig:   `{"stored_request_versions":{"candidate":"broken","candidate_percent":100}}`,
+			expectedVersion: "",
+			expectedFormat:  openrtb2.Format{W: 300, H: 250},
+		},
+		{
+			name:            "candidate_version_fetch_error_falls_back_to_current",
+			accountConfig:   `{"stored_request_versions":{"current":"v2","candidate":"broken","candidate_percent":100}}`,
+			expectedVersion: "v2",
+			expectedFormat:  openrtb2.Format{W: 728, H: 90},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			deps := &endpointDeps{
+				fakeUUIDGenerator{},
+				&warningsCheckExchange{},
+				ortb.NewRequestValidator(openrtb_ext.BuildBidderMap(), map[string]string{}, mockBidderParamValidator{}),
+				&mockVersionedStoredReqFetcher{data: storedData, errorVersion: "broken"},
+				empty_fetcher.EmptyFetcher{},
+				&mockAccountFetcher{data: map[string]json.RawMessage{"acc-1": json.RawMessage(test.accountConfig)}},
+				&config.Configuration{MaxRequestSize: int64(len(reqBody))},
+				&metricsConfig.NilMetricsEngine{},
+				analyticsBuild.New(&config.Analytics{}),
+				map[string]string{},
+				false,
+				[]byte{},
+				openrtb_ext.BuildBidderMap(),
+				nil,
+				nil,
+				hardcodedResponseIPValidator{response: true},
+				empty_fetcher.EmptyFetcher{},
+				hooks.EmptyPlanBuilder{},
+				nil,
+				openrtb_ext.NormalizeBidderName,
+			}
+
+			hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAuction, deps.metricsEngine)
+			req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(reqBody))
+
+			resReq, _, _, _, _, _, storedRequestVersion, errL := deps.parseRequest(req, &metrics.Labels{}, hookExecutor)
+
+			assert.False(t, errortypes.ContainsFatalError(errL), "unexpected errors: %v", errL)
+			assert.Equal(t, test.expectedVersion, storedRequestVersion)
+			if assert.Len(t, resReq.Imp, 1) && assert.NotNil(t, resReq.Imp[0].Banner) {
+				assert.Equal(t, []openrtb2.Format{test.expectedFormat}, resReq.Imp[0].Banner.Format)
+			}
+		})
+	}
+}
+
+func TestFetchStoredRequestVersion(t *testing.T) {
+	storedImps := map[string]json.RawMessage{"imp-1": json.RawMessage(`{"id":"imp-1"}`)}
+	fetcher := &mockVersionedStoredReqFetcher{
+		data:         map[string]json.RawMessage{"imp-1@v2": json.RawMessage(`{"id":"imp-1","tagid":"v2"}`)},
+		errorVersion: "broken",
+	}
+	account := &config.Account{ID: "acc-1", StoredRequestVersions: config.AccountStoredRequestVersions{Current: "v2", Candidate: "broken"}}
+
+	metricsMock := &metrics.MetricsEngineMock{}
+	metricsMock.On("RecordStoredRequestVersionFallback").Once()
+	deps := &endpointDeps{storedReqFetcher: fetcher, metricsEngine: metricsMock}
+
+	version, _, imps, errs := deps.fetchStoredRequestVersion(context.Background(), account, "broken", nil, storedImps)
+	assert.Empty(t, errs)
+	assert.Equal(t, "v2", version, "the current version must be served when the candidate version cannot be fetched")
+	assert.JSONEq(t, `{"id":"imp-1","tagid":"v2"}`, string(imps["imp-1"]))
+	metricsMock.AssertExpectations(t)
+
+	// the auction fails as without a rollout when the current version cannot be fetched
+	account.StoredRequestVersions.Current = "broken"
+	version, _, imps, errs = deps.fetchStoredRequestVersion(context.Background(), account, "broken", nil, storedImps)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "broken", version)
+	assert.Equal(t, storedImps, imps)
+	metricsMock.AssertNumberOfCalls(t, "RecordStoredRequestVersionFallback", 1)
+}
+
+func TestStoredRequestUserKey(t *testing.T) {
+	testCases := []struct {
+		name        string
+		request     string
+		expectedKey string
+	}{
+		{
+			name:        "user_id",
+			request:     `{"id":"req-1","user":{"id":"user-1","buyeruid":"buyer-1"},"device":{"ifa":"ifa-1"}}`,
+			expectedKey: "user-1",
+		},
+		{
+			name:        "buyeruid",
+			request:     `{"id":"req-1","user":{"buyeruid":"buyer-1"},"device":{"ifa":"ifa-1"}}`,
+			expectedKey: "buyer-1",
+		},
+		{
+			name:        "device_ifa",
+			request:     `{"id":"req-1","device":{"ifa":"ifa-1"}}`,
+			expectedKey: "ifa-1",
+		},
+		{
+			name:        "unknown_user",
+			request:     `{"id":"req-1","device":{"ua":"ua"}}`,
+			expectedKey: "req-1",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			var req openrtb2.BidRequest
+			assert.NoError(t, jsonutil.UnmarshalValid([]byte(test.request), &req))
+
+			assert.Equal(t, test.expectedKey, storedRequestUserKey(&req))
+			assert.Equal(t, test.expectedKey, storedRequestUserKeyJSON([]byte(test.request)))
+		})
+	}
+}
+
+func TestSelectStoredRequestVersion(t *testing.T) {
+	account := &config.Account{StoredRequestVersions: config.AccountStoredRequestVersions{Current: "v1", Candidate: "v2", CandidatePercent: 50}}
+
+	versions := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		userKey := fmt.Sprintf("user-%d", i)
+		version := selectStoredRequestVersion(account, userKey)
+		assert.Equal(t, version, selectStoredRequestVersion(account, userKey), "a user must be served the same version in all its auctions")
+		versions[version]++
+	}
+	assert.InDelta(t, 500, versions["v2"], 100, "the candidate version should serve about half of the users")
+	assert.Equal(t, 1000, versions["v1"]+versions["v2"])
+}
+
+// mockVersionedStoredReqFetcher returns the Stored Request data it holds for the requested IDs
+type mockVersionedStoredReqFetcher struct {
+	data map[string]json.RawMessage
+	// errorVersion is the version the fetcher fails to fetch
+	errorVersion string
+}
+
+func (cf *mockVersionedStoredReqFetcher) FetchRequests(ctx context.Context, requestIDs []string, impIDs []string) (requestData map[string]json.RawMessage, impData map[string]json.RawMessage, errs []error) {
+	requestData, errs = cf.fetch("Request", requestIDs, errs)
+	impData, errs = cf.fetch("Imp", impIDs, errs)
+	return
+}
+
+func (cf *mockVersionedStoredReqFetcher) fetch(dataType string, ids []string, errs []error) (map[string]json.RawMessage, []error) {
+	data := make(map[string]json.RawMessage, len(ids))
+	for _, id := range ids {
+		if value, ok := cf.data[id]; ok {
+			data[id] = value
+		} else if len(cf.errorVersion) > 0 && strings.HasSuffix(id, stored_requests.VersionSeparator+cf.errorVersion) {
+			errs = append(errs, errors.New("stored data backend unavailable"))
+		} else {
+			errs = append(errs, stored_requests.NotFoundError{ID: id, DataType: dataType})
+		}
+	}
+	return data, errs
+}
+
+func (cf *mockVersionedStoredReqFetcher) FetchResponses(ctx context.Context, ids []string) (data map[string]json.RawMessage, errs []error) {
+	return nil, nil
+}
+
 func TestParseGzipedRequest(t *testing.T) {
 	testCases :=
 		[]struct {
@@ -3871,7 +4147,7 @@ func TestParseGzipedRequest(t *testing.T) {
 		} else {
 			req = httptest.NewRequest("POST", "/openrtb2/auction", bytes.NewReader(reqBody))
 		}
-		resReq, impExtInfoMap, _, _, _, _, errL := deps.parseRequest(req, &metrics.Labels{}, hookExecutor)
+		resReq, impExtInfoMap, _, _, _, _, _, errL := deps.parseRequest(req, &metrics.Labels{}, hookExecutor)
 
 		if test.expectedErr == "" {
 			assert.Nil(t, errL, "Error list should be nil", test.desc)
@@ -4041,7 +4317,7 @@ func TestParseRequestMergeBidderParams(t *testing.T) {
 
 			req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(test.givenRequestBody))
 
-			resReq, _, _, _, _, _, errL := deps.parseRequest(req, &metrics.Labels{}, hookExecutor)
+			resReq, _, _, _, _, _, _, errL := deps.parseRequest(req, &metrics.Labels{}, hookExecutor)
 
 			assert.NoError(t, resReq.RebuildRequest())
 
@@ -4147,7 +4423,7 @@ func TestParseRequestStoredResponses(t *testing.T) {
 
 			req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(test.givenRequestBody))
 
-			_, _, storedResponses, _, _, _, errL := deps.parseRequest(req, &metrics.Labels{}, hookExecutor)
+			_, _, storedResponses, _, _, _, _, errL := deps.parseRequest(req, &metrics.Labels{}, hookExecutor)
 
 			if test.expectedErrorCount == 0 {
 				assert.Equal(t, test.expectedStoredResponses, storedResponses, "stored responses should match")
@@ -4259,7 +4535,7 @@ func TestParseRequestStoredBidResponses(t *testing.T) {
 			hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAuction, deps.metricsEngine)
 
 			req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(test.givenRequestBody))
-			_, _, _, storedBidResponses, _, _, errL := deps.parseRequest(req, &metrics.Labels{}, hookExecutor)
+			_, _, _, storedBidResponses, _, _, _, errL := deps.parseRequest(req, &metrics.Labels{}, hookExecutor)
 			if test.expectedErrorCount == 0 {
 				assert.Empty(t, errL)
 				assert.Equal(t, test.expectedStoredBidResponses, storedBidResponses, "stored responses should match")
@@ -5320,7 +5596,7 @@ func TestParseRequestMultiBid(t *testing.T) {
 
 			req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(test.givenRequestBody))
 
-			resReq, _, _, _, _, _, errL := deps.parseRequest(req, &metrics.Labels{}, hookExecutor)
+			resReq, _, _, _, _, _, _, errL := deps.parseRequest(req, &metrics.Labels{}, hookExecutor)
 
 			assert.NoError(t, resReq.RebuildRequest())
 
@@ -143,7 +143,7 @@ func (deps *ctvEndpointDeps) CTVAuctionEndpoint(w http.ResponseWriter, r *http.R
 	hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointCtv, deps.metricsEngine)
 
 	//Parse ORTB Request and do Standard Validation
-	reqWrapper, _, _, _, _, _, errL = deps.parseRequest(r, &deps.labels, hookExecutor)
+	reqWrapper, _, _, _, _, _, _, errL = deps.parseRequest(r, &deps.labels, hookExecutor)
 	if errortypes.ContainsFatalError(errL) && writeError(errL, w, &deps.labels) {
 		return
 	}
@@ -253,6 +253,8 @@ func (deps *ctvEndpointDeps) CTVAuctionEndpoint(w http.ResponseWriter, r *http.R
 
 	response = auctionResponse.BidResponse
 	seatNonBid.Append(auctionResponse.SeatNonBid)
+	ao.PrivacyAudit = auctionResponse.PrivacyAudit
+	ao.DSA = auctionResponse.DSA
 	seatNonBid.Append(getNonBidsFromStageOutcomes(hookExecutor.GetOutcomes())) // append seatNonBids available in hook-stage-outcomes
 	ao.SeatNonBid = seatNonBid.Get()
 	// add seatNonBids in response.Ext based on 'returnallbidstatus' flag
@@ -241,6 +241,8 @@ func testCTVExchange(testCfg *ctvtestConfigValues, adapterMap map[openrtb_ext.Bi
 		macros.NewStringIndexBasedReplacer(),
 		&floors.PriceFloorFetcher{},
 		nil,
+		nil,
+		nil,
 	)
 
 	testExchange = &exchangeTestWrapper{
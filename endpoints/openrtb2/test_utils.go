@@ -1284,6 +1284,8 @@ func buildTestExchange(testCfg *testConfigValues, adapterMap map[openrtb_ext.Bid
 		&adscert.NilSigner{},
 		macros.NewStringIndexBasedReplacer(),
 		nil,
+		nil,
+		nil,
 		singleFormatBidders,
 	)
 
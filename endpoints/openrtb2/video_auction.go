@@ -179,7 +179,6 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 	}
 	labels.RequestSize = len(requestJson)
 
-	resolvedRequest := requestJson
 	if debugLog.DebugEnabledOrOverridden {
 		debugLog.Data.Request = string(requestJson)
 		if headerBytes, err := jsonutil.Marshal(r.Header); err == nil {
@@ -189,85 +188,9 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 		}
 	}
 
-	//load additional data - stored simplified req
-	storedRequestId, err := getVideoStoredRequestId(requestJson)
-
-	if err != nil {
-		if deps.cfg.VideoStoredRequestRequired {
-			handleError(&labels, w, []error{err}, &vo, &debugLog)
-			return
-		}
-	} else {
-		storedRequest, errs := deps.loadStoredVideoRequest(context.Background(), storedRequestId)
-		if len(errs) > 0 {
-			handleError(&labels, w, errs, &vo, &debugLog)
-			return
-		}
-
-		//merge incoming req with stored video req
-		resolvedRequest, err = jsonpatch.MergePatch(storedRequest, requestJson)
-		if err != nil {
-			handleError(&labels, w, []error{err}, &vo, &debugLog)
-			return
-		}
-	}
-	//unmarshal and validate combined result
-	videoBidReq, errL, podErrors := deps.parseVideoRequest(resolvedRequest, r.Header)
-	if len(errL) > 0 {
-		handleError(&labels, w, errL, &vo, &debugLog)
-		return
-	}
-
+	videoBidReq, bidReqWrapper, podErrors, errL := deps.loadVideoRequest(requestJson, r.Header, debugLog.DebugEnabledOrOverridden, "")
 	vo.VideoRequest = videoBidReq
-
-	var bidReq = &openrtb2.BidRequest{}
-	if deps.defaultRequest {
-		if err := jsonutil.UnmarshalValid(deps.defReqJSON, bidReq); err != nil {
-			err = fmt.Errorf("Invalid JSON in Default Request Settings: %s", err)
-			handleError(&labels, w, []error{err}, &vo, &debugLog)
-			return
-		}
-	}
-
-	//create full open rtb req from full video request
-	mergeData(videoBidReq, bidReq)
-	// If debug query param is set, force the response to enable test flag
-	if debugLog.DebugEnabledOrOverridden {
-		bidReq.Test = 1
-	}
-
-	initialPodNumber := len(videoBidReq.PodConfig.Pods)
-	if len(podErrors) > 0 {
-		//remove incorrect pods
-		videoBidReq = cleanupVideoBidRequest(videoBidReq, podErrors)
-	}
-
-	//create impressions array
-	imps, podErrors := deps.createImpressions(videoBidReq, podErrors)
-
-	if len(podErrors) == initialPodNumber {
-		resPodErr := make([]string, 0)
-		for _, podEr := range podErrors {
-			resPodErr = append(resPodErr, strings.Join(podEr.ErrMsgs, ", "))
-		}
-		err := fmt.Errorf("all pods are incorrect: %s", strings.Join(resPodErr, "; "))
-		errL = append(errL, err)
-		handleError(&labels, w, errL, &vo, &debugLog)
-		return
-	}
-
-	bidReq.Imp = imps
-	bidReq.ID = "bid_id" //TODO: look at prebid.js
-
-	// all code after this line should use the bidReqWrapper instead of bidReq directly
-	bidReqWrapper := &openrtb_ext.RequestWrapper{BidRequest: bidReq}
-
-	if err := openrtb_ext.ConvertUpTo26(bidReqWrapper); err != nil {
-		handleError(&labels, w, []error{err}, &vo, &debugLog)
-		return
-	}
-
-	if err := ortb.SetDefaults(bidReqWrapper, deps.cfg.TmaxDefault); err != nil {
+	if len(errL) > 0 {
 		handleError(&labels, w, errL, &vo, &debugLog)
 		return
 	}
@@ -305,6 +228,17 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	// The account rolling out a version of the Stored Request data is only known once the untagged data is loaded
+	if storedRequestVersion := selectStoredRequestVersion(account, storedRequestUserKey(bidReqWrapper.BidRequest)); len(storedRequestVersion) > 0 {
+		videoBidReq, bidReqWrapper, podErrors, errL = deps.loadVideoRequest(requestJson, r.Header, debugLog.DebugEnabledOrOverridden, storedRequestVersion)
+		vo.VideoRequest = videoBidReq
+		if len(errL) > 0 {
+			handleError(&labels, w, errL, &vo, &debugLog)
+			return
+		}
+		vo.StoredRequestVersion = storedRequestVersion
+	}
+
 	tcf2Config, gdprSignal, gdprEnforced, gdprErrs := deps.processGDPR(bidReqWrapper, account.GDPR, labels.RType)
 	errL = append(errL, gdprErrs...)
 
@@ -354,6 +288,8 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 	if auctionResponse != nil {
 		response = auctionResponse.BidResponse
 		seatNonBid.Append(auctionResponse.SeatNonBid)
+		vo.PrivacyAudit = auctionResponse.PrivacyAudit
+		vo.DSA = auctionResponse.DSA
 	}
 	vo.Response = response
 	vo.SeatNonBid = seatNonBid.Get()
@@ -370,7 +306,7 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 		handleError(&labels, w, errL, &vo, &debugLog)
 		return
 	}
-	if bidReq.Test == 1 {
+	if bidReqWrapper.Test == 1 {
 		err = setSeatNonBidRaw(bidReqWrapper, response, vo.SeatNonBid)
 		if err != nil {
 			logger.Errorf("Error setting seat non-bid: %v", err)
@@ -406,6 +342,85 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 	w.Write(resp)
 }
 
+// loadVideoRequest builds the OpenRTB request of a video request from the given version of its Stored Request data.
+func (deps *endpointDeps) loadVideoRequest(requestJson []byte, header http.Header, test bool, storedRequestVersion string) (videoBidReq *openrtb_ext.BidRequestVideo, bidReqWrapper *openrtb_ext.RequestWrapper, podErrors []PodError, errs []error) {
+	resolvedRequest := requestJson
+
+	//load additional data - stored simplified req
+	storedRequestId, err := getVideoStoredRequestId(requestJson)
+
+	if err != nil {
+		if deps.cfg.VideoStoredRequestRequired {
+			return nil, nil, nil, []error{err}
+		}
+	} else {
+		storedRequest, errs := deps.loadStoredVideoRequest(context.Background(), storedRequestId, storedRequestVersion)
+		if len(errs) > 0 {
+			return nil, nil, nil, errs
+		}
+
+		//merge incoming req with stored video req
+		resolvedRequest, err = jsonpatch.MergePatch(storedRequest, requestJson)
+		if err != nil {
+			return nil, nil, nil, []error{err}
+		}
+	}
+	//unmarshal and validate combined result
+	videoBidReq, errs, podErrors = deps.parseVideoRequest(resolvedRequest, header)
+	if len(errs) > 0 {
+		return nil, nil, nil, errs
+	}
+
+	var bidReq = &openrtb2.BidRequest{}
+	if deps.defaultRequest {
+		if err := jsonutil.UnmarshalValid(deps.defReqJSON, bidReq); err != nil {
+			err = fmt.Errorf("Invalid JSON in Default Request Settings: %s", err)
+			return videoBidReq, nil, nil, []error{err}
+		}
+	}
+
+	//create full open rtb req from full video request
+	mergeData(videoBidReq, bidReq)
+	// If debug query param is set, force the response to enable test flag
+	if test {
+		bidReq.Test = 1
+	}
+
+	initialPodNumber := len(videoBidReq.PodConfig.Pods)
+	if len(podErrors) > 0 {
+		//remove incorrect pods
+		videoBidReq = cleanupVideoBidRequest(videoBidReq, podErrors)
+	}
+
+	//create impressions array
+	imps, podErrors := deps.createImpressions(videoBidReq, podErrors, storedRequestVersion)
+
+	if len(podErrors) == initialPodNumber {
+		resPodErr := make([]string, 0)
+		for _, podEr := range podErrors {
+			resPodErr = append(resPodErr, strings.Join(podEr.ErrMsgs, ", "))
+		}
+		err := fmt.Errorf("all pods are incorrect: %s", strings.Join(resPodErr, "; "))
+		return videoBidReq, nil, nil, []error{err}
+	}
+
+	bidReq.Imp = imps
+	bidReq.ID = "bid_id" //TODO: look at prebid.js
+
+	// all code after this line should use the bidReqWrapper instead of bidReq directly
+	bidReqWrapper = &openrtb_ext.RequestWrapper{BidRequest: bidReq}
+
+	if err := openrtb_ext.ConvertUpTo26(bidReqWrapper); err != nil {
+		return videoBidReq, nil, nil, []error{err}
+	}
+
+	if err := ortb.SetDefaults(bidReqWrapper, deps.cfg.TmaxDefault); err != nil {
+		return videoBidReq, nil, nil, []error{err}
+	}
+
+	return videoBidReq, bidReqWrapper, podErrors, nil
+}
+
 func cleanupVideoBidRequest(videoReq *openrtb_ext.BidRequestVideo, podErrors []PodError) *openrtb_ext.BidRequestVideo {
 	for i := len(podErrors) - 1; i >= 0; i-- {
 		videoReq.PodConfig.Pods = append(videoReq.PodConfig.Pods[:podErrors[i].PodIndex], videoReq.PodConfig.Pods[podErrors[i].PodIndex+1:]...)
@@ -447,7 +462,7 @@ func handleError(labels *metrics.Labels, w http.ResponseWriter, errL []error, vo
 	vo.Errors = append(vo.Errors, errL...)
 }
 
-func (deps *endpointDeps) createImpressions(videoReq *openrtb_ext.BidRequestVideo, podErrors []PodError) ([]openrtb2.Imp, []PodError) {
+func (deps *endpointDeps) createImpressions(videoReq *openrtb_ext.BidRequestVideo, podErrors []PodError, storedRequestVersion string) ([]openrtb2.Imp, []PodError) {
 	videoDur := videoReq.PodConfig.DurationRangeSec
 	minDuration, maxDuration := minMax(videoDur)
 	reqExactDur := videoReq.PodConfig.RequireExactDuration
@@ -458,7 +473,7 @@ func (deps *endpointDeps) createImpressions(videoReq *openrtb_ext.BidRequestVide
 
 		//load stored impression
 		storedImpressionId := string(pod.ConfigId)
-		storedImp, errs := deps.loadStoredImp(storedImpressionId)
+		storedImp, errs := deps.loadStoredImp(storedImpressionId, storedRequestVersion)
 		if errs != nil {
 			err := fmt.Sprintf("unable to load configid %s, Pod id: %d", storedImpressionId, pod.PodId)
 			podErr := PodError{}
@@ -516,7 +531,7 @@ func createImpressionTemplate(imp openrtb2.Imp, video *openrtb2.Video) openrtb2.
 	return imp
 }
 
-func (deps *endpointDeps) loadStoredImp(storedImpId string) (openrtb2.Imp, []error) {
+func (deps *endpointDeps) loadStoredImp(storedImpId string, storedRequestVersion string) (openrtb2.Imp, []error) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(deps.cfg.StoredRequestsTimeout)*time.Millisecond)
 	defer cancel()
 
@@ -525,6 +540,9 @@ func (deps *endpointDeps) loadStoredImp(storedImpId string) (openrtb2.Imp, []err
 	if err != nil {
 		return impr, err
 	}
+	if _, imp, err = stored_requests.FetchVersion(ctx, deps.storedReqFetcher, storedRequestVersion, nil, imp); err != nil {
+		return impr, err
+	}
 
 	if err := jsonutil.UnmarshalValid(imp[storedImpId], &impr); err != nil {
 		return impr, []error{err}
@@ -636,8 +654,11 @@ func findAdPod(podInd int64, pods []*openrtb_ext.AdPod) *openrtb_ext.AdPod {
 	return nil
 }
 
-func (deps *endpointDeps) loadStoredVideoRequest(ctx context.Context, storedRequestId string) ([]byte, []error) {
+func (deps *endpointDeps) loadStoredVideoRequest(ctx context.Context, storedRequestId string, storedRequestVersion string) ([]byte, []error) {
 	storedRequests, _, errs := deps.videoFetcher.FetchRequests(ctx, []string{storedRequestId}, []string{})
+	if len(errs) == 0 {
+		storedRequests, _, errs = stored_requests.FetchVersion(ctx, deps.videoFetcher, storedRequestVersion, storedRequests, nil)
+	}
 	jsonString := storedRequests[storedRequestId]
 	return jsonString, errs
 }
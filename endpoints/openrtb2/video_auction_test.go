@@ -68,6 +68,32 @@ func TestVideoEndpointImpressionsNumber(t *testing.T) {
 	assert.Equal(t, "ABC_123", resp.AdPods[0].Targeting[0].HbDeal, "If DealID exists in bid response, hb_deal targeting needs to be added to resp")
 }
 
+func TestVideoEndpointStoredRequestVersion(t *testing.T) {
+	storedImps := map[string]json.RawMessage{
+		"fba10607-0c12-43d1-ad07-b8a513bc75d6@v2": json.RawMessage(`{"ext": {"appnexus": {"placementId": 1}}}`),
+	}
+	for id, imp := range testVideoStoredImpData {
+		storedImps[id] = imp
+	}
+
+	ex := &mockExchangeVideo{}
+	reqBody := readVideoTestFile(t, "sample-requests/video/video_valid_sample.json")
+	req := httptest.NewRequest("POST", "/openrtb2/video", strings.NewReader(reqBody))
+	recorder := httptest.NewRecorder()
+
+	deps := mockDeps(t, ex)
+	deps.storedReqFetcher = &mockVersionedStoredReqFetcher{data: storedImps}
+	deps.cfg.AccountDefaults.StoredRequestVersions = config.AccountStoredRequestVersions{Candidate: "v2", CandidatePercent: 100}
+	deps.VideoAuctionEndpoint(recorder, req, nil)
+
+	if ex.lastRequest == nil {
+		t.Fatalf("The request never made it into the Exchange.")
+	}
+	assert.Len(t, ex.lastRequest.Imp, 11, "Incorrect number of impressions in request")
+	assert.JSONEq(t, `{"prebid": {"bidder": {"appnexus": {"placementId": 1}}}}`, string(ex.lastRequest.Imp[0].Ext), "The imps of the first pod should come from the candidate version")
+	assert.JSONEq(t, `{"prebid": {"bidder": {"appnexus": {"placementId": 15016213}}}}`, string(ex.lastRequest.Imp[len(ex.lastRequest.Imp)-1].Ext), "The imps without a candidate version should be untagged")
+}
+
 func TestVideoEndpointImpressionsDuration(t *testing.T) {
 	ex := &mockExchangeVideo{}
 	reqBody := readVideoTestFile(t, "sample-requests/video/video_valid_sample_different_durations.json")
@@ -108,6 +108,7 @@ func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]use
 
 		policies := privacy.Policies{
 			GPPSID: gppSID,
+			GPP:    query.Get("gpp"),
 		}
 
 		userSyncActivityAllowed := activityControl.Allow(privacy.ActivitySyncUser,
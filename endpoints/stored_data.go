@@ -0,0 +1,208 @@
+package endpoints
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/account"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/logger"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/ortb"
+	"github.com/prebid/prebid-server/v3/stored_requests"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// StoredDataEndpoint is the path the stored data endpoint handles, followed by <type>/<id>
+const StoredDataEndpoint = "/stored_data/"
+
+// storedDataTypes maps the types of the endpoint paths to the stored data types, they match the keys of the
+// cache events of stored_requests/events
+var storedDataTypes = map[string]stored_requests.DataType{
+	"requests":  stored_requests.RequestData,
+	"imps":      stored_requests.ImpData,
+	"responses": stored_requests.ResponseData,
+	"accounts":  stored_requests.AccountData,
+}
+
+type storedDataEndpoint struct {
+	writers          map[stored_requests.DataType]stored_requests.Writer
+	requestValidator ortb.RequestValidator
+	maxRequestSize   int64
+	timeout          time.Duration
+}
+
+// NewStoredDataEndpoint manages the Stored Requests, Imps, Responses and Accounts kept in writable backends:
+//
+//	GET    /stored_data/<type>/<id> returns the stored data
+//	PUT    /stored_data/<type>/<id> validates the body and saves it
+//	DELETE /stored_data/<type>/<id> deletes the stored data
+//
+// where <type> is one of requests, imps, responses or accounts. The types without a writable backend are not found.
+// Stored Requests and Imps are validated as OpenRTB, including the bidder params, and Accounts as account config.
+// Bodies larger than maxRequestSize bytes are rejected.
+func NewStoredDataEndpoint(writers map[stored_requests.DataType]stored_requests.Writer, requestValidator ortb.RequestValidator, maxRequestSize int64, timeout time.Duration) http.HandlerFunc {
+	endpoint := &storedDataEndpoint{
+		writers:          writers,
+		requestValidator: requestValidator,
+		maxRequestSize:   maxRequestSize,
+		timeout:          timeout,
+	}
+	return endpoint.handle
+}
+
+func (e *storedDataEndpoint) handle(w http.ResponseWriter, r *http.Request) {
+	dataType, id, ok := parseStoredDataPath(r.URL.Path)
+	if !ok {
+		http.Error(w, fmt.Sprintf("Invalid path, expected %s<type>/<id>", StoredDataEndpoint), http.StatusNotFound)
+		return
+	}
+	writer, ok := e.writers[dataType]
+	if !ok {
+		http.Error(w, fmt.Sprintf("Stored %s data is not kept in a writable backend", dataType), http.StatusNotFound)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), e.timeout)
+	defer cancel()
+
+	switch r.Method {
+	case http.MethodGet:
+		data, err := writer.Read(ctx, dataType, id)
+		if err != nil {
+			writeStoredDataError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	case http.MethodPut:
+		data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, e.maxRequestSize))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				http.Error(w, fmt.Sprintf("Stored data size exceeded max size of %d bytes", maxBytesErr.Limit), http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, "Missing stored data", http.StatusBadRequest)
+			return
+		}
+		if err := e.validate(dataType, data); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid Stored %s: %v", dataType, err), http.StatusBadRequest)
+			return
+		}
+		if err := writer.Save(ctx, dataType, id, data); err != nil {
+			writeStoredDataError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		if err := writer.Delete(ctx, dataType, id); err != nil {
+			writeStoredDataError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func parseStoredDataPath(path string) (stored_requests.DataType, string, bool) {
+	typeName, id, found := strings.Cut(strings.TrimPrefix(path, StoredDataEndpoint), "/")
+	if !found || len(id) == 0 || strings.Contains(id, "/") {
+		return "", "", false
+	}
+	dataType, ok := storedDataTypes[typeName]
+	return dataType, id, ok
+}
+
+func writeStoredDataError(w http.ResponseWriter, err error) {
+	var notFoundErr stored_requests.NotFoundError
+	if errors.As(err, &notFoundErr) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	logger.Errorf("%s Failed to access the stored data backend: %v", StoredDataEndpoint, err)
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+func (e *storedDataEndpoint) validate(dataType stored_requests.DataType, data []byte) error {
+	switch dataType {
+	case stored_requests.RequestData:
+		return e.validateRequest(data)
+	case stored_requests.ImpData:
+		var imp openrtb2.Imp
+		if err := jsonutil.UnmarshalValid(data, &imp); err != nil {
+			return err
+		}
+		return e.validateImp(&imp, 0, nil)
+	case stored_requests.ResponseData:
+		return validateStoredResponse(data)
+	case stored_requests.AccountData:
+		return validateAccount(data)
+	}
+	return nil
+}
+
+// validateRequest validates the imps of a Stored Request, the other fields are only checked to be valid OpenRTB
+// as Stored Requests are partial bid requests completed by the HTTP requests
+func (e *storedDataEndpoint) validateRequest(data []byte) error {
+	req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{}}
+	if err := jsonutil.UnmarshalValid(data, req.BidRequest); err != nil {
+		return err
+	}
+	reqExt, err := req.GetRequestExt()
+	if err != nil {
+		return err
+	}
+	var aliases map[string]string
+	if prebid := reqExt.GetPrebid(); prebid != nil {
+		aliases = prebid.Aliases
+	}
+	for i := range req.Imp {
+		if err := e.validateImp(&req.Imp[i], i, aliases); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *storedDataEndpoint) validateImp(imp *openrtb2.Imp, index int, aliases map[string]string) error {
+	// the validation may fill in the imp, the stored data is saved as given. Warnings, like unknown bidders,
+	// are ignored as they are for the auctions.
+	errs := e.requestValidator.ValidateImp(&openrtb_ext.ImpWrapper{Imp: imp}, ortb.ValidationConfig{}, index, aliases, false, nil)
+	return errors.Join(errortypes.FatalOnly(errs)...)
+}
+
+// validateStoredResponse validates the stored auction responses, a list of seat bids. Stored bid responses are
+// the raw responses of the bidders and are only checked to be JSON.
+func validateStoredResponse(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		var seatBids []openrtb2.SeatBid
+		return jsonutil.UnmarshalValid(data, &seatBids)
+	}
+	var response any
+	return jsonutil.UnmarshalValid(data, &response)
+}
+
+// validateAccount validates an account config as the auctions do, so that an account saved through the API isn't
+// rejected as malformed by every auction
+func validateAccount(data []byte) error {
+	var accountCfg config.Account
+	if err := jsonutil.UnmarshalValid(data, &accountCfg); err != nil {
+		return err
+	}
+	if err := config.UnpackDSADefault(accountCfg.Privacy.DSA); err != nil {
+		return fmt.Errorf("invalid privacy.dsa.default: %v", err)
+	}
+
+	_, errs := account.ValidateAccount(&accountCfg)
+	return errors.Join(errs...)
+}
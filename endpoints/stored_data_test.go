@@ -0,0 +1,277 @@
+package endpoints
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/ortb"
+	"github.com/prebid/prebid-server/v3/stored_requests"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoredDataEndpoint(t *testing.T) {
+	validImp := `{"id":"imp-1","banner":{"format":[{"w":300,"h":250}]},"ext":{"prebid":{"bidder":{"appnexus":{"placementId":1}}}}}`
+
+	testCases := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		storedData     map[string]string
+		expectedStatus int
+		expectedBody   string
+		expectedData   map[string]string
+	}{
+		{
+			name:           "get",
+			method:         http.MethodGet,
+			path:           "/stored_data/imps/imp-1",
+			storedData:     map[string]string{"Imp:imp-1": validImp},
+			expectedStatus: http.StatusOK,
+			expectedBody:   validImp,
+		},
+		{
+			name:           "get_not_found",
+			method:         http.MethodGet,
+			path:           "/stored_data/imps/imp-2",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "put_imp",
+			method:         http.MethodPut,
+			path:           "/stored_data/imps/imp-1",
+			body:           validImp,
+			expectedStatus: http.StatusNoContent,
+			expectedData:   map[string]string{"Imp:imp-1": validImp},
+		},
+		{
+			name:           "put_too_large",
+			method:         http.MethodPut,
+			path:           "/stored_data/accounts/acc-1",
+			body:           `{"targeting_prefix":"` + strings.Repeat("a", 1024) + `"}`,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   "Stored data size exceeded max size of 1024 bytes\n",
+			expectedData:   map[string]string{},
+		},
+		{
+			name:           "put_imp_invalid_openrtb",
+			method:         http.MethodPut,
+			path:           "/stored_data/imps/imp-1",
+			body:           `{"id":"imp-1","ext":{"prebid":{"bidder":{"appnexus":{"placementId":1}}}}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "put_imp_invalid_bidder_params",
+			method:         http.MethodPut,
+			path:           "/stored_data/imps/imp-1",
+			body:           `{"id":"imp-1","banner":{"format":[{"w":300,"h":250}]},"ext":{"prebid":{"bidder":{"appnexus":{"placementId":"invalid"}}}}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "put_request",
+			method:         http.MethodPut,
+			path:           "/stored_data/requests/req-1",
+			body:           `{"tmax":500,"imp":[` + validImp + `]}`,
+			expectedStatus: http.StatusNoContent,
+			expectedData:   map[string]string{"Request:req-1": `{"tmax":500,"imp":[` + validImp + `]}`},
+		},
+		{
+			name:           "put_request_invalid_imp",
+			method:         http.MethodPut,
+			path:           "/stored_data/requests/req-1",
+			body:           `{"imp":[{"banner":{"format":[{"w":300,"h":250}]}}]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "put_request_malformed",
+			method:         http.MethodPut,
+			path:           "/stored_data/requests/req-1",
+			body:           `{"tmax":"500"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "put_auction_response",
+			method:         http.MethodPut,
+			path:           "/stored_data/responses/resp-1",
+			body:           `[{"seat":"appnexus","bid":[{"id":"bid-1","impid":"imp-1","price":1}]}]`,
+			expectedStatus: http.StatusNoContent,
+			expectedData:   map[string]string{"Response:resp-1": `[{"seat":"appnexus","bid":[{"id":"bid-1","impid":"imp-1","price":1}]}]`},
+		},
+		{
+			name:           "put_auction_response_invalid",
+			method:         http.MethodPut,
+			path:           "/stored_data/responses/resp-1",
+			body:           `[{"seat":1}]`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "put_account",
+			method:         http.MethodPut,
+			path:           "/stored_data/accounts/acc-1",
+			body:           `{"price_clearing":{"mode":"second_price"}}`,
+			expectedStatus: http.StatusNoContent,
+			expectedData:   map[string]string{"Account:acc-1": `{"price_clearing":{"mode":"second_price"}}`},
+		},
+		{
+			name:           "put_account_invalid",
+			method:         http.MethodPut,
+			path:           "/stored_data/accounts/acc-1",
+			body:           `{"price_clearing":{"mode":"third_price"}}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid Stored Account: price_clearing.mode must be first_price or second_price, got third_price\n",
+		},
+		{
+			name:           "put_account_invalid_bidder_transforms",
+			method:         http.MethodPut,
+			path:           "/stored_data/accounts/acc-1",
+			body:           `{"bidder_transforms":{"rules":[{"bidders":[],"op":"cap_imps","max_imps":2}]}}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid Stored Account: bidder_transforms.rules[0]: bidders must not be empty\n",
+		},
+		{
+			name:           "delete",
+			method:         http.MethodDelete,
+			path:           "/stored_data/accounts/acc-1",
+			storedData:     map[string]string{"Account:acc-1": `{}`},
+			expectedStatus: http.StatusNoContent,
+			expectedData:   map[string]string{},
+		},
+		{
+			name:           "delete_not_found",
+			method:         http.MethodDelete,
+			path:           "/stored_data/accounts/acc-1",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "unknown_type",
+			method:         http.MethodGet,
+			path:           "/stored_data/categories/freewheel",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "missing_id",
+			method:         http.MethodGet,
+			path:           "/stored_data/imps/",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "method_not_allowed",
+			method:         http.MethodPost,
+			path:           "/stored_data/imps/imp-1",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			writer := &mockStoredDataWriter{data: map[string]string{}}
+			for key, value := range tc.storedData {
+				writer.data[key] = value
+			}
+			writers := map[stored_requests.DataType]stored_requests.Writer{
+				stored_requests.RequestData:  writer,
+				stored_requests.ImpData:      writer,
+				stored_requests.ResponseData: writer,
+				stored_requests.AccountData:  writer,
+			}
+			handler := NewStoredDataEndpoint(writers, newTestRequestValidator(), 1024, time.Second)
+
+			w := httptest.NewRecorder()
+			handler(w, httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body)))
+
+			assert.Equal(t, tc.expectedStatus, w.Code, w.Body.String())
+			if len(tc.expectedBody) > 0 {
+				assert.Equal(t, tc.expectedBody, w.Body.String())
+			}
+			if tc.expectedData != nil {
+				assert.Equal(t, tc.expectedData, writer.data)
+			}
+		})
+	}
+}
+
+func TestStoredDataEndpointWithoutWriter(t *testing.T) {
+	writers := map[stored_requests.DataType]stored_requests.Writer{
+		stored_requests.AccountData: &mockStoredDataWriter{data: map[string]string{}},
+	}
+	handler := NewStoredDataEndpoint(writers, newTestRequestValidator(), 1024, time.Second)
+
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodPut, "/stored_data/imps/imp-1", strings.NewReader(`{}`)))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "Stored Imp data is not kept in a writable backend\n", w.Body.String())
+}
+
+func TestStoredDataEndpointBackendError(t *testing.T) {
+	writer := &mockStoredDataWriter{err: errors.New("connection refused")}
+	writers := map[stored_requests.DataType]stored_requests.Writer{stored_requests.AccountData: writer}
+	handler := NewStoredDataEndpoint(writers, newTestRequestValidator(), 1024, time.Second)
+
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodPut, "/stored_data/accounts/acc-1", strings.NewReader(`{}`)))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "connection refused\n", w.Body.String())
+}
+
+func newTestRequestValidator() ortb.RequestValidator {
+	return ortb.NewRequestValidator(openrtb_ext.BuildBidderMap(), map[string]string{}, fakeBidderParamsValidator{})
+}
+
+// fakeBidderParamsValidator rejects the bidder params marked invalid
+type fakeBidderParamsValidator struct{}
+
+func (fakeBidderParamsValidator) Validate(name openrtb_ext.BidderName, ext json.RawMessage) error {
+	if bytes.Contains(ext, []byte("invalid")) {
+		return errors.New("invalid params")
+	}
+	return nil
+}
+
+func (fakeBidderParamsValidator) Schema(name openrtb_ext.BidderName) string {
+	return ""
+}
+
+// mockStoredDataWriter keeps the stored data at <data type>:<id>
+type mockStoredDataWriter struct {
+	data map[string]string
+	err  error
+}
+
+func (w *mockStoredDataWriter) Read(ctx context.Context, dataType stored_requests.DataType, id string) (json.RawMessage, error) {
+	if w.err != nil {
+		return nil, w.err
+	}
+	value, ok := w.data[string(dataType)+":"+id]
+	if !ok {
+		return nil, stored_requests.NotFoundError{ID: id, DataType: string(dataType)}
+	}
+	return json.RawMessage(value), nil
+}
+
+func (w *mockStoredDataWriter) Save(ctx context.Context, dataType stored_requests.DataType, id string, data json.RawMessage) error {
+	if w.err != nil {
+		return w.err
+	}
+	w.data[string(dataType)+":"+id] = string(data)
+	return nil
+}
+
+func (w *mockStoredDataWriter) Delete(ctx context.Context, dataType stored_requests.DataType, id string) error {
+	if w.err != nil {
+		return w.err
+	}
+	key := string(dataType) + ":" + id
+	if _, ok := w.data[key]; !ok {
+		return stored_requests.NotFoundError{ID: id, DataType: string(dataType)}
+	}
+	delete(w.data, key)
+	return nil
+}
@@ -0,0 +1,45 @@
+package endpoints
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/prebid/prebid-server/v3/logger"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// vendorListsInfo holds the versions of the GDPR global vendor lists loaded by the server.
+type vendorListsInfo struct {
+	VendorLists []vendorListVersions `json:"vendorLists"`
+}
+
+type vendorListVersions struct {
+	SpecVersion  uint16   `json:"specVersion"`
+	ListVersions []uint16 `json:"listVersions"`
+}
+
+func newVendorListsInfo(loadedVersions map[uint16][]uint16) vendorListsInfo {
+	info := vendorListsInfo{VendorLists: make([]vendorListVersions, 0, len(loadedVersions))}
+	for specVersion, listVersions := range loadedVersions {
+		info.VendorLists = append(info.VendorLists, vendorListVersions{SpecVersion: specVersion, ListVersions: listVersions})
+	}
+	sort.Slice(info.VendorLists, func(i, j int) bool {
+		return info.VendorLists[i].SpecVersion < info.VendorLists[j].SpecVersion
+	})
+	return info
+}
+
+// NewVendorListsEndpoint returns the versions of the GDPR global vendor lists currently loaded, by spec version.
+func NewVendorListsEndpoint(loadedVersions func() map[uint16][]uint16) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		jsonOutput, err := jsonutil.Marshal(newVendorListsInfo(loadedVersions()))
+		if err != nil {
+			logger.Errorf("/gdpr/vendorlists Critical error when trying to marshal vendorListsInfo: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(jsonOutput)
+	}
+}
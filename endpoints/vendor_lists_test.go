@@ -0,0 +1,41 @@
+package endpoints
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVendorListsEndpoint(t *testing.T) {
+	testCases := []struct {
+		name           string
+		loadedVersions map[uint16][]uint16
+		expectedBody   string
+	}{
+		{
+			name:           "none_loaded",
+			loadedVersions: nil,
+			expectedBody:   `{"vendorLists":[]}`,
+		},
+		{
+			name:           "sorted_by_spec_version",
+			loadedVersions: map[uint16][]uint16{3: {1, 2, 3}, 2: {2}},
+			expectedBody:   `{"vendorLists":[{"specVersion":2,"listVersions":[2]},{"specVersion":3,"listVersions":[1,2,3]}]}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewVendorListsEndpoint(func() map[uint16][]uint16 { return tc.loadedVersions })
+			w := httptest.NewRecorder()
+
+			handler(w, httptest.NewRequest(http.MethodGet, "/gdpr/vendorlists", nil))
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			assert.JSONEq(t, tc.expectedBody, w.Body.String())
+		})
+	}
+}
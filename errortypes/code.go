@@ -49,6 +49,11 @@ const (
 	TooLongTargetingPrefixWarningCode
 	TooShortTargetingPrefixWarningCode
 	BidderBlockedByPrivacySettings
+	AuctionMacroWarningCode
+	PriceClearingWarningCode
+	InvalidFledgeAuctionConfigWarningCode
+	InvalidSChainWarningCode
+	BidderTransformWarningCode
 )
 
 // Coder provides an error or warning code with severity.
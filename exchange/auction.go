@@ -106,18 +106,44 @@ func (d *DebugLog) PutDebugLogError(cache prebid_cache_client.Client, timeout in
 	return nil
 }
 
-func newAuction(seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, numImps int, preferDeals bool) *auction {
-	winningBids := make(map[string]*entities.PbsOrtbBid, numImps)
+// selectWinningBids returns the winning bid of every imp across all seats. Seats are visited in name order, so bids
+// ranking the same are won by the first seat. The winners are selected before price clearing, which can lower the
+// price of a winning bid down to the price of the runner-up.
+func selectWinningBids(seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, preferDeals bool) map[string]*entities.PbsOrtbBid {
+	winningBids := make(map[string]*entities.PbsOrtbBid)
+	for _, seat := range sortedSeats(seatBids) {
+		if seatBids[seat] == nil {
+			continue
+		}
+		for _, bid := range seatBids[seat].Bids {
+			if bid == nil || bid.Bid == nil {
+				continue
+			}
+			if wbid, ok := winningBids[bid.Bid.ImpID]; !ok || isNewWinningBid(bid.Bid, wbid.Bid, preferDeals) {
+				winningBids[bid.Bid.ImpID] = bid
+			}
+		}
+	}
+	return winningBids
+}
+
+// sortedSeats returns the seats in name order
+func sortedSeats(seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid) []openrtb_ext.BidderName {
+	seats := make([]openrtb_ext.BidderName, 0, len(seatBids))
+	for seat := range seatBids {
+		seats = append(seats, seat)
+	}
+	sort.Slice(seats, func(i, j int) bool { return seats[i] < seats[j] })
+	return seats
+}
+
+// newAuction groups the bids of every imp by bidder around the winning bids selected by selectWinningBids
+func newAuction(seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, winningBids map[string]*entities.PbsOrtbBid, numImps int) *auction {
 	allBidsByBidder := make(map[string]map[openrtb_ext.BidderName][]*entities.PbsOrtbBid, numImps)
 
 	for bidderName, seatBid := range seatBids {
 		if seatBid != nil {
 			for _, bid := range seatBid.Bids {
-				wbid, ok := winningBids[bid.Bid.ImpID]
-				if !ok || isNewWinningBid(bid.Bid, wbid.Bid, preferDeals) {
-					winningBids[bid.Bid.ImpID] = bid
-				}
-
 				if bidMap, ok := allBidsByBidder[bid.Bid.ImpID]; ok {
 					bidMap[bidderName] = append(bidMap[bidderName], bid)
 				} else {
@@ -151,9 +177,14 @@ func isNewWinningBid(bid, wbid *openrtb2.Bid, preferDeals bool) bool {
 func (a *auction) validateAndUpdateMultiBid(adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, preferDeals bool, accountDefaultBidLimit int) {
 	bidsSnipped := false
 	// sort bids for multibid targeting
-	for _, topBidsPerBidder := range a.allBidsByBidder {
+	for impID, topBidsPerBidder := range a.allBidsByBidder {
+		winner := a.winningBids[impID]
 		for bidder, topBids := range topBidsPerBidder {
 			sort.Slice(topBids, func(i, j int) bool {
+				// the winning bid may tie with the other bids of its seat after price clearing, it stays on top
+				if topBids[i] == winner || topBids[j] == winner {
+					return topBids[i] == winner
+				}
 				return isNewWinningBid(topBids[i].Bid, topBids[j].Bid, preferDeals)
 			})
 
@@ -188,16 +219,18 @@ func (a *auction) validateAndUpdateMultiBid(adapterBids map[openrtb_ext.BidderNa
 func (a *auction) setRoundedPrices(targetingData targetData, account config.Account) {
 	roundedPrices := make(map[*entities.PbsOrtbBid]string, 5*len(a.winningBids))
 	for _, topBidsPerImp := range a.allBidsByBidder {
-		for _, topBidsPerBidder := range topBidsPerImp {
+		for bidder, topBidsPerBidder := range topBidsPerImp {
 			for _, topBid := range topBidsPerBidder {
-				roundedPrices[topBid] = GetPriceBucket(*topBid.Bid, targetingData, account)
+				roundedPrices[topBid] = GetPriceBucket(*topBid.Bid, bidder, targetingData, account)
 			}
 		}
 	}
 	a.roundedPrices = roundedPrices
 }
 
-func (a *auction) doCache(ctx context.Context, cache prebid_cache_client.Client, targData *targetData, evTracking *eventTracking, bidRequest *openrtb2.BidRequest, ttlBuffer int64, defaultTTLs *config.DefaultTTLs, bidCategory map[string]string, debugLog *DebugLog) []error {
+// doCache stores the bids in Prebid Cache. With returnCreativeOnFailure, the bids which couldn't be cached are
+// recorded so that their creatives are returned in place of the cache IDs.
+func (a *auction) doCache(ctx context.Context, cache prebid_cache_client.Client, targData *targetData, evTracking *eventTracking, bidRequest *openrtb2.BidRequest, ttlBuffer int64, defaultTTLs *config.DefaultTTLs, bidCategory map[string]string, debugLog *DebugLog, returnCreativeOnFailure bool) []error {
 	var bids, vast, includeBidderKeys, includeWinners bool = targData.includeCacheBids, targData.includeCacheVast, targData.includeBidderKeys, targData.includeWinners
 	if !((bids || vast) && (includeBidderKeys || includeWinners)) {
 		return nil
@@ -328,9 +361,27 @@ func (a *auction) doCache(ctx context.Context, cache prebid_cache_client.Client,
 			}
 		}
 	}
+	if returnCreativeOnFailure {
+		a.uncachedBids = make(map[*openrtb2.Bid]bool)
+		for index, bid := range bidIndices {
+			if ids[index] == "" {
+				a.uncachedBids[bid] = true
+			}
+		}
+		for index, bid := range vastIndices {
+			if ids[index] == "" {
+				a.uncachedBids[bid] = true
+			}
+		}
+	}
 	return errs
 }
 
+// returnsUncachedCreative reports whether the creative of the bid is returned as the bid couldn't be cached
+func (a *auction) returnsUncachedCreative(bid *openrtb2.Bid) bool {
+	return a != nil && a.uncachedBids[bid]
+}
+
 // makeVAST returns some VAST XML for the given bid. If AdM is defined,
 // it takes precedence. Otherwise the Nurl will be wrapped in a redirect tag.
 func makeVAST(bid *openrtb2.Bid) string {
@@ -408,4 +459,6 @@ type auction struct {
 	cacheIds map[*openrtb2.Bid]string
 	// vastCacheIds stores UUIDS from Prebid cache for fetching the VAST markup to video bids.
 	vastCacheIds map[*openrtb2.Bid]string
+	// uncachedBids stores the bids which couldn't be cached, when their creatives are returned instead.
+	uncachedBids map[*openrtb2.Bid]bool
 }
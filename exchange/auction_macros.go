@@ -0,0 +1,69 @@
+package exchange
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/macros"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+)
+
+// expandAuctionMacros resolves the standard OpenRTB auction macros in the adm, nurl, burl and lurl of all bids
+// when enabled for the account. The winning bids are the ones selected for the auction by selectWinningBids, bid
+// prices are expected to be cleared and in the auction currency at this point.
+func expandAuctionMacros(reqWrapper *openrtb_ext.RequestWrapper, seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, winningBids map[string]*entities.PbsOrtbBid, cfg config.AccountAuctionMacros, replacer macros.Replacer, encoders macros.PriceEncoders) []error {
+	if !cfg.Enabled || len(seatBids) == 0 {
+		return nil
+	}
+
+	encoder, err := encoders.Get(cfg.PriceEncoding)
+	if err != nil {
+		return []error{&errortypes.Warning{
+			Message:     fmt.Sprintf("auction macros not resolved: %s", err.Error()),
+			WarningCode: errortypes.AuctionMacroWarningCode,
+		}}
+	}
+
+	var errs []error
+	macroProvider := macros.NewProvider(reqWrapper)
+	for seat, seatBid := range seatBids {
+		for _, pbsBid := range seatBid.Bids {
+			if pbsBid == nil || pbsBid.Bid == nil {
+				continue
+			}
+
+			price, err := encoder.EncodePrice(pbsBid.Bid.Price, seatBid.Currency, seat.String())
+			if err != nil {
+				errs = append(errs, &errortypes.Warning{
+					Message:     fmt.Sprintf("auction macros not resolved for %s bid id %s: %s", seat, pbsBid.Bid.ID, err.Error()),
+					WarningCode: errortypes.AuctionMacroWarningCode,
+				})
+				continue
+			}
+
+			lossReason := macros.LossReasonBidWon
+			if pbsBid != winningBids[pbsBid.Bid.ImpID] {
+				lossReason = macros.LossReasonLostToHigherBid
+			}
+
+			macroProvider.PopulateAuctionMacros(pbsBid, seat.String(), seatBid.Currency, price, lossReason)
+			pbsBid.Bid.AdM = replaceAuctionMacros(replacer, pbsBid.Bid.AdM, macroProvider)
+			pbsBid.Bid.NURL = replaceAuctionMacros(replacer, pbsBid.Bid.NURL, macroProvider)
+			pbsBid.Bid.BURL = replaceAuctionMacros(replacer, pbsBid.Bid.BURL, macroProvider)
+			pbsBid.Bid.LURL = replaceAuctionMacros(replacer, pbsBid.Bid.LURL, macroProvider)
+		}
+	}
+	return errs
+}
+
+func replaceAuctionMacros(replacer macros.Replacer, input string, macroProvider *macros.MacroProvider) string {
+	if !strings.Contains(input, "${") {
+		return input
+	}
+	builder := strings.Builder{}
+	replacer.Replace(&builder, input, macroProvider)
+	return builder.String()
+}
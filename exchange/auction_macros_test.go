@@ -0,0 +1,141 @@
+package exchange
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/currency"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/macros"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandAuctionMacros(t *testing.T) {
+	reqWrapper := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{ID: "req1"}}
+	getSeatBids := func() map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid {
+		return map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+			"pubmatic": {
+				Currency: "USD",
+				Bids: []*entities.PbsOrtbBid{
+					{Bid: &openrtb2.Bid{
+						ID:    "bid1",
+						ImpID: "imp1",
+						Price: 2.5,
+						AdM:   `<img src="http://imp.com?p=${AUCTION_PRICE}&g=${GDPR}"/>`,
+						NURL:  "http://win.com?p=${AUCTION_PRICE}&a=${AUCTION_ID}&s=${AUCTION_SEAT_ID}",
+						BURL:  "http://bill.com?b=${AUCTION_BID_ID}&i=${AUCTION_IMP_ID}&c=${AUCTION_CURRENCY}",
+						LURL:  "http://loss.com?l=${AUCTION_LOSS}",
+					}},
+				},
+			},
+			"appnexus": {
+				Currency: "USD",
+				Bids: []*entities.PbsOrtbBid{
+					{Bid: &openrtb2.Bid{
+						ID:    "bid2",
+						ImpID: "imp1",
+						Price: 1.25,
+						AdM:   "<div>no macros</div>",
+						LURL:  "http://loss.com?l=${AUCTION_LOSS}&p=${AUCTION_PRICE}",
+					}},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		cfg            config.AccountAuctionMacros
+		expectedBids   map[openrtb_ext.BidderName]openrtb2.Bid
+		expectedErrors []error
+	}{
+		{
+			name: "disabled",
+			cfg:  config.AccountAuctionMacros{Enabled: false},
+			expectedBids: map[openrtb_ext.BidderName]openrtb2.Bid{
+				"pubmatic": *getSeatBids()["pubmatic"].Bids[0].Bid,
+				"appnexus": *getSeatBids()["appnexus"].Bids[0].Bid,
+			},
+		},
+		{
+			name: "enabled_clear_price",
+			cfg:  config.AccountAuctionMacros{Enabled: true, PriceEncoding: macros.PriceEncodingClear},
+			expectedBids: map[openrtb_ext.BidderName]openrtb2.Bid{
+				"pubmatic": {
+					ID:    "bid1",
+					ImpID: "imp1",
+					Price: 2.5,
+					AdM:   `<img src="http://imp.com?p=2.5&g=${GDPR}"/>`,
+					NURL:  "http://win.com?p=2.5&a=req1&s=pubmatic",
+					BURL:  "http://bill.com?b=bid1&i=imp1&c=USD",
+					LURL:  "http://loss.com?l=0",
+				},
+				"appnexus": {
+					ID:    "bid2",
+					ImpID: "imp1",
+					Price: 1.25,
+					AdM:   "<div>no macros</div>",
+					LURL:  "http://loss.com?l=102&p=1.25",
+				},
+			},
+		},
+		{
+			name: "enabled_unknown_price_encoder",
+			cfg:  config.AccountAuctionMacros{Enabled: true, PriceEncoding: "unknown"},
+			expectedBids: map[openrtb_ext.BidderName]openrtb2.Bid{
+				"pubmatic": *getSeatBids()["pubmatic"].Bids[0].Bid,
+				"appnexus": *getSeatBids()["appnexus"].Bids[0].Bid,
+			},
+			expectedErrors: []error{&errortypes.Warning{
+				Message:     "auction macros not resolved: price encoder unknown is not registered",
+				WarningCode: errortypes.AuctionMacroWarningCode,
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seatBids := getSeatBids()
+			errs := expandAuctionMacros(reqWrapper, seatBids, selectWinningBids(seatBids, false), tt.cfg, macros.NewAuctionMacroReplacer(), nil)
+			assert.Equal(t, tt.expectedErrors, errs)
+			for seat, expectedBid := range tt.expectedBids {
+				assert.Equal(t, expectedBid, *seatBids[seat].Bids[0].Bid, string(seat))
+			}
+		})
+	}
+}
+
+func TestExpandAuctionMacrosClearedWinner(t *testing.T) {
+	reqWrapper := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{ID: "req1", Imp: []openrtb2.Imp{{ID: "imp1"}}}}
+	seatBids := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+		"pubmatic": {
+			Currency: "USD",
+			Bids:     []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{ID: "bid1", ImpID: "imp1", Price: 5, LURL: "l=${AUCTION_LOSS}&p=${AUCTION_PRICE}"}}},
+		},
+		"appnexus": {
+			Currency: "USD",
+			Bids:     []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{ID: "bid2", ImpID: "imp1", Price: 2, LURL: "l=${AUCTION_LOSS}&p=${AUCTION_PRICE}"}}},
+		},
+	}
+	encoders := macros.PriceEncoders{"mock": mockPriceEncoder{}}
+
+	// the winner clears at the price of the runner-up and must still get the win macros
+	winningBids := selectWinningBids(seatBids, false)
+	clearingErrs := applyPriceClearing(reqWrapper, seatBids, winningBids, config.AccountPriceClearing{Mode: config.ClearingModeSecondPrice}, currency.Conversions(currency.NewRates(nil)))
+	assert.Empty(t, clearingErrs)
+	require.Equal(t, 2.0, seatBids["pubmatic"].Bids[0].Bid.Price)
+
+	errs := expandAuctionMacros(reqWrapper, seatBids, winningBids, config.AccountAuctionMacros{Enabled: true, PriceEncoding: "mock"}, macros.NewAuctionMacroReplacer(), encoders)
+	assert.Empty(t, errs)
+	assert.Equal(t, "l=0&p=encoded-pubmatic", seatBids["pubmatic"].Bids[0].Bid.LURL)
+	assert.Equal(t, "l=102&p=encoded-appnexus", seatBids["appnexus"].Bids[0].Bid.LURL)
+}
+
+type mockPriceEncoder struct{}
+
+func (mockPriceEncoder) EncodePrice(price float64, currency string, seat string) (string, error) {
+	return "encoded-" + seat, nil
+}
@@ -10,4 +10,7 @@ type AuctionResponse struct {
 	*openrtb2.BidResponse
 	ExtBidResponse *openrtb_ext.ExtBidResponse
 	SeatNonBid     openrtb_ext.SeatNonBidBuilder
+	PrivacyAudit   []openrtb_ext.PrivacyAuditRecord
+	// DSA holds the DSA transparency information of the bids of the response
+	DSA []openrtb_ext.DSARecord
 }
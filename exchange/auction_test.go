@@ -288,7 +288,7 @@ func runCacheSpec(t *testing.T, fileDisplayName string, specData *cacheSpec) {
 		externalURL:        "http://localhost",
 		auctionTimestampMs: 1234567890,
 	}
-	_ = testAuction.doCache(ctx, cache, targData, evTracking, &specData.BidRequest, 60, &specData.DefaultTTLs, bidCategory, &specData.DebugLog)
+	_ = testAuction.doCache(ctx, cache, targData, evTracking, &specData.BidRequest, 60, &specData.DefaultTTLs, bidCategory, &specData.DebugLog, false)
 
 	if len(specData.ExpectedCacheables) > len(cache.items) {
 		t.Errorf("%s:  [CACHE_ERROR] Less elements were cached than expected \n", fileDisplayName)
@@ -339,6 +339,36 @@ func runCacheSpec(t *testing.T, fileDisplayName string, specData *cacheSpec) {
 	}
 }
 
+func TestDoCacheReturnCreativeOnFailure(t *testing.T) {
+	bannerBid := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "bid-1", ImpID: "imp-1", Price: 1, AdM: "<div></div>"}, BidType: openrtb_ext.BidTypeBanner}
+	videoBid := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "bid-2", ImpID: "imp-2", Price: 2, AdM: "<VAST></VAST>"}, BidType: openrtb_ext.BidTypeVideo}
+	targData := &targetData{
+		includeWinners:   true,
+		includeCacheBids: true,
+		includeCacheVast: true,
+	}
+	bidRequest := &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp-1"}, {ID: "imp-2"}}}
+
+	for _, returnCreativeOnFailure := range []bool{true, false} {
+		testAuction := &auction{
+			winningBids: map[string]*entities.PbsOrtbBid{"imp-1": bannerBid, "imp-2": videoBid},
+			allBidsByBidder: map[string]map[openrtb_ext.BidderName][]*entities.PbsOrtbBid{
+				"imp-1": {"appnexus": {bannerBid}},
+				"imp-2": {"appnexus": {videoBid}},
+			},
+			roundedPrices: map[*entities.PbsOrtbBid]string{bannerBid: "1.00", videoBid: "2.00"},
+		}
+		// the mock cache fails to store every value
+		testAuction.doCache(context.Background(), &mockCache{}, targData, &eventTracking{}, bidRequest, 60, &config.DefaultTTLs{}, nil, nil, returnCreativeOnFailure)
+
+		assert.Equal(t, returnCreativeOnFailure, testAuction.returnsUncachedCreative(bannerBid.Bid))
+		assert.Equal(t, returnCreativeOnFailure, testAuction.returnsUncachedCreative(videoBid.Bid))
+	}
+
+	var noAuction *auction
+	assert.False(t, noAuction.returnsUncachedCreative(bannerBid.Bid))
+}
+
 func TestNewAuction(t *testing.T) {
 	bid1p077 := entities.PbsOrtbBid{
 		Bid: &openrtb2.Bid{
@@ -592,13 +622,42 @@ func TestNewAuction(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		auc := newAuction(test.seatBids, test.numImps, test.preferDeals)
+		auc := newAuction(test.seatBids, selectWinningBids(test.seatBids, test.preferDeals), test.numImps)
 
 		assert.Equal(t, test.expectedAuction, *auc, test.description)
 	}
 
 }
 
+func TestSelectWinningBids(t *testing.T) {
+	seatBids := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+		"pubmatic": {
+			Bids: []*entities.PbsOrtbBid{
+				{Bid: &openrtb2.Bid{ID: "bid1", ImpID: "imp1", Price: 3}},
+				{Bid: &openrtb2.Bid{ID: "bid2", ImpID: "imp2", Price: 4}},
+			},
+		},
+		"appnexus": {
+			Bids: []*entities.PbsOrtbBid{
+				{Bid: &openrtb2.Bid{ID: "bid3", ImpID: "imp1", Price: 3}},
+				{Bid: &openrtb2.Bid{ID: "bid4", ImpID: "imp1", Price: 1}},
+				{Bid: nil},
+			},
+		},
+		"rubicon": nil,
+	}
+
+	for i := 0; i < 10; i++ {
+		winningBids := selectWinningBids(seatBids, false)
+		assert.Len(t, winningBids, 2)
+		assert.Equal(t, "bid3", winningBids["imp1"].Bid.ID, "ties are won by the first seat in name order")
+		assert.Equal(t, "bid2", winningBids["imp2"].Bid.ID)
+	}
+
+	seatBids["rubicon"] = &entities.PbsOrtbSeatBid{Bids: []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{ID: "bid5", ImpID: "imp1", Price: 0.5, DealID: "deal1"}}}}
+	assert.Equal(t, "bid5", selectWinningBids(seatBids, true)["imp1"].Bid.ID)
+}
+
 func TestValidateAndUpdateMultiBid(t *testing.T) {
 	// create new bids for new test cases since the last one changes a few bids. Ex marks bid1p001.Bid = nil
 	bid1p001 := entities.PbsOrtbBid{
@@ -79,11 +79,13 @@ type bidRequestOptions struct {
 	tmaxAdjustments        *TmaxAdjustmentsPreprocessed
 	bidderRequestStartTime time.Time
 	responseDebugAllowed   bool
+	bidderTransforms       config.AccountBidderTransforms
 }
 
 type extraBidderRespInfo struct {
 	respProcessingStartTime time.Time
 	seatNonBidBuilder       openrtb_ext.SeatNonBidBuilder
+	transformChanges        []openrtb_ext.ExtBidderTransformChange
 }
 
 type extraAuctionResponseInfo struct {
@@ -195,6 +197,10 @@ func (bidder *BidderAdapter) requestBid(ctx context.Context, bidderRequest Bidde
 		extraRespInfo   extraBidderRespInfo
 	)
 
+	// apply the account transformation rules once modules are done with the request
+	transformChanges, transformErr := applyBidderTransforms(&request, bidderRequest.BidderName, bidRequestOptions.bidderTransforms)
+	extraRespInfo.transformChanges = transformChanges
+
 	// rebuild request after modules execution
 	request.RebuildRequest()
 	bidderRequest.BidRequest = request.BidRequest
@@ -214,8 +220,14 @@ func (bidder *BidderAdapter) requestBid(ctx context.Context, bidderRequest Bidde
 			if len(errs) == 0 {
 				errs = append(errs, &errortypes.FailedToRequestBids{Message: "The adapter failed to generate any bid requests, but also failed to generate an error explaining why"})
 			}
+			if transformErr != nil {
+				errs = append(errs, transformErr)
+			}
 			return nil, extraBidderRespInfo{}, errs
 		}
+		if transformErr != nil {
+			errs = append(errs, transformErr)
+		}
 		xPrebidHeader := version.BuildXPrebidHeaderForRequest(bidderRequest.BidRequest, version.Ver)
 
 		for i := 0; i < len(reqData); i++ {
@@ -3576,3 +3576,33 @@ func BenchmarkCompressToGZIPOptimized(b *testing.B) {
 		getRequestBody(req, "GZIP")
 	}
 }
+
+func TestRequestBidAppliesBidderTransforms(t *testing.T) {
+	server := httptest.NewServer(mockHandler(200, "getBody", "{}"))
+	defer server.Close()
+
+	bidderImpl := &goodSingleBidder{
+		httpRequest: &adapters.RequestData{Method: "POST", Uri: server.URL, Body: []byte("{}"), Headers: http.Header{}},
+		bidResponse: &adapters.BidderResponse{},
+	}
+	bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, nil, "")
+	currencyConverter := currency.NewRateConverter(&http.Client{}, time.Duration(1), "", time.Duration(0))
+
+	bidderReq := BidderRequest{
+		BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp1"}, {ID: "imp2"}}},
+		BidderName: "appnexus",
+	}
+	bidReqOptions := bidRequestOptions{
+		bidderTransforms: config.AccountBidderTransforms{Rules: []config.BidderTransformRule{
+			{Bidders: []string{"appnexus"}, Op: config.BidderTransformCapImps, MaxImps: 1},
+		}},
+	}
+
+	_, extraBidderRespInfo, errs := bidder.requestBid(context.Background(), bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{}, &hookexecution.EmptyHookExecutor{}, nil)
+
+	assert.Empty(t, errs)
+	assert.Equal(t, []openrtb2.Imp{{ID: "imp1"}}, bidderImpl.bidRequest.Imp, "the adapter must receive the transformed request")
+	assert.Equal(t, []openrtb_ext.ExtBidderTransformChange{
+		{Op: "cap_imps", Path: "/imp", Old: json.RawMessage(`2`), New: json.RawMessage(`1`)},
+	}, extraBidderRespInfo.transformChanges)
+}
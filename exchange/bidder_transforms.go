@@ -0,0 +1,562 @@
+package exchange
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+var impPointer = jsonutil.Pointer{"imp"}
+
+// bidRequestFields indexes the fields of the bid request by JSON name, the rules with a pointer operate on the
+// fields named by its first token
+var bidRequestFields = func() map[string]int {
+	requestType := reflect.TypeOf(openrtb2.BidRequest{})
+	fields := make(map[string]int, requestType.NumField())
+	for i := 0; i < requestType.NumField(); i++ {
+		name, _, _ := strings.Cut(requestType.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields[name] = i
+		}
+	}
+	return fields
+}()
+
+// applyBidderTransforms rewrites the request of the bidder with the account transformation rules listing it and
+// returns the changes made. The rules apply to a copy of the request, which is left untouched when a rule fails.
+func applyBidderTransforms(request *openrtb_ext.RequestWrapper, bidder openrtb_ext.BidderName, transforms config.AccountBidderTransforms) ([]openrtb_ext.ExtBidderTransformChange, error) {
+	var rules []config.BidderTransformRule
+	for _, rule := range transforms.Rules {
+		if rule.AppliesTo(bidder.String()) {
+			rules = append(rules, rule)
+		}
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	if err := request.RebuildRequest(); err != nil {
+		return nil, newBidderTransformWarning(err)
+	}
+	transformer := newBidderTransformer(request.BidRequest)
+	for _, rule := range rules {
+		if err := transformer.apply(rule); err != nil {
+			return nil, newBidderTransformWarning(fmt.Errorf("%s rule failed: %s", rule.Op, err))
+		}
+	}
+
+	*request = openrtb_ext.RequestWrapper{BidRequest: transformer.request}
+	return transformer.changes, nil
+}
+
+func newBidderTransformWarning(err error) error {
+	return &errortypes.Warning{
+		WarningCode: errortypes.BidderTransformWarningCode,
+		Message:     fmt.Sprintf("bidder request transformations discarded: %s", err),
+	}
+}
+
+// bidderTransformer applies the rules to a shallow copy of the request. The fields a rule modifies are replaced,
+// never written through, as they may be shared with the requests of other bidders.
+type bidderTransformer struct {
+	request *openrtb2.BidRequest
+	changes []openrtb_ext.ExtBidderTransformChange
+}
+
+func newBidderTransformer(request *openrtb2.BidRequest) *bidderTransformer {
+	requestCopy := *request
+	requestCopy.Imp = slices.Clone(request.Imp)
+	return &bidderTransformer{request: &requestCopy}
+}
+
+func (t *bidderTransformer) apply(rule config.BidderTransformRule) error {
+	switch rule.Op {
+	case config.BidderTransformSet, config.BidderTransformDelete, config.BidderTransformCopy, config.BidderTransformRename:
+		return t.applyPointerRule(rule)
+	case config.BidderTransformCapImps:
+		t.capImps(rule)
+	case config.BidderTransformStripFormats:
+		t.stripFormats(rule)
+	case config.BidderTransformMapParams:
+		return t.mapParams(rule)
+	default:
+		return errors.New("unknown op")
+	}
+	return nil
+}
+
+// applyPointerRule applies a rule with a pointer to the generic JSON representation of the request fields it
+// names, and writes them back to the request
+func (t *bidderTransformer) applyPointerRule(rule config.BidderTransformRule) error {
+	names, err := pointerFieldNames(rule)
+	if err != nil {
+		return err
+	}
+
+	root := make(map[string]any, len(names))
+	for _, name := range names {
+		value, found, err := t.getField(name)
+		if err != nil {
+			return err
+		}
+		if found {
+			root[name] = value
+		}
+	}
+
+	doc := &transformDocument{root: root}
+	switch rule.Op {
+	case config.BidderTransformSet:
+		err = doc.set(rule)
+	case config.BidderTransformDelete:
+		err = doc.delete(rule)
+	default:
+		err = doc.copy(rule, rule.Op == config.BidderTransformRename)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		value, found := root[name]
+		if err := t.setField(name, value, found); err != nil {
+			return err
+		}
+	}
+	t.changes = append(t.changes, doc.changes...)
+	return nil
+}
+
+// pointerFieldNames returns the request fields named by the first token of the path and from pointers of the rule
+func pointerFieldNames(rule config.BidderTransformRule) ([]string, error) {
+	pointers := []string{rule.Path}
+	if rule.Op == config.BidderTransformCopy || rule.Op == config.BidderTransformRename {
+		pointers = append(pointers, rule.From)
+	}
+
+	var names []string
+	for _, pointer := range pointers {
+		tokens, err := jsonutil.ParsePointer(pointer)
+		if err != nil {
+			return nil, err
+		}
+		if len(tokens) == 0 {
+			return nil, errors.New("the whole request can't be replaced")
+		}
+		if _, ok := bidRequestFields[tokens[0]]; !ok {
+			return nil, fmt.Errorf("unknown request field %s", tokens[0])
+		}
+		if !slices.Contains(names, tokens[0]) {
+			names = append(names, tokens[0])
+		}
+	}
+	return names, nil
+}
+
+// getField returns the generic JSON representation of the request field, not found when it isn't set
+func (t *bidderTransformer) getField(name string) (any, bool, error) {
+	field := reflect.ValueOf(t.request).Elem().Field(bidRequestFields[name])
+	if field.IsZero() {
+		return nil, false, nil
+	}
+	data, err := jsonutil.Marshal(field.Interface())
+	if err != nil {
+		return nil, false, err
+	}
+	value, err := decodeTransformValue(data)
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// setField replaces the request field with the generic JSON value, or clears it when not found
+func (t *bidderTransformer) setField(name string, value any, found bool) error {
+	field := reflect.ValueOf(t.request).Elem().Field(bidRequestFields[name])
+	field.SetZero()
+	if !found {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if err := jsonutil.UnmarshalValid(data, field.Addr().Interface()); err != nil {
+		return fmt.Errorf("/%s is invalid: %s", name, err)
+	}
+	return nil
+}
+
+func (t *bidderTransformer) capImps(rule config.BidderTransformRule) {
+	if len(t.request.Imp) <= rule.MaxImps {
+		return
+	}
+
+	t.record(rule.Op, impPointer, nil, len(t.request.Imp), rule.MaxImps)
+	t.request.Imp = t.request.Imp[:rule.MaxImps]
+}
+
+func (t *bidderTransformer) stripFormats(rule config.BidderTransformRule) {
+	kept := make([]openrtb2.Imp, 0, len(t.request.Imp))
+	for i, imp := range t.request.Imp {
+		stripped := false
+		for _, mediaType := range rule.MediaTypes {
+			if format, found := stripImpFormat(&imp, mediaType); found {
+				t.record(rule.Op, jsonutil.Pointer{"imp", strconv.Itoa(i), string(mediaType)}, nil, format, nil)
+				stripped = true
+			}
+		}
+
+		// imps left without any format can't be bid on
+		if stripped && imp.Banner == nil && imp.Video == nil && imp.Audio == nil && imp.Native == nil {
+			t.record(rule.Op, jsonutil.Pointer{"imp", strconv.Itoa(i)}, nil, imp, nil)
+			continue
+		}
+		kept = append(kept, imp)
+	}
+	t.request.Imp = kept
+}
+
+// stripImpFormat removes the format of the media type from the imp and returns it
+func stripImpFormat(imp *openrtb2.Imp, mediaType openrtb_ext.BidType) (any, bool) {
+	var format any
+	switch {
+	case mediaType == openrtb_ext.BidTypeBanner && imp.Banner != nil:
+		format, imp.Banner = imp.Banner, nil
+	case mediaType == openrtb_ext.BidTypeVideo && imp.Video != nil:
+		format, imp.Video = imp.Video, nil
+	case mediaType == openrtb_ext.BidTypeAudio && imp.Audio != nil:
+		format, imp.Audio = imp.Audio, nil
+	case mediaType == openrtb_ext.BidTypeNative && imp.Native != nil:
+		format, imp.Native = imp.Native, nil
+	default:
+		return nil, false
+	}
+	return format, true
+}
+
+func (t *bidderTransformer) mapParams(rule config.BidderTransformRule) error {
+	wrapper := &openrtb_ext.RequestWrapper{BidRequest: t.request}
+	names := slices.Sorted(maps.Keys(rule.Params))
+	for i, imp := range wrapper.GetImp() {
+		impExt, err := imp.GetImpExt()
+		if err != nil {
+			return err
+		}
+		ext := impExt.GetExt()
+
+		var params map[string]json.RawMessage
+		if err := jsonutil.Unmarshal(ext[openrtb_ext.PrebidExtBidderKey], &params); err != nil || params == nil {
+			continue
+		}
+
+		// all params are removed before being set under their new name so that names can be swapped
+		moved := make(map[string]json.RawMessage, len(names))
+		for _, name := range names {
+			if value, found := params[name]; found {
+				moved[name] = value
+				delete(params, name)
+			}
+		}
+		if len(moved) == 0 {
+			continue
+		}
+		for _, name := range names {
+			value, found := moved[name]
+			if !found {
+				continue
+			}
+			params[rule.Params[name]] = value
+			t.record(rule.Op,
+				jsonutil.Pointer{"imp", strconv.Itoa(i), "ext", "bidder", rule.Params[name]},
+				jsonutil.Pointer{"imp", strconv.Itoa(i), "ext", "bidder", name},
+				nil, value)
+		}
+
+		if ext[openrtb_ext.PrebidExtBidderKey], err = jsonutil.Marshal(params); err != nil {
+			return err
+		}
+		impExt.SetExt(ext)
+	}
+	return wrapper.RebuildRequest()
+}
+
+func (t *bidderTransformer) record(op config.BidderTransformOp, path, from jsonutil.Pointer, oldValue, newValue any) {
+	t.changes = append(t.changes, newBidderTransformChange(op, path, from, oldValue, newValue))
+}
+
+// transformDocument is the generic JSON representation of the request fields a rule with a pointer operates on.
+// Numbers are kept as json.Number to be written back as is.
+type transformDocument struct {
+	root    any
+	changes []openrtb_ext.ExtBidderTransformChange
+}
+
+func (d *transformDocument) set(rule config.BidderTransformRule) error {
+	path, err := jsonutil.ParsePointer(rule.Path)
+	if err != nil {
+		return err
+	}
+
+	for _, pointer := range expandPointer(d.root, path) {
+		// every pointer gets its own copy of the value, later rules may modify it
+		value, err := copyTransformValue(rule.Value)
+		if err != nil {
+			return err
+		}
+		old, _ := getTransformValue(d.root, pointer)
+		if d.root, err = setTransformValue(d.root, pointer, value); err != nil {
+			return fmt.Errorf("%s: %s", pointer, err)
+		}
+		d.record(rule.Op, pointer, nil, old, value)
+	}
+	return nil
+}
+
+func (d *transformDocument) delete(rule config.BidderTransformRule) error {
+	path, err := jsonutil.ParsePointer(rule.Path)
+	if err != nil {
+		return err
+	}
+
+	// removing from the last pointer keeps the array indexes of the previous ones valid
+	pointers := expandPointer(d.root, path)
+	for i := len(pointers) - 1; i >= 0; i-- {
+		var old any
+		var found bool
+		if d.root, old, found = removeTransformValue(d.root, pointers[i]); found {
+			d.record(rule.Op, pointers[i], nil, old, nil)
+		}
+	}
+	return nil
+}
+
+func (d *transformDocument) copy(rule config.BidderTransformRule, removeSource bool) error {
+	path, err := jsonutil.ParsePointer(rule.Path)
+	if err != nil {
+		return err
+	}
+	from, err := jsonutil.ParsePointer(rule.From)
+	if err != nil {
+		return err
+	}
+
+	// renaming from the last source keeps the array indexes of the previous ones valid
+	sources := expandPointer(d.root, from)
+	for i := len(sources) - 1; i >= 0; i-- {
+		source := sources[i]
+		value, found := getTransformValue(d.root, source)
+		if !found {
+			continue
+		}
+		if value, err = copyTransformValue(value); err != nil {
+			return err
+		}
+
+		destination := bindWildcards(path, from, source)
+		old, _ := getTransformValue(d.root, destination)
+		if d.root, err = setTransformValue(d.root, destination, value); err != nil {
+			return fmt.Errorf("%s: %s", destination, err)
+		}
+		if removeSource {
+			d.root, _, _ = removeTransformValue(d.root, source)
+		}
+		d.record(rule.Op, destination, source, old, value)
+	}
+	return nil
+}
+
+func (d *transformDocument) record(op config.BidderTransformOp, path, from jsonutil.Pointer, oldValue, newValue any) {
+	d.changes = append(d.changes, newBidderTransformChange(op, path, from, oldValue, newValue))
+}
+
+func newBidderTransformChange(op config.BidderTransformOp, path, from jsonutil.Pointer, oldValue, newValue any) openrtb_ext.ExtBidderTransformChange {
+	change := openrtb_ext.ExtBidderTransformChange{Op: string(op), Path: path.String()}
+	if from != nil {
+		change.From = from.String()
+	}
+	if oldValue != nil {
+		change.Old, _ = json.Marshal(oldValue)
+	}
+	if newValue != nil {
+		change.New, _ = json.Marshal(newValue)
+	}
+	return change
+}
+
+// decodeTransformValue decodes JSON keeping numbers as json.Number
+func decodeTransformValue(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// copyTransformValue deep copies the value into its generic JSON representation
+func copyTransformValue(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return decodeTransformValue(data)
+}
+
+// expandPointer resolves the "*" tokens of the pointer to the indexes of the arrays present in the document
+func expandPointer(root any, pointer jsonutil.Pointer) []jsonutil.Pointer {
+	wildcard := slices.Index(pointer, jsonutil.PointerWildcard)
+	if wildcard < 0 {
+		return []jsonutil.Pointer{pointer}
+	}
+
+	value, _ := getTransformValue(root, pointer[:wildcard])
+	array, ok := value.([]any)
+	if !ok {
+		return nil
+	}
+
+	var expanded []jsonutil.Pointer
+	for i := range array {
+		concrete := slices.Clone(pointer)
+		concrete[wildcard] = strconv.Itoa(i)
+		expanded = append(expanded, expandPointer(root, concrete)...)
+	}
+	return expanded
+}
+
+// bindWildcards replaces the "*" tokens of the pointer with the indexes the ones of from resolved to in concrete
+func bindWildcards(pointer, from, concrete jsonutil.Pointer) jsonutil.Pointer {
+	var indexes []string
+	for i, token := range from {
+		if token == jsonutil.PointerWildcard {
+			indexes = append(indexes, concrete[i])
+		}
+	}
+
+	bound := slices.Clone(pointer)
+	for i, token := range bound {
+		if token == jsonutil.PointerWildcard && len(indexes) > 0 {
+			bound[i] = indexes[0]
+			indexes = indexes[1:]
+		}
+	}
+	return bound
+}
+
+func getTransformValue(node any, tokens []string) (any, bool) {
+	for _, token := range tokens {
+		switch n := node.(type) {
+		case map[string]any:
+			value, found := n[token]
+			if !found {
+				return nil, false
+			}
+			node = value
+		case []any:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(n) {
+				return nil, false
+			}
+			node = n[index]
+		default:
+			return nil, false
+		}
+	}
+	return node, true
+}
+
+// setTransformValue sets the value at the tokens and returns the updated node. Missing objects are created,
+// the "-" token or the array length append to an array.
+func setTransformValue(node any, tokens []string, value any) (any, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+
+	token := tokens[0]
+	switch n := node.(type) {
+	case nil:
+		return setTransformValue(map[string]any{}, tokens, value)
+	case map[string]any:
+		child, err := setTransformValue(n[token], tokens[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		n[token] = child
+		return n, nil
+	case []any:
+		index := len(n)
+		if token != "-" {
+			var err error
+			if index, err = strconv.Atoi(token); err != nil || index < 0 || index > len(n) {
+				return nil, fmt.Errorf("invalid array index %s", token)
+			}
+		}
+		if index == len(n) {
+			child, err := setTransformValue(nil, tokens[1:], value)
+			if err != nil {
+				return nil, err
+			}
+			return append(n, child), nil
+		}
+		child, err := setTransformValue(n[index], tokens[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		n[index] = child
+		return n, nil
+	default:
+		return nil, fmt.Errorf("cannot set field %s of a scalar value", token)
+	}
+}
+
+// removeTransformValue removes the value at the tokens and returns the updated node with the removed value
+func removeTransformValue(node any, tokens []string) (any, any, bool) {
+	if len(tokens) == 0 {
+		return node, nil, false
+	}
+
+	token := tokens[0]
+	switch n := node.(type) {
+	case map[string]any:
+		child, found := n[token]
+		if !found {
+			return node, nil, false
+		}
+		if len(tokens) == 1 {
+			delete(n, token)
+			return n, child, true
+		}
+		updated, removed, found := removeTransformValue(child, tokens[1:])
+		n[token] = updated
+		return n, removed, found
+	case []any:
+		index, err := strconv.Atoi(token)
+		if err != nil || index < 0 || index >= len(n) {
+			return node, nil, false
+		}
+		if len(tokens) == 1 {
+			removed := n[index]
+			return slices.Delete(n, index, index+1), removed, true
+		}
+		updated, removed, found := removeTransformValue(n[index], tokens[1:])
+		n[index] = updated
+		return n, removed, found
+	default:
+		return node, nil, false
+	}
+}
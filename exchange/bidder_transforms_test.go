@@ -0,0 +1,171 @@
+package exchange
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyBidderTransforms(t *testing.T) {
+	newRequest := func() *openrtb2.BidRequest {
+		return &openrtb2.BidRequest{
+			ID: "req1",
+			Imp: []openrtb2.Imp{
+				{ID: "imp1", TagID: "tag1", Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{"placementId":12345678901234567,"size":1}}`)},
+				{ID: "imp2", TagID: "tag2", Video: &openrtb2.Video{MIMEs: []string{"video/mp4"}}, Ext: json.RawMessage(`{"bidder":{"placementId":2}}`)},
+				{ID: "imp3", Banner: &openrtb2.Banner{}, Video: &openrtb2.Video{MIMEs: []string{"video/mp4"}}},
+			},
+			Site: &openrtb2.Site{Page: "https://example.com", Keywords: "a,b"},
+		}
+	}
+
+	tests := []struct {
+		name             string
+		rules            []config.BidderTransformRule
+		expectedRequest  func(*openrtb2.BidRequest)
+		expectedChanges  []openrtb_ext.ExtBidderTransformChange
+		expectedWarnings []string
+	}{
+		{
+			name:  "no_rule_for_bidder",
+			rules: []config.BidderTransformRule{{Bidders: []string{"rubicon"}, Op: config.BidderTransformDelete, Path: "/site"}},
+		},
+		{
+			name: "set_and_delete",
+			rules: []config.BidderTransformRule{
+				{Bidders: []string{"*"}, Op: config.BidderTransformSet, Path: "/tmax", Value: 500},
+				{Bidders: []string{"appnexus"}, Op: config.BidderTransformSet, Path: "/site/ext/pub", Value: map[string]any{"id": "p1"}},
+				{Bidders: []string{"appnexus"}, Op: config.BidderTransformDelete, Path: "/site/keywords"},
+				{Bidders: []string{"appnexus"}, Op: config.BidderTransformDelete, Path: "/user"},
+			},
+			expectedRequest: func(r *openrtb2.BidRequest) {
+				r.TMax = 500
+				r.Site.Ext = json.RawMessage(`{"pub":{"id":"p1"}}`)
+				r.Site.Keywords = ""
+			},
+			expectedChanges: []openrtb_ext.ExtBidderTransformChange{
+				{Op: "set", Path: "/tmax", New: json.RawMessage(`500`)},
+				{Op: "set", Path: "/site/ext/pub", New: json.RawMessage(`{"id":"p1"}`)},
+				{Op: "delete", Path: "/site/keywords", Old: json.RawMessage(`"a,b"`)},
+			},
+		},
+		{
+			name: "wildcard_set_and_delete",
+			rules: []config.BidderTransformRule{
+				{Bidders: []string{"appnexus"}, Op: config.BidderTransformSet, Path: "/imp/*/ext/bidder/siteId", Value: "s1"},
+				{Bidders: []string{"appnexus"}, Op: config.BidderTransformDelete, Path: "/imp/*/tagid"},
+			},
+			expectedRequest: func(r *openrtb2.BidRequest) {
+				r.Imp[0].TagID = ""
+				r.Imp[0].Ext = json.RawMessage(`{"bidder":{"placementId":12345678901234567,"siteId":"s1","size":1}}`)
+				r.Imp[1].TagID = ""
+				r.Imp[1].Ext = json.RawMessage(`{"bidder":{"placementId":2,"siteId":"s1"}}`)
+				r.Imp[2].Ext = json.RawMessage(`{"bidder":{"siteId":"s1"}}`)
+			},
+			expectedChanges: []openrtb_ext.ExtBidderTransformChange{
+				{Op: "set", Path: "/imp/0/ext/bidder/siteId", New: json.RawMessage(`"s1"`)},
+				{Op: "set", Path: "/imp/1/ext/bidder/siteId", New: json.RawMessage(`"s1"`)},
+				{Op: "set", Path: "/imp/2/ext/bidder/siteId", New: json.RawMessage(`"s1"`)},
+				{Op: "delete", Path: "/imp/1/tagid", Old: json.RawMessage(`"tag2"`)},
+				{Op: "delete", Path: "/imp/0/tagid", Old: json.RawMessage(`"tag1"`)},
+			},
+		},
+		{
+			name: "copy_and_rename",
+			rules: []config.BidderTransformRule{
+				{Bidders: []string{"appnexus"}, Op: config.BidderTransformCopy, Path: "/site/ext/page", From: "/site/page"},
+				{Bidders: []string{"appnexus"}, Op: config.BidderTransformRename, Path: "/imp/*/ext/bidder/tag", From: "/imp/*/tagid"},
+			},
+			expectedRequest: func(r *openrtb2.BidRequest) {
+				r.Site.Ext = json.RawMessage(`{"page":"https://example.com"}`)
+				r.Imp[0].TagID = ""
+				r.Imp[0].Ext = json.RawMessage(`{"bidder":{"placementId":12345678901234567,"size":1,"tag":"tag1"}}`)
+				r.Imp[1].TagID = ""
+				r.Imp[1].Ext = json.RawMessage(`{"bidder":{"placementId":2,"tag":"tag2"}}`)
+			},
+			expectedChanges: []openrtb_ext.ExtBidderTransformChange{
+				{Op: "copy", Path: "/site/ext/page", From: "/site/page", New: json.RawMessage(`"https://example.com"`)},
+				{Op: "rename", Path: "/imp/1/ext/bidder/tag", From: "/imp/1/tagid", New: json.RawMessage(`"tag2"`)},
+				{Op: "rename", Path: "/imp/0/ext/bidder/tag", From: "/imp/0/tagid", New: json.RawMessage(`"tag1"`)},
+			},
+		},
+		{
+			name:  "cap_imps",
+			rules: []config.BidderTransformRule{{Bidders: []string{"appnexus"}, Op: config.BidderTransformCapImps, MaxImps: 1}},
+			expectedRequest: func(r *openrtb2.BidRequest) {
+				r.Imp = r.Imp[:1]
+			},
+			expectedChanges: []openrtb_ext.ExtBidderTransformChange{
+				{Op: "cap_imps", Path: "/imp", Old: json.RawMessage(`3`), New: json.RawMessage(`1`)},
+			},
+		},
+		{
+			name:  "strip_formats",
+			rules: []config.BidderTransformRule{{Bidders: []string{"appnexus"}, Op: config.BidderTransformStripFormats, MediaTypes: []openrtb_ext.BidType{openrtb_ext.BidTypeVideo}}},
+			expectedRequest: func(r *openrtb2.BidRequest) {
+				r.Imp[2].Video = nil
+				r.Imp = []openrtb2.Imp{r.Imp[0], r.Imp[2]}
+			},
+			expectedChanges: []openrtb_ext.ExtBidderTransformChange{
+				{Op: "strip_formats", Path: "/imp/1/video", Old: json.RawMessage(`{"mimes":["video/mp4"]}`)},
+				{Op: "strip_formats", Path: "/imp/1", Old: json.RawMessage(`{"id":"imp2","tagid":"tag2","ext":{"bidder":{"placementId":2}}}`)},
+				{Op: "strip_formats", Path: "/imp/2/video", Old: json.RawMessage(`{"mimes":["video/mp4"]}`)},
+			},
+		},
+		{
+			name:  "map_params",
+			rules: []config.BidderTransformRule{{Bidders: []string{"appnexus"}, Op: config.BidderTransformMapParams, Params: map[string]string{"placementId": "size", "size": "placementId"}}},
+			expectedRequest: func(r *openrtb2.BidRequest) {
+				r.Imp[0].Ext = json.RawMessage(`{"bidder":{"placementId":1,"size":12345678901234567}}`)
+				r.Imp[1].Ext = json.RawMessage(`{"bidder":{"size":2}}`)
+			},
+			expectedChanges: []openrtb_ext.ExtBidderTransformChange{
+				{Op: "map_params", Path: "/imp/0/ext/bidder/size", From: "/imp/0/ext/bidder/placementId", New: json.RawMessage(`12345678901234567`)},
+				{Op: "map_params", Path: "/imp/0/ext/bidder/placementId", From: "/imp/0/ext/bidder/size", New: json.RawMessage(`1`)},
+				{Op: "map_params", Path: "/imp/1/ext/bidder/size", From: "/imp/1/ext/bidder/placementId", New: json.RawMessage(`2`)},
+			},
+		},
+		{
+			name: "failing_rule_discards_all",
+			rules: []config.BidderTransformRule{
+				{Bidders: []string{"appnexus"}, Op: config.BidderTransformSet, Path: "/tmax", Value: 500},
+				{Bidders: []string{"appnexus"}, Op: config.BidderTransformSet, Path: "/site/page/ext", Value: 1},
+			},
+			expectedWarnings: []string{"bidder request transformations discarded: set rule failed: /site/page/ext: cannot set field ext of a scalar value"},
+		},
+		{
+			name:             "invalid_transformed_request",
+			rules:            []config.BidderTransformRule{{Bidders: []string{"appnexus"}, Op: config.BidderTransformSet, Path: "/tmax", Value: "500"}},
+			expectedWarnings: []string{"bidder request transformations discarded: set rule failed: /tmax is invalid"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := newRequest()
+			wrapper := &openrtb_ext.RequestWrapper{BidRequest: request}
+			changes, err := applyBidderTransforms(wrapper, "appnexus", config.AccountBidderTransforms{Rules: tt.rules})
+			transformed := wrapper.BidRequest
+
+			expectedRequest := newRequest()
+			if tt.expectedRequest != nil {
+				tt.expectedRequest(expectedRequest)
+			}
+			assert.Equal(t, expectedRequest, transformed)
+			assert.Equal(t, tt.expectedChanges, changes)
+			assert.Equal(t, newRequest(), request, "the original request must not be modified")
+
+			if len(tt.expectedWarnings) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.expectedWarnings[0])
+			assert.Equal(t, errortypes.BidderTransformWarningCode, errortypes.ReadCode(err))
+		})
+	}
+}
@@ -35,6 +35,7 @@ type PbsOrtbSeatBid struct {
 // PbsOrtbBid.BidVideo is optional but should be filled out by the Bidder if BidType is video.
 // PbsOrtbBid.BidEvents is set by exchange when event tracking is enabled
 // PbsOrtbBid.BidFloors is set by exchange when floors is enabled
+// PbsOrtbBid.BidClearing is set by exchange when the winning bid clears at a price other than the bid price
 // PbsOrtbBid.DealPriority is optionally provided by adapters and used internally by the exchange to support deal targeted campaigns.
 // PbsOrtbBid.DealTierSatisfied is set to true by exchange.updateHbPbCatDur if deal tier satisfied otherwise it will be set to false
 // PbsOrtbBid.GeneratedBidID is unique Bid id generated by prebid server if generate Bid id option is enabled in config
@@ -47,6 +48,7 @@ type PbsOrtbBid struct {
 	BidVideo            *openrtb_ext.ExtBidPrebidVideo
 	BidEvents           *openrtb_ext.ExtBidPrebidEvents
 	BidFloors           *openrtb_ext.ExtBidPrebidFloors
+	BidClearing         *openrtb_ext.ExtBidPrebidClearing
 	DealPriority        int
 	DealTierSatisfied   bool
 	GeneratedBidID      string
@@ -31,12 +31,13 @@ import (
 	"github.com/prebid/prebid-server/v3/logger"
 	"github.com/prebid/prebid-server/v3/macros"
 	"github.com/prebid/prebid-server/v3/metrics"
-	"github.com/prebid/prebid-server/v3/modules/pubmatic/openwrap/models/nbr"
 	"github.com/prebid/prebid-server/v3/modules/pubmatic/openwrap/utils"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/ortb"
 	"github.com/prebid/prebid-server/v3/prebid_cache_client"
 	"github.com/prebid/prebid-server/v3/privacy"
+	"github.com/prebid/prebid-server/v3/privacysandbox"
+	"github.com/prebid/prebid-server/v3/schain"
 	"github.com/prebid/prebid-server/v3/stored_requests"
 	"github.com/prebid/prebid-server/v3/stored_responses"
 	"github.com/prebid/prebid-server/v3/usersync"
@@ -84,11 +85,15 @@ type exchange struct {
 	bidValidationEnforcement config.Validations
 	requestSplitter          requestSplitter
 	macroReplacer            macros.Replacer
+	auctionMacroReplacer     macros.Replacer
+	priceEncoders            macros.PriceEncoders
 	priceFloorEnabled        bool
 	priceFloorFetcher        floors.FloorFetcher
+	floorSuggester           floors.FloorSuggester
 	singleFormatBidders      map[openrtb_ext.BidderName]struct{}
 	floor                    config.PriceFloors
 	trackerURL               string
+	sChainValidator          *schain.Validator
 }
 
 // Container to pass out response ext data from the GetAllBids goroutines back into the main thread
@@ -101,6 +106,9 @@ type seatResponseExtra struct {
 	HttpCalls []*openrtb_ext.ExtHttpCall
 	// NonBid contains non bid reason information
 	NonBid *openrtb_ext.NonBid
+	// BidderTransforms lists the changes made to the request by the account transformation rules.
+	// This will become response.ext.debug.biddertransforms.{bidder} on the final Response.
+	BidderTransforms []openrtb_ext.ExtBidderTransformChange
 }
 
 type bidResponseWrapper struct {
@@ -140,7 +148,7 @@ func (randomDeduplicateBidBooleanGenerator) Generate() bool {
 	return rand.Intn(100) < 50
 }
 
-func NewExchange(adapters map[openrtb_ext.BidderName]AdaptedBidder, cache prebid_cache_client.Client, cfg *config.Configuration, requestValidator ortb.RequestValidator, syncersByBidder map[string]usersync.Syncer, metricsEngine metrics.MetricsEngine, infos config.BidderInfos, gdprPermsBuilder gdpr.PermissionsBuilder, currencyConverter *currency.RateConverter, categoriesFetcher stored_requests.CategoryFetcher, adsCertSigner adscert.Signer, macroReplacer macros.Replacer, priceFloorFetcher floors.FloorFetcher, singleFormatBidders map[openrtb_ext.BidderName]struct{}) Exchange {
+func NewExchange(adapters map[openrtb_ext.BidderName]AdaptedBidder, cache prebid_cache_client.Client, cfg *config.Configuration, requestValidator ortb.RequestValidator, syncersByBidder map[string]usersync.Syncer, metricsEngine metrics.MetricsEngine, infos config.BidderInfos, gdprPermsBuilder gdpr.PermissionsBuilder, currencyConverter *currency.RateConverter, categoriesFetcher stored_requests.CategoryFetcher, adsCertSigner adscert.Signer, macroReplacer macros.Replacer, priceFloorFetcher floors.FloorFetcher, floorSuggester floors.FloorSuggester, priceEncoders macros.PriceEncoders, singleFormatBidders map[openrtb_ext.BidderName]struct{}) Exchange {
 	bidderToSyncerKey := map[string]string{}
 	for bidder, syncer := range syncersByBidder {
 		bidderToSyncerKey[bidder] = syncer.Key()
@@ -161,6 +169,13 @@ func NewExchange(adapters map[openrtb_ext.BidderName]AdaptedBidder, cache prebid
 		requestValidator:  requestValidator,
 	}
 
+	// the sellers.json files are checked by the config validation, the supply chains aren't validated if they can't
+	// be loaded anymore
+	sChainValidator, err := schain.NewValidator(cfg.SChainValidation)
+	if err != nil {
+		logger.Errorf("Failed to create the schain validator, the supply chains won't be validated: %v", err)
+	}
+
 	return &exchange{
 		adapterMap:               adapters,
 		bidderInfo:               infos,
@@ -180,11 +195,15 @@ func NewExchange(adapters map[openrtb_ext.BidderName]AdaptedBidder, cache prebid
 		bidValidationEnforcement: cfg.Validations,
 		requestSplitter:          requestSplitter,
 		macroReplacer:            macroReplacer,
+		auctionMacroReplacer:     macros.NewAuctionMacroReplacer(),
+		priceEncoders:            priceEncoders,
 		priceFloorEnabled:        cfg.PriceFloors.Enabled,
 		priceFloorFetcher:        priceFloorFetcher,
+		floorSuggester:           floorSuggester,
 		singleFormatBidders:      singleFormatBidders,
 		floor:                    cfg.PriceFloors,
 		trackerURL:               cfg.TrackerURL,
+		sChainValidator:          sChainValidator,
 	}
 }
 
@@ -225,6 +244,8 @@ type AuctionRequest struct {
 	TmaxAdjustments         *TmaxAdjustmentsPreprocessed
 	GDPRSignal              gdpr.Signal
 	GDPREnforced            bool
+	// PrivacyAudit collects the privacy decisions taken for each bidder, decisions are not recorded when nil
+	PrivacyAudit *privacy.AuditTrail
 }
 
 // BidderRequest holds the bidder specific request and all other
@@ -273,6 +294,7 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 	targData, warning := getExtTargetData(requestExtPrebid, cacheInstructions, r.Account)
 	if targData != nil {
 		_, targData.cacheHost, targData.cachePath = e.cache.GetExtCacheData()
+		targData.currency = getAuctionCurrency(r.BidRequestWrapper.Cur)
 	}
 
 	for _, w := range warning {
@@ -336,6 +358,10 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 		return nil, err
 	}
 
+	if e.sChainValidator != nil {
+		r.Warnings = append(r.Warnings, e.validateSChains(r.BidRequestWrapper, requestExt, requestExtPrebid)...)
+	}
+
 	// rebuild/resync the request in the request wrapper.
 	if err := r.BidRequestWrapper.RebuildRequest(); err != nil {
 		return nil, err
@@ -346,6 +372,9 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 		Prebid: *requestExtPrebid,
 		SChain: requestExt.GetSChain(),
 	}
+	if r.PrivacyAudit == nil {
+		r.PrivacyAudit = &privacy.AuditTrail{}
+	}
 	bidderRequests, privacyLabels, errs := e.requestSplitter.cleanOpenRTBRequests(ctx, *r, requestExtLegacy, bidAdjustmentFactors)
 	for _, err := range errs {
 		if errortypes.ReadCode(err) == errortypes.InvalidImpFirstPartyDataErrorCode {
@@ -433,6 +462,11 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 		recordBids(ctx, e.me, r.PubID, adapterBids)
 		recordVastVersion(e.me, adapterBids)
 
+		if e.floorSuggester != nil {
+			// observe the landscape before enforcement so rejected bids still inform suggested floors
+			e.floorSuggester.RecordBids(r.Account, r.BidRequestWrapper, adapterBids, conversions)
+		}
+
 		if requestExtPrebid.GoogleSSUFeatureEnabled {
 			validationErrs := filterBidsByVastVersion(adapterBids, &seatNonBidBuilder)
 			errs = append(errs, validationErrs...)
@@ -505,6 +539,14 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 			}
 		}
 
+		preferDeals := targData != nil && targData.preferDeals
+		winningBids := selectWinningBids(adapterBids, preferDeals)
+		clearingErrs := applyPriceClearing(r.BidRequestWrapper, adapterBids, winningBids, r.Account.PriceClearing, conversions)
+		errs = append(errs, clearingErrs...)
+
+		macroErrs := expandAuctionMacros(r.BidRequestWrapper, adapterBids, winningBids, r.Account.AuctionMacros, e.auctionMacroReplacer, e.priceEncoders)
+		errs = append(errs, macroErrs...)
+
 		evTracking := getEventTracking(requestExtPrebid, r.StartTime, &r.Account, e.bidderInfo, e.externalURL,
 			OpenWrapEventTracking{
 				enabledVideoEvents: requestExtPrebid == nil || !requestExtPrebid.ExtOWRequestPrebid.TrackerDisabled,
@@ -518,7 +560,7 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 			multiBidMap := buildMultiBidMap(requestExtPrebid)
 
 			// A non-nil auction is only needed if targeting is active. (It is used below this block to extract cache keys)
-			auc = newAuction(adapterBids, len(r.BidRequestWrapper.Imp), targData.preferDeals)
+			auc = newAuction(adapterBids, winningBids, len(r.BidRequestWrapper.Imp))
 			auc.validateAndUpdateMultiBid(adapterBids, targData.preferDeals, r.Account.DefaultBidLimit)
 			auc.setRoundedPrices(*targData, r.Account)
 
@@ -537,7 +579,7 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 				}
 			}
 
-			cacheErrs = auc.doCache(ctx, e.cache, targData, evTracking, r.BidRequestWrapper.BidRequest, 60, &r.Account.CacheTTL, bidCategory, debugLog)
+			cacheErrs = auc.doCache(ctx, e.cache, targData, evTracking, r.BidRequestWrapper.BidRequest, 60, &r.Account.CacheTTL, bidCategory, debugLog, r.Account.ReturnCreativeOnCacheFailure)
 			if len(cacheErrs) > 0 {
 				errs = append(errs, cacheErrs...)
 			}
@@ -610,6 +652,8 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 		BidResponse:    bidResponse,
 		ExtBidResponse: bidResponseExt,
 		SeatNonBid:     seatNonBidBuilder,
+		PrivacyAudit:   r.PrivacyAudit.Records(),
+		DSA:            dsa.Records(bidResponse),
 	}, nil
 }
 
@@ -837,6 +881,7 @@ func (e *exchange) getAllBids(
 				tmaxAdjustments:        tmaxAdjustments,
 				bidderRequestStartTime: start,
 				responseDebugAllowed:   responseDebugAllowed,
+				bidderTransforms:       account.BidderTransforms,
 			}
 			seatBids, extraBidderRespInfo, err := e.adapterMap[bidderRequest.BidderCoreName].requestBid(ctx, bidderRequest, conversions, &reqInfo, e.adsCertSigner, bidReqOptions, alternateBidderCodes, hookExecutor, bidAdjustmentRules)
 			brw.bidderResponseStartTime = extraBidderRespInfo.respProcessingStartTime
@@ -845,12 +890,14 @@ func (e *exchange) getAllBids(
 			elapsed := time.Since(start)
 			brw.adapterSeatBids = seatBids
 			brw.seatNonBidBuilder = extraBidderRespInfo.seatNonBidBuilder
+			err = append(err, e.validateFledge(bidderRequest, seatBids)...)
 			// Structure to record extra tracking data generated during bidding
 			ae := new(seatResponseExtra)
 			ae.ResponseTimeMillis = int(elapsed / time.Millisecond)
 			if len(seatBids) != 0 {
 				ae.HttpCalls = seatBids[0].HttpCalls
 			}
+			ae.BidderTransforms = extraBidderRespInfo.transformChanges
 			// Timing statistics
 			e.me.RecordAdapterTime(bidderRequest.BidderLabels, elapsed)
 			bidderRequest.BidderLabels.AdapterBids = bidsToMetric(brw.adapterSeatBids)
@@ -935,6 +982,32 @@ func (e *exchange) getAllBids(
 	return adapterBids, adapterExtra, extraRespInfo
 }
 
+// validateFledge drops the auction configs of the bidder which can't run as a component auction of their imp, and
+// returns a warning for each of them. The seller signals of the imps are merged into the kept configs.
+func (e *exchange) validateFledge(bidderRequest BidderRequest, seatBids []*entities.PbsOrtbSeatBid) []error {
+	var paapiImps map[string]privacysandbox.PAAPIImp
+	var warnings []error
+	for _, seatBid := range seatBids {
+		if seatBid == nil || len(seatBid.FledgeAuctionConfigs) == 0 {
+			continue
+		}
+		if paapiImps == nil {
+			paapiImps = privacysandbox.PAAPIImps(bidderRequest.BidRequest.Imp)
+		}
+
+		validConfigs, configWarnings := privacysandbox.ValidateAuctionConfigs(seatBid.FledgeAuctionConfigs, paapiImps)
+		if len(validConfigs) > 0 {
+			e.me.RecordAdapterFledgeAuctionConfigs(bidderRequest.BidderCoreName, metrics.FledgeAuctionConfigAccepted, len(validConfigs))
+		}
+		if len(configWarnings) > 0 {
+			e.me.RecordAdapterFledgeAuctionConfigs(bidderRequest.BidderCoreName, metrics.FledgeAuctionConfigRejected, len(configWarnings))
+		}
+		seatBid.FledgeAuctionConfigs = validConfigs
+		warnings = append(warnings, configWarnings...)
+	}
+	return warnings
+}
+
 func collectFledgeFromSeatBid(fledge *openrtb_ext.Fledge, bidderName openrtb_ext.BidderName, adapterName openrtb_ext.BidderName, seatBid *entities.PbsOrtbSeatBid) *openrtb_ext.Fledge {
 	if seatBid.FledgeAuctionConfigs != nil {
 		if fledge == nil {
@@ -1192,7 +1265,7 @@ func applyCategoryMapping(ctx context.Context, r *AuctionRequest, targeting open
 
 			// TODO: consider should we remove bids with zero duration here?
 
-			priceBucket = GetPriceBucket(*bid.Bid, *targData, r.Account)
+			priceBucket = GetPriceBucket(*bid.Bid, bidderName, *targData, r.Account)
 
 			newDur, err := findDurationRange(duration, targeting.DurationRangeSec)
 			if err != nil {
@@ -1398,11 +1471,24 @@ func (e *exchange) makeExtBidResponse(adapterBids map[openrtb_ext.BidderName]*en
 		}
 	}
 
+	if debugInfo && len(r.PrivacyAudit.Records()) > 0 {
+		if bidResponseExt.Prebid == nil {
+			bidResponseExt.Prebid = &openrtb_ext.ExtResponsePrebid{}
+		}
+		bidResponseExt.Prebid.PrivacyAudit = r.PrivacyAudit.Records()
+	}
+
 	for bidderName, responseExtra := range adapterExtra {
 
 		if debugInfo && len(responseExtra.HttpCalls) > 0 {
 			bidResponseExt.Debug.HttpCalls[bidderName] = responseExtra.HttpCalls
 		}
+		if debugInfo && len(responseExtra.BidderTransforms) > 0 {
+			if bidResponseExt.Debug.BidderTransforms == nil {
+				bidResponseExt.Debug.BidderTransforms = make(map[openrtb_ext.BidderName][]openrtb_ext.ExtBidderTransformChange)
+			}
+			bidResponseExt.Debug.BidderTransforms[bidderName] = responseExtra.BidderTransforms
+		}
 		if len(responseExtra.Warnings) > 0 {
 			bidResponseExt.Warnings[bidderName] = responseExtra.Warnings
 		}
@@ -1453,7 +1539,7 @@ func (e *exchange) makeBid(bids []*entities.PbsOrtbBid, auc *auction, returnCrea
 			}
 			bidResponseExt.Warnings[adapter] = append(bidResponseExt.Warnings[adapter], dsaMessage)
 			nonBidParams := entities.GetNonBidParamsFromPbsOrtbBid(bid, adapter.String())
-			nonBidParams.NonBidReason = int(nbr.ResponseRejectedDSA)
+			nonBidParams.NonBidReason = int(dsaErrorToNonBidReason(err))
 			seatNonBidBuilder.AddBid(openrtb_ext.NewNonBid(nonBidParams), adapter.String())
 			continue // Don't add bid to result
 		}
@@ -1486,6 +1572,7 @@ func (e *exchange) makeBid(bids []*entities.PbsOrtbBid, auc *auction, returnCrea
 			Events:            bid.BidEvents,
 			Targeting:         bid.BidTargets,
 			Floors:            bid.BidFloors,
+			Clearing:          bid.BidClearing,
 			Type:              bid.BidType,
 			Meta:              bid.BidMeta,
 			Video:             bid.BidVideo,
@@ -1509,7 +1596,7 @@ func (e *exchange) makeBid(bids []*entities.PbsOrtbBid, auc *auction, returnCrea
 			result = append(result, *bid.Bid)
 			resultBid := &result[len(result)-1]
 			resultBid.Ext = bidExtJSON
-			if !returnCreative {
+			if !returnCreative && !auc.returnsUncachedCreative(bid.Bid) {
 				resultBid.AdM = ""
 			}
 		}
@@ -272,7 +272,7 @@ func GetPriceBucketOW(cpm float64, config openrtb_ext.PriceGranularity, account
 		priceGranularity:          *newPG,
 		mediaTypePriceGranularity: openrtb_ext.MediaTypePriceGranularity{},
 	}
-	return GetPriceBucket(bid, targetData, account)
+	return GetPriceBucket(bid, "", targetData, account)
 }
 
 func setPriceGranularityOW(pg *openrtb_ext.PriceGranularity) *openrtb_ext.PriceGranularity {
@@ -6,7 +6,9 @@ import (
 	"syscall"
 
 	"github.com/prebid/openrtb/v20/openrtb3"
+	"github.com/prebid/prebid-server/v3/dsa"
 	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/modules/pubmatic/openwrap/models/nbr"
 )
 
 // SeatNonBid list the reasons why bid was not resulted in positive bid
@@ -55,6 +57,24 @@ func errorToNonBidReason(err error) openrtb3.NoBidReason {
 	}
 }
 
+// dsaErrorToNonBidReason returns the non bid reason of a bid rejected by the DSA validation
+func dsaErrorToNonBidReason(err error) openrtb3.NoBidReason {
+	switch err {
+	case dsa.ErrBehalfTooLong:
+		return nbr.ResponseRejectedDSABehalfTooLong
+	case dsa.ErrPaidTooLong:
+		return nbr.ResponseRejectedDSAPaidTooLong
+	case dsa.ErrNeitherWillRender, dsa.ErrBothWillRender:
+		return nbr.ResponseRejectedDSARenderConflict
+	case dsa.ErrTransparencyDomainInvalid, dsa.ErrTransparencyParamsInvalid:
+		return nbr.ResponseRejectedDSATransparencyInvalid
+	case dsa.ErrTransparencyParamsNotDeclared:
+		return nbr.ResponseRejectedDSAParamsNotDeclared
+	default:
+		return nbr.ResponseRejectedDSA
+	}
+}
+
 // httpInfoToNonBidReason determines NoBidReason code (NBR)
 // It will first try to resolve the NBR based on prebid's proprietary error code.
 // If proprietary error code not found then it will try to determine NBR using
@@ -7,7 +7,9 @@ import (
 	"testing"
 
 	"github.com/prebid/openrtb/v20/openrtb3"
+	"github.com/prebid/prebid-server/v3/dsa"
 	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/modules/pubmatic/openwrap/models/nbr"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -64,3 +66,25 @@ func Test_httpInfoToNonBidReason(t *testing.T) {
 		})
 	}
 }
+
+func Test_dsaErrorToNonBidReason(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want openrtb3.NoBidReason
+	}{
+		{name: "missing", err: dsa.ErrDsaMissing, want: nbr.ResponseRejectedDSA},
+		{name: "behalf-too-long", err: dsa.ErrBehalfTooLong, want: nbr.ResponseRejectedDSABehalfTooLong},
+		{name: "paid-too-long", err: dsa.ErrPaidTooLong, want: nbr.ResponseRejectedDSAPaidTooLong},
+		{name: "neither-will-render", err: dsa.ErrNeitherWillRender, want: nbr.ResponseRejectedDSARenderConflict},
+		{name: "both-will-render", err: dsa.ErrBothWillRender, want: nbr.ResponseRejectedDSARenderConflict},
+		{name: "transparency-domain-invalid", err: dsa.ErrTransparencyDomainInvalid, want: nbr.ResponseRejectedDSATransparencyInvalid},
+		{name: "transparency-params-invalid", err: dsa.ErrTransparencyParamsInvalid, want: nbr.ResponseRejectedDSATransparencyInvalid},
+		{name: "transparency-params-not-declared", err: dsa.ErrTransparencyParamsNotDeclared, want: nbr.ResponseRejectedDSAParamsNotDeclared},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, dsaErrorToNonBidReason(tt.err))
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package exchange
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/currency"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/floors"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+)
+
+// impClearingCandidates holds the bids of an imp needed to compute the clearing price of its winner
+type impClearingCandidates struct {
+	winner       *entities.PbsOrtbBid
+	winnerCur    string
+	secondPrice  float64
+	winnerBidder openrtb_ext.BidderName
+}
+
+// applyPriceClearing updates the price of the winning bid of every imp according to the account clearing mode.
+// The winners are selected beforehand by selectWinningBids and keep winning the auction once cleared. With second
+// price clearing the winner pays max(second highest bid, soft floor) plus the configured increment, capped at its own
+// bid. Winning bids below the soft floor and deal bids clear at the bid price.
+// Bid adjustments are applied by the bidders before this point, so the clearing uses the adjusted prices.
+func applyPriceClearing(reqWrapper *openrtb_ext.RequestWrapper, seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, winningBids map[string]*entities.PbsOrtbBid, cfg config.AccountPriceClearing, conversions currency.Conversions) []error {
+	if !cfg.IsSecondPrice() || len(seatBids) == 0 {
+		return nil
+	}
+
+	var errs []error
+	candidates := getImpClearingCandidates(seatBids, winningBids)
+	impsByID := make(map[string]*openrtb_ext.ImpWrapper, reqWrapper.LenImp())
+	for _, imp := range reqWrapper.GetImp() {
+		impsByID[imp.ID] = imp
+	}
+
+	for impID, candidate := range candidates {
+		winner := candidate.winner
+		if winner.Bid.DealID != "" {
+			continue
+		}
+
+		var hardFloor float64
+		if imp, ok := impsByID[impID]; ok {
+			var err error
+			hardFloor, err = floors.GetImpFloor(imp.Imp, candidate.winnerCur, conversions)
+			if err != nil {
+				errs = append(errs, &errortypes.Warning{
+					Message:     fmt.Sprintf("%s bid id %s clears at bid price - unable to convert floor for imp %s: %s", candidate.winnerBidder, winner.Bid.ID, impID, err.Error()),
+					WarningCode: errortypes.PriceClearingWarningCode,
+				})
+				continue
+			}
+		}
+
+		bidPrice := winner.Bid.Price
+		softFloor := floors.GetSoftFloor(hardFloor, cfg.SoftFloorMultiplier)
+		clearPrice := bidPrice
+		if bidPrice >= softFloor {
+			clearPrice = math.Min(bidPrice, math.Max(candidate.secondPrice, softFloor)+cfg.Increment)
+			clearPrice = math.Round(clearPrice*10000) / 10000
+		}
+
+		if clearPrice == bidPrice {
+			continue
+		}
+		winner.Bid.Price = clearPrice
+		winner.BidClearing = &openrtb_ext.ExtBidPrebidClearing{
+			Mode:       string(cfg.Mode),
+			BidPrice:   bidPrice,
+			ClearPrice: clearPrice,
+		}
+	}
+	return errs
+}
+
+// getImpClearingCandidates returns the winning bid and the highest price of the other bids for every imp across
+// all seats
+func getImpClearingCandidates(seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, winningBids map[string]*entities.PbsOrtbBid) map[string]*impClearingCandidates {
+	candidates := make(map[string]*impClearingCandidates, len(winningBids))
+	for impID, winner := range winningBids {
+		candidates[impID] = &impClearingCandidates{winner: winner}
+	}
+
+	for bidderName, seatBid := range seatBids {
+		if seatBid == nil {
+			continue
+		}
+		for _, pbsBid := range seatBid.Bids {
+			if pbsBid == nil || pbsBid.Bid == nil {
+				continue
+			}
+
+			candidate, ok := candidates[pbsBid.Bid.ImpID]
+			if !ok {
+				continue
+			}
+			if pbsBid == candidate.winner {
+				candidate.winnerCur = seatBid.Currency
+				candidate.winnerBidder = bidderName
+			} else {
+				candidate.secondPrice = math.Max(candidate.secondPrice, pbsBid.Bid.Price)
+			}
+		}
+	}
+	return candidates
+}
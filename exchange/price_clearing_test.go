@@ -0,0 +1,238 @@
+package exchange
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/currency"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyPriceClearing(t *testing.T) {
+	secondPrice := config.AccountPriceClearing{Mode: config.ClearingModeSecondPrice, Increment: 0.01}
+	conversions := currency.Conversions(currency.NewRates(map[string]map[string]float64{"USD": {"EUR": 0.5}}))
+
+	type expectedBid struct {
+		price    float64
+		clearing *openrtb_ext.ExtBidPrebidClearing
+	}
+	tests := []struct {
+		name           string
+		cfg            config.AccountPriceClearing
+		preferDeals    bool
+		imps           []openrtb2.Imp
+		bids           map[openrtb_ext.BidderName][]*openrtb2.Bid
+		expectedBids   map[string]expectedBid
+		expectedErrors []error
+	}{
+		{
+			name: "first_price_unchanged",
+			cfg:  config.AccountPriceClearing{Mode: config.ClearingModeFirstPrice},
+			imps: []openrtb2.Imp{{ID: "imp1"}},
+			bids: map[openrtb_ext.BidderName][]*openrtb2.Bid{
+				"pubmatic": {{ID: "bid1", ImpID: "imp1", Price: 5}},
+				"appnexus": {{ID: "bid2", ImpID: "imp1", Price: 3}},
+			},
+			expectedBids: map[string]expectedBid{
+				"bid1": {price: 5},
+				"bid2": {price: 3},
+			},
+		},
+		{
+			name: "second_price_winner_pays_runner_up_plus_increment",
+			cfg:  secondPrice,
+			imps: []openrtb2.Imp{{ID: "imp1", BidFloor: 1, BidFloorCur: "USD"}},
+			bids: map[openrtb_ext.BidderName][]*openrtb2.Bid{
+				"pubmatic": {{ID: "bid1", ImpID: "imp1", Price: 5}},
+				"appnexus": {{ID: "bid2", ImpID: "imp1", Price: 3}},
+			},
+			expectedBids: map[string]expectedBid{
+				"bid1": {price: 3.01, clearing: &openrtb_ext.ExtBidPrebidClearing{Mode: "second_price", BidPrice: 5, ClearPrice: 3.01}},
+				"bid2": {price: 3},
+			},
+		},
+		{
+			name: "second_price_single_bid_pays_floor_plus_increment",
+			cfg:  secondPrice,
+			imps: []openrtb2.Imp{{ID: "imp1", BidFloor: 1, BidFloorCur: "USD"}},
+			bids: map[openrtb_ext.BidderName][]*openrtb2.Bid{
+				"pubmatic": {{ID: "bid1", ImpID: "imp1", Price: 5}},
+			},
+			expectedBids: map[string]expectedBid{
+				"bid1": {price: 1.01, clearing: &openrtb_ext.ExtBidPrebidClearing{Mode: "second_price", BidPrice: 5, ClearPrice: 1.01}},
+			},
+		},
+		{
+			name: "second_price_capped_at_bid_price",
+			cfg:  secondPrice,
+			imps: []openrtb2.Imp{{ID: "imp1"}},
+			bids: map[openrtb_ext.BidderName][]*openrtb2.Bid{
+				"pubmatic": {{ID: "bid1", ImpID: "imp1", Price: 3}},
+				"appnexus": {{ID: "bid2", ImpID: "imp1", Price: 3}},
+			},
+			expectedBids: map[string]expectedBid{
+				"bid1": {price: 3},
+				"bid2": {price: 3},
+			},
+		},
+		{
+			name: "soft_floor_bid_between_hard_and_soft_floor_clears_at_bid",
+			cfg:  config.AccountPriceClearing{Mode: config.ClearingModeSecondPrice, Increment: 0.01, SoftFloorMultiplier: 2},
+			imps: []openrtb2.Imp{{ID: "imp1", BidFloor: 1, BidFloorCur: "USD"}},
+			bids: map[openrtb_ext.BidderName][]*openrtb2.Bid{
+				"pubmatic": {{ID: "bid1", ImpID: "imp1", Price: 1.5}},
+				"appnexus": {{ID: "bid2", ImpID: "imp1", Price: 1.1}},
+			},
+			expectedBids: map[string]expectedBid{
+				"bid1": {price: 1.5},
+				"bid2": {price: 1.1},
+			},
+		},
+		{
+			name: "soft_floor_bid_above_soft_floor_clears_at_soft_floor",
+			cfg:  config.AccountPriceClearing{Mode: config.ClearingModeSecondPrice, Increment: 0.01, SoftFloorMultiplier: 2},
+			imps: []openrtb2.Imp{{ID: "imp1", BidFloor: 1, BidFloorCur: "USD"}},
+			bids: map[openrtb_ext.BidderName][]*openrtb2.Bid{
+				"pubmatic": {{ID: "bid1", ImpID: "imp1", Price: 4}},
+				"appnexus": {{ID: "bid2", ImpID: "imp1", Price: 1.1}},
+			},
+			expectedBids: map[string]expectedBid{
+				"bid1": {price: 2.01, clearing: &openrtb_ext.ExtBidPrebidClearing{Mode: "second_price", BidPrice: 4, ClearPrice: 2.01}},
+				"bid2": {price: 1.1},
+			},
+		},
+		{
+			name: "floor_converted_to_bid_currency",
+			cfg:  secondPrice,
+			imps: []openrtb2.Imp{{ID: "imp1", BidFloor: 4, BidFloorCur: "EUR"}},
+			bids: map[openrtb_ext.BidderName][]*openrtb2.Bid{
+				"pubmatic": {{ID: "bid1", ImpID: "imp1", Price: 10}},
+			},
+			expectedBids: map[string]expectedBid{
+				"bid1": {price: 8.01, clearing: &openrtb_ext.ExtBidPrebidClearing{Mode: "second_price", BidPrice: 10, ClearPrice: 8.01}},
+			},
+		},
+		{
+			name: "floor_conversion_failure_clears_at_bid",
+			cfg:  secondPrice,
+			imps: []openrtb2.Imp{{ID: "imp1", BidFloor: 4, BidFloorCur: "JPY"}},
+			bids: map[openrtb_ext.BidderName][]*openrtb2.Bid{
+				"pubmatic": {{ID: "bid1", ImpID: "imp1", Price: 10}},
+			},
+			expectedBids: map[string]expectedBid{
+				"bid1": {price: 10},
+			},
+			expectedErrors: []error{&errortypes.Warning{
+				Message:     "pubmatic bid id bid1 clears at bid price - unable to convert floor for imp imp1: Currency conversion rate not found: 'JPY' => 'USD'",
+				WarningCode: errortypes.PriceClearingWarningCode,
+			}},
+		},
+		{
+			name:        "prefer_deals_deal_bid_wins_and_clears_at_bid",
+			cfg:         secondPrice,
+			preferDeals: true,
+			imps:        []openrtb2.Imp{{ID: "imp1"}},
+			bids: map[openrtb_ext.BidderName][]*openrtb2.Bid{
+				"pubmatic": {{ID: "bid1", ImpID: "imp1", Price: 2, DealID: "deal1"}},
+				"appnexus": {{ID: "bid2", ImpID: "imp1", Price: 5}},
+			},
+			expectedBids: map[string]expectedBid{
+				"bid1": {price: 2},
+				"bid2": {price: 5},
+			},
+		},
+		{
+			name: "without_prefer_deals_highest_bid_wins",
+			cfg:  secondPrice,
+			imps: []openrtb2.Imp{{ID: "imp1"}},
+			bids: map[openrtb_ext.BidderName][]*openrtb2.Bid{
+				"pubmatic": {{ID: "bid1", ImpID: "imp1", Price: 2, DealID: "deal1"}},
+				"appnexus": {{ID: "bid2", ImpID: "imp1", Price: 5}},
+			},
+			expectedBids: map[string]expectedBid{
+				"bid1": {price: 2},
+				"bid2": {price: 2.01, clearing: &openrtb_ext.ExtBidPrebidClearing{Mode: "second_price", BidPrice: 5, ClearPrice: 2.01}},
+			},
+		},
+		{
+			name: "deal_bid_clears_at_bid",
+			cfg:  secondPrice,
+			imps: []openrtb2.Imp{{ID: "imp1"}},
+			bids: map[openrtb_ext.BidderName][]*openrtb2.Bid{
+				"pubmatic": {{ID: "bid1", ImpID: "imp1", Price: 5, DealID: "deal1"}},
+				"appnexus": {{ID: "bid2", ImpID: "imp1", Price: 3}},
+			},
+			expectedBids: map[string]expectedBid{
+				"bid1": {price: 5},
+				"bid2": {price: 3},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqWrapper := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Imp: tt.imps}}
+			seatBids := make(map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid)
+			pbsBids := make(map[string]*entities.PbsOrtbBid)
+			for bidder, bids := range tt.bids {
+				seatBid := &entities.PbsOrtbSeatBid{Currency: "USD"}
+				for _, bid := range bids {
+					pbsBid := &entities.PbsOrtbBid{Bid: bid}
+					seatBid.Bids = append(seatBid.Bids, pbsBid)
+					pbsBids[bid.ID] = pbsBid
+				}
+				seatBids[bidder] = seatBid
+			}
+
+			errs := applyPriceClearing(reqWrapper, seatBids, selectWinningBids(seatBids, tt.preferDeals), tt.cfg, conversions)
+			assert.Equal(t, tt.expectedErrors, errs)
+			for bidID, expected := range tt.expectedBids {
+				assert.Equal(t, expected.price, pbsBids[bidID].Bid.Price, bidID)
+				assert.Equal(t, expected.clearing, pbsBids[bidID].BidClearing, bidID)
+			}
+		})
+	}
+}
+
+func TestGetImpClearingCandidates(t *testing.T) {
+	seatBids := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+		"pubmatic": {Currency: "USD", Bids: []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{ID: "bid1", ImpID: "imp1", Price: 4}}}},
+		"appnexus": {Currency: "EUR", Bids: []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{ID: "bid2", ImpID: "imp1", Price: 4}}}},
+		"rubicon":  {Currency: "USD", Bids: []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{ID: "bid3", ImpID: "imp1", Price: 1}}}},
+	}
+
+	candidates := getImpClearingCandidates(seatBids, map[string]*entities.PbsOrtbBid{"imp1": seatBids["pubmatic"].Bids[0]})
+	assert.Equal(t, "bid1", candidates["imp1"].winner.Bid.ID)
+	assert.Equal(t, openrtb_ext.BidderName("pubmatic"), candidates["imp1"].winnerBidder)
+	assert.Equal(t, "USD", candidates["imp1"].winnerCur)
+	assert.Equal(t, 4.0, candidates["imp1"].secondPrice)
+}
+
+func TestPriceClearingKeepsWinner(t *testing.T) {
+	cfg := config.AccountPriceClearing{Mode: config.ClearingModeSecondPrice}
+	reqWrapper := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp1"}}}}
+
+	for i := 0; i < 50; i++ {
+		winner := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "bid1", ImpID: "imp1", Price: 5}}
+		seatRunnerUp := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "bid2", ImpID: "imp1", Price: 3}}
+		loser := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "bid3", ImpID: "imp1", Price: 3}}
+		seatBids := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+			"pubmatic": {Currency: "USD", Bids: []*entities.PbsOrtbBid{seatRunnerUp, winner}},
+			"appnexus": {Currency: "USD", Bids: []*entities.PbsOrtbBid{loser}},
+		}
+
+		winningBids := selectWinningBids(seatBids, false)
+		errs := applyPriceClearing(reqWrapper, seatBids, winningBids, cfg, currency.Conversions(currency.NewRates(nil)))
+		assert.Empty(t, errs)
+		assert.Equal(t, 3.0, winner.Bid.Price, "the winner clears at the runner-up price")
+
+		auc := newAuction(seatBids, winningBids, 1)
+		assert.Same(t, winner, auc.winningBids["imp1"], "the cleared bid keeps winning the tie with the runner-up")
+
+		auc.validateAndUpdateMultiBid(seatBids, false, 1)
+		assert.Equal(t, []*entities.PbsOrtbBid{winner}, seatBids["pubmatic"].Bids, "the cleared bid stays the top bid of its seat")
+	}
+}
@@ -11,25 +11,15 @@ import (
 )
 
 // GetPriceBucket is the externally facing function for computing CPM buckets
-func GetPriceBucket(bid openrtb2.Bid, targetingData targetData, account config.Account) string {
+func GetPriceBucket(bid openrtb2.Bid, bidder openrtb_ext.BidderName, targetingData targetData, account config.Account) string {
 	cpmStr := ""
 	bucketMax := 0.0
-	bucketMin := 0.0
-	increment := 0.0
+	var bucket *openrtb_ext.GranularityRange
 
-	config := targetingData.priceGranularity //assign default price granularity
-
-	if bidType, err := getMediaTypeForBid(bid); err == nil {
-		if bidType == openrtb_ext.BidTypeBanner && targetingData.mediaTypePriceGranularity.Banner != nil {
-			config = *targetingData.mediaTypePriceGranularity.Banner
-		} else if bidType == openrtb_ext.BidTypeVideo && targetingData.mediaTypePriceGranularity.Video != nil {
-			config = *targetingData.mediaTypePriceGranularity.Video
-		} else if bidType == openrtb_ext.BidTypeNative && targetingData.mediaTypePriceGranularity.Native != nil {
-			config = *targetingData.mediaTypePriceGranularity.Native
-		}
-	}
+	config := targetingData.selectPriceGranularity(bid, bidder)
 
 	precision := *config.Precision
+	rounding := getRoundingMode(config.Rounding, account.BidRounding)
 
 	cpm := bid.Price
 	for i := 0; i < len(config.Ranges); i++ {
@@ -38,8 +28,7 @@ func GetPriceBucket(bid openrtb2.Bid, targetingData targetData, account config.A
 		}
 		// find what range cpm is in
 		if cpm >= config.Ranges[i].Min && cpm <= config.Ranges[i].Max {
-			increment = config.Ranges[i].Increment
-			bucketMin = config.Ranges[i].Min
+			bucket = &config.Ranges[i]
 		}
 	}
 
@@ -47,33 +36,94 @@ func GetPriceBucket(bid openrtb2.Bid, targetingData targetData, account config.A
 	if config.Test || cpm > bucketMax {
 		// We are over max, just return that
 		cpmStr = strconv.FormatFloat(bucketMax, 'f', precision, 64)
-	} else if increment > 0 {
+	} else if bucket != nil && bucket.Scale == openrtb_ext.GranularityScaleLog && bucket.Increment > 1 {
+		cpmStr = getLogCpmTarget(cpm, bucket.Min, bucket.Max, bucket.Increment, precision, rounding)
+	} else if bucket != nil && bucket.Increment > 0 {
 		// If increment exists, get cpm string value
-		cpmStr = getCpmTarget(cpm, bucketMin, increment, precision, account.BidRounding)
+		cpmStr = getCpmTarget(cpm, bucket.Min, bucket.Increment, precision, rounding)
 	}
 
 	return cpmStr
 }
 
+// selectPriceGranularity picks the most specific bucket table for the bid. Deal tables take
+// precedence over bidder tables, then media type overrides, then the auction currency table
+// and finally the request default.
+func (targData *targetData) selectPriceGranularity(bid openrtb2.Bid, bidder openrtb_ext.BidderName) openrtb_ext.PriceGranularity {
+	if pg, ok := targData.dealPriceGranularity[bid.DealID]; ok && bid.DealID != "" && pg != nil {
+		return *pg
+	}
+	if pg, ok := targData.bidderPriceGranularity[bidder.String()]; ok && pg != nil {
+		return *pg
+	}
+
+	if bidType, err := getMediaTypeForBid(bid); err == nil {
+		if bidType == openrtb_ext.BidTypeBanner && targData.mediaTypePriceGranularity.Banner != nil {
+			return *targData.mediaTypePriceGranularity.Banner
+		} else if bidType == openrtb_ext.BidTypeVideo && targData.mediaTypePriceGranularity.Video != nil {
+			return *targData.mediaTypePriceGranularity.Video
+		} else if bidType == openrtb_ext.BidTypeNative && targData.mediaTypePriceGranularity.Native != nil {
+			return *targData.mediaTypePriceGranularity.Native
+		}
+	}
+
+	if pg, ok := targData.currencyPriceGranularity[targData.currency]; ok && pg != nil {
+		return *pg
+	}
+	return targData.priceGranularity //assign default price granularity
+}
+
+// getRoundingMode maps a price granularity rounding override onto the account bid rounding modes
+func getRoundingMode(rounding string, accountRounding config.BidRoundingMode) config.BidRoundingMode {
+	switch rounding {
+	case openrtb_ext.PriceGranularityRoundingDown:
+		return config.RoundingModeDown
+	case openrtb_ext.PriceGranularityRoundingUp:
+		return config.RoundingModeUp
+	case openrtb_ext.PriceGranularityRoundingNearest:
+		return config.RoundingModeTrue
+	case openrtb_ext.PriceGranularityRoundingTimeSplit:
+		return config.RoundingModeTimeSplit
+	default:
+		return accountRounding
+	}
+}
+
 func getCpmTarget(cpm float64, bucketMin float64, increment float64, precision int, rounding config.BidRoundingMode) string {
 	increments := (cpm - bucketMin) / increment
-	var incrementsRounded float64
+	roundedCPM := roundIncrements(increments, rounding)*increment + bucketMin
+	return strconv.FormatFloat(roundedCPM, 'f', precision, 64)
+}
+
+// getLogCpmTarget rounds the cpm onto buckets spaced by a constant factor, counting down from bucketMax.
+// Prices that round below bucketMin are reported as bucketMin.
+func getLogCpmTarget(cpm float64, bucketMin float64, bucketMax float64, factor float64, precision int, rounding config.BidRoundingMode) string {
+	roundedCPM := bucketMin
+	if cpm > 0 {
+		increments := math.Log(cpm/bucketMax) / math.Log(factor)
+		// snap to whole increments so prices on a bucket boundary are not rounded away by float error
+		if whole := math.Round(increments); math.Abs(increments-whole) < 1e-9 {
+			increments = whole
+		}
+		roundedCPM = math.Max(bucketMax*math.Pow(factor, roundIncrements(increments, rounding)), bucketMin)
+	}
+	return strconv.FormatFloat(roundedCPM, 'f', precision, 64)
+}
+
+func roundIncrements(increments float64, rounding config.BidRoundingMode) float64 {
 	switch rounding {
 	case config.RoundingModeTrue:
-		incrementsRounded = math.Round(increments)
+		return math.Round(increments)
 	case config.RoundingModeTimeSplit:
 		if rand.Intn(2) == 1 {
-			incrementsRounded = math.Floor(increments)
-		} else {
-			incrementsRounded = math.Ceil(increments)
+			return math.Floor(increments)
 		}
+		return math.Ceil(increments)
 	case config.RoundingModeUp:
-		incrementsRounded = math.Ceil(increments)
+		return math.Ceil(increments)
 	case config.RoundingModeDown:
 		fallthrough
 	default:
-		incrementsRounded = math.Floor(increments)
+		return math.Floor(increments)
 	}
-	roundedCPM := incrementsRounded*increment + bucketMin
-	return strconv.FormatFloat(roundedCPM, 'f', precision, 64)
 }
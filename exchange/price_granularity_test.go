@@ -205,7 +205,7 @@ func TestGetPriceBucketString(t *testing.T) {
 		for i, test := range testGroup.testCases {
 			var priceBucket string
 			assert.NotPanics(t, func() {
-				priceBucket = GetPriceBucket(testGroup.bid, test.targetData, config.Account{BidRounding: config.RoundingModeDown})
+				priceBucket = GetPriceBucket(testGroup.bid, "", test.targetData, config.Account{BidRounding: config.RoundingModeDown})
 			}, "Group: %s Granularity: %d", testGroup.groupDesc, i)
 			assert.Equal(t, test.expectedPriceBucket, priceBucket, "Group: %s Granularity: %s :: Expected %s, got %s from %f", testGroup.groupDesc, test.granularityId, test.expectedPriceBucket, priceBucket, testGroup.bid.Price)
 		}
@@ -300,8 +300,135 @@ func TestGetPriceBucketRounding(t *testing.T) {
 	for _, test := range tests {
 		var priceBucket string
 		assert.NotPanics(t, func() {
-			priceBucket = GetPriceBucket(test.bid, target, test.account)
+			priceBucket = GetPriceBucket(test.bid, "", target, test.account)
 		}, "Case: %s", test.desc)
 		assert.Contains(t, test.expectedPriceBuckets, priceBucket, "Case: %s Rounding mode: %s :: Expected %s, got %s from %f", test.desc, test.account.BidRounding, test.expectedPriceBuckets, priceBucket, test.bid.Price)
 	}
 }
+
+func TestGetPriceBucketGranularitySelection(t *testing.T) {
+	linear := func(increment float64) *openrtb_ext.PriceGranularity {
+		return &openrtb_ext.PriceGranularity{
+			Precision: ptrutil.ToPtr(2),
+			Ranges:    []openrtb_ext.GranularityRange{{Min: 0, Max: 20, Increment: increment}},
+		}
+	}
+	target := targetData{
+		priceGranularity:          *linear(0.1),
+		mediaTypePriceGranularity: openrtb_ext.MediaTypePriceGranularity{Banner: linear(1)},
+		bidderPriceGranularity:    map[string]*openrtb_ext.PriceGranularity{"appnexus": linear(2)},
+		dealPriceGranularity:      map[string]*openrtb_ext.PriceGranularity{"deal-1": linear(5)},
+		currencyPriceGranularity:  map[string]*openrtb_ext.PriceGranularity{"EUR": linear(0.25)},
+	}
+
+	testCases := []struct {
+		name                string
+		bid                 openrtb2.Bid
+		bidder              openrtb_ext.BidderName
+		currency            string
+		expectedPriceBucket string
+	}{
+		{
+			name:                "default",
+			bid:                 openrtb2.Bid{Price: 7.35},
+			bidder:              "rubicon",
+			currency:            "USD",
+			expectedPriceBucket: "7.30",
+		},
+		{
+			name:                "currency",
+			bid:                 openrtb2.Bid{Price: 7.35},
+			bidder:              "rubicon",
+			currency:            "EUR",
+			expectedPriceBucket: "7.25",
+		},
+		{
+			name:                "mediatype-over-currency",
+			bid:                 openrtb2.Bid{Price: 7.35, MType: openrtb2.MarkupBanner},
+			bidder:              "rubicon",
+			currency:            "EUR",
+			expectedPriceBucket: "7.00",
+		},
+		{
+			name:                "bidder-over-mediatype",
+			bid:                 openrtb2.Bid{Price: 7.35, MType: openrtb2.MarkupBanner},
+			bidder:              "appnexus",
+			currency:            "EUR",
+			expectedPriceBucket: "6.00",
+		},
+		{
+			name:                "deal-over-bidder",
+			bid:                 openrtb2.Bid{Price: 7.35, MType: openrtb2.MarkupBanner, DealID: "deal-1"},
+			bidder:              "appnexus",
+			currency:            "EUR",
+			expectedPriceBucket: "5.00",
+		},
+		{
+			name:                "unknown-deal",
+			bid:                 openrtb2.Bid{Price: 7.35, MType: openrtb2.MarkupBanner, DealID: "deal-2"},
+			bidder:              "rubicon",
+			currency:            "USD",
+			expectedPriceBucket: "7.00",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			target.currency = test.currency
+			priceBucket := GetPriceBucket(test.bid, test.bidder, target, config.Account{BidRounding: config.RoundingModeDown})
+			assert.Equal(t, test.expectedPriceBucket, priceBucket)
+		})
+	}
+}
+
+func TestGetPriceBucketLogScale(t *testing.T) {
+	granularity := openrtb_ext.PriceGranularity{
+		Precision: ptrutil.ToPtr(2),
+		Ranges: []openrtb_ext.GranularityRange{
+			{Min: 0, Max: 80, Increment: 2, Scale: openrtb_ext.GranularityScaleLog},
+		},
+	}
+
+	testCases := []struct {
+		name                string
+		cpm                 float64
+		rounding            string
+		expectedPriceBucket string
+	}{
+		{name: "on-boundary", cpm: 10, expectedPriceBucket: "10.00"},
+		{name: "round-down", cpm: 13, expectedPriceBucket: "10.00"},
+		{name: "round-up", cpm: 13, rounding: openrtb_ext.PriceGranularityRoundingUp, expectedPriceBucket: "20.00"},
+		{name: "round-nearest-lower", cpm: 13, rounding: openrtb_ext.PriceGranularityRoundingNearest, expectedPriceBucket: "10.00"},
+		{name: "round-nearest-upper", cpm: 15, rounding: openrtb_ext.PriceGranularityRoundingNearest, expectedPriceBucket: "20.00"},
+		{name: "max", cpm: 80, expectedPriceBucket: "80.00"},
+		{name: "above-max", cpm: 95, expectedPriceBucket: "80.00"},
+		{name: "zero", cpm: 0, expectedPriceBucket: "0.00"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			granularity.Rounding = test.rounding
+			target := targetData{priceGranularity: granularity}
+			priceBucket := GetPriceBucket(openrtb2.Bid{Price: test.cpm}, "", target, config.Account{BidRounding: config.RoundingModeDown})
+			assert.Equal(t, test.expectedPriceBucket, priceBucket)
+		})
+	}
+}
+
+func TestGetRoundingMode(t *testing.T) {
+	testCases := []struct {
+		rounding        string
+		accountRounding config.BidRoundingMode
+		expected        config.BidRoundingMode
+	}{
+		{rounding: "", accountRounding: config.RoundingModeUp, expected: config.RoundingModeUp},
+		{rounding: openrtb_ext.PriceGranularityRoundingDown, accountRounding: config.RoundingModeUp, expected: config.RoundingModeDown},
+		{rounding: openrtb_ext.PriceGranularityRoundingUp, accountRounding: config.RoundingModeDown, expected: config.RoundingModeUp},
+		{rounding: openrtb_ext.PriceGranularityRoundingNearest, accountRounding: config.RoundingModeDown, expected: config.RoundingModeTrue},
+		{rounding: openrtb_ext.PriceGranularityRoundingTimeSplit, accountRounding: config.RoundingModeDown, expected: config.RoundingModeTimeSplit},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expected, getRoundingMode(test.rounding, test.accountRounding), "rounding: %s", test.rounding)
+	}
+}
@@ -0,0 +1,90 @@
+package exchange
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/schain"
+)
+
+// validateSChains validates the supply chains of the request, request.source.schain and request.ext.prebid.schains,
+// before they are written to the bidder requests. The invalid chains are repaired or removed, the violations and
+// the nodes flagged by the sellers.json lookup are returned as debug warnings.
+func (e *exchange) validateSChains(req *openrtb_ext.RequestWrapper, requestExt *openrtb_ext.RequestExt, requestExtPrebid *openrtb_ext.ExtRequestPrebid) []error {
+	var warnings []error
+
+	if req.Source != nil && req.Source.SChain != nil {
+		result := e.sChainValidator.Validate(*req.Source.SChain)
+		warnings = append(warnings, e.recordSChainValidation("request.source.schain", result)...)
+		if !result.Valid() {
+			sourceCopy := *req.Source
+			sourceCopy.SChain = result.SChain
+			req.Source = &sourceCopy
+		}
+	}
+
+	if requestExtPrebid == nil || len(requestExtPrebid.SChains) == 0 {
+		return warnings
+	}
+	sChains := make([]*openrtb_ext.ExtRequestPrebidSChain, 0, len(requestExtPrebid.SChains))
+	for i, sChain := range requestExtPrebid.SChains {
+		if sChain == nil {
+			continue
+		}
+		result := e.sChainValidator.Validate(sChain.SChain)
+		warnings = append(warnings, e.recordSChainValidation(fmt.Sprintf("request.ext.prebid.schains[%d]", i), result)...)
+		if result.Valid() {
+			sChains = append(sChains, sChain)
+		} else if result.SChain != nil {
+			repaired := *sChain
+			repaired.SChain = *result.SChain
+			sChains = append(sChains, &repaired)
+		} else if len(sChain.Bidders) > 0 {
+			warnings = append(warnings, &errortypes.DebugWarning{
+				Message:     fmt.Sprintf("request.ext.prebid.schains[%d] rejected, bidders %s fall back to the other supply chains of the request", i, strings.Join(sChain.Bidders, ",")),
+				WarningCode: errortypes.InvalidSChainWarningCode,
+			})
+		}
+	}
+	requestExtPrebid.SChains = sChains
+	requestExt.SetPrebid(requestExtPrebid)
+	return warnings
+}
+
+func (e *exchange) recordSChainValidation(path string, result schain.Result) []error {
+	var warnings []error
+
+	outcome := "repaired"
+	switch {
+	case result.Valid():
+		e.me.RecordSChainValidation(metrics.SChainValid)
+	case result.SChain == nil:
+		e.me.RecordSChainValidation(metrics.SChainRejected)
+		outcome = "rejected"
+	default:
+		e.me.RecordSChainValidation(metrics.SChainRepaired)
+	}
+	for _, violation := range result.Violations {
+		warnings = append(warnings, &errortypes.DebugWarning{
+			Message:     fmt.Sprintf("%s %s: %s", path, outcome, violation),
+			WarningCode: errortypes.InvalidSChainWarningCode,
+		})
+	}
+
+	if len(result.UnknownSellers) > 0 {
+		e.me.RecordSChainValidation(metrics.SChainUnknownSeller)
+	}
+	if len(result.MismatchedSellers) > 0 {
+		e.me.RecordSChainValidation(metrics.SChainMismatchedSeller)
+	}
+	for _, seller := range append(result.UnknownSellers, result.MismatchedSellers...) {
+		warnings = append(warnings, &errortypes.DebugWarning{
+			Message:     fmt.Sprintf("%s %s", path, seller),
+			WarningCode: errortypes.InvalidSChainWarningCode,
+		})
+	}
+	return warnings
+}
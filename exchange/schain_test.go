@@ -0,0 +1,102 @@
+package exchange
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/schain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSChains(t *testing.T) {
+	tests := []struct {
+		name             string
+		enforcement      string
+		request          *openrtb2.BidRequest
+		expectedSource   *openrtb2.Source
+		expectedExt      string
+		expectedStatuses []metrics.SChainValidationStatus
+		expectedWarnings []string
+	}{
+		{
+			name:        "valid",
+			enforcement: config.SChainEnforcementReject,
+			request: &openrtb2.BidRequest{
+				Source: &openrtb2.Source{SChain: &openrtb2.SupplyChain{Complete: 1, Ver: "1.0", Nodes: []openrtb2.SupplyChainNode{{ASI: "publisher.com", SID: "1"}}}},
+				Ext:    json.RawMessage(`{"prebid":{"schains":[{"bidders":["appnexus"],"schain":{"complete":1,"ver":"1.0","nodes":[{"asi":"exchange.com","sid":"2"}]}}]}}`),
+			},
+			expectedSource:   &openrtb2.Source{SChain: &openrtb2.SupplyChain{Complete: 1, Ver: "1.0", Nodes: []openrtb2.SupplyChainNode{{ASI: "publisher.com", SID: "1"}}}},
+			expectedExt:      `{"prebid":{"schains":[{"bidders":["appnexus"],"schain":{"complete":1,"ver":"1.0","nodes":[{"asi":"exchange.com","sid":"2"}]}}]}}`,
+			expectedStatuses: []metrics.SChainValidationStatus{metrics.SChainValid, metrics.SChainValid},
+		},
+		{
+			name:        "repaired",
+			enforcement: config.SChainEnforcementRepair,
+			request: &openrtb2.BidRequest{
+				Source: &openrtb2.Source{TID: "tid", SChain: &openrtb2.SupplyChain{Complete: 1, Ver: "1.0", Nodes: []openrtb2.SupplyChainNode{{ASI: "publisher.com", SID: "1"}, {ASI: "publisher.com", SID: "1"}}}},
+				Ext:    json.RawMessage(`{"prebid":{"schains":[{"bidders":["appnexus"],"schain":{"complete":1,"ver":"1.0","nodes":[{"asi":"not a domain","sid":"2"},{"asi":"exchange.com","sid":"2"}]}}]}}`),
+			},
+			expectedSource:   &openrtb2.Source{TID: "tid", SChain: &openrtb2.SupplyChain{Complete: 0, Ver: "1.0", Nodes: []openrtb2.SupplyChainNode{{ASI: "publisher.com", SID: "1"}}}},
+			expectedExt:      `{"prebid":{"schains":[{"bidders":["appnexus"],"schain":{"complete":0,"ver":"1.0","nodes":[{"asi":"exchange.com","sid":"2"}]}}]}}`,
+			expectedStatuses: []metrics.SChainValidationStatus{metrics.SChainRepaired, metrics.SChainRepaired},
+			expectedWarnings: []string{
+				"request.source.schain repaired: nodes[1] repeats nodes[0], the chain has a loop",
+				`request.ext.prebid.schains[0] repaired: nodes[0] asi "not a domain" must be a domain`,
+			},
+		},
+		{
+			name:        "rejected",
+			enforcement: config.SChainEnforcementReject,
+			request: &openrtb2.BidRequest{
+				Source: &openrtb2.Source{TID: "tid", SChain: &openrtb2.SupplyChain{Complete: 3, Ver: "1.0", Nodes: []openrtb2.SupplyChainNode{{ASI: "publisher.com", SID: "1"}}}},
+				Ext:    json.RawMessage(`{"prebid":{"schains":[{"bidders":["appnexus"],"schain":{"complete":1,"ver":"1.0","nodes":[{"asi":"exchange.com","sid":""}]}},{"bidders":["rubicon"],"schain":{"complete":1,"ver":"1.0","nodes":[{"asi":"exchange.com","sid":"2"}]}}]}}`),
+			},
+			expectedSource:   &openrtb2.Source{TID: "tid"},
+			expectedExt:      `{"prebid":{"schains":[{"bidders":["rubicon"],"schain":{"complete":1,"ver":"1.0","nodes":[{"asi":"exchange.com","sid":"2"}]}}]}}`,
+			expectedStatuses: []metrics.SChainValidationStatus{metrics.SChainRejected, metrics.SChainRejected, metrics.SChainValid},
+			expectedWarnings: []string{
+				"request.source.schain rejected: complete must be 0 or 1. Got 3",
+				"request.ext.prebid.schains[0] rejected: nodes[0] sid must be set and not exceed 64 chars",
+				"request.ext.prebid.schains[0] rejected, bidders appnexus fall back to the other supply chains of the request",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			validator, err := schain.NewValidator(config.SChainValidation{Enabled: true, Enforcement: test.enforcement, MaxDepth: 10})
+			require.NoError(t, err)
+
+			metricsMock := &metrics.MetricsEngineMock{}
+			for _, status := range test.expectedStatuses {
+				metricsMock.On("RecordSChainValidation", status).Return().Once()
+			}
+			e := exchange{me: metricsMock, sChainValidator: validator}
+
+			req := &openrtb_ext.RequestWrapper{BidRequest: test.request}
+			requestExt, err := req.GetRequestExt()
+			require.NoError(t, err)
+
+			warnings := e.validateSChains(req, requestExt, requestExt.GetPrebid())
+			require.NoError(t, req.RebuildRequest())
+
+			assert.Equal(t, test.expectedSource, req.Source)
+			assert.JSONEq(t, test.expectedExt, string(req.Ext))
+			metricsMock.AssertExpectations(t)
+
+			var messages []string
+			for _, warning := range warnings {
+				assert.Equal(t, errortypes.InvalidSChainWarningCode, errortypes.ReadCode(warning))
+				assert.Equal(t, errortypes.ScopeDebug, errortypes.ReadScope(warning))
+				messages = append(messages, warning.Error())
+			}
+			assert.Equal(t, test.expectedWarnings, messages)
+		})
+	}
+}
@@ -3,8 +3,10 @@ package exchange
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/dsa"
 	"github.com/prebid/prebid-server/v3/modules/pubmatic/openwrap/utils"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 )
@@ -23,6 +25,9 @@ const DefaultKeyPrefix = "hb"
 type targetData struct {
 	priceGranularity          openrtb_ext.PriceGranularity
 	mediaTypePriceGranularity openrtb_ext.MediaTypePriceGranularity
+	bidderPriceGranularity    map[string]*openrtb_ext.PriceGranularity
+	dealPriceGranularity      map[string]*openrtb_ext.PriceGranularity
+	currencyPriceGranularity  map[string]*openrtb_ext.PriceGranularity
 	includeWinners            bool
 	includeBidderKeys         bool
 	includeCacheBids          bool
@@ -34,6 +39,8 @@ type targetData struct {
 	cacheHost string
 	cachePath string
 	prefix    string
+	// currency is the auction currency used to select from currencyPriceGranularity
+	currency string
 }
 
 // setTargeting writes all the targeting params into the bids.
@@ -103,6 +110,9 @@ func (targData *targetData) setTargeting(auc *auction, env string, categoryMappi
 				if len(categoryMapping) > 0 {
 					targData.addKeys(targets, openrtb_ext.CategoryDurationKey, categoryMapping[utils.GetOriginalBidId(topBid.Bid.ID)], targetingBidderCode, isOverallWinner, truncateTargetAttr, bidHasDeal)
 				}
+				if bidDSA := dsa.GetBidDSA(topBid); bidDSA != nil {
+					targData.addDSAKeys(targets, bidDSA, targetingBidderCode, isOverallWinner, truncateTargetAttr, bidHasDeal)
+				}
 				targData.addBidderKeys(targets, topBid.BidTargets)
 				topBid.BidTargets = targets
 			}
@@ -123,6 +133,37 @@ func (targData *targetData) addKeys(keys map[string]string, key openrtb_ext.Targ
 	}
 }
 
+func (targData *targetData) addDSAKeys(keys map[string]string, bidDSA *openrtb_ext.ExtBidDSA, bidderName openrtb_ext.BidderName, overallWinner bool, truncateTargetAttr *int, bidHasDeal bool) {
+	if bidDSA.AdRender != nil {
+		targData.addKeys(keys, openrtb_ext.DSAAdRenderKey, strconv.Itoa(int(*bidDSA.AdRender)), bidderName, overallWinner, truncateTargetAttr, bidHasDeal)
+	}
+	maxLength := MaxKeyLength
+	if truncateTargetAttr != nil && *truncateTargetAttr > 0 {
+		maxLength = *truncateTargetAttr
+	}
+	if behalf := dsaTargetingValue(bidDSA.Behalf, maxLength); behalf != "" {
+		targData.addKeys(keys, openrtb_ext.DSABehalfKey, behalf, bidderName, overallWinner, truncateTargetAttr, bidHasDeal)
+	}
+	if paid := dsaTargetingValue(bidDSA.Paid, maxLength); paid != "" {
+		targData.addKeys(keys, openrtb_ext.DSAPaidKey, paid, bidderName, overallWinner, truncateTargetAttr, bidHasDeal)
+	}
+}
+
+// dsaTargetingValue makes the DSA transparency text of a bid safe for the ad server: characters other than letters,
+// digits, '.', '-' and '_' are replaced by '_' and the value is truncated to maxLength
+func dsaTargetingValue(value string, maxLength int) string {
+	value = strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, strings.TrimSpace(value))
+	if len(value) > maxLength {
+		value = value[:maxLength]
+	}
+	return value
+}
+
 func makeHbSize(bid *openrtb2.Bid) string {
 	if bid.W != 0 && bid.H != 0 {
 		return strconv.FormatInt(bid.W, 10) + "x" + strconv.FormatInt(bid.H, 10)
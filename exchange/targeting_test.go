@@ -330,6 +330,11 @@ var bid123 *openrtb2.Bid = &openrtb2.Bid{
 	Price: 1.23,
 }
 
+var bid123DSA *openrtb2.Bid = &openrtb2.Bid{
+	Price: 1.23,
+	Ext:   json.RawMessage(`{"dsa":{"adrender":1,"behalf":"advertiser","paid":"buyer"}}`),
+}
+
 var bid111 *openrtb2.Bid = &openrtb2.Bid{
 	Price:  1.11,
 	DealID: "mydeal",
@@ -420,6 +425,44 @@ var TargetingTests []TargetingTestData = []TargetingTestData{
 		},
 		TruncateTargetAttr: nil,
 	},
+	{
+		Description: "Targeting winners with DSA transparency information",
+		TargetData: targetData{
+			priceGranularity: lookupPriceGranularity("med"),
+			includeWinners:   true,
+			prefix:           DefaultKeyPrefix,
+		},
+		Auction: auction{
+			allBidsByBidder: map[string]map[openrtb_ext.BidderName][]*entities.PbsOrtbBid{
+				"ImpId-1": {
+					openrtb_ext.BidderAppnexus: {{
+						Bid:     bid123DSA,
+						BidType: openrtb_ext.BidTypeBanner,
+					}},
+					openrtb_ext.BidderRubicon: {{
+						Bid:     bid084,
+						BidType: openrtb_ext.BidTypeBanner,
+					}},
+				},
+			},
+		},
+		ExpectedPbsBids: map[string]map[openrtb_ext.BidderName][]ExpectedPbsBid{
+			"ImpId-1": {
+				openrtb_ext.BidderAppnexus: []ExpectedPbsBid{
+					{
+						BidTargets: map[string]string{
+							"hb_bidder":       "appnexus",
+							"hb_pb":           "1.20",
+							"hb_dsa_adrender": "1",
+							"hb_dsa_behalf":   "advertiser",
+							"hb_dsa_paid":     "buyer",
+						},
+					},
+				},
+				openrtb_ext.BidderRubicon: []ExpectedPbsBid{},
+			},
+		},
+	},
 	{
 		Description: "Targeting on bidders only",
 		TargetData: targetData{
@@ -1250,3 +1293,23 @@ func TestSetTargeting(t *testing.T) {
 		}
 	}
 }
+
+func TestDSATargetingValue(t *testing.T) {
+	testCases := []struct {
+		name      string
+		value     string
+		maxLength int
+		expected  string
+	}{
+		{name: "safe", value: "advertiser", maxLength: 20, expected: "advertiser"},
+		{name: "unsafe_characters_replaced", value: " Acme, Inc.=<script>", maxLength: 30, expected: "Acme__Inc.__script_"},
+		{name: "non_ascii_replaced", value: "Société", maxLength: 20, expected: "Soci_t_"},
+		{name: "truncated", value: "a-very-long-advertiser-name", maxLength: 20, expected: "a-very-long-advertis"},
+		{name: "empty", value: "  ", maxLength: 20, expected: ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, dsaTargetingValue(tc.value, tc.maxLength))
+		})
+	}
+}
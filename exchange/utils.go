@@ -189,7 +189,7 @@ func (rs *requestSplitter) cleanOpenRTBRequests(ctx context.Context,
 		auctionPermissions := gdprPerms.AuctionActivitiesAllowed(ctx, coreBidder, openrtb_ext.BidderName(bidder))
 
 		// privacy blocking
-		if rs.isBidderBlockedByPrivacy(reqWrapperCopy, auctionReq.Activities, auctionPermissions, coreBidder, openrtb_ext.BidderName(bidder)) {
+		if rs.isBidderBlockedByPrivacy(reqWrapperCopy, auctionReq.Activities, auctionPermissions, coreBidder, openrtb_ext.BidderName(bidder), auctionReq.PrivacyAudit) {
 			errs = append(errs, &errortypes.Warning{
 				Message:     fmt.Sprintf("bidder %q blocked by privacy settings", coreBidder),
 				WarningCode: errortypes.BidderBlockedByPrivacySettings,
@@ -348,20 +348,23 @@ func PreloadExts(req *openrtb_ext.RequestWrapper) error {
 	return nil
 }
 
-func (rs *requestSplitter) isBidderBlockedByPrivacy(r *openrtb_ext.RequestWrapper, activities privacy.ActivityControl, auctionPermissions gdpr.AuctionPermissions, coreBidder, bidderName openrtb_ext.BidderName) bool {
+func (rs *requestSplitter) isBidderBlockedByPrivacy(r *openrtb_ext.RequestWrapper, activities privacy.ActivityControl, auctionPermissions gdpr.AuctionPermissions, coreBidder, bidderName openrtb_ext.BidderName, audit *privacy.AuditTrail) bool {
 	// activities control
 	scope := privacy.Component{Type: privacy.ComponentTypeBidder, Name: bidderName.String()}
-	fetchBidsActivityAllowed := activities.Allow(privacy.ActivityFetchBids, scope, privacy.NewRequestFromBidRequest(*r))
-	if !fetchBidsActivityAllowed {
+	fetchBidsDecision := activities.Decide(privacy.ActivityFetchBids, scope, privacy.NewRequestFromBidRequest(*r))
+	if !fetchBidsDecision.Allowed {
+		audit.Record(bidderName.String(), privacy.ActivityFetchBids, fetchBidsDecision)
 		return true
 	}
 
 	// gdpr
 	if !auctionPermissions.AllowBidRequest {
 		rs.me.RecordAdapterGDPRRequestBlocked(coreBidder)
+		audit.Record(bidderName.String(), privacy.ActivityFetchBids, privacy.Deny(privacy.AuditPolicyTCF2))
 		return true
 	}
 
+	audit.Record(bidderName.String(), privacy.ActivityFetchBids, fetchBidsDecision)
 	return false
 }
 
@@ -372,36 +375,45 @@ func (rs *requestSplitter) applyPrivacy(reqWrapper *openrtb_ext.RequestWrapper,
 	bidRequest := ortb.CloneBidRequestPartial(reqWrapper.BidRequest)
 	reqWrapper.BidRequest = bidRequest
 
-	passIDActivityAllowed := auctionReq.Activities.Allow(privacy.ActivityTransmitUserFPD, scope, privacy.NewRequestFromBidRequest(*reqWrapper))
+	// the audit records the first policy restricting each activity
+	passIDDecision := auctionReq.Activities.Decide(privacy.ActivityTransmitUserFPD, scope, privacy.NewRequestFromBidRequest(*reqWrapper))
 	buyerUIDSet := reqWrapper.User != nil && reqWrapper.User.BuyerUID != ""
 	buyerUIDRemoved := false
-	if !passIDActivityAllowed {
+	if !passIDDecision.Allowed {
 		privacy.ScrubUserFPD(reqWrapper)
 		buyerUIDRemoved = true
 	} else {
 		if !auctionPermissions.PassID {
 			privacy.ScrubGdprID(reqWrapper)
 			buyerUIDRemoved = true
+			passIDDecision = privacy.Deny(privacy.AuditPolicyTCF2)
 		}
 
 		if ccpaEnforcer.ShouldEnforce(bidderName) {
 			privacy.ScrubDeviceIDsIPsUserDemoExt(reqWrapper, ipConf, "eids", false, true)
 			buyerUIDRemoved = true
+			if passIDDecision.Allowed {
+				passIDDecision = privacy.Deny(privacy.AuditPolicyCCPA)
+			}
 		}
 	}
 	if buyerUIDSet && buyerUIDRemoved {
 		rs.me.RecordAdapterBuyerUIDScrubbed(coreBidderName)
 	}
 
-	passGeoActivityAllowed := auctionReq.Activities.Allow(privacy.ActivityTransmitPreciseGeo, scope, privacy.NewRequestFromBidRequest(*reqWrapper))
-	if !passGeoActivityAllowed {
+	passGeoDecision := auctionReq.Activities.Decide(privacy.ActivityTransmitPreciseGeo, scope, privacy.NewRequestFromBidRequest(*reqWrapper))
+	if !passGeoDecision.Allowed {
 		privacy.ScrubGeoAndDeviceIP(reqWrapper, ipConf)
 	} else {
 		if !auctionPermissions.PassGeo {
 			privacy.ScrubGeoAndDeviceIP(reqWrapper, ipConf)
+			passGeoDecision = privacy.Deny(privacy.AuditPolicyTCF2)
 		}
 		if ccpaEnforcer.ShouldEnforce(bidderName) {
 			privacy.ScrubDeviceIDsIPsUserDemoExt(reqWrapper, ipConf, "eids", false, true)
+			if passGeoDecision.Allowed {
+				passGeoDecision = privacy.Deny(privacy.AuditPolicyCCPA)
+			}
 		}
 	}
 
@@ -409,17 +421,47 @@ func (rs *requestSplitter) applyPrivacy(reqWrapper *openrtb_ext.RequestWrapper,
 	// LMT: for app traffic only, when device.os is iOS or Android, preserve IP; otherwise mask IP (incl. site / non-mobile).
 	if coppa {
 		privacy.ScrubDeviceIDsIPsUserDemoExt(reqWrapper, ipConf, "eids", true, true)
+		if passIDDecision.Allowed {
+			passIDDecision = privacy.Deny(privacy.AuditPolicyCOPPA)
+		}
+		if passGeoDecision.Allowed {
+			passGeoDecision = privacy.Deny(privacy.AuditPolicyCOPPA)
+		}
 	} else if lmt {
 		isMobileAppOS := reqWrapper.App != nil && reqWrapper.Device != nil &&
 			(strings.EqualFold(reqWrapper.Device.OS, "ios") || strings.EqualFold(reqWrapper.Device.OS, "android"))
 		scrubDeviceIP := !isMobileAppOS
 		privacy.ScrubDeviceIDsIPsUserDemoExt(reqWrapper, ipConf, "eids", false, scrubDeviceIP)
+		if passIDDecision.Allowed {
+			passIDDecision = privacy.Deny(privacy.AuditPolicyLMT)
+		}
 	}
+	auctionReq.PrivacyAudit.Record(bidderName, privacy.ActivityTransmitUserFPD, passIDDecision)
+	auctionReq.PrivacyAudit.Record(bidderName, privacy.ActivityTransmitPreciseGeo, passGeoDecision)
 
-	passTIDAllowed := auctionReq.Activities.Allow(privacy.ActivityTransmitTIDs, scope, privacy.NewRequestFromBidRequest(*reqWrapper))
-	if !passTIDAllowed {
+	passTIDDecision := auctionReq.Activities.Decide(privacy.ActivityTransmitTIDs, scope, privacy.NewRequestFromBidRequest(*reqWrapper))
+	if !passTIDDecision.Allowed {
 		privacy.ScrubTID(reqWrapper)
 	}
+	auctionReq.PrivacyAudit.Record(bidderName, privacy.ActivityTransmitTIDs, passTIDDecision)
+
+	// the auction configs returned for the imps without Protected Audience signals are dropped
+	passPAAPIDecision := auctionReq.Activities.Decide(privacy.ActivityTransmitPAAPI, scope, privacy.NewRequestFromBidRequest(*reqWrapper))
+	if passPAAPIDecision.Allowed && auctionReq.Account.Privacy.PrivacySandbox.PAAPI.Disabled() {
+		passPAAPIDecision = privacy.Deny(privacy.AuditPolicyAccount)
+	}
+	if !passPAAPIDecision.Allowed {
+		privacy.ScrubPAAPI(reqWrapper)
+	}
+	auctionReq.PrivacyAudit.Record(bidderName, privacy.ActivityTransmitPAAPI, passPAAPIDecision)
+
+	// the EIDs of the sources denied by the transmitEids rules are removed even when the activity is allowed for
+	// the bidder, the audit reports the decisions taken for the bidder
+	idsRequest := privacy.NewRequestFromBidRequest(*reqWrapper)
+	auctionReq.PrivacyAudit.Record(bidderName, privacy.ActivityTransmitEIDs, auctionReq.Activities.Decide(privacy.ActivityTransmitEIDs, scope, idsRequest))
+	auctionReq.PrivacyAudit.Record(bidderName, privacy.ActivityTransmitDeviceIDs, auctionReq.Activities.Decide(privacy.ActivityTransmitDeviceIDs, scope, idsRequest))
+	deniedIDs := auctionReq.Activities.DeniedIDs(reqWrapper, scope, idsRequest)
+	privacy.ScrubDeniedIDs(reqWrapper, deniedIDs)
 
 	if err := reqWrapper.RebuildRequest(); err != nil {
 		return err
@@ -952,6 +994,9 @@ func getExtTargetData(requestExtPrebid *openrtb_ext.ExtRequestPrebid, cacheInstr
 		prefix, warning := getTargetDataPrefix(requestExtPrebid.Targeting.Prefix, account)
 		return &targetData{
 			alwaysIncludeDeals:        requestExtPrebid.Targeting.AlwaysIncludeDeals,
+			bidderPriceGranularity:    requestExtPrebid.Targeting.BidderPriceGranularity,
+			currencyPriceGranularity:  requestExtPrebid.Targeting.CurrencyPriceGranularity,
+			dealPriceGranularity:      requestExtPrebid.Targeting.DealPriceGranularity,
 			includeBidderKeys:         ptrutil.ValueOrDefault(requestExtPrebid.Targeting.IncludeBidderKeys),
 			includeCacheBids:          cacheInstructions.cacheBids,
 			includeCacheVast:          cacheInstructions.cacheVAST,
@@ -967,6 +1012,15 @@ func getExtTargetData(requestExtPrebid *openrtb_ext.ExtRequestPrebid, cacheInstr
 	return nil, nil
 }
 
+// getAuctionCurrency returns the currency bid prices are converted to, the first
+// requested currency or USD when none is requested
+func getAuctionCurrency(requestCur []string) string {
+	if len(requestCur) > 0 && requestCur[0] != "" {
+		return requestCur[0]
+	}
+	return "USD"
+}
+
 func getTargetDataPrefix(requestPrefix string, account config.Account) (string, []*errortypes.Warning) {
 	var warnings []*errortypes.Warning
 
@@ -4358,6 +4358,24 @@ func TestCleanOpenRTBRequestsFilterBidderRequestExt(t *testing.T) {
 	}
 }
 
+func TestGetAuctionCurrency(t *testing.T) {
+	testCases := []struct {
+		name             string
+		givenCur         []string
+		expectedCurrency string
+	}{
+		{name: "nil", givenCur: nil, expectedCurrency: "USD"},
+		{name: "empty-first", givenCur: []string{""}, expectedCurrency: "USD"},
+		{name: "first-requested", givenCur: []string{"EUR", "USD"}, expectedCurrency: "EUR"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expectedCurrency, getAuctionCurrency(test.givenCur))
+		})
+	}
+}
+
 func TestGetTargetDataPrefix(t *testing.T) {
 	testCases := []struct {
 		description      string
@@ -5515,6 +5533,87 @@ func TestCleanOpenRTBRequestsActivities(t *testing.T) {
 	}
 }
 
+func TestCleanOpenRTBRequestsPAAPI(t *testing.T) {
+	const paapiImpExt = `{"ae":1,"igs":{"ae":1,"sellersignals":{"floor":1}},"prebid":{"bidder":{"appnexus":{"placementId":1}}}}`
+
+	testCases := []struct {
+		name           string
+		privacyConfig  config.AccountPrivacy
+		paapiEnabled   *bool
+		expectedImpExt string
+		expectedAudit  openrtb_ext.PrivacyAuditRecord
+	}{
+		{
+			name:           "paapi_default",
+			privacyConfig:  getTransmitPAAPIActivityConfig("appnexus", true),
+			paapiEnabled:   nil,
+			expectedImpExt: `{"ae":1,"igs":{"ae":1,"sellersignals":{"floor":1}},"bidder":{"placementId":1}}`,
+			expectedAudit:  openrtb_ext.PrivacyAuditRecord{Bidder: "appnexus", Activity: "transmitPaapi", Policy: privacy.AuditPolicyActivityControl, RuleIndex: ptrutil.ToPtr(0), Result: openrtb_ext.PrivacyAuditResultAllow},
+		},
+		{
+			name:           "paapi_disabled",
+			privacyConfig:  getTransmitPAAPIActivityConfig("appnexus", true),
+			paapiEnabled:   ptrutil.ToPtr(false),
+			expectedImpExt: `{"bidder":{"placementId":1}}`,
+			expectedAudit:  openrtb_ext.PrivacyAuditRecord{Bidder: "appnexus", Activity: "transmitPaapi", Policy: privacy.AuditPolicyAccount, Result: openrtb_ext.PrivacyAuditResultDeny},
+		},
+		{
+			name:           "transmit_paapi_allowed",
+			privacyConfig:  getTransmitPAAPIActivityConfig("appnexus", true),
+			paapiEnabled:   ptrutil.ToPtr(true),
+			expectedImpExt: `{"ae":1,"igs":{"ae":1,"sellersignals":{"floor":1}},"bidder":{"placementId":1}}`,
+			expectedAudit:  openrtb_ext.PrivacyAuditRecord{Bidder: "appnexus", Activity: "transmitPaapi", Policy: privacy.AuditPolicyActivityControl, RuleIndex: ptrutil.ToPtr(0), Result: openrtb_ext.PrivacyAuditResultAllow},
+		},
+		{
+			name:           "transmit_paapi_deny",
+			privacyConfig:  getTransmitPAAPIActivityConfig("appnexus", false),
+			paapiEnabled:   ptrutil.ToPtr(true),
+			expectedImpExt: `{"bidder":{"placementId":1}}`,
+			expectedAudit:  openrtb_ext.PrivacyAuditRecord{Bidder: "appnexus", Activity: "transmitPaapi", Policy: privacy.AuditPolicyActivityControl, RuleIndex: ptrutil.ToPtr(0), Result: openrtb_ext.PrivacyAuditResultDeny},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			req := newBidRequest()
+			req.Imp[0].Ext = json.RawMessage(paapiImpExt)
+
+			privacyConfig := test.privacyConfig
+			privacyConfig.PrivacySandbox.PAAPI.Enabled = test.paapiEnabled
+
+			auctionReq := AuctionRequest{
+				BidRequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: req},
+				UserSyncs:         &emptyUsersync{},
+				Activities:        privacy.NewActivityControl(&privacyConfig),
+				Account:           config.Account{Privacy: privacyConfig},
+				TCF2Config:        gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{}),
+				PrivacyAudit:      &privacy.AuditTrail{},
+			}
+
+			metricsMock := metrics.MetricsEngineMock{}
+			metricsMock.Mock.On("RecordAdapterBuyerUIDScrubbed", mock.Anything).Return()
+
+			reqSplitter := &requestSplitter{
+				bidderToSyncerKey: map[string]string{},
+				me:                &metricsMock,
+				hostSChainNode:    nil,
+				bidderInfo:        config.BidderInfos{"appnexus": config.BidderInfo{OpenRTB: &config.OpenRTBInfo{Version: "2.6"}}},
+			}
+
+			bidderRequests, _, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, nil, map[string]float64{})
+			assert.Empty(t, errs)
+			if assert.Len(t, bidderRequests, 1) {
+				assert.JSONEq(t, test.expectedImpExt, string(bidderRequests[0].BidRequest.Imp[0].Ext))
+			}
+
+			records := auctionReq.PrivacyAudit.Records()
+			assert.Contains(t, records, test.expectedAudit)
+			assert.Contains(t, records, openrtb_ext.PrivacyAuditRecord{Bidder: "appnexus", Activity: "transmitEids", Policy: privacy.AuditPolicyActivityControl, Result: openrtb_ext.PrivacyAuditResultAllow})
+			assert.Contains(t, records, openrtb_ext.PrivacyAuditRecord{Bidder: "appnexus", Activity: "transmitDeviceIds", Policy: privacy.AuditPolicyActivityControl, Result: openrtb_ext.PrivacyAuditResultAllow})
+		})
+	}
+}
+
 func buildDefaultActivityConfig(componentName string, allow bool) config.Activity {
 	return config.Activity{
 		Default: ptrutil.ToPtr(true),
@@ -5562,6 +5661,14 @@ func getTransmitTIDActivityConfig(componentName string, allow bool) config.Accou
 	}
 }
 
+func getTransmitPAAPIActivityConfig(componentName string, allow bool) config.AccountPrivacy {
+	return config.AccountPrivacy{
+		AllowActivities: &config.AllowActivities{
+			TransmitPAAPI: buildDefaultActivityConfig(componentName, allow),
+		},
+	}
+}
+
 func TestApplyBidAdjustmentToFloor(t *testing.T) {
 	type args struct {
 		bidRequestWrapper    *openrtb_ext.RequestWrapper
@@ -1,14 +1,17 @@
 package floors
 
 import (
+	"bytes"
 	"container/heap"
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"math"
 	"net/http"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/alitto/pond"
@@ -51,6 +54,13 @@ type PriceFloorFetcher struct {
 	time            timeutil.Time         // time interface to record request timings
 	metricEngine    metrics.MetricsEngine // Records malfunctions in dynamic fetch
 	maxRetries      int                   // Max number of retries for failing URLs
+	ruleIndexes     sync.Map              // Map of URL with the rule indexes compiled for the cached floor data
+}
+
+// fetchedRuleIndexes holds the rule indexes compiled for each model group of the cached floor data
+type fetchedRuleIndexes struct {
+	floorData []byte
+	indexes   []*ruleIndex
 }
 
 type FetchQueue []*fetchInfo
@@ -140,8 +150,10 @@ func (f *PriceFloorFetcher) Fetch(config config.AccountPriceFloors) (*openrtb_ex
 		if err := json.Unmarshal(result, &fetchedFloorData); err != nil || fetchedFloorData.Data == nil {
 			return nil, openrtb_ext.FetchError
 		}
+		f.attachRuleIndexes(config.Fetcher.URL, result, fetchedFloorData.Data)
 		return &fetchedFloorData, openrtb_ext.FetchSuccess
 	}
+	f.ruleIndexes.Delete(config.Fetcher.URL)
 
 	//miss: push to channel to fetch and return empty response
 	if config.Enabled && config.Fetcher.Enabled && config.Fetcher.Timeout > 0 {
@@ -152,6 +164,25 @@ func (f *PriceFloorFetcher) Fetch(config config.AccountPriceFloors) (*openrtb_ex
 	return nil, openrtb_ext.FetchInprogress
 }
 
+// attachRuleIndexes sets the rule indexes of the model groups of the floor data, the indexes are compiled once for
+// each floor data cached for the URL
+func (f *PriceFloorFetcher) attachRuleIndexes(url string, floorData []byte, data *openrtb_ext.PriceFloorData) {
+	var fetched *fetchedRuleIndexes
+	if cached, ok := f.ruleIndexes.Load(url); ok && bytes.Equal(cached.(*fetchedRuleIndexes).floorData, floorData) {
+		fetched = cached.(*fetchedRuleIndexes)
+	} else {
+		fetched = &fetchedRuleIndexes{floorData: floorData, indexes: make([]*ruleIndex, len(data.ModelGroups))}
+		for i := range data.ModelGroups {
+			fetched.indexes[i] = newModelGroupRuleIndex(data.ModelGroups[i])
+		}
+		f.ruleIndexes.Store(url, fetched)
+	}
+
+	for i := range data.ModelGroups {
+		data.ModelGroups[i].RuleIndex = fetched.indexes[i]
+	}
+}
+
 func (f *PriceFloorFetcher) worker(fetchConfig fetchInfo) {
 	floorData, fetchedMaxAge := f.fetchAndValidate(fetchConfig.AccountFloorFetch)
 	if floorData != nil {
@@ -323,6 +354,10 @@ func validateRules(config config.AccountFloorFetch, priceFloors *openrtb_ext.Pri
 		return errors.New("usefetchdatarate should be greater than or equal to 0 and less than or equal to 100")
 	}
 
+	if priceFloors.Data.Currency != "" && !isValidCurrency(priceFloors.Data.Currency) {
+		return fmt.Errorf("invalid currency %s in price floor data", priceFloors.Data.Currency)
+	}
+
 	for _, modelGroup := range priceFloors.Data.ModelGroups {
 		if len(modelGroup.Values) == 0 || len(modelGroup.Values) > config.MaxRules {
 			return errors.New("invalid number of floor rules, floor rules should be greater than zero and less than MaxRules specified in account config")
@@ -339,6 +374,19 @@ func validateRules(config config.AccountFloorFetch, priceFloors *openrtb_ext.Pri
 		if modelGroup.Default < 0 {
 			return errors.New("modelGroup.Default should be greater than 0")
 		}
+
+		if modelGroup.Currency != "" && !isValidCurrency(modelGroup.Currency) {
+			return fmt.Errorf("invalid currency %s in model group", modelGroup.Currency)
+		}
+
+		for rule, cur := range modelGroup.ValueCurrencies {
+			if !isValidCurrency(cur) {
+				return fmt.Errorf("invalid currency %s for floor rule %s", cur, rule)
+			}
+			if _, ok := modelGroup.Values[rule]; !ok {
+				return fmt.Errorf("currency provided for unknown floor rule %s", rule)
+			}
+		}
 	}
 
 	return nil
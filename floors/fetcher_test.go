@@ -424,6 +424,114 @@ func TestValidatePriceFloorRules(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Invalid floor rule currency",
+			args: args{
+				configs: config.AccountFloorFetch{
+					Enabled:       true,
+					URL:           testURL,
+					Timeout:       5,
+					MaxFileSizeKB: 20,
+					MaxRules:      2,
+					MaxAge:        20,
+					Period:        10,
+				},
+				priceFloors: &openrtb_ext.PriceFloorRules{
+					Data: &openrtb_ext.PriceFloorData{
+						ModelGroups: []openrtb_ext.PriceFloorModelGroup{{
+							Values: map[string]float64{
+								"*|*|www.website.com": 15.01,
+							},
+							ValueCurrencies: map[string]string{
+								"*|*|www.website.com": "XYZ",
+							},
+						}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Currency for unknown floor rule",
+			args: args{
+				configs: config.AccountFloorFetch{
+					Enabled:       true,
+					URL:           testURL,
+					Timeout:       5,
+					MaxFileSizeKB: 20,
+					MaxRules:      2,
+					MaxAge:        20,
+					Period:        10,
+				},
+				priceFloors: &openrtb_ext.PriceFloorRules{
+					Data: &openrtb_ext.PriceFloorData{
+						ModelGroups: []openrtb_ext.PriceFloorModelGroup{{
+							Values: map[string]float64{
+								"*|*|www.website.com": 15.01,
+							},
+							ValueCurrencies: map[string]string{
+								"*|*|*": "EUR",
+							},
+						}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid model group currency",
+			args: args{
+				configs: config.AccountFloorFetch{
+					Enabled:       true,
+					URL:           testURL,
+					Timeout:       5,
+					MaxFileSizeKB: 20,
+					MaxRules:      2,
+					MaxAge:        20,
+					Period:        10,
+				},
+				priceFloors: &openrtb_ext.PriceFloorRules{
+					Data: &openrtb_ext.PriceFloorData{
+						ModelGroups: []openrtb_ext.PriceFloorModelGroup{{
+							Currency: "US",
+							Values: map[string]float64{
+								"*|*|www.website.com": 15.01,
+							},
+						}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Valid floor rule currencies",
+			args: args{
+				configs: config.AccountFloorFetch{
+					Enabled:       true,
+					URL:           testURL,
+					Timeout:       5,
+					MaxFileSizeKB: 20,
+					MaxRules:      2,
+					MaxAge:        20,
+					Period:        10,
+				},
+				priceFloors: &openrtb_ext.PriceFloorRules{
+					Data: &openrtb_ext.PriceFloorData{
+						Currency: "USD",
+						ModelGroups: []openrtb_ext.PriceFloorModelGroup{{
+							Values: map[string]float64{
+								"*|*|www.website.com": 15.01,
+								"*|*|*":               1.01,
+							},
+							ValueCurrencies: map[string]string{
+								"*|*|www.website.com": "EUR",
+							},
+						}},
+					},
+				},
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -894,8 +1002,57 @@ func TestFetcherDataPresentInCache(t *testing.T) {
 	fetcherInstance.SetWithExpiry("http://test.com/floor", []byte(data), fetchConfig.Fetcher.MaxAge)
 
 	val, status := fetcherInstance.Fetch(fetchConfig)
-	assert.Equal(t, res, val, "Invalid value in cache or cache is empty")
 	assert.Equal(t, "success", status, "Floor fetch should be success")
+	if assert.NotNil(t, val) && assert.Len(t, val.Data.ModelGroups, 1) {
+		assert.NotNil(t, val.Data.ModelGroups[0].RuleIndex, "rule index must be attached to the fetched model group")
+		val.Data.ModelGroups[0].RuleIndex = nil
+	}
+	assert.Equal(t, res, val, "Invalid value in cache or cache is empty")
+}
+
+func TestFetcherRuleIndexes(t *testing.T) {
+	floorConfig := config.PriceFloors{
+		Enabled: true,
+		Fetcher: config.PriceFloorFetcher{
+			CacheSize: 1,
+			Worker:    2,
+			Capacity:  5,
+		},
+	}
+
+	fetcherInstance := mockFetcherInstance(floorConfig, http.DefaultClient, &metricsConf.NilMetricsEngine{})
+	defer fetcherInstance.Stop()
+
+	fetchConfig := config.AccountPriceFloors{
+		Enabled:        true,
+		UseDynamicData: true,
+		Fetcher: config.AccountFloorFetch{
+			URL:    "http://test.com/floor",
+			MaxAge: 20,
+		},
+	}
+	fetcherInstance.SetWithExpiry("http://test.com/floor", []byte(`{"data":{"modelgroups":[{"values":{"BANNER|*":1},"schema":{"fields":["mediaType","size"]}}]}}`), fetchConfig.Fetcher.MaxAge)
+
+	first, _ := fetcherInstance.Fetch(fetchConfig)
+	second, _ := fetcherInstance.Fetch(fetchConfig)
+	assert.Same(t, first.Data.ModelGroups[0].RuleIndex, second.Data.ModelGroups[0].RuleIndex, "rule index must be compiled once for the cached floor data")
+
+	rule, found := first.Data.ModelGroups[0].RuleIndex.FindRule([]string{"banner", "300x250"})
+	assert.True(t, found)
+	assert.Equal(t, "banner|*", rule, "rule keys must be lower cased as the rules of the request are")
+
+	fetcherInstance.SetWithExpiry("http://test.com/floor", []byte(`{"data":{"modelgroups":[{"values":{"video|*":1},"schema":{"fields":["mediaType","size"]}}]}}`), fetchConfig.Fetcher.MaxAge)
+	third, _ := fetcherInstance.Fetch(fetchConfig)
+	assert.NotSame(t, first.Data.ModelGroups[0].RuleIndex, third.Data.ModelGroups[0].RuleIndex, "rule index must be compiled again for new floor data")
+
+	fetcherInstance.SetWithExpiry("http://test.com/floor", []byte(`{"data":{"modelgroups":[{"values":{"*|SEG1":1},"schema":{"fields":["mediaType","userSegment"]}}]}}`), fetchConfig.Fetcher.MaxAge)
+	fourth, _ := fetcherInstance.Fetch(fetchConfig)
+	assert.Equal(t, map[string]struct{}{"seg1": {}}, fourth.Data.ModelGroups[0].RuleIndex.UserSegments(), "user segments must be collected once for the fetched floor data")
+
+	fetcherInstance.cache.Clear()
+	fetcherInstance.Fetch(fetchConfig)
+	_, found = fetcherInstance.ruleIndexes.Load("http://test.com/floor")
+	assert.False(t, found, "rule indexes must be dropped with the cached floor data")
 }
 
 func TestFetcherDataNotPresentInCache(t *testing.T) {
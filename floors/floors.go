@@ -2,9 +2,12 @@ package floors
 
 import (
 	"errors"
+	"fmt"
 	"math"
 	"math/rand"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/currency"
@@ -13,6 +16,9 @@ import (
 	"github.com/prebid/prebid-server/v3/util/ptrutil"
 )
 
+// timezoneLocations caches the loaded locations of publisher timezones
+var timezoneLocations sync.Map
+
 type Price struct {
 	FloorMin    float64
 	FloorMinCur string
@@ -31,6 +37,9 @@ const (
 	dataRateMin      int     = 0
 	dataRateMax      int     = 100
 	floorPrecision   float64 = 0.01
+	maxUserSegments  int     = 50
+	maxSchemaValLen  int     = 100
+	maxHourOfDay     int     = 23
 )
 
 const (
@@ -57,13 +66,14 @@ func EnrichWithPriceFloors(bidRequestWrapper *openrtb_ext.RequestWrapper, accoun
 	}
 	floors, err := resolveFloors(account, bidRequestWrapper, conversions, priceFloorFetcher, metricsEngine)
 
-	updateReqErrs := updateBidRequestWithFloors(floors, bidRequestWrapper, conversions, metricsEngine, account.ID)
+	requestTime := time.Now().In(getTimezoneLocation(account.PriceFloors.Timezone))
+	updateReqErrs := updateBidRequestWithFloors(floors, bidRequestWrapper, conversions, metricsEngine, account.ID, requestTime)
 	updateFloorsInRequest(bidRequestWrapper, floors)
 	return append(err, updateReqErrs...)
 }
 
 // updateBidRequestWithFloors will update imp.bidfloor and imp.bidfloorcur based on rules matching
-func updateBidRequestWithFloors(extFloorRules *openrtb_ext.PriceFloorRules, request *openrtb_ext.RequestWrapper, conversions currency.Conversions, metricEngine metrics.MetricsEngine, accountID string) []error {
+func updateBidRequestWithFloors(extFloorRules *openrtb_ext.PriceFloorRules, request *openrtb_ext.RequestWrapper, conversions currency.Conversions, metricEngine metrics.MetricsEngine, accountID string, requestTime time.Time) []error {
 	var (
 		floorErrList []error
 		floorVal     float64
@@ -86,13 +96,21 @@ func updateBidRequestWithFloors(extFloorRules *openrtb_ext.PriceFloorRules, requ
 	}
 
 	floorErrList = validateFloorRulesAndLowerValidRuleKey(modelGroup.Schema, modelGroup.Schema.Delimiter, modelGroup.Values)
+	floorErrList = append(floorErrList, validateRuleCurrenciesAndLowerRuleKey(modelGroup.Values, modelGroup.ValueCurrencies)...)
 	if len(modelGroup.Values) > 0 {
+		index := modelGroup.RuleIndex
+		if index == nil {
+			index = newRequestRuleIndex(modelGroup.Schema, modelGroup.Values)
+		}
+		keyCtx := ruleKeyContext{requestTime: requestTime, userSegments: index.UserSegments()}
 		for _, imp := range request.GetImp() {
-			desiredRuleKey := createRuleKey(modelGroup.Schema, request, imp)
-			matchedRule, isRuleMatched := findRule(modelGroup.Values, modelGroup.Schema.Delimiter, desiredRuleKey)
+			desiredRuleKey := createRuleKey(modelGroup.Schema, request, imp, keyCtx)
+			matchedRule, isRuleMatched := index.FindRule(desiredRuleKey)
 			floorVal = modelGroup.Default
+			floorRuleCur := ""
 			if isRuleMatched {
 				floorVal = modelGroup.Values[matchedRule]
+				floorRuleCur = modelGroup.ValueCurrencies[matchedRule]
 			}
 
 			// No rule is matched or no default value provided or non-zero bidfloor not provided
@@ -102,6 +120,16 @@ func updateBidRequestWithFloors(extFloorRules *openrtb_ext.PriceFloorRules, requ
 
 			floorMinVal, floorCur, err := getMinFloorValue(extFloorRules, imp, conversions)
 			if err == nil {
+				floorRuleVal := floorVal
+				isRuleConverted := floorRuleCur != "" && floorRuleCur != floorCur
+				if isRuleConverted {
+					rate, err := conversions.GetRate(floorRuleCur, floorCur)
+					if err != nil {
+						floorErrList = append(floorErrList, fmt.Errorf("Error in converting floor rule '%s' from %s to %s : '%v'", matchedRule, floorRuleCur, floorCur, err.Error()))
+						continue
+					}
+					floorVal = rate * floorVal
+				}
 				floorVal = roundToFourDecimals(floorVal)
 				bidFloor := floorVal
 				if floorMinVal > 0.0 && floorVal < floorMinVal {
@@ -128,6 +156,9 @@ func updateBidRequestWithFloors(extFloorRules *openrtb_ext.PriceFloorRules, requ
 				}
 
 				err = updateImpExtWithFloorDetails(imp, matchedRule, floorVal, imp.BidFloor)
+				if err == nil && isRuleConverted {
+					err = updateImpExtWithFloorRuleConversion(imp, floorRuleVal, floorRuleCur)
+				}
 				if err != nil {
 					floorErrList = append(floorErrList, err)
 				}
@@ -139,6 +170,20 @@ func updateBidRequestWithFloors(extFloorRules *openrtb_ext.PriceFloorRules, requ
 	return floorErrList
 }
 
+// getTimezoneLocation returns the location of the publisher timezone, invalid or empty timezones resolve to UTC
+func getTimezoneLocation(timezone string) *time.Location {
+	if location, ok := timezoneLocations.Load(timezone); ok {
+		return location.(*time.Location)
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	timezoneLocations.Store(timezone, location)
+	return location
+}
+
 // roundToFourDecimals retuns given value to 4 decimal points
 func roundToFourDecimals(in float64) float64 {
 	return math.Round(in*10000) / 10000
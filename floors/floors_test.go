@@ -5,6 +5,7 @@ import (
 	"errors"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/prebid/openrtb/v20/openrtb2"
@@ -2374,9 +2375,182 @@ func TestUpdateBidRequestWithFloors(t *testing.T) {
 		if test.setup != nil {
 			test.setup()
 		}
-		updateBidRequestWithFloors(test.args.extFloorRules, test.args.request, test.args.conversions, me, test.args.accountID)
+		updateBidRequestWithFloors(test.args.extFloorRules, test.args.request, test.args.conversions, me, test.args.accountID, time.Time{})
 		assert.Equal(t, test.args.request.Imp[0].BidFloor, test.expFloor.bidFloor, test.name)
 		assert.Equal(t, test.args.request.Imp[0].BidFloorCur, test.expFloor.bidFloorCur, test.name)
 		assert.True(t, me.AssertExpectations(t))
 	}
 }
+
+func TestUpdateBidRequestWithFloorsRuleCurrency(t *testing.T) {
+	getFloorRules := func(ruleCur string) *openrtb_ext.PriceFloorRules {
+		return &openrtb_ext.PriceFloorRules{
+			Data: &openrtb_ext.PriceFloorData{
+				Currency: "USD",
+				ModelGroups: []openrtb_ext.PriceFloorModelGroup{
+					{
+						Currency: "USD",
+						Values: map[string]float64{
+							"banner|www.website.com": 2,
+							"*|*":                    1,
+						},
+						ValueCurrencies: map[string]string{
+							"BANNER|www.website.com": ruleCur,
+						},
+						Schema: openrtb_ext.PriceFloorSchema{
+							Fields:    []string{"mediaType", "domain"},
+							Delimiter: "|",
+						},
+					},
+				},
+			},
+		}
+	}
+	conversions := currency.NewRates(map[string]map[string]float64{"EUR": {"USD": 1.2}})
+
+	tests := []struct {
+		name              string
+		extFloorRules     *openrtb_ext.PriceFloorRules
+		expectedErrs      []error
+		expectedBidFloor  float64
+		expectedImpFloors *openrtb_ext.ExtImpPrebidFloors
+	}{
+		{
+			name:             "rule_in_floors_currency",
+			extFloorRules:    getFloorRules("USD"),
+			expectedBidFloor: 2,
+			expectedImpFloors: &openrtb_ext.ExtImpPrebidFloors{
+				FloorRule:      "banner|www.website.com",
+				FloorRuleValue: 2,
+				FloorValue:     2,
+			},
+		},
+		{
+			name:             "rule_converted_to_floors_currency",
+			extFloorRules:    getFloorRules("EUR"),
+			expectedBidFloor: 2.4,
+			expectedImpFloors: &openrtb_ext.ExtImpPrebidFloors{
+				FloorRule:          "banner|www.website.com",
+				FloorRuleValue:     2.4,
+				FloorValue:         2.4,
+				FloorRuleOrigValue: 2,
+				FloorRuleOrigCur:   "EUR",
+			},
+		},
+		{
+			name:             "rule_conversion_not_available",
+			extFloorRules:    getFloorRules("GBP"),
+			expectedErrs:     []error{errors.New("Error in converting floor rule 'banner|www.website.com' from GBP to USD : 'Currency conversion rate not found: 'GBP' => 'USD''")},
+			expectedBidFloor: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{
+					Site: &openrtb2.Site{Domain: "www.website.com"},
+					Imp:  []openrtb2.Imp{{ID: "1234", Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}}}}},
+				},
+			}
+
+			errs := updateBidRequestWithFloors(tt.extFloorRules, request, conversions, &metrics.MetricsEngineMock{}, "5890", time.Time{})
+			assert.Equal(t, tt.expectedErrs, errs)
+			assert.Equal(t, tt.expectedBidFloor, request.Imp[0].BidFloor)
+
+			impExt, err := request.GetImp()[0].GetImpExt()
+			assert.NoError(t, err)
+			var impFloors *openrtb_ext.ExtImpPrebidFloors
+			if prebid := impExt.GetPrebid(); prebid != nil {
+				impFloors = prebid.Floors
+			}
+			assert.Equal(t, tt.expectedImpFloors, impFloors)
+		})
+	}
+}
+
+func TestUpdateBidRequestWithFloorsExtendedFields(t *testing.T) {
+	getFloorRules := func() *openrtb_ext.PriceFloorRules {
+		return &openrtb_ext.PriceFloorRules{
+			Data: &openrtb_ext.PriceFloorData{
+				ModelGroups: []openrtb_ext.PriceFloorModelGroup{
+					{
+						Values: map[string]float64{
+							"SEG2|21": 3,
+							"seg1|*":  2,
+							"*|21":    1.5,
+							"*|*":     1,
+						},
+						Schema: openrtb_ext.PriceFloorSchema{
+							Fields:    []string{"userSegment", "hourOfDay"},
+							Delimiter: "|",
+						},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name             string
+		segments         []openrtb2.Segment
+		requestTime      time.Time
+		expectedRule     string
+		expectedBidFloor float64
+	}{
+		{
+			name:             "segment_in_rules_and_hour_matched",
+			segments:         []openrtb2.Segment{{ID: "seg3"}, {ID: "seg2"}},
+			requestTime:      time.Date(2024, 5, 1, 21, 30, 0, 0, time.UTC),
+			expectedRule:     "seg2|21",
+			expectedBidFloor: 3,
+		},
+		{
+			name:             "hour_in_publisher_timezone",
+			segments:         []openrtb2.Segment{{ID: "seg2"}},
+			requestTime:      time.Date(2024, 5, 1, 21, 30, 0, 0, time.UTC).In(getTimezoneLocation("America/New_York")),
+			expectedRule:     "*|*",
+			expectedBidFloor: 1,
+		},
+		{
+			name:             "segment_matched_any_hour",
+			segments:         []openrtb2.Segment{{ID: "seg1"}},
+			requestTime:      time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC),
+			expectedRule:     "seg1|*",
+			expectedBidFloor: 2,
+		},
+		{
+			name:             "no_segment_hour_matched",
+			requestTime:      time.Date(2024, 5, 1, 21, 0, 0, 0, time.UTC),
+			expectedRule:     "*|21",
+			expectedBidFloor: 1.5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{
+					User: &openrtb2.User{Data: []openrtb2.Data{{Segment: tt.segments}}},
+					Imp:  []openrtb2.Imp{{ID: "1234", Banner: &openrtb2.Banner{}}},
+				},
+			}
+
+			errs := updateBidRequestWithFloors(getFloorRules(), request, getCurrencyRates(map[string]map[string]float64{}), &metrics.MetricsEngineMock{}, "5890", tt.requestTime)
+			assert.Empty(t, errs)
+			assert.Equal(t, tt.expectedBidFloor, request.Imp[0].BidFloor)
+
+			impExt, err := request.GetImp()[0].GetImpExt()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedRule, impExt.GetPrebid().Floors.FloorRule)
+		})
+	}
+}
+
+func TestGetTimezoneLocation(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+
+	assert.Equal(t, newYork, getTimezoneLocation("America/New_York"))
+	assert.Equal(t, newYork, getTimezoneLocation("America/New_York"), "cached location")
+	assert.Equal(t, time.UTC, getTimezoneLocation(""))
+	assert.Equal(t, time.UTC, getTimezoneLocation("Mars/Olympus_Mons"))
+}
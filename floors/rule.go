@@ -4,11 +4,15 @@ import (
 	"fmt"
 	"math/bits"
 	"regexp"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v3/currency"
+	"github.com/prebid/prebid-server/v3/dsa"
 	"github.com/prebid/prebid-server/v3/logger"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/util/ptrutil"
@@ -26,6 +30,11 @@ const (
 	AdUnitCode          string = "adUnitCode"
 	Country             string = "country"
 	DeviceType          string = "deviceType"
+	ContentGenre        string = "contentGenre"
+	LiveStream          string = "liveStream"
+	UserSegment         string = "userSegment"
+	HourOfDay           string = "hourOfDay"
+	DSARequired         string = "dsaRequired"
 	Tablet              string = "tablet"
 	Desktop             string = "desktop"
 	Phone               string = "phone"
@@ -160,6 +169,23 @@ func updateImpExtWithFloorDetails(imp *openrtb_ext.ImpWrapper, matchedRule strin
 	return err
 }
 
+// updateImpExtWithFloorRuleConversion records the original value and currency of a floor rule converted to the
+// floors currency into imp.ext.prebid.floors
+func updateImpExtWithFloorRuleConversion(imp *openrtb_ext.ImpWrapper, floorRuleOrigVal float64, floorRuleOrigCur string) error {
+	impExt, err := imp.GetImpExt()
+	if err != nil {
+		return err
+	}
+	extImpPrebid := impExt.GetPrebid()
+	if extImpPrebid == nil || extImpPrebid.Floors == nil {
+		return nil
+	}
+	extImpPrebid.Floors.FloorRuleOrigValue = roundToFourDecimals(floorRuleOrigVal)
+	extImpPrebid.Floors.FloorRuleOrigCur = floorRuleOrigCur
+	impExt.SetPrebid(extImpPrebid)
+	return nil
+}
+
 // selectFloorModelGroup selects one modelgroup based on modelweight out of multiple modelgroups, if provided into floors JSON.
 func selectFloorModelGroup(modelGroups []openrtb_ext.PriceFloorModelGroup, f func(int) int) []openrtb_ext.PriceFloorModelGroup {
 	totalModelWeight := 0
@@ -209,20 +235,32 @@ func shouldSkipFloors(ModelGroupsSkipRate, DataSkipRate, RootSkipRate int, f fun
 	return skipRate >= f(skipRateMax+1)
 }
 
-// findRule prepares rule combinations based on schema dimensions provided in floors data, request values associated with these fields and
-// does matching with rules provided in floors data and returns matched rule
-func findRule(ruleValues map[string]float64, delimiter string, desiredRuleKey []string) (string, bool) {
-	ruleKeys := prepareRuleCombinations(desiredRuleKey, delimiter)
-	for i := 0; i < len(ruleKeys); i++ {
-		if _, ok := ruleValues[ruleKeys[i]]; ok {
-			return ruleKeys[i], true
+// ruleKeyContext holds the inputs of schema dimensions which are not derived from the request alone
+type ruleKeyContext struct {
+	// requestTime is the auction time in the publisher timezone
+	requestTime time.Time
+	// userSegments holds the userSegment values present in the floor rules, nil if any segment can be used
+	userSegments map[string]struct{}
+}
+
+// ruleUserSegments returns the userSegment values present in the floor rules, nil if the schema has no userSegment field
+func ruleUserSegments(floorSchema openrtb_ext.PriceFloorSchema, ruleValues map[string]float64) map[string]struct{} {
+	segmentIndex := slices.Index(floorSchema.Fields, UserSegment)
+	if segmentIndex < 0 {
+		return nil
+	}
+
+	userSegments := make(map[string]struct{})
+	for ruleKey := range ruleValues {
+		if parsedKey := strings.Split(ruleKey, floorSchema.Delimiter); len(parsedKey) == len(floorSchema.Fields) {
+			userSegments[strings.ToLower(parsedKey[segmentIndex])] = struct{}{}
 		}
 	}
-	return "", false
+	return userSegments
 }
 
 // createRuleKey prepares rule keys based on schema dimension and values present in request
-func createRuleKey(floorSchema openrtb_ext.PriceFloorSchema, request *openrtb_ext.RequestWrapper, imp *openrtb_ext.ImpWrapper) []string {
+func createRuleKey(floorSchema openrtb_ext.PriceFloorSchema, request *openrtb_ext.RequestWrapper, imp *openrtb_ext.ImpWrapper, keyCtx ruleKeyContext) []string {
 	var ruleKeys []string
 
 	for _, field := range floorSchema.Fields {
@@ -250,6 +288,16 @@ func createRuleKey(floorSchema openrtb_ext.PriceFloorSchema, request *openrtb_ex
 			value = getGptSlot(imp)
 		case AdUnitCode:
 			value = getAdUnitCode(imp)
+		case ContentGenre:
+			value = getContentGenre(request)
+		case LiveStream:
+			value = getLiveStream(request)
+		case UserSegment:
+			value = getUserSegment(request, keyCtx.userSegments)
+		case HourOfDay:
+			value = getHourOfDay(keyCtx.requestTime)
+		case DSARequired:
+			value = getDSARequired(request)
 		}
 		ruleKeys = append(ruleKeys, value)
 	}
@@ -459,6 +507,85 @@ func getAdUnitCode(imp *openrtb_ext.ImpWrapper) string {
 	return adUnitCode
 }
 
+// getContent returns content object provided into site or app object
+func getContent(request *openrtb_ext.RequestWrapper) *openrtb2.Content {
+	if request.Site != nil {
+		return request.Site.Content
+	} else if request.App != nil {
+		return request.App.Content
+	}
+	return nil
+}
+
+// getContentGenre returns content genre provided into site or app object
+func getContentGenre(request *openrtb_ext.RequestWrapper) string {
+	value := catchAll
+	if content := getContent(request); content != nil && len(content.Genre) > 0 {
+		value = content.Genre
+	}
+	return value
+}
+
+// getLiveStream returns 1 for live content and 0 for non-live content provided into site or app object
+func getLiveStream(request *openrtb_ext.RequestWrapper) string {
+	value := catchAll
+	if content := getContent(request); content != nil && content.LiveStream != nil {
+		value = strconv.Itoa(int(*content.LiveStream))
+	}
+	return value
+}
+
+// getUserSegment returns the first user segment id provided into user data which is also present in the floor rules,
+// only the first maxUserSegments segment ids are considered
+func getUserSegment(request *openrtb_ext.RequestWrapper, ruleSegments map[string]struct{}) string {
+	if request.User == nil {
+		return catchAll
+	}
+
+	segmentCount := 0
+	for _, data := range request.User.Data {
+		for _, segment := range data.Segment {
+			if segment.ID == "" {
+				continue
+			}
+			if segmentCount++; segmentCount > maxUserSegments {
+				return catchAll
+			}
+			if ruleSegments == nil {
+				return segment.ID
+			}
+			if _, ok := ruleSegments[strings.ToLower(segment.ID)]; ok {
+				return segment.ID
+			}
+		}
+	}
+	return catchAll
+}
+
+// getHourOfDay returns hour of the request time, in the range 0-23
+func getHourOfDay(requestTime time.Time) string {
+	if requestTime.IsZero() {
+		return catchAll
+	}
+	return strconv.Itoa(requestTime.Hour())
+}
+
+// getDSARequired returns 1 if DSA transparency is required as per regs.ext.dsa.dsarequired, otherwise 0
+func getDSARequired(request *openrtb_ext.RequestWrapper) string {
+	value := catchAll
+	regExt, err := request.GetRegExt()
+	if err != nil || regExt == nil {
+		return value
+	}
+	if regsDSA := regExt.GetDSA(); regsDSA != nil && regsDSA.Required != nil {
+		value = "0"
+		if *regsDSA.Required == dsa.Required || *regsDSA.Required == dsa.RequiredOnlinePlatform {
+			value = "1"
+		}
+	}
+	return value
+}
+
 // isMobileDevice returns true if device is mobile
 func isMobileDevice(userAgent string) bool {
 	isMobile, err := regexp.MatchString("(?i)Phone|iPhone|Android.*Mobile|Mobile.*Android", userAgent)
@@ -0,0 +1,163 @@
+package floors
+
+import (
+	"strings"
+
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+)
+
+// ruleIndexNode is a node of the rule index trie, children are keyed by the lower cased schema field value
+// of the rules, including the catch all value
+type ruleIndexNode struct {
+	children map[string]*ruleIndexNode
+	ruleKey  string
+}
+
+// ruleIndex is a trie of floor rules keyed by schema field values in schema order. It selects the same rule as
+// the prebid rule selection process without expanding the 2^n wildcard combinations of the desired rule key. An index
+// is compiled once per fetched floors data and shared by the requests using it, floors data of the request with more
+// schema fields than the rule combinations support is compiled once per request.
+type ruleIndex struct {
+	root         *ruleIndexNode
+	numFields    int
+	userSegments map[string]struct{}
+}
+
+// newModelGroupRuleIndex compiles the rule index of the model group rules as they are validated for a request
+func newModelGroupRuleIndex(modelGroup openrtb_ext.PriceFloorModelGroup) *ruleIndex {
+	modelGroup = modelGroup.Copy()
+	if modelGroup.Schema.Delimiter == "" {
+		modelGroup.Schema.Delimiter = defaultDelimiter
+	}
+	validateFloorRulesAndLowerValidRuleKey(modelGroup.Schema, modelGroup.Schema.Delimiter, modelGroup.Values)
+	validateRuleCurrenciesAndLowerRuleKey(modelGroup.Values, modelGroup.ValueCurrencies)
+	return newRuleIndex(modelGroup.Schema, modelGroup.Values)
+}
+
+// newRuleIndex compiles the floor rules into a rule index, rules not having a value for every schema field are skipped
+func newRuleIndex(schema openrtb_ext.PriceFloorSchema, ruleValues map[string]float64) *ruleIndex {
+	index := &ruleIndex{
+		root:         &ruleIndexNode{},
+		numFields:    len(schema.Fields),
+		userSegments: ruleUserSegments(schema, ruleValues),
+	}
+
+	for ruleKey := range ruleValues {
+		fieldValues := strings.Split(ruleKey, schema.Delimiter)
+		if len(fieldValues) != index.numFields {
+			continue
+		}
+
+		node := index.root
+		for _, value := range fieldValues {
+			value = strings.ToLower(value)
+			child, ok := node.children[value]
+			if !ok {
+				if node.children == nil {
+					node.children = make(map[string]*ruleIndexNode)
+				}
+				child = &ruleIndexNode{}
+				node.children[value] = child
+			}
+			node = child
+		}
+		node.ruleKey = ruleKey
+	}
+	return index
+}
+
+// FindRule returns the most specific rule matching the desired rule key: the rule with the least wildcards and, among
+// rules with as many wildcards, the rule whose left-most fields are exact. Values of desiredRuleKey are lower cased in place.
+func (index *ruleIndex) FindRule(desiredRuleKey []string) (string, bool) {
+	if index == nil || index.numFields == 0 || len(desiredRuleKey) != index.numFields {
+		return "", false
+	}
+
+	for i := range desiredRuleKey {
+		desiredRuleKey[i] = strings.ToLower(desiredRuleKey[i])
+	}
+
+	var matched *ruleIndexNode
+	matchedWildcards := index.numFields + 1
+	index.root.search(desiredRuleKey, 0, 0, &matched, &matchedWildcards)
+	if matched == nil {
+		return "", false
+	}
+	return matched.ruleKey, true
+}
+
+// UserSegments returns the userSegment values of the rules, collected when the index is compiled
+func (index *ruleIndex) UserSegments() map[string]struct{} {
+	if index == nil {
+		return nil
+	}
+	return index.userSegments
+}
+
+// search walks the trie depth first, exact values before wildcards, so the first rule reached with a given number of
+// wildcards is the one whose left-most fields are exact. Branches which cannot have less wildcards than the current
+// match are pruned.
+func (node *ruleIndexNode) search(values []string, depth, wildcards int, matched **ruleIndexNode, matchedWildcards *int) {
+	if wildcards >= *matchedWildcards {
+		return
+	}
+	if depth == len(values) {
+		*matched = node
+		*matchedWildcards = wildcards
+		return
+	}
+
+	value := values[depth]
+	if value != catchAll {
+		if child, ok := node.children[value]; ok {
+			child.search(values, depth+1, wildcards, matched, matchedWildcards)
+		}
+		wildcards++
+	}
+	if child, ok := node.children[catchAll]; ok {
+		child.search(values, depth+1, wildcards, matched, matchedWildcards)
+	}
+}
+
+// maxRuleCombinationFields is the number of schema fields up to which the rule combinations are ordered correctly, their
+// weights overflow beyond it
+const maxRuleCombinationFields = 5
+
+// ruleCombinations selects the floor rule by looking up the rule combinations of the desired rule key, from the most
+// to the least specific, in the rule values. It is used for floors data of the request which is not shared by other
+// requests, looking up the few combinations of a small schema is cheaper than compiling the rule index.
+type ruleCombinations struct {
+	delimiter    string
+	ruleValues   map[string]float64
+	userSegments map[string]struct{}
+}
+
+// newRequestRuleIndex returns the rule selection of floors data used by a single request
+func newRequestRuleIndex(schema openrtb_ext.PriceFloorSchema, ruleValues map[string]float64) openrtb_ext.PriceFloorRuleIndex {
+	if len(schema.Fields) <= maxRuleCombinationFields {
+		return ruleCombinations{
+			delimiter:    schema.Delimiter,
+			ruleValues:   ruleValues,
+			userSegments: ruleUserSegments(schema, ruleValues),
+		}
+	}
+	return newRuleIndex(schema, ruleValues)
+}
+
+// FindRule returns the first rule combination of the desired rule key present in the rule values
+func (c ruleCombinations) FindRule(desiredRuleKey []string) (string, bool) {
+	if len(desiredRuleKey) == 0 {
+		return "", false
+	}
+	for _, ruleKey := range prepareRuleCombinations(desiredRuleKey, c.delimiter) {
+		if _, ok := c.ruleValues[ruleKey]; ok {
+			return ruleKey, true
+		}
+	}
+	return "", false
+}
+
+// UserSegments returns the userSegment values of the rules
+func (c ruleCombinations) UserSegments() map[string]struct{} {
+	return c.userSegments
+}
@@ -0,0 +1,253 @@
+package floors
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestSchema(numFields int) openrtb_ext.PriceFloorSchema {
+	fields := []string{MediaType, Size, Domain, Country, DeviceType, Channel}
+	return openrtb_ext.PriceFloorSchema{Fields: fields[:numFields], Delimiter: "|"}
+}
+
+// generateRules returns random rule values, each schema field value is taken from fieldValues or is a catch all
+func generateRules(r *rand.Rand, numFields, numRules int, fieldValues []string) map[string]float64 {
+	rules := make(map[string]float64, numRules)
+	for len(rules) < numRules {
+		rules[strings.Join(generateRuleKey(r, numFields, fieldValues), "|")] = float64(len(rules) + 1)
+	}
+	return rules
+}
+
+func generateRuleKey(r *rand.Rand, numFields int, fieldValues []string) []string {
+	key := make([]string, numFields)
+	for i := range key {
+		if r.Intn(3) == 0 {
+			key[i] = catchAll
+		} else {
+			key[i] = fieldValues[r.Intn(len(fieldValues))]
+		}
+	}
+	return key
+}
+
+func TestRuleIndexFindRule(t *testing.T) {
+	tests := []struct {
+		name           string
+		numFields      int
+		rules          map[string]float64
+		desiredRuleKey []string
+		expectedRule   string
+		expectedFound  bool
+	}{
+		{
+			name:           "exact_match",
+			numFields:      3,
+			rules:          map[string]float64{"banner|300x250|www.website.com": 1, "*|*|*": 2},
+			desiredRuleKey: []string{"banner", "300x250", "www.website.com"},
+			expectedRule:   "banner|300x250|www.website.com",
+			expectedFound:  true,
+		},
+		{
+			name:           "desired_key_lower_cased",
+			numFields:      3,
+			rules:          map[string]float64{"banner|300x250|www.website.com": 1},
+			desiredRuleKey: []string{"BANNER", "300x250", "WWW.Website.com"},
+			expectedRule:   "banner|300x250|www.website.com",
+			expectedFound:  true,
+		},
+		{
+			name:           "least_wildcards_preferred",
+			numFields:      3,
+			rules:          map[string]float64{"*|*|www.website.com": 1, "banner|*|*": 2, "banner|300x250|*": 3},
+			desiredRuleKey: []string{"banner", "300x250", "www.website.com"},
+			expectedRule:   "banner|300x250|*",
+			expectedFound:  true,
+		},
+		{
+			name:           "left_most_exact_preferred",
+			numFields:      3,
+			rules:          map[string]float64{"*|300x250|www.website.com": 1, "banner|*|www.website.com": 2, "banner|300x250|*": 3},
+			desiredRuleKey: []string{"banner", "300x250", "www.website.com"},
+			expectedRule:   "banner|300x250|*",
+			expectedFound:  true,
+		},
+		{
+			name:           "left_most_exact_preferred_six_fields",
+			numFields:      6,
+			rules:          map[string]float64{"*|b|c|d|e|f": 1, "a|b|c|d|e|*": 2},
+			desiredRuleKey: []string{"a", "b", "c", "d", "e", "f"},
+			expectedRule:   "a|b|c|d|e|*",
+			expectedFound:  true,
+		},
+		{
+			name:           "least_wildcards_preferred_six_fields",
+			numFields:      6,
+			rules:          map[string]float64{"a|*|*|*|*|f": 1, "*|b|c|d|e|f": 2, "*|*|*|*|*|*": 3},
+			desiredRuleKey: []string{"a", "b", "c", "d", "e", "f"},
+			expectedRule:   "*|b|c|d|e|f",
+			expectedFound:  true,
+		},
+		{
+			name:           "left_most_exact_preferred_six_fields_two_wildcards",
+			numFields:      6,
+			rules:          map[string]float64{"*|b|*|d|e|f": 1, "a|*|c|*|e|f": 2, "a|b|c|d|*|x": 3},
+			desiredRuleKey: []string{"a", "b", "c", "d", "e", "f"},
+			expectedRule:   "a|*|c|*|e|f",
+			expectedFound:  true,
+		},
+		{
+			name:           "catch_all_only_six_fields",
+			numFields:      6,
+			rules:          map[string]float64{"a|b|c|d|e|x": 1, "*|*|*|*|*|*": 2},
+			desiredRuleKey: []string{"a", "b", "c", "d", "e", "f"},
+			expectedRule:   "*|*|*|*|*|*",
+			expectedFound:  true,
+		},
+		{
+			name:           "catch_all_request_value_matches_as_exact",
+			numFields:      2,
+			rules:          map[string]float64{"*|www.website.com": 1, "banner|*": 2},
+			desiredRuleKey: []string{"*", "www.website.com"},
+			expectedRule:   "*|www.website.com",
+			expectedFound:  true,
+		},
+		{
+			name:           "no_match",
+			numFields:      2,
+			rules:          map[string]float64{"video|www.website.com": 1},
+			desiredRuleKey: []string{"banner", "www.website.com"},
+			expectedFound:  false,
+		},
+		{
+			name:           "desired_key_length_mismatch",
+			numFields:      2,
+			rules:          map[string]float64{"*|*": 1},
+			desiredRuleKey: []string{"banner"},
+			expectedFound:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			index := newRuleIndex(newTestSchema(tt.numFields), tt.rules)
+			rule, found := index.FindRule(tt.desiredRuleKey)
+			assert.Equal(t, tt.expectedFound, found)
+			assert.Equal(t, tt.expectedRule, rule)
+		})
+	}
+}
+
+func TestRuleIndexSameResultsAsRuleCombinations(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	fieldValues := []string{"a", "b", "c"}
+
+	// rule combinations are ordered by weights which overflow beyond five schema fields
+	for numFields := 1; numFields <= 5; numFields++ {
+		for iteration := 0; iteration < 50; iteration++ {
+			schema := newTestSchema(numFields)
+			rules := generateRules(r, numFields, 1+r.Intn(1<<numFields), fieldValues)
+			index := newRuleIndex(schema, rules)
+
+			for lookup := 0; lookup < 20; lookup++ {
+				desiredRuleKey := generateRuleKey(r, numFields, append(fieldValues, "d", "A"))
+				expectedRule, expectedFound := ruleCombinations{delimiter: schema.Delimiter, ruleValues: rules}.FindRule(desiredRuleKey)
+
+				rule, found := index.FindRule(append([]string(nil), desiredRuleKey...))
+				if !assert.Equal(t, expectedFound, found, "rules %v, key %v", rules, desiredRuleKey) ||
+					!assert.Equal(t, expectedRule, rule, "rules %v, key %v", rules, desiredRuleKey) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func TestNewRequestRuleIndex(t *testing.T) {
+	rules := map[string]float64{"*|*|*|*|*": 1}
+	assert.IsType(t, ruleCombinations{}, newRequestRuleIndex(newTestSchema(maxRuleCombinationFields), rules))
+
+	rules = map[string]float64{"*|*|*|*|*|*": 1}
+	assert.IsType(t, &ruleIndex{}, newRequestRuleIndex(newTestSchema(maxRuleCombinationFields+1), rules))
+}
+
+func TestRuleIndexUserSegments(t *testing.T) {
+	schema := openrtb_ext.PriceFloorSchema{Delimiter: "|", Fields: []string{MediaType, UserSegment}}
+	rules := map[string]float64{"banner|seg1": 1, "*|*": 2}
+	expected := map[string]struct{}{"seg1": {}, "*": {}}
+
+	assert.Equal(t, expected, newRuleIndex(schema, rules).UserSegments())
+	assert.Equal(t, expected, newRequestRuleIndex(schema, rules).UserSegments())
+	assert.Nil(t, newRuleIndex(newTestSchema(2), map[string]float64{"*|*": 1}).UserSegments())
+
+	var index *ruleIndex
+	assert.Nil(t, index.UserSegments())
+}
+
+func TestRuleCombinationsFindRule(t *testing.T) {
+	rules := map[string]float64{"banner|*|www.website.com": 1, "banner|300x250|*": 2}
+	index := ruleCombinations{delimiter: "|", ruleValues: rules}
+
+	rule, found := index.FindRule([]string{"BANNER", "300x250", "www.website.com"})
+	assert.True(t, found)
+	assert.Equal(t, "banner|300x250|*", rule)
+
+	rule, found = index.FindRule([]string{"video", "300x250", "www.website.com"})
+	assert.False(t, found)
+	assert.Empty(t, rule)
+}
+
+func benchmarkFindRule(b *testing.B, newIndex func(openrtb_ext.PriceFloorSchema, map[string]float64) openrtb_ext.PriceFloorRuleIndex) {
+	r := rand.New(rand.NewSource(1))
+	fieldValues := []string{"banner", "video", "300x250", "728x90", "www.website.com", "usa", "phone", "web"}
+	schema := newTestSchema(5)
+	rules := generateRules(r, len(schema.Fields), 1000, fieldValues)
+
+	var desiredRuleKeys [][]string
+	for i := 0; i < 5; i++ {
+		desiredRuleKeys = append(desiredRuleKeys, generateRuleKey(r, len(schema.Fields), fieldValues))
+	}
+
+	index := newIndex(schema, rules)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, desiredRuleKey := range desiredRuleKeys {
+			index.FindRule(desiredRuleKey)
+		}
+	}
+}
+
+// BenchmarkRuleIndexFindRule measures the rule index compiled once per fetched floors data and shared by the imps
+// of every request
+func BenchmarkRuleIndexFindRule(b *testing.B) {
+	benchmarkFindRule(b, func(schema openrtb_ext.PriceFloorSchema, rules map[string]float64) openrtb_ext.PriceFloorRuleIndex {
+		return newRuleIndex(schema, rules)
+	})
+}
+
+// BenchmarkRuleCombinationsFindRule measures the lookup of the rule combinations of the desired rule key
+func BenchmarkRuleCombinationsFindRule(b *testing.B) {
+	benchmarkFindRule(b, func(schema openrtb_ext.PriceFloorSchema, rules map[string]float64) openrtb_ext.PriceFloorRuleIndex {
+		return ruleCombinations{delimiter: schema.Delimiter, ruleValues: rules}
+	})
+}
+
+// BenchmarkRuleIndexCompileAndFindRule measures the rule index compiled for floors data of the request
+func BenchmarkRuleIndexCompileAndFindRule(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	fieldValues := []string{"banner", "video", "300x250", "728x90", "www.website.com", "usa", "phone", "web"}
+	schema := newTestSchema(5)
+	rules := generateRules(r, len(schema.Fields), 100, fieldValues)
+	desiredRuleKey := generateRuleKey(r, len(schema.Fields), fieldValues)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		newRuleIndex(schema, rules).FindRule(desiredRuleKey)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v3/currency"
@@ -515,12 +516,130 @@ func TestCreateRuleKeys(t *testing.T) {
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			out := createRuleKey(tc.floorSchema, &openrtb_ext.RequestWrapper{BidRequest: tc.request}, &openrtb_ext.ImpWrapper{Imp: &tc.request.Imp[0]})
+			out := createRuleKey(tc.floorSchema, &openrtb_ext.RequestWrapper{BidRequest: tc.request}, &openrtb_ext.ImpWrapper{Imp: &tc.request.Imp[0]}, ruleKeyContext{})
 			assert.Equal(t, out, tc.out, tc.name)
 		})
 	}
 }
 
+func TestCreateRuleKeysExtendedFields(t *testing.T) {
+	requestTime := time.Date(2024, 5, 1, 21, 30, 0, 0, time.UTC)
+	testCases := []struct {
+		name        string
+		request     *openrtb2.BidRequest
+		floorSchema openrtb_ext.PriceFloorSchema
+		keyCtx      ruleKeyContext
+		out         []string
+	}{
+		{
+			name: "site content and dsa required",
+			request: &openrtb2.BidRequest{
+				Site: &openrtb2.Site{Content: &openrtb2.Content{Genre: "Sports", LiveStream: ptrutil.ToPtr[int8](1)}},
+				Regs: &openrtb2.Regs{Ext: json.RawMessage(`{"dsa":{"dsarequired":2}}`)},
+				Imp:  []openrtb2.Imp{{ID: "1234", Video: &openrtb2.Video{}}},
+			},
+			floorSchema: openrtb_ext.PriceFloorSchema{Delimiter: "|", Fields: []string{"contentGenre", "liveStream", "dsaRequired", "hourOfDay"}},
+			keyCtx:      ruleKeyContext{requestTime: requestTime},
+			out:         []string{"Sports", "1", "1", "21"},
+		},
+		{
+			name: "app content and dsa supported",
+			request: &openrtb2.BidRequest{
+				App:  &openrtb2.App{Content: &openrtb2.Content{Genre: "News", LiveStream: ptrutil.ToPtr[int8](0)}},
+				Regs: &openrtb2.Regs{Ext: json.RawMessage(`{"dsa":{"dsarequired":1}}`)},
+				Imp:  []openrtb2.Imp{{ID: "1234", Video: &openrtb2.Video{}}},
+			},
+			floorSchema: openrtb_ext.PriceFloorSchema{Delimiter: "|", Fields: []string{"contentGenre", "liveStream", "dsaRequired"}},
+			out:         []string{"News", "0", "0"},
+		},
+		{
+			name: "values not present",
+			request: &openrtb2.BidRequest{
+				Site: &openrtb2.Site{},
+				Imp:  []openrtb2.Imp{{ID: "1234", Video: &openrtb2.Video{}}},
+			},
+			floorSchema: openrtb_ext.PriceFloorSchema{Delimiter: "|", Fields: []string{"contentGenre", "liveStream", "dsaRequired", "hourOfDay", "userSegment"}},
+			out:         []string{"*", "*", "*", "*", "*"},
+		},
+		{
+			name: "user segment present in rules",
+			request: &openrtb2.BidRequest{
+				User: &openrtb2.User{Data: []openrtb2.Data{
+					{Segment: []openrtb2.Segment{{ID: "seg1"}, {ID: ""}}},
+					{Segment: []openrtb2.Segment{{ID: "SEG2"}}},
+				}},
+				Imp: []openrtb2.Imp{{ID: "1234", Banner: &openrtb2.Banner{}}},
+			},
+			floorSchema: openrtb_ext.PriceFloorSchema{Delimiter: "|", Fields: []string{"userSegment"}},
+			keyCtx:      ruleKeyContext{userSegments: map[string]struct{}{"seg2": {}}},
+			out:         []string{"SEG2"},
+		},
+		{
+			name: "user segment without rule set takes first segment",
+			request: &openrtb2.BidRequest{
+				User: &openrtb2.User{Data: []openrtb2.Data{{Segment: []openrtb2.Segment{{ID: "seg1"}, {ID: "seg2"}}}}},
+				Imp:  []openrtb2.Imp{{ID: "1234", Banner: &openrtb2.Banner{}}},
+			},
+			floorSchema: openrtb_ext.PriceFloorSchema{Delimiter: "|", Fields: []string{"userSegment"}},
+			out:         []string{"seg1"},
+		},
+		{
+			name: "user segment not present in rules",
+			request: &openrtb2.BidRequest{
+				User: &openrtb2.User{Data: []openrtb2.Data{{Segment: []openrtb2.Segment{{ID: "seg1"}}}}},
+				Imp:  []openrtb2.Imp{{ID: "1234", Banner: &openrtb2.Banner{}}},
+			},
+			floorSchema: openrtb_ext.PriceFloorSchema{Delimiter: "|", Fields: []string{"userSegment"}},
+			keyCtx:      ruleKeyContext{userSegments: map[string]struct{}{"seg2": {}}},
+			out:         []string{"*"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out := createRuleKey(tc.floorSchema, &openrtb_ext.RequestWrapper{BidRequest: tc.request}, &openrtb_ext.ImpWrapper{Imp: &tc.request.Imp[0]}, tc.keyCtx)
+			assert.Equal(t, tc.out, out)
+		})
+	}
+}
+
+func TestGetUserSegmentLimit(t *testing.T) {
+	var segments []openrtb2.Segment
+	for i := 0; i < maxUserSegments; i++ {
+		segments = append(segments, openrtb2.Segment{ID: "seg"})
+	}
+	segments = append(segments, openrtb2.Segment{ID: "match"})
+	request := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{User: &openrtb2.User{Data: []openrtb2.Data{{Segment: segments}}}}}
+
+	assert.Equal(t, "*", getUserSegment(request, map[string]struct{}{"match": {}}), "segments beyond the limit must not be considered")
+}
+
+func TestRuleUserSegments(t *testing.T) {
+	testCases := []struct {
+		name       string
+		schema     openrtb_ext.PriceFloorSchema
+		ruleValues map[string]float64
+		expected   map[string]struct{}
+	}{
+		{
+			name:       "schema without user segment",
+			schema:     openrtb_ext.PriceFloorSchema{Delimiter: "|", Fields: []string{"mediaType", "hourOfDay"}},
+			ruleValues: map[string]float64{"banner|21": 1},
+			expected:   nil,
+		},
+		{
+			name:       "schema with user segment",
+			schema:     openrtb_ext.PriceFloorSchema{Delimiter: "|", Fields: []string{"mediaType", "userSegment"}},
+			ruleValues: map[string]float64{"banner|seg1": 1, "video|SEG2": 1, "*|*": 1, "invalid": 1},
+			expected:   map[string]struct{}{"seg1": {}, "seg2": {}, "*": {}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ruleUserSegments(tc.schema, tc.ruleValues))
+		})
+	}
+}
+
 func TestShouldSkipFloors(t *testing.T) {
 
 	testCases := []struct {
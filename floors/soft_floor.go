@@ -0,0 +1,33 @@
+package floors
+
+import (
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/currency"
+)
+
+// GetImpFloor returns the hard floor of an imp (imp.bidfloor) converted to the given currency
+func GetImpFloor(imp *openrtb2.Imp, cur string, conversions currency.Conversions) (float64, error) {
+	if imp == nil || imp.BidFloor <= 0 {
+		return 0, nil
+	}
+
+	floorCur := imp.BidFloorCur
+	if floorCur == "" {
+		floorCur = defaultCurrency
+	}
+
+	rate, err := getCurrencyConversionRate(floorCur, cur, conversions)
+	if err != nil {
+		return 0, err
+	}
+	return roundToFourDecimals(imp.BidFloor * rate), nil
+}
+
+// GetSoftFloor returns the soft floor derived from the hard floor of an imp. Winning bids between
+// the hard and the soft floor are not eligible for second price clearing and clear at the bid price.
+func GetSoftFloor(hardFloor float64, softFloorMultiplier float64) float64 {
+	if softFloorMultiplier <= 1 {
+		return hardFloor
+	}
+	return roundToFourDecimals(hardFloor * softFloorMultiplier)
+}
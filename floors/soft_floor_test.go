@@ -0,0 +1,91 @@
+package floors
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetImpFloor(t *testing.T) {
+	tests := []struct {
+		name        string
+		imp         *openrtb2.Imp
+		cur         string
+		want        float64
+		expectedErr error
+	}{
+		{
+			name: "nil_imp",
+			imp:  nil,
+			cur:  "USD",
+			want: 0,
+		},
+		{
+			name: "no_floor",
+			imp:  &openrtb2.Imp{ID: "1"},
+			cur:  "USD",
+			want: 0,
+		},
+		{
+			name: "same_currency",
+			imp:  &openrtb2.Imp{ID: "1", BidFloor: 1.5, BidFloorCur: "USD"},
+			cur:  "USD",
+			want: 1.5,
+		},
+		{
+			name: "default_floor_currency_converted",
+			imp:  &openrtb2.Imp{ID: "1", BidFloor: 2},
+			cur:  "INR",
+			want: 155.18,
+		},
+		{
+			name:        "unsupported_conversion",
+			imp:         &openrtb2.Imp{ID: "1", BidFloor: 2, BidFloorCur: "EUR"},
+			cur:         "USD",
+			want:        0,
+			expectedErr: errors.New("currency conversion not supported"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetImpFloor(tt.imp, tt.cur, convert{})
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetSoftFloor(t *testing.T) {
+	tests := []struct {
+		name       string
+		hardFloor  float64
+		multiplier float64
+		want       float64
+	}{
+		{
+			name:       "multiplier_not_set",
+			hardFloor:  1.5,
+			multiplier: 0,
+			want:       1.5,
+		},
+		{
+			name:       "multiplier_one",
+			hardFloor:  1.5,
+			multiplier: 1,
+			want:       1.5,
+		},
+		{
+			name:       "multiplier_applied",
+			hardFloor:  1.5,
+			multiplier: 1.2,
+			want:       1.8,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, GetSoftFloor(tt.hardFloor, tt.multiplier))
+		})
+	}
+}
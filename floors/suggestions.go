@@ -0,0 +1,339 @@
+package floors
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/currency"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/logger"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/prebid/prebid-server/v3/util/timeutil"
+)
+
+const (
+	suggestionFloorProvider = "pbs-suggestions"
+	landscapeShardCount     = 32
+	// fillRateEpsilon absorbs the float error of the target fill rate complement, e.g. 10 * (1 - 0.8) = 1.9999999999999996
+	fillRateEpsilon = 1e-9
+)
+
+// FloorSuggester aggregates the bid landscape observed in auctions and derives candidate floors data per account from it
+type FloorSuggester interface {
+	RecordBids(account config.Account, request *openrtb_ext.RequestWrapper, seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, conversions currency.Conversions)
+	GetSuggestion(accountID string) (*openrtb_ext.PriceFloorData, bool)
+	Stop()
+}
+
+// bidSamples holds a reservoir sample of the top bid prices observed for a rule
+type bidSamples struct {
+	seen   int
+	prices []float64
+}
+
+// accountLandscape holds the top bid prices observed for an account, overall and per floor rule
+type accountLandscape struct {
+	all   bidSamples
+	rules map[string]*bidSamples
+}
+
+// landscapeShard holds the landscapes of the accounts hashed to it, so concurrent auctions of different accounts
+// rarely contend on the same lock
+type landscapeShard struct {
+	lock       sync.Mutex
+	landscapes map[string]*accountLandscape
+}
+
+type PriceFloorSuggester struct {
+	config      config.PriceFloorSuggestions
+	schema      openrtb_ext.PriceFloorSchema
+	shards      [landscapeShardCount]landscapeShard
+	accounts    atomic.Int64
+	suggestLock sync.RWMutex
+	suggestions map[string]*openrtb_ext.PriceFloorData
+	done        chan struct{}
+	time        timeutil.Time
+	randFunc    func(int) int
+}
+
+// NewPriceFloorSuggester starts a suggester which regenerates floors data for every account with enough observed bids
+// each configured period. It returns nil when suggestions are disabled.
+func NewPriceFloorSuggester(config config.PriceFloorSuggestions) FloorSuggester {
+	if !config.Enabled {
+		return nil
+	}
+
+	suggester := newPriceFloorSuggester(config)
+	suggester.time = &timeutil.RealTime{}
+	suggester.randFunc = rand.Intn
+
+	go suggester.run()
+
+	return suggester
+}
+
+func newPriceFloorSuggester(config config.PriceFloorSuggestions) *PriceFloorSuggester {
+	suggester := &PriceFloorSuggester{
+		config:      config,
+		schema:      openrtb_ext.PriceFloorSchema{Fields: config.Fields, Delimiter: defaultDelimiter},
+		suggestions: make(map[string]*openrtb_ext.PriceFloorData),
+		done:        make(chan struct{}),
+	}
+	for i := range suggester.shards {
+		suggester.shards[i].landscapes = make(map[string]*accountLandscape)
+	}
+	return suggester
+}
+
+func (s *PriceFloorSuggester) shard(accountID string) *landscapeShard {
+	h := fnv.New32a()
+	h.Write([]byte(accountID))
+	return &s.shards[h.Sum32()%landscapeShardCount]
+}
+
+// RecordBids records the highest bid price of every imp, converted to the suggestions currency, under the floor rule
+// matching the imp for the given account, time based dimensions are derived in the account floors timezone
+func (s *PriceFloorSuggester) RecordBids(account config.Account, request *openrtb_ext.RequestWrapper, seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, conversions currency.Conversions) {
+	if s == nil || request == nil {
+		return
+	}
+
+	topBids := make(map[string]float64)
+	for _, seatBid := range seatBids {
+		if seatBid == nil {
+			continue
+		}
+		bidCur := seatBid.Currency
+		if bidCur == "" {
+			bidCur = defaultCurrency
+		}
+		rate := 1.0
+		if bidCur != s.config.Currency {
+			var err error
+			if rate, err = conversions.GetRate(bidCur, s.config.Currency); err != nil {
+				continue
+			}
+		}
+		for _, bid := range seatBid.Bids {
+			if bid == nil || bid.Bid == nil || bid.Bid.Price <= 0 {
+				continue
+			}
+			if price := bid.Bid.Price * rate; price > topBids[bid.Bid.ImpID] {
+				topBids[bid.Bid.ImpID] = price
+			}
+		}
+	}
+	if len(topBids) == 0 {
+		return
+	}
+
+	keyCtx := ruleKeyContext{requestTime: s.time.Now().In(getTimezoneLocation(account.PriceFloors.Timezone))}
+	ruleKeys := make(map[string]string, len(topBids))
+	for _, imp := range request.GetImp() {
+		if _, ok := topBids[imp.ID]; ok {
+			ruleKeys[imp.ID] = strings.ToLower(strings.Join(createRuleKey(s.schema, request, imp, keyCtx), s.schema.Delimiter))
+		}
+	}
+
+	shard := s.shard(account.ID)
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
+
+	landscape, ok := shard.landscapes[account.ID]
+	if !ok {
+		if s.accounts.Add(1) > int64(s.config.MaxAccounts) {
+			s.accounts.Add(-1)
+			return
+		}
+		landscape = &accountLandscape{rules: make(map[string]*bidSamples)}
+		shard.landscapes[account.ID] = landscape
+	}
+	for impID, price := range topBids {
+		ruleKey, ok := ruleKeys[impID]
+		if !ok {
+			continue
+		}
+		s.addSample(&landscape.all, price)
+
+		samples, ok := landscape.rules[ruleKey]
+		if !ok {
+			if len(landscape.rules) >= s.config.MaxRules {
+				continue
+			}
+			samples = &bidSamples{}
+			landscape.rules[ruleKey] = samples
+		}
+		s.addSample(samples, price)
+	}
+}
+
+// GetSuggestion returns the latest floors data suggested for the account
+func (s *PriceFloorSuggester) GetSuggestion(accountID string) (*openrtb_ext.PriceFloorData, bool) {
+	if s == nil {
+		return nil, false
+	}
+
+	s.suggestLock.RLock()
+	defer s.suggestLock.RUnlock()
+
+	data, ok := s.suggestions[accountID]
+	return data, ok
+}
+
+// Stop terminates price floor suggester
+func (s *PriceFloorSuggester) Stop() {
+	if s == nil {
+		return
+	}
+
+	close(s.done)
+}
+
+func (s *PriceFloorSuggester) run() {
+	ticker := time.NewTicker(time.Duration(s.config.PeriodSec) * time.Second)
+
+	for {
+		select {
+		case <-ticker.C:
+			s.generate()
+		case <-s.done:
+			ticker.Stop()
+			logger.Infof("Price Floor suggester terminated")
+			return
+		}
+	}
+}
+
+// generate replaces the suggestion of every account with enough observed bids and resets the observed landscape
+func (s *PriceFloorSuggester) generate() {
+	landscapes := make(map[string]*accountLandscape)
+	for i := range s.shards {
+		shard := &s.shards[i]
+		shard.lock.Lock()
+		for accountID, landscape := range shard.landscapes {
+			landscapes[accountID] = landscape
+		}
+		s.accounts.Add(-int64(len(shard.landscapes)))
+		shard.landscapes = make(map[string]*accountLandscape)
+		shard.lock.Unlock()
+	}
+
+	modelTimestamp := int(s.time.Now().Unix())
+	for accountID, landscape := range landscapes {
+		data := s.buildFloorData(landscape, modelTimestamp)
+		if data == nil {
+			continue
+		}
+
+		s.suggestLock.Lock()
+		s.suggestions[accountID] = data
+		s.suggestLock.Unlock()
+
+		if s.config.OutputDir != "" {
+			if err := writeFloorData(s.config.OutputDir, accountID, data); err != nil {
+				logger.Errorf("Error writing suggested floors for account %s: %v", accountID, err)
+			}
+		}
+	}
+}
+
+// buildFloorData derives floors data in fetch format from the landscape, only rules with at least the configured
+// minimum number of samples are suggested
+func (s *PriceFloorSuggester) buildFloorData(landscape *accountLandscape, modelTimestamp int) *openrtb_ext.PriceFloorData {
+	values := make(map[string]float64)
+	for ruleKey, samples := range landscape.rules {
+		if len(samples.prices) < s.config.MinSamples {
+			continue
+		}
+		if value := s.suggestFloor(samples.prices); value > 0 {
+			values[ruleKey] = value
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	modelGroup := openrtb_ext.PriceFloorModelGroup{
+		Currency:     s.config.Currency,
+		ModelVersion: fmt.Sprintf("%s-%d", suggestionFloorProvider, modelTimestamp),
+		Schema:       s.schema,
+		Values:       values,
+	}
+	if len(landscape.all.prices) >= s.config.MinSamples {
+		modelGroup.Default = s.suggestFloor(landscape.all.prices)
+	}
+
+	return &openrtb_ext.PriceFloorData{
+		Currency:       s.config.Currency,
+		ModelTimestamp: modelTimestamp,
+		ModelGroups:    []openrtb_ext.PriceFloorModelGroup{modelGroup},
+		FloorProvider:  suggestionFloorProvider,
+	}
+}
+
+// suggestFloor returns the floor for the observed prices according to the configured strategy
+func (s *PriceFloorSuggester) suggestFloor(prices []float64) float64 {
+	sorted := make([]float64, len(prices))
+	copy(sorted, prices)
+	sort.Float64s(sorted)
+
+	var index int
+	switch s.config.Strategy {
+	case config.FloorSuggestionStrategyTargetFill:
+		// highest price that at least the target share of observed prices is greater than or equal to
+		index = int(math.Floor(float64(len(sorted))*(1-s.config.TargetFillRate) + fillRateEpsilon))
+	default:
+		// nearest-rank percentile
+		index = int(math.Ceil(float64(len(sorted))*s.config.Percentile/100)) - 1
+	}
+	index = max(0, min(index, len(sorted)-1))
+
+	return roundToFourDecimals(sorted[index])
+}
+
+// addSample adds the price to the samples, once the configured maximum is reached prices are reservoir sampled
+func (s *PriceFloorSuggester) addSample(samples *bidSamples, price float64) {
+	samples.seen++
+	if len(samples.prices) < s.config.MaxSamples {
+		samples.prices = append(samples.prices, price)
+		return
+	}
+	if i := s.randFunc(samples.seen); i < len(samples.prices) {
+		samples.prices[i] = price
+	}
+}
+
+// writeFloorData atomically writes the floors data to <dir>/<account>.json
+func writeFloorData(dir, accountID string, data *openrtb_ext.PriceFloorData) error {
+	body, err := jsonutil.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	tmpFile, err := os.CreateTemp(dir, ".floors-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(body); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile.Name(), filepath.Join(dir, url.PathEscape(accountID)+".json"))
+}
@@ -0,0 +1,253 @@
+package floors
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+type fixedTime struct {
+	time time.Time
+}
+
+func (f fixedTime) Now() time.Time {
+	return f.time
+}
+
+func newTestSuggester(cfg config.PriceFloorSuggestions) *PriceFloorSuggester {
+	suggester := newPriceFloorSuggester(cfg)
+	suggester.time = fixedTime{time: time.Unix(1700000000, 0)}
+	suggester.randFunc = func(n int) int { return n - 1 }
+	return suggester
+}
+
+func trackedLandscapes(suggester *PriceFloorSuggester) map[string]*accountLandscape {
+	landscapes := make(map[string]*accountLandscape)
+	for i := range suggester.shards {
+		for accountID, landscape := range suggester.shards[i].landscapes {
+			landscapes[accountID] = landscape
+		}
+	}
+	return landscapes
+}
+
+func suggestionRequest(domain string) *openrtb_ext.RequestWrapper {
+	return &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+		Site: &openrtb2.Site{Domain: domain},
+		Imp: []openrtb2.Imp{
+			{ID: "imp1", Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}}}},
+			{ID: "imp2", Video: &openrtb2.Video{Placement: 1}},
+		},
+	}}
+}
+
+func suggestionSeatBids(cur string, prices map[string][]float64) map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid {
+	seatBid := &entities.PbsOrtbSeatBid{Currency: cur}
+	for impID, impPrices := range prices {
+		for _, price := range impPrices {
+			seatBid.Bids = append(seatBid.Bids, &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ImpID: impID, Price: price}})
+		}
+	}
+	return map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{"pubmatic": seatBid}
+}
+
+func TestPriceFloorSuggesterGenerate(t *testing.T) {
+	cfg := config.PriceFloorSuggestions{
+		Enabled:     true,
+		PeriodSec:   60,
+		Strategy:    config.FloorSuggestionStrategyPercentile,
+		Percentile:  50,
+		Fields:      []string{MediaType, Size, Domain},
+		Currency:    "USD",
+		MinSamples:  3,
+		MaxSamples:  10,
+		MaxRules:    10,
+		MaxAccounts: 10,
+	}
+	suggester := newTestSuggester(cfg)
+
+	suggester.RecordBids(config.Account{ID: "acc1"}, suggestionRequest("Example.com"), suggestionSeatBids("USD", map[string][]float64{"imp1": {1, 3}, "imp2": {5}}), convert{})
+	suggester.RecordBids(config.Account{ID: "acc1"}, suggestionRequest("example.com"), suggestionSeatBids("USD", map[string][]float64{"imp1": {2}}), convert{})
+	suggester.RecordBids(config.Account{ID: "acc1"}, suggestionRequest("example.com"), suggestionSeatBids("INR", map[string][]float64{"imp1": {100}}), convert{})
+	suggester.RecordBids(config.Account{ID: "acc1"}, suggestionRequest("example.com"), suggestionSeatBids("EUR", map[string][]float64{"imp1": {100}}), convert{})
+	suggester.RecordBids(config.Account{ID: "acc2"}, suggestionRequest("example.com"), suggestionSeatBids("USD", map[string][]float64{"imp1": {4}}), convert{})
+
+	suggester.generate()
+
+	expected := &openrtb_ext.PriceFloorData{
+		Currency:       "USD",
+		ModelTimestamp: 1700000000,
+		FloorProvider:  "pbs-suggestions",
+		ModelGroups: []openrtb_ext.PriceFloorModelGroup{{
+			Currency:     "USD",
+			ModelVersion: "pbs-suggestions-1700000000",
+			Schema:       openrtb_ext.PriceFloorSchema{Fields: []string{MediaType, Size, Domain}, Delimiter: "|"},
+			Values:       map[string]float64{"banner|300x250|example.com": 2},
+			Default:      2,
+		}},
+	}
+	data, found := suggester.GetSuggestion("acc1")
+	assert.True(t, found)
+	assert.Equal(t, expected, data)
+
+	_, found = suggester.GetSuggestion("acc2")
+	assert.False(t, found, "account below min samples must not get a suggestion")
+
+	assert.Empty(t, trackedLandscapes(suggester), "landscape must be reset after generation")
+	assert.Equal(t, int64(0), suggester.accounts.Load(), "tracked accounts must be reset after generation")
+
+	suggester.generate()
+	data, found = suggester.GetSuggestion("acc1")
+	assert.True(t, found, "previous suggestion must be kept when no new one could be derived")
+	assert.Equal(t, expected, data)
+}
+
+func TestPriceFloorSuggesterMaxRules(t *testing.T) {
+	cfg := config.PriceFloorSuggestions{
+		Fields:      []string{Domain},
+		Currency:    "USD",
+		MaxSamples:  10,
+		MaxRules:    1,
+		MaxAccounts: 1,
+	}
+	suggester := newTestSuggester(cfg)
+
+	suggester.RecordBids(config.Account{ID: "acc1"}, suggestionRequest("a.com"), suggestionSeatBids("USD", map[string][]float64{"imp1": {1}}), convert{})
+	suggester.RecordBids(config.Account{ID: "acc1"}, suggestionRequest("b.com"), suggestionSeatBids("USD", map[string][]float64{"imp1": {2}}), convert{})
+
+	landscape := trackedLandscapes(suggester)["acc1"]
+	assert.Equal(t, map[string]*bidSamples{"a.com": {seen: 1, prices: []float64{1}}}, landscape.rules)
+	assert.Equal(t, bidSamples{seen: 2, prices: []float64{1, 2}}, landscape.all)
+}
+
+func TestPriceFloorSuggesterTimezone(t *testing.T) {
+	cfg := config.PriceFloorSuggestions{
+		Fields:      []string{HourOfDay},
+		Currency:    "USD",
+		MaxSamples:  10,
+		MaxRules:    10,
+		MaxAccounts: 10,
+	}
+	suggester := newTestSuggester(cfg)
+
+	utcAccount := config.Account{ID: "acc1"}
+	istAccount := config.Account{ID: "acc2", PriceFloors: config.AccountPriceFloors{Timezone: "Asia/Kolkata"}}
+	suggester.RecordBids(utcAccount, suggestionRequest("a.com"), suggestionSeatBids("USD", map[string][]float64{"imp1": {1}}), convert{})
+	suggester.RecordBids(istAccount, suggestionRequest("a.com"), suggestionSeatBids("USD", map[string][]float64{"imp1": {1}}), convert{})
+
+	landscapes := trackedLandscapes(suggester)
+	assert.Contains(t, landscapes["acc1"].rules, "22", "hour of day must default to UTC")
+	assert.Contains(t, landscapes["acc2"].rules, "3", "hour of day must be derived in the account floors timezone")
+}
+
+func TestPriceFloorSuggesterMaxAccounts(t *testing.T) {
+	cfg := config.PriceFloorSuggestions{
+		Fields:      []string{Domain},
+		Currency:    "USD",
+		MaxSamples:  10,
+		MaxRules:    10,
+		MaxAccounts: 2,
+	}
+	suggester := newTestSuggester(cfg)
+
+	for _, accountID := range []string{"acc1", "acc2", "acc3", "acc1"} {
+		suggester.RecordBids(config.Account{ID: accountID}, suggestionRequest("a.com"), suggestionSeatBids("USD", map[string][]float64{"imp1": {1}}), convert{})
+	}
+
+	landscapes := trackedLandscapes(suggester)
+	assert.Len(t, landscapes, 2)
+	assert.Contains(t, landscapes, "acc1")
+	assert.Contains(t, landscapes, "acc2")
+	assert.Equal(t, 2, landscapes["acc1"].all.seen, "already tracked account must keep recording once the limit is reached")
+
+	suggester.generate()
+	suggester.RecordBids(config.Account{ID: "acc3"}, suggestionRequest("a.com"), suggestionSeatBids("USD", map[string][]float64{"imp1": {1}}), convert{})
+	assert.Contains(t, trackedLandscapes(suggester), "acc3", "limit must be reset after generation")
+}
+
+func TestSuggestFloor(t *testing.T) {
+	prices := []float64{5, 1, 4, 2, 3, 6, 8, 7, 10, 9}
+
+	tests := []struct {
+		name     string
+		cfg      config.PriceFloorSuggestions
+		expected float64
+	}{
+		{
+			name:     "percentile_median",
+			cfg:      config.PriceFloorSuggestions{Strategy: config.FloorSuggestionStrategyPercentile, Percentile: 50},
+			expected: 5,
+		},
+		{
+			name:     "percentile_zero_is_min",
+			cfg:      config.PriceFloorSuggestions{Strategy: config.FloorSuggestionStrategyPercentile, Percentile: 0},
+			expected: 1,
+		},
+		{
+			name:     "percentile_hundred_is_max",
+			cfg:      config.PriceFloorSuggestions{Strategy: config.FloorSuggestionStrategyPercentile, Percentile: 100},
+			expected: 10,
+		},
+		{
+			name:     "target_fill_eighty_percent",
+			cfg:      config.PriceFloorSuggestions{Strategy: config.FloorSuggestionStrategyTargetFill, TargetFillRate: 0.8},
+			expected: 3,
+		},
+		{
+			name:     "target_fill_full",
+			cfg:      config.PriceFloorSuggestions{Strategy: config.FloorSuggestionStrategyTargetFill, TargetFillRate: 1},
+			expected: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suggester := newTestSuggester(tt.cfg)
+			assert.Equal(t, tt.expected, suggester.suggestFloor(prices))
+		})
+	}
+	assert.Equal(t, []float64{5, 1, 4, 2, 3, 6, 8, 7, 10, 9}, prices, "observed prices must not be reordered")
+}
+
+func TestAddSampleReservoir(t *testing.T) {
+	suggester := newTestSuggester(config.PriceFloorSuggestions{MaxSamples: 2})
+	replaceIndex := 0
+	suggester.randFunc = func(int) int { return replaceIndex }
+
+	samples := &bidSamples{}
+	suggester.addSample(samples, 1)
+	suggester.addSample(samples, 2)
+	suggester.addSample(samples, 3)
+	assert.Equal(t, &bidSamples{seen: 3, prices: []float64{3, 2}}, samples)
+
+	replaceIndex = 5
+	suggester.addSample(samples, 4)
+	assert.Equal(t, &bidSamples{seen: 4, prices: []float64{3, 2}}, samples)
+}
+
+func TestWriteFloorData(t *testing.T) {
+	dir := t.TempDir()
+	data := &openrtb_ext.PriceFloorData{Currency: "USD", FloorProvider: "pbs-suggestions"}
+
+	err := writeFloorData(dir, "pub/1", data)
+	assert.NoError(t, err)
+
+	body, err := os.ReadFile(filepath.Join(dir, "pub%2F1.json"))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"currency":"USD","floorprovider":"pbs-suggestions"}`, string(body))
+
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary file must not be left behind")
+}
+
+func TestNewPriceFloorSuggesterDisabled(t *testing.T) {
+	suggester := NewPriceFloorSuggester(config.PriceFloorSuggestions{Enabled: false})
+	assert.True(t, suggester == nil, "disabled suggester must be a nil interface")
+}
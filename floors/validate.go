@@ -2,24 +2,31 @@ package floors
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"golang.org/x/text/currency"
 )
 
 var validSchemaDimensions = map[string]struct{}{
-	SiteDomain: {},
-	PubDomain:  {},
-	Domain:     {},
-	Bundle:     {},
-	Channel:    {},
-	MediaType:  {},
-	Size:       {},
-	GptSlot:    {},
-	AdUnitCode: {},
-	Country:    {},
-	DeviceType: {},
+	SiteDomain:   {},
+	PubDomain:    {},
+	Domain:       {},
+	Bundle:       {},
+	Channel:      {},
+	MediaType:    {},
+	Size:         {},
+	GptSlot:      {},
+	AdUnitCode:   {},
+	Country:      {},
+	DeviceType:   {},
+	ContentGenre: {},
+	LiveStream:   {},
+	UserSegment:  {},
+	HourOfDay:    {},
+	DSARequired:  {},
 }
 
 // validateSchemaDimensions validates schema dimesions given in floors JSON
@@ -44,6 +51,11 @@ func validateFloorRulesAndLowerValidRuleKey(schema openrtb_ext.PriceFloorSchema,
 			delete(ruleValues, key)
 			continue
 		}
+		if err := validateRuleKeyValues(schema.Fields, parsedKey); err != nil {
+			errs = append(errs, fmt.Errorf("Invalid Floor Rule = '%s' : %v", key, err))
+			delete(ruleValues, key)
+			continue
+		}
 		lowerKey := strings.ToLower(key)
 		if strings.Compare(key, lowerKey) != 0 {
 			delete(ruleValues, key)
@@ -53,6 +65,63 @@ func validateFloorRulesAndLowerValidRuleKey(schema openrtb_ext.PriceFloorSchema,
 	return errs
 }
 
+// validateRuleKeyValues validates the values of a rule key against the limits of the schema dimensions having a bounded set of values
+func validateRuleKeyValues(fields []string, values []string) error {
+	for i, field := range fields {
+		value := values[i]
+		if value == catchAll {
+			continue
+		}
+		switch field {
+		case LiveStream, DSARequired:
+			if value != "0" && value != "1" {
+				return fmt.Errorf("value '%s' for schema field '%s' should be 0 or 1", value, field)
+			}
+		case HourOfDay:
+			hour, err := strconv.Atoi(value)
+			if err != nil || hour < 0 || hour > maxHourOfDay || strconv.Itoa(hour) != value {
+				return fmt.Errorf("value '%s' for schema field '%s' should be between 0 and %d", value, field, maxHourOfDay)
+			}
+		case ContentGenre, UserSegment:
+			if len(value) > maxSchemaValLen {
+				return fmt.Errorf("value for schema field '%s' should not be longer than %d characters", field, maxSchemaValLen)
+			}
+		}
+	}
+	return nil
+}
+
+// validateRuleCurrenciesAndLowerRuleKey validates the currencies of the floor rules having their own currency.
+// Currencies of unknown rules or with invalid currency codes are dropped and the rule keys are lower cased
+// to match the keys of the floor rules
+func validateRuleCurrenciesAndLowerRuleKey(ruleValues map[string]float64, ruleCurrencies map[string]string) []error {
+	var errs []error
+	for key, cur := range ruleCurrencies {
+		lowerKey := strings.ToLower(key)
+		if !isValidCurrency(cur) {
+			errs = append(errs, fmt.Errorf("Invalid currency = '%s' for Floor Rule = '%s'", cur, key))
+			delete(ruleCurrencies, key)
+			delete(ruleValues, lowerKey)
+			continue
+		}
+		if _, ok := ruleValues[lowerKey]; !ok {
+			delete(ruleCurrencies, key)
+			continue
+		}
+		if strings.Compare(key, lowerKey) != 0 {
+			delete(ruleCurrencies, key)
+			ruleCurrencies[lowerKey] = cur
+		}
+	}
+	return errs
+}
+
+// isValidCurrency checks the currency is a recognized ISO 4217 currency code
+func isValidCurrency(cur string) bool {
+	_, err := currency.ParseISO(cur)
+	return err == nil
+}
+
 // validateFloorParams validates SchemaVersion, SkipRate and FloorMin
 func validateFloorParams(extFloorRules *openrtb_ext.PriceFloorRules) error {
 	if extFloorRules.Data != nil && extFloorRules.Data.FloorsSchemaVersion != 0 && extFloorRules.Data.FloorsSchemaVersion != 2 {
@@ -3,6 +3,7 @@ package floors
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/prebid/prebid-server/v3/config"
@@ -394,6 +395,28 @@ func TestValidateFloorRulesAndLowerValidRuleKey(t *testing.T) {
 				"*|*|*":                          16.01,
 			},
 		},
+		{
+			name: "Invalid values for bounded schema fields",
+			floorExt: &openrtb_ext.PriceFloorRules{Data: &openrtb_ext.PriceFloorData{
+				ModelGroups: []openrtb_ext.PriceFloorModelGroup{{
+					ModelVersion: "Version 1",
+					Schema:       openrtb_ext.PriceFloorSchema{Fields: []string{"liveStream", "hourOfDay", "dsaRequired", "contentGenre"}, Delimiter: "|"},
+					Values: map[string]float64{
+						"1|21|1|Sports": 1.01,
+						"*|*|*|*":       2.01,
+						"2|21|1|sports": 3.01,
+					},
+					Default: 0.01,
+				}},
+			}},
+			Err: []error{
+				errors.New("Invalid Floor Rule = '2|21|1|sports' : value '2' for schema field 'liveStream' should be 0 or 1"),
+			},
+			expctedFloor: map[string]float64{
+				"1|21|1|sports": 1.01,
+				"*|*|*|*":       2.01,
+			},
+		},
 	}
 
 	for _, tc := range tt {
@@ -405,6 +428,55 @@ func TestValidateFloorRulesAndLowerValidRuleKey(t *testing.T) {
 	}
 }
 
+func TestValidateRuleKeyValues(t *testing.T) {
+	fields := []string{"liveStream", "hourOfDay", "dsaRequired", "contentGenre", "userSegment"}
+	tests := []struct {
+		name   string
+		values []string
+		err    error
+	}{
+		{
+			name:   "valid_values",
+			values: []string{"1", "23", "0", "sports", "seg1"},
+		},
+		{
+			name:   "catch_all_values",
+			values: []string{"*", "*", "*", "*", "*"},
+		},
+		{
+			name:   "invalid_live_stream",
+			values: []string{"2", "0", "0", "sports", "seg1"},
+			err:    errors.New("value '2' for schema field 'liveStream' should be 0 or 1"),
+		},
+		{
+			name:   "invalid_hour_of_day",
+			values: []string{"1", "24", "0", "sports", "seg1"},
+			err:    errors.New("value '24' for schema field 'hourOfDay' should be between 0 and 23"),
+		},
+		{
+			name:   "non_canonical_hour_of_day",
+			values: []string{"1", "07", "0", "sports", "seg1"},
+			err:    errors.New("value '07' for schema field 'hourOfDay' should be between 0 and 23"),
+		},
+		{
+			name:   "invalid_dsa_required",
+			values: []string{"1", "0", "yes", "sports", "seg1"},
+			err:    errors.New("value 'yes' for schema field 'dsaRequired' should be 0 or 1"),
+		},
+		{
+			name:   "too_long_user_segment",
+			values: []string{"1", "0", "0", "sports", strings.Repeat("a", 101)},
+			err:    errors.New("value for schema field 'userSegment' should not be longer than 100 characters"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateRuleKeyValues(fields, tt.values)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
 func TestValidateSchemaDimensions(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -415,6 +487,10 @@ func TestValidateSchemaDimensions(t *testing.T) {
 			name:   "valid_fields",
 			fields: []string{"deviceType", "size"},
 		},
+		{
+			name:   "valid_extended_fields",
+			fields: []string{"contentGenre", "liveStream", "userSegment", "hourOfDay", "dsaRequired"},
+		},
 		{
 			name:   "invalid_fields",
 			fields: []string{"deviceType", "dealType"},
@@ -428,3 +504,51 @@ func TestValidateSchemaDimensions(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateRuleCurrenciesAndLowerRuleKey(t *testing.T) {
+	tests := []struct {
+		name               string
+		ruleValues         map[string]float64
+		ruleCurrencies     map[string]string
+		expectedErrs       []error
+		expectedValues     map[string]float64
+		expectedCurrencies map[string]string
+	}{
+		{
+			name:               "no_rule_currencies",
+			ruleValues:         map[string]float64{"banner|*": 1},
+			expectedValues:     map[string]float64{"banner|*": 1},
+			expectedCurrencies: nil,
+		},
+		{
+			name:               "rule_keys_lowered",
+			ruleValues:         map[string]float64{"banner|www.website.com": 1, "*|*": 2},
+			ruleCurrencies:     map[string]string{"BANNER|WWW.WEBSITE.COM": "EUR", "*|*": "USD"},
+			expectedValues:     map[string]float64{"banner|www.website.com": 1, "*|*": 2},
+			expectedCurrencies: map[string]string{"banner|www.website.com": "EUR", "*|*": "USD"},
+		},
+		{
+			name:               "currency_for_unknown_rule_dropped",
+			ruleValues:         map[string]float64{"*|*": 2},
+			ruleCurrencies:     map[string]string{"video|*": "EUR"},
+			expectedValues:     map[string]float64{"*|*": 2},
+			expectedCurrencies: map[string]string{},
+		},
+		{
+			name:               "invalid_currency_drops_rule",
+			ruleValues:         map[string]float64{"banner|*": 1, "*|*": 2},
+			ruleCurrencies:     map[string]string{"banner|*": "ABC"},
+			expectedErrs:       []error{errors.New("Invalid currency = 'ABC' for Floor Rule = 'banner|*'")},
+			expectedValues:     map[string]float64{"*|*": 2},
+			expectedCurrencies: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateRuleCurrenciesAndLowerRuleKey(tt.ruleValues, tt.ruleCurrencies)
+			assert.Equal(t, tt.expectedErrs, errs)
+			assert.Equal(t, tt.expectedValues, tt.ruleValues)
+			assert.Equal(t, tt.expectedCurrencies, tt.ruleCurrencies)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package gdpr
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/prebid/go-gdpr/vendorlist2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/logger"
+)
+
+// The disk cache and the snapshot store the vendor lists with the layout of the vendor list archives:
+// <dir>/v<specVersion>/vendor-list-v<listVersion>.json
+
+// loadVendorListDiskCache seeds the cache directory from the snapshot and saves the vendor lists it holds to the cache.
+// It returns the function persisting fetched vendor lists, which is nil when no cache directory is configured.
+func loadVendorListDiskCache(cfg config.GDPRVendorListCache, saver saveVendors) func(specVersion, listVersion uint16, body []byte) {
+	if cfg.Dir == "" {
+		if cfg.SnapshotDir != "" {
+			loadVendorListDir(cfg.SnapshotDir, saver)
+		}
+		return nil
+	}
+
+	if cfg.SnapshotDir != "" {
+		if err := seedVendorListDir(cfg.SnapshotDir, cfg.Dir); err != nil {
+			logger.Errorf("Error seeding gdpr vendor list cache %s from snapshot %s: %v", cfg.Dir, cfg.SnapshotDir, err)
+		}
+	}
+	loadVendorListDir(cfg.Dir, saver)
+
+	dir := cfg.Dir
+	return func(specVersion, listVersion uint16, body []byte) {
+		if err := writeVendorListFile(vendorListFilePath(dir, specVersion, listVersion), body); err != nil {
+			logger.Errorf("Error persisting gdpr vendor list spec version %d list version %d: %v", specVersion, listVersion, err)
+		}
+	}
+}
+
+func vendorListFilePath(dir string, specVersion, listVersion uint16) string {
+	return filepath.Join(dir, "v"+strconv.Itoa(int(specVersion)), "vendor-list-v"+strconv.Itoa(int(listVersion))+".json")
+}
+
+func vendorListFiles(dir string) []string {
+	// the pattern is well formed so Glob cannot fail
+	files, _ := filepath.Glob(filepath.Join(dir, "v*", "vendor-list-v*.json"))
+	return files
+}
+
+// loadVendorListDir parses the vendor lists stored in dir and saves them to the cache, malformed files are skipped
+func loadVendorListDir(dir string, saver saveVendors) int {
+	loaded := 0
+	for _, file := range vendorListFiles(dir) {
+		body, err := os.ReadFile(file)
+		if err != nil {
+			logger.Errorf("Error reading gdpr vendor list %s: %v", file, err)
+			continue
+		}
+
+		list, err := vendorlist2.ParseEagerly(body)
+		if err != nil {
+			logger.Errorf("Malformed gdpr vendor list %s: %v", file, err)
+			continue
+		}
+		saver(list.SpecVersion(), list.Version(), list)
+		loaded++
+	}
+
+	logger.Infof("Loaded %d gdpr vendor lists from %s", loaded, dir)
+	return loaded
+}
+
+// seedVendorListDir copies the vendor lists of the snapshot missing from the cache directory
+func seedVendorListDir(snapshotDir, dir string) error {
+	for _, file := range vendorListFiles(snapshotDir) {
+		rel, err := filepath.Rel(snapshotDir, file)
+		if err != nil {
+			return err
+		}
+
+		body, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if err := writeVendorListFile(filepath.Join(dir, rel), body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeVendorListFile atomically writes the vendor list unless the file already exists, a version of the vendor
+// list never changes once published
+func writeVendorListFile(path string, body []byte) error {
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	tmpFile, err := os.CreateTemp(dir, ".vendor-list-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(body); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile.Name(), path)
+}
@@ -0,0 +1,133 @@
+package gdpr
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestVendorList(t *testing.T, dir string, specVersion, listVersion uint16, body string) {
+	path := vendorListFilePath(dir, specVersion, listVersion)
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	assert.NoError(t, os.WriteFile(path, []byte(body), 0644))
+}
+
+func TestLoadVendorListDiskCache(t *testing.T) {
+	snapshotDir := t.TempDir()
+	cacheDir := t.TempDir()
+	writeTestVendorList(t, snapshotDir, 3, 1, vendorList1)
+	writeTestVendorList(t, snapshotDir, 3, 2, "malformed")
+	writeTestVendorList(t, cacheDir, 3, 2, vendorList2)
+
+	s := make(saver, 0, 2)
+	persist := loadVendorListDiskCache(config.GDPRVendorListCache{Dir: cacheDir, SnapshotDir: snapshotDir}, s.saveVendorLists)
+
+	assert.ElementsMatch(t, []versionInfo{{specVersion: 3, listVersion: 1}, {specVersion: 3, listVersion: 2}}, s)
+
+	seeded, err := os.ReadFile(vendorListFilePath(cacheDir, 3, 1))
+	assert.NoError(t, err)
+	assert.Equal(t, vendorList1, string(seeded), "snapshot vendor lists must be copied to the cache directory")
+
+	cached, err := os.ReadFile(vendorListFilePath(cacheDir, 3, 2))
+	assert.NoError(t, err)
+	assert.Equal(t, vendorList2, string(cached), "cached vendor lists must not be overwritten by the snapshot")
+
+	if assert.NotNil(t, persist) {
+		persist(2, 7, []byte(vendorList1))
+		persisted, err := os.ReadFile(vendorListFilePath(cacheDir, 2, 7))
+		assert.NoError(t, err)
+		assert.Equal(t, vendorList1, string(persisted))
+	}
+}
+
+func TestLoadVendorListDiskCacheSnapshotOnly(t *testing.T) {
+	snapshotDir := t.TempDir()
+	writeTestVendorList(t, snapshotDir, 3, 1, vendorList1)
+
+	s := make(saver, 0, 1)
+	persist := loadVendorListDiskCache(config.GDPRVendorListCache{SnapshotDir: snapshotDir}, s.saveVendorLists)
+
+	assert.Nil(t, persist, "vendor lists must not be persisted without a cache directory")
+	assert.Equal(t, saver{{specVersion: 3, listVersion: 1}}, s)
+}
+
+func TestLoadVendorListDiskCacheDisabled(t *testing.T) {
+	s := make(saver, 0)
+	persist := loadVendorListDiskCache(config.GDPRVendorListCache{}, s.saveVendorLists)
+
+	assert.Nil(t, persist)
+	assert.Empty(t, s)
+}
+
+// TestLoadVendorListDirDefaultSnapshot loads the snapshot directory of the default config, the image build downloads
+// the vendor lists into it before running the tests so a malformed snapshot fails the build.
+func TestLoadVendorListDirDefaultSnapshot(t *testing.T) {
+	dir := filepath.Join("..", "static", "vendorlist-snapshot")
+	info, err := os.Stat(dir)
+	if assert.NoError(t, err, "the default snapshot directory must exist") {
+		assert.True(t, info.IsDir())
+	}
+
+	s := make(saver, 0)
+	loaded := loadVendorListDir(dir, s.saveVendorLists)
+
+	assert.Equal(t, len(vendorListFiles(dir)), loaded, "every vendor list of the snapshot must be loaded")
+	assert.Len(t, s, loaded)
+}
+
+func TestWriteVendorListFile(t *testing.T) {
+	path := vendorListFilePath(t.TempDir(), 3, 4)
+
+	assert.NoError(t, writeVendorListFile(path, []byte(vendorList1)))
+	assert.NoError(t, writeVendorListFile(path, []byte(vendorList2)))
+
+	body, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, vendorList1, string(body), "published vendor list versions must not be rewritten")
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary file must not be left behind")
+}
+
+func TestFetcherDiskCache(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(mockServer(serverSettings{
+		vendorListLatestVersion: 2,
+		vendorLists: map[int]map[int]string{
+			3: {
+				2: vendorList2,
+			},
+		},
+	})))
+	defer server.Close()
+
+	cacheDir := t.TempDir()
+	// the server does not have the first list version, it can only be loaded from the cache directory
+	writeTestVendorList(t, cacheDir, 3, 1, vendorList1)
+
+	cfg := testConfig()
+	cfg.VendorListCache = config.GDPRVendorListCache{Dir: cacheDir}
+	m := &metrics.MetricsEngineMock{}
+	m.On("RecordGvlListRequest").Return()
+	fetcher := NewVendorListFetcher(context.Background(), cfg, server.Client(), m, testURLMaker(server))
+	defer func() { cachePersist = nil }()
+
+	for _, listVersion := range []uint16{1, 2} {
+		list, err := fetcher(context.Background(), 3, listVersion, m)
+		if assert.NoError(t, err) {
+			assert.Equal(t, listVersion, list.Version())
+		}
+	}
+	assert.Equal(t, map[uint16][]uint16{3: {1, 2}}, LoadedVendorListVersions())
+
+	persisted, err := os.ReadFile(vendorListFilePath(cacheDir, 3, 2))
+	assert.NoError(t, err)
+	assert.Equal(t, vendorList2, string(persisted), "fetched vendor lists must be persisted")
+}
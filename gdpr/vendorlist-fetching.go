@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
 	"strconv"
 	"sync"
 	"sync/atomic"
@@ -22,8 +23,14 @@ import (
 type saveVendors func(uint16, uint16, api.VendorList)
 type VendorListFetcher func(ctx context.Context, specVersion uint16, listVersion uint16, metricsEngine metrics.MetricsEngine) (vendorlist.VendorList, error)
 
+type loadVendors func(uint16, uint16) api.VendorList
+
 var cacheSave func(specVersion, listVersion uint16, list api.VendorList)
 var cacheLoad func(specVersion, listVersion uint16) api.VendorList
+var cacheVersions func() map[uint16][]uint16
+
+// cachePersist writes fetched vendor lists to the disk cache, it is nil when the disk cache is disabled
+var cachePersist func(specVersion, listVersion uint16, body []byte)
 
 // This file provides the vendorlist-fetching function for Prebid Server.
 //
@@ -32,11 +39,12 @@ var cacheLoad func(specVersion, listVersion uint16) api.VendorList
 // Nothing in this file is exported. Public APIs can be found in gdpr.go
 
 func NewVendorListFetcher(initCtx context.Context, cfg config.GDPR, client *http.Client, metricsEngine metrics.MetricsEngine, urlMaker func(uint16, uint16) string) VendorListFetcher {
-	cacheSave, cacheLoad = newVendorListCache()
+	cacheSave, cacheLoad, cacheVersions = newVendorListCache()
+	cachePersist = loadVendorListDiskCache(cfg.VendorListCache, cacheSave)
 
 	preloadContext, cancel := context.WithTimeout(initCtx, cfg.Timeouts.InitTimeout())
 	defer cancel()
-	preloadCache(preloadContext, client, urlMaker, cacheSave, metricsEngine)
+	preloadCache(preloadContext, client, urlMaker, cacheSave, cacheLoad, metricsEngine)
 
 	saveOneRateLimited := newOccasionalSaver(cfg.Timeouts.ActiveTimeout())
 	return func(ctx context.Context, specVersion, listVersion uint16, metricsEngine metrics.MetricsEngine) (vendorlist.VendorList, error) {
@@ -64,8 +72,17 @@ func makeVendorListNotFoundError(specVersion, listVersion uint16) error {
 	return fmt.Errorf("gdpr vendor list spec version %d list version %d does not exist, or has not been loaded yet. Try again in a few minutes", specVersion, listVersion)
 }
 
-// preloadCache saves all the known versions of the vendor list for future use.
-func preloadCache(ctx context.Context, client *http.Client, urlMaker func(uint16, uint16) string, saver saveVendors, metricsEngine metrics.MetricsEngine) {
+// LoadedVendorListVersions returns the list versions of the vendor lists loaded in the cache by spec version
+func LoadedVendorListVersions() map[uint16][]uint16 {
+	if cacheVersions == nil {
+		return nil
+	}
+	return cacheVersions()
+}
+
+// preloadCache saves all the known versions of the vendor list for future use. Versions already loaded from the
+// disk cache are not fetched again.
+func preloadCache(ctx context.Context, client *http.Client, urlMaker func(uint16, uint16) string, saver saveVendors, loader loadVendors, metricsEngine metrics.MetricsEngine) {
 	versions := [2]struct {
 		specVersion      uint16
 		firstListVersion uint16
@@ -83,6 +100,9 @@ func preloadCache(ctx context.Context, client *http.Client, urlMaker func(uint16
 		latestVersion := saveOne(ctx, client, urlMaker(v.specVersion, 0), saver, metricsEngine)
 
 		for i := v.firstListVersion; i < latestVersion; i++ {
+			if list := loader(v.specVersion, i); list != nil {
+				continue
+			}
 			saveOne(ctx, client, urlMaker(v.specVersion, i), saver, metricsEngine)
 		}
 	}
@@ -153,24 +173,43 @@ func saveOne(ctx context.Context, client *http.Client, url string, saver saveVen
 	}
 
 	saver(newList.SpecVersion(), newList.Version(), newList)
+	if cachePersist != nil {
+		cachePersist(newList.SpecVersion(), newList.Version(), respBody)
+	}
 	return newList.Version()
 }
 
-func newVendorListCache() (save func(specVersion, listVersion uint16, list api.VendorList), load func(specVersion, listVersion uint16) api.VendorList) {
+type vendorListKey struct {
+	specVersion uint16
+	listVersion uint16
+}
+
+func newVendorListCache() (save func(specVersion, listVersion uint16, list api.VendorList), load func(specVersion, listVersion uint16) api.VendorList, versions func() map[uint16][]uint16) {
 	cache := &sync.Map{}
 
 	save = func(specVersion uint16, listVersion uint16, list api.VendorList) {
-		key := fmt.Sprint(specVersion) + "-" + fmt.Sprint(listVersion)
-		cache.Store(key, list)
+		cache.Store(vendorListKey{specVersion: specVersion, listVersion: listVersion}, list)
 	}
 
 	load = func(specVersion, listVersion uint16) api.VendorList {
-		key := fmt.Sprint(specVersion) + "-" + fmt.Sprint(listVersion)
-		list, ok := cache.Load(key)
+		list, ok := cache.Load(vendorListKey{specVersion: specVersion, listVersion: listVersion})
 		if ok {
 			return list.(vendorlist.VendorList)
 		}
 		return nil
 	}
+
+	versions = func() map[uint16][]uint16 {
+		loaded := make(map[uint16][]uint16)
+		cache.Range(func(k, _ interface{}) bool {
+			key := k.(vendorListKey)
+			loaded[key.specVersion] = append(loaded[key.specVersion], key.listVersion)
+			return true
+		})
+		for _, listVersions := range loaded {
+			sort.Slice(listVersions, func(i, j int) bool { return listVersions[i] < listVersions[j] })
+		}
+		return loaded
+	}
 	return
 }
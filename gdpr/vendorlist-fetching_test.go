@@ -11,6 +11,7 @@ import (
 
 	"github.com/prebid/go-gdpr/api"
 	"github.com/prebid/go-gdpr/consentconstants"
+	"github.com/prebid/go-gdpr/vendorlist2"
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/metrics"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
@@ -264,7 +265,7 @@ func TestPreloadCache(t *testing.T) {
 	s := make(saver, 0, 5)
 	m := &metrics.MetricsEngineMock{}
 	m.On("RecordGvlListRequest").Times(5)
-	preloadCache(context.Background(), server.Client(), testURLMaker(server), s.saveVendorLists, m)
+	preloadCache(context.Background(), server.Client(), testURLMaker(server), s.saveVendorLists, noVendorLists, m)
 
 	expectedLoadedVersions := []versionInfo{
 		{specVersion: 2, listVersion: 2},
@@ -277,6 +278,57 @@ func TestPreloadCache(t *testing.T) {
 	assert.ElementsMatch(t, expectedLoadedVersions, s)
 }
 
+func TestPreloadCacheSkipsLoadedVersions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(mockServer(serverSettings{
+		vendorListLatestVersion: 3,
+		vendorLists: map[int]map[int]string{
+			3: {
+				1: MarshalVendorList(vendorList{GVLSpecificationVersion: 3, VendorListVersion: 1}),
+				2: MarshalVendorList(vendorList{GVLSpecificationVersion: 3, VendorListVersion: 2}),
+				3: MarshalVendorList(vendorList{GVLSpecificationVersion: 3, VendorListVersion: 3}),
+			},
+		},
+	})))
+	defer server.Close()
+
+	loaded := func(specVersion, listVersion uint16) api.VendorList {
+		if specVersion == 3 && listVersion == 1 {
+			return loadVendorList(t, vendorList1)
+		}
+		return nil
+	}
+
+	s := make(saver, 0, 2)
+	m := &metrics.MetricsEngineMock{}
+	m.On("RecordGvlListRequest").Times(3)
+	preloadCache(context.Background(), server.Client(), testURLMaker(server), s.saveVendorLists, loaded, m)
+
+	expectedLoadedVersions := []versionInfo{
+		{specVersion: 3, listVersion: 2},
+		{specVersion: 3, listVersion: 3},
+	}
+	assert.ElementsMatch(t, expectedLoadedVersions, s)
+}
+
+func TestLoadedVendorListVersions(t *testing.T) {
+	save, _, versions := newVendorListCache()
+	save(3, 2, loadVendorList(t, vendorList2))
+	save(2, 5, loadVendorList(t, vendorList1))
+	save(3, 1, loadVendorList(t, vendorList1))
+
+	assert.Equal(t, map[uint16][]uint16{2: {5}, 3: {1, 2}}, versions())
+}
+
+func noVendorLists(uint16, uint16) api.VendorList {
+	return nil
+}
+
+func loadVendorList(t *testing.T, body string) api.VendorList {
+	list, err := vendorlist2.ParseEagerly([]byte(body))
+	assert.NoError(t, err)
+	return list
+}
+
 var vendorList1 = MarshalVendorList(vendorList{
 	GVLSpecificationVersion: 3,
 	VendorListVersion:       1,
@@ -154,7 +154,7 @@ func Test_vendorListScheduler_runLoadCache(t *testing.T) {
 
 			tt.fields.scheduler.timeout = 2 * time.Minute
 			mockCacheSave := func(uint16, uint16, api.VendorList) {}
-			cacheSave, cacheLoad = newVendorListCache() // initialise global func variables
+			cacheSave, cacheLoad, cacheVersions = newVendorListCache() // initialise global func variables
 			metricsEngine := &metrics.MetricsEngineMock{}
 			metricsEngine.On("RecordGvlListRequest").Return()
 			tt.fields.scheduler.metricsEngine = metricsEngine
@@ -197,7 +197,7 @@ func Benchmark_vendorListScheduler_runLoadCache(b *testing.B) {
 	scheduler.timeout = 2 * time.Minute
 
 	for n := 0; n < b.N; n++ {
-		cacheSave, cacheLoad = newVendorListCache()
+		cacheSave, cacheLoad, cacheVersions = newVendorListCache()
 		scheduler.runLoadCache()
 	}
 
@@ -215,7 +215,7 @@ func Test_vendorListScheduler_cacheFuncs(t *testing.T) {
 	})))
 	defer server.Close()
 	config := testConfig()
-	cacheSave, cacheLoad = newVendorListCache()
+	cacheSave, cacheLoad, cacheVersions = newVendorListCache()
 	metricsEngine := &metrics.MetricsEngineMock{}
 	metricsEngine.On("RecordGvlListRequest").Return()
 	_ = NewVendorListFetcher(context.Background(), config, server.Client(), metricsEngine, testURLMaker(server))
@@ -74,7 +74,7 @@ func executeGroup[H any, P any](
 	for _, hook := range group.Hooks {
 		mCtx := executionCtx.getModuleContext(hook.Module)
 		mCtx.HookImplCode = hook.Code
-		newPayload := handleModuleActivities(hook.Code, executionCtx.activityControl, payload, executionCtx.account)
+		newPayload := handleModuleActivities(hook.Code, hook.Module, executionCtx.activityControl, payload, executionCtx.account)
 		wg.Add(1)
 		go func(hw hooks.HookWrapper[H], moduleCtx hookstage.ModuleInvocationContext) {
 			defer wg.Done()
@@ -329,22 +329,25 @@ func handleHookMutations[P any](
 	return payload
 }
 
-func handleModuleActivities[P any](hookCode string, activityControl privacy.ActivityControl, payload P, account *config.Account) P {
+func handleModuleActivities[P any](hookCode, moduleCode string, activityControl privacy.ActivityControl, payload P, account *config.Account) P {
 	payloadData, ok := any(&payload).(hookstage.RequestUpdater)
 	if !ok {
 		return payload
 	}
 
-	scopeGeneral := privacy.Component{Type: privacy.ComponentTypeGeneral, Name: hookCode}
+	// rules can target a single hook by its code or all hooks of a module by the module code
+	scopeGeneral := privacy.Component{Type: privacy.ComponentTypeGeneral, Name: hookCode, Module: moduleCode}
 	transmitUserFPDActivityAllowed := activityControl.Allow(privacy.ActivityTransmitUserFPD, scopeGeneral, privacy.ActivityRequest{})
 	transmitPreciseGeoActivityAllowed := activityControl.Allow(privacy.ActivityTransmitPreciseGeo, scopeGeneral, privacy.ActivityRequest{})
 
-	if transmitUserFPDActivityAllowed && transmitPreciseGeoActivityAllowed {
+	bidderReq := payloadData.GetBidderRequestPayload()
+	deniedIDs := activityControl.DeniedIDs(bidderReq, scopeGeneral, privacy.ActivityRequest{})
+
+	if transmitUserFPDActivityAllowed && transmitPreciseGeoActivityAllowed && !deniedIDs.Any() {
 		return payload
 	}
 
 	// changes need to be applied to new payload and leave original payload unchanged
-	bidderReq := payloadData.GetBidderRequestPayload()
 
 	bidderReqCopy := &openrtb_ext.RequestWrapper{
 		BidRequest: ortb.CloneBidRequestPartial(bidderReq.BidRequest),
@@ -365,6 +368,7 @@ func handleModuleActivities[P any](hookCode string, activityControl privacy.Acti
 
 		privacy.ScrubGeoAndDeviceIP(bidderReqCopy, ipConf)
 	}
+	privacy.ScrubDeniedIDs(bidderReqCopy, deniedIDs)
 
 	var newPayload = payload
 	var np = any(&newPayload).(hookstage.RequestUpdater)
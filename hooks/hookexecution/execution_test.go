@@ -93,7 +93,7 @@ func TestHandleModuleActivitiesBidderRequestPayload(t *testing.T) {
 			//check input payload didn't change
 			origInPayloadData := test.inPayloadData
 			activityControl := privacy.NewActivityControl(test.privacyConfig)
-			newPayload := handleModuleActivities(test.hookCode, activityControl, test.inPayloadData, nil)
+			newPayload := handleModuleActivities(test.hookCode, "", activityControl, test.inPayloadData, nil)
 			assert.Equal(t, test.expectedPayloadData.Request.BidRequest, newPayload.Request.BidRequest)
 			assert.Equal(t, origInPayloadData, test.inPayloadData)
 		})
@@ -177,7 +177,7 @@ func TestHandleModuleActivitiesProcessedAuctionRequestPayload(t *testing.T) {
 			origInPayloadData := test.inPayloadData
 			activityControl := privacy.NewActivityControl(test.privacyConfig)
 			account := &config.Account{Privacy: config.AccountPrivacy{IPv6Config: config.IPv6{AnonKeepBits: testIPv6ScrubBytes}}}
-			newPayload := handleModuleActivities(test.hookCode, activityControl, test.inPayloadData, account)
+			newPayload := handleModuleActivities(test.hookCode, "", activityControl, test.inPayloadData, account)
 			assert.Equal(t, test.expectedPayloadData.Request.BidRequest, newPayload.Request.BidRequest)
 			assert.Equal(t, origInPayloadData, test.inPayloadData)
 		})
@@ -227,9 +227,61 @@ func TestHandleModuleActivitiesNoBidderRequestPayload(t *testing.T) {
 			//check input payload didn't change
 			origInPayloadData := test.inPayloadData
 			activityControl := privacy.NewActivityControl(test.privacyConfig)
-			newPayload := handleModuleActivities(test.hookCode, activityControl, test.inPayloadData, &config.Account{})
+			newPayload := handleModuleActivities(test.hookCode, "", activityControl, test.inPayloadData, &config.Account{})
 			assert.Equal(t, test.expectedPayloadData, newPayload)
 			assert.Equal(t, origInPayloadData, test.inPayloadData)
 		})
 	}
 }
+
+func TestHandleModuleActivitiesModuleScope(t *testing.T) {
+	privacyConfig := &config.AccountPrivacy{
+		AllowActivities: &config.AllowActivities{
+			TransmitUserFPD: buildDefaultActivityConfig("scope3.rtd", false),
+			TransmitEids: config.Activity{
+				Rules: []config.ActivityRule{{
+					Condition: config.ActivityCondition{
+						ComponentName: []string{"fiftyonedegrees.devicedetection"},
+						EIDSource:     []string{"liveramp.com"},
+					},
+				}},
+			},
+			TransmitDeviceIds: buildDefaultActivityConfig("fiftyonedegrees.devicedetection", false),
+		},
+	}
+	activityControl := privacy.NewActivityControl(privacyConfig)
+
+	newPayload := func() hookstage.BidderRequestPayload {
+		return hookstage.BidderRequestPayload{
+			Request: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+				Device: &openrtb2.Device{IFA: "test_ifa"},
+				User: &openrtb2.User{
+					ID:   "test_user_id",
+					EIDs: []openrtb2.EID{{Source: "liveramp.com"}, {Source: "id5-sync.com"}},
+				},
+			}},
+		}
+	}
+
+	t.Run("module_rule_applies_to_its_hooks", func(t *testing.T) {
+		payload := handleModuleActivities("scope3-rtd-hook", "scope3.rtd", activityControl, newPayload(), nil)
+		assert.Empty(t, payload.Request.User.ID)
+		assert.Nil(t, payload.Request.User.EIDs)
+	})
+
+	t.Run("device_ids_and_eid_sources_denied", func(t *testing.T) {
+		inPayload := newPayload()
+		payload := handleModuleActivities("51d-hook", "fiftyonedegrees.devicedetection", activityControl, inPayload, nil)
+		assert.Equal(t, "test_user_id", payload.Request.User.ID)
+		assert.Empty(t, payload.Request.Device.IFA)
+		assert.Equal(t, []openrtb2.EID{{Source: "id5-sync.com"}}, payload.Request.User.EIDs)
+		assert.Equal(t, "test_ifa", inPayload.Request.Device.IFA, "input payload must not change")
+		assert.Len(t, inPayload.Request.User.EIDs, 2, "input payload must not change")
+	})
+
+	t.Run("other_module_not_scrubbed", func(t *testing.T) {
+		inPayload := newPayload()
+		payload := handleModuleActivities("hook", "prebid.ortb2blocking", activityControl, inPayload, nil)
+		assert.Equal(t, inPayload, payload)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/privacy"
 )
 
 // HookResult represents the result of execution the concrete hook instance.
@@ -36,6 +37,8 @@ type ModuleInvocationContext struct {
 	ModuleContext ModuleContext
 	// HookImplCode is the hook_impl_code for a module instance to differentiate between multiple hooks
 	HookImplCode string
+	// ActivityControl holds the privacy activity controls of the account, built once per request
+	ActivityControl privacy.ActivityControl
 }
 
 // ModuleContext holds arbitrary data passed between module hooks at different stages.
@@ -0,0 +1,66 @@
+package macros
+
+import (
+	"strings"
+)
+
+const (
+	auctionMacroStartDelimiter = "${"
+	auctionMacroEndDelimiter   = "}"
+)
+
+type auctionMacroReplacer struct{}
+
+// NewAuctionMacroReplacer returns a replacer for the standard OpenRTB auction macros in ${MACRO} format.
+// Unlike the string index based replacer, macros which are not auction macros are preserved as is and
+// templates are not cached since the input is usually bid specific markup.
+func NewAuctionMacroReplacer() Replacer {
+	return &auctionMacroReplacer{}
+}
+
+// constructAuctionMacroTemplate finds the index bounds of all macros in an input string where macro format is ${data}.
+// Start index of the macro points to the first character after the starting delimiter and end index points to the
+// last character before the ending delimiter.
+func constructAuctionMacroTemplate(input string) urlMetaTemplate {
+	tmplt := urlMetaTemplate{
+		startingIndices: []int{},
+		endingIndices:   []int{},
+	}
+	currentIndex := 0
+	for currentIndex < len(input) {
+		startIndex := strings.Index(input[currentIndex:], auctionMacroStartDelimiter)
+		if startIndex == -1 {
+			break
+		}
+		startIndex = currentIndex + startIndex + len(auctionMacroStartDelimiter)
+		endIndex := strings.Index(input[startIndex:], auctionMacroEndDelimiter)
+		if endIndex == -1 {
+			break
+		}
+		endIndex = startIndex + endIndex - 1
+		tmplt.startingIndices = append(tmplt.startingIndices, startIndex)
+		tmplt.endingIndices = append(tmplt.endingIndices, endIndex)
+		currentIndex = endIndex + 1 + len(auctionMacroEndDelimiter)
+	}
+	return tmplt
+}
+
+// Replace function replaces the auction macros in a given string with the data from macroProvider.
+// Values are substituted unescaped as they may be placed in markup as well as in urls.
+func (s *auctionMacroReplacer) Replace(result *strings.Builder, input string, macroProvider *MacroProvider) {
+	template := constructAuctionMacroTemplate(input)
+	currentIndex := 0
+	startDelimLen := len(auctionMacroStartDelimiter)
+	endDelimLen := len(auctionMacroEndDelimiter)
+	for i, index := range template.startingIndices {
+		macro := input[index : template.endingIndices[i]+1]
+		value, ok := macroProvider.getAuctionMacro(macro)
+		if !ok {
+			continue
+		}
+		result.WriteString(input[currentIndex : index-startDelimLen])
+		result.WriteString(value)
+		currentIndex = index + len(macro) + endDelimLen
+	}
+	result.WriteString(input[currentIndex:])
+}
@@ -0,0 +1,120 @@
+package macros
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuctionMacroReplace(t *testing.T) {
+	auctionBid := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "bidId123", ImpID: "imp1", AdID: "ad1"}}
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "all_auction_macros",
+			input: "http://win.com?p=${AUCTION_PRICE}&a=${AUCTION_ID}&b=${AUCTION_BID_ID}&i=${AUCTION_IMP_ID}&s=${AUCTION_SEAT_ID}&ad=${AUCTION_AD_ID}&c=${AUCTION_CURRENCY}&l=${AUCTION_LOSS}",
+			want:  "http://win.com?p=1.25&a=123&b=bidId123&i=imp1&s=pubmatic&ad=ad1&c=USD&l=0",
+		},
+		{
+			name:  "markup_with_macros",
+			input: `<img src="http://imp.com?price=${AUCTION_PRICE}"/>`,
+			want:  `<img src="http://imp.com?price=1.25"/>`,
+		},
+		{
+			name:  "unknown_macros_preserved",
+			input: "http://win.com?p=${AUCTION_PRICE}&gdpr=${GDPR}&bid=##PBS-BIDID##&x=${PBS-BIDID}",
+			want:  "http://win.com?p=1.25&gdpr=${GDPR}&bid=##PBS-BIDID##&x=${PBS-BIDID}",
+		},
+		{
+			name:  "no_macros",
+			input: "http://win.com",
+			want:  "http://win.com",
+		},
+		{
+			name:  "unterminated_macro",
+			input: "http://win.com?p=${AUCTION_PRICE",
+			want:  "http://win.com?p=${AUCTION_PRICE",
+		},
+		{
+			name:  "empty_macro",
+			input: "http://win.com?p=${}",
+			want:  "http://win.com?p=${}",
+		},
+		{
+			name:  "empty_input",
+			input: "",
+			want:  "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			macroProvider := NewProvider(req)
+			macroProvider.PopulateAuctionMacros(auctionBid, "pubmatic", "USD", "1.25", LossReasonBidWon)
+
+			replacer := NewAuctionMacroReplacer()
+			builder := strings.Builder{}
+			replacer.Replace(&builder, tt.input, macroProvider)
+			assert.Equal(t, tt.want, builder.String())
+		})
+	}
+}
+
+type mockPriceEncoder struct {
+	err error
+}
+
+func (m mockPriceEncoder) EncodePrice(price float64, currency string, seat string) (string, error) {
+	return "encrypted-" + seat, m.err
+}
+
+func TestPriceEncodersGet(t *testing.T) {
+	encoders := PriceEncoders{"mock": mockPriceEncoder{}}
+
+	tests := []struct {
+		name        string
+		encoding    string
+		wantPrice   string
+		expectedErr error
+	}{
+		{
+			name:      "default_is_clear",
+			encoding:  "",
+			wantPrice: "1.2345",
+		},
+		{
+			name:      "clear",
+			encoding:  PriceEncodingClear,
+			wantPrice: "1.2345",
+		},
+		{
+			name:      "registered_encoder",
+			encoding:  "mock",
+			wantPrice: "encrypted-pubmatic",
+		},
+		{
+			name:        "unregistered_encoder",
+			encoding:    "unknown",
+			expectedErr: errors.New("price encoder unknown is not registered"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoder, err := encoders.Get(tt.encoding)
+			assert.Equal(t, tt.expectedErr, err)
+			if err != nil {
+				return
+			}
+			price, err := encoder.EncodePrice(1.2345, "USD", "pubmatic")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantPrice, price)
+		})
+	}
+}
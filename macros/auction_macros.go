@@ -0,0 +1,96 @@
+package macros
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+)
+
+// Standard OpenRTB 2.x substitution macros resolved by the exchange after the auction
+const (
+	AuctionMacroPrice    = "AUCTION_PRICE"
+	AuctionMacroID       = "AUCTION_ID"
+	AuctionMacroBidID    = "AUCTION_BID_ID"
+	AuctionMacroImpID    = "AUCTION_IMP_ID"
+	AuctionMacroSeatID   = "AUCTION_SEAT_ID"
+	AuctionMacroAdID     = "AUCTION_AD_ID"
+	AuctionMacroCurrency = "AUCTION_CURRENCY"
+	AuctionMacroLoss     = "AUCTION_LOSS"
+)
+
+// auctionMacroKeys is the set of macros the auction macro replacer is allowed to resolve.
+// Any other ${...} token found in markup is left untouched for downstream consumers.
+var auctionMacroKeys = map[string]struct{}{
+	AuctionMacroPrice:    {},
+	AuctionMacroID:       {},
+	AuctionMacroBidID:    {},
+	AuctionMacroImpID:    {},
+	AuctionMacroSeatID:   {},
+	AuctionMacroAdID:     {},
+	AuctionMacroCurrency: {},
+	AuctionMacroLoss:     {},
+}
+
+// Loss reason codes substituted for ${AUCTION_LOSS} as defined by the OpenRTB specification
+const (
+	LossReasonBidWon          = 0
+	LossReasonLostToHigherBid = 102
+)
+
+// PriceEncodingClear substitutes ${AUCTION_PRICE} with the clearing price in plain text
+const PriceEncodingClear = "clear"
+
+// PriceEncoder encodes the clearing price substituted for ${AUCTION_PRICE}. Hosts that need
+// encrypted prices register their implementation with router.RegisterPriceEncoder and reference it
+// by name from the account auction macros configuration.
+type PriceEncoder interface {
+	EncodePrice(price float64, currency string, seat string) (string, error)
+}
+
+type clearPriceEncoder struct{}
+
+// EncodePrice returns the price formatted with the minimal number of digits required to represent it
+func (clearPriceEncoder) EncodePrice(price float64, currency string, seat string) (string, error) {
+	return strconv.FormatFloat(price, 'f', -1, 64), nil
+}
+
+// PriceEncoders holds the host price encoders by name. The clear price encoder is always available.
+type PriceEncoders map[string]PriceEncoder
+
+// Get returns the price encoder registered under the given name. An empty name resolves to the
+// clear price encoder.
+func (p PriceEncoders) Get(name string) (PriceEncoder, error) {
+	if name == "" || name == PriceEncodingClear {
+		return clearPriceEncoder{}, nil
+	}
+	encoder, ok := p[name]
+	if !ok {
+		return nil, fmt.Errorf("price encoder %s is not registered", name)
+	}
+	return encoder, nil
+}
+
+// PopulateAuctionMacros sets the standard OpenRTB auction macros for the given bid. The price
+// is expected to already be encoded by the configured PriceEncoder.
+func (b *MacroProvider) PopulateAuctionMacros(bid *entities.PbsOrtbBid, seat, currency, encodedPrice string, lossReason int) {
+	b.macros[AuctionMacroID] = b.macros[MacroKeyAuctionID]
+	b.macros[AuctionMacroPrice] = encodedPrice
+	b.macros[AuctionMacroSeatID] = seat
+	b.macros[AuctionMacroCurrency] = currency
+	b.macros[AuctionMacroLoss] = strconv.Itoa(lossReason)
+	if bid.Bid != nil {
+		b.macros[AuctionMacroBidID] = bid.Bid.ID
+		b.macros[AuctionMacroImpID] = bid.Bid.ImpID
+		b.macros[AuctionMacroAdID] = bid.Bid.AdID
+	}
+}
+
+// getAuctionMacro returns the unescaped value of an auction macro and whether it can be resolved
+func (b *MacroProvider) getAuctionMacro(key string) (string, bool) {
+	if _, ok := auctionMacroKeys[key]; !ok {
+		return "", false
+	}
+	value, ok := b.macros[key]
+	return value, ok
+}
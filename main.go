@@ -71,7 +71,7 @@ func serve(cfg *config.Configuration) error {
 	httpTimeout := time.Duration(cfg.CurrencyConverter.FetchTimeoutMilliseconds) * time.Millisecond
 	fetchingInterval := time.Duration(cfg.CurrencyConverter.FetchIntervalSeconds) * time.Second
 	staleRatesThreshold := time.Duration(cfg.CurrencyConverter.StaleRatesSeconds) * time.Second
-	currencyConverter := currency.NewRateConverter(&http.Client{}, httpTimeout, cfg.CurrencyConverter.FetchURL, staleRatesThreshold)
+	currencyConverter := currency.NewRateConverterWithSources(currency.NewRateSources(&http.Client{}, httpTimeout, cfg.CurrencyConverter), staleRatesThreshold, cfg.CurrencyConverter.MaxRateDeviationPercent, cfg.CurrencyConverter.RateDeviationConfirmations)
 
 	currencyConverterTickerTask := task.NewTickerTask(fetchingInterval, currencyConverter)
 	currencyConverterTickerTask.Start()
@@ -82,7 +82,7 @@ func serve(cfg *config.Configuration) error {
 	}
 
 	corsRouter := router.SupportCORS(r)
-	if err := server.Listen(cfg, router.NoCache{Handler: corsRouter}, router.Admin(currencyConverter, fetchingInterval), r.MetricsEngine); err != nil {
+	if err := server.Listen(cfg, router.NoCache{Handler: corsRouter}, router.Admin(currencyConverter, fetchingInterval, r.StoredDataAPI, r.FloorSuggestionsAPI), r.MetricsEngine); err != nil {
 		logger.Fatalf("prebid-server returned an error: %v", err)
 	}
 
@@ -261,6 +261,13 @@ func (me *MultiMetricsEngine) RecordPrebidCacheRequestTime(success bool, length
 	}
 }
 
+// RecordPrebidCacheWriteError across all engines
+func (me *MultiMetricsEngine) RecordPrebidCacheWriteError(cacheType metrics.PrebidCacheType) {
+	for _, thisME := range *me {
+		thisME.RecordPrebidCacheWriteError(cacheType)
+	}
+}
+
 // RecordRequestQueueTime across all engines
 func (me *MultiMetricsEngine) RecordRequestQueueTime(success bool, requestType metrics.RequestType, length time.Duration) {
 	for _, thisME := range *me {
@@ -282,6 +289,20 @@ func (me *MultiMetricsEngine) RecordRequestPrivacy(privacy metrics.PrivacyLabels
 	}
 }
 
+// RecordAdapterFledgeAuctionConfigs across all engines
+func (me *MultiMetricsEngine) RecordAdapterFledgeAuctionConfigs(adapter openrtb_ext.BidderName, status metrics.FledgeAuctionConfigStatus, count int) {
+	for _, thisME := range *me {
+		thisME.RecordAdapterFledgeAuctionConfigs(adapter, status, count)
+	}
+}
+
+// RecordSChainValidation across all engines
+func (me *MultiMetricsEngine) RecordSChainValidation(status metrics.SChainValidationStatus) {
+	for _, thisME := range *me {
+		thisME.RecordSChainValidation(status)
+	}
+}
+
 // RecordAdapterBuyerUIDScrubbed across all engines
 func (me *MultiMetricsEngine) RecordAdapterBuyerUIDScrubbed(adapter openrtb_ext.BidderName) {
 	for _, thisME := range *me {
@@ -357,6 +378,13 @@ func (me *MultiMetricsEngine) RecordGvlListRequest() {
 	}
 }
 
+// RecordStoredRequestVersionFallback across all engines
+func (me *MultiMetricsEngine) RecordStoredRequestVersionFallback() {
+	for _, thisME := range *me {
+		thisME.RecordStoredRequestVersionFallback()
+	}
+}
+
 func (me *MultiMetricsEngine) RecordRejectedBidsForAccount(pubId string) {
 	for _, thisME := range *me {
 		thisME.RecordRejectedBidsForAccount(pubId)
@@ -592,6 +620,10 @@ func (me *NilMetricsEngine) RecordAccountCacheResult(cacheResult metrics.CacheRe
 func (me *NilMetricsEngine) RecordPrebidCacheRequestTime(success bool, length time.Duration) {
 }
 
+// RecordPrebidCacheWriteError as a noop
+func (me *NilMetricsEngine) RecordPrebidCacheWriteError(cacheType metrics.PrebidCacheType) {
+}
+
 // RecordRequestQueueTime as a noop
 func (me *NilMetricsEngine) RecordRequestQueueTime(success bool, requestType metrics.RequestType, length time.Duration) {
 }
@@ -604,6 +636,14 @@ func (me *NilMetricsEngine) RecordTimeoutNotice(success bool) {
 func (me *NilMetricsEngine) RecordRequestPrivacy(privacy metrics.PrivacyLabels) {
 }
 
+// RecordAdapterFledgeAuctionConfigs as a noop
+func (me *NilMetricsEngine) RecordAdapterFledgeAuctionConfigs(adapter openrtb_ext.BidderName, status metrics.FledgeAuctionConfigStatus, count int) {
+}
+
+// RecordSChainValidation as a noop
+func (me *NilMetricsEngine) RecordSChainValidation(status metrics.SChainValidationStatus) {
+}
+
 // RecordAdapterBuyerUIDScrubbed as a noop
 func (me *NilMetricsEngine) RecordAdapterBuyerUIDScrubbed(adapter openrtb_ext.BidderName) {
 }
@@ -621,6 +661,10 @@ func (me *NilMetricsEngine) RecordStoredResponse(pubId string) {
 
 func (me *NilMetricsEngine) RecordGvlListRequest() {
 }
+
+// RecordStoredRequestVersionFallback as a noop
+func (me *NilMetricsEngine) RecordStoredRequestVersionFallback() {
+}
 func (me *NilMetricsEngine) RecordRejectedBidsForAccount(pubId string) {
 }
 
@@ -26,6 +26,7 @@ type Metrics struct {
 	RequestsQueueTimer             map[RequestType]map[bool]metrics.Timer
 	PrebidCacheRequestTimerSuccess metrics.Timer
 	PrebidCacheRequestTimerError   metrics.Timer
+	PrebidCacheWriteErrorMeter     map[PrebidCacheType]metrics.Meter
 	StoredDataFetchTimer           map[StoredDataType]map[StoredDataFetchType]metrics.Timer
 	StoredDataErrorMeter           map[StoredDataType]map[StoredDataError]metrics.Meter
 	StoredReqCacheMeter            map[CacheResult]metrics.Meter
@@ -36,6 +37,7 @@ type Metrics struct {
 	BidderServerResponseTimer      metrics.Timer
 	StoredResponsesMeter           metrics.Meter
 	GvlListRequestsMeter           metrics.Meter
+	StoredReqVersionFallbackMeter  metrics.Meter
 
 	// Metrics for OpenRTB requests specifically
 	RequestStatuses        map[RequestType]map[RequestStatus]metrics.Meter
@@ -43,6 +45,7 @@ type Metrics struct {
 	AmpNoCookieMeter       metrics.Meter
 	CookieSyncMeter        metrics.Meter
 	CookieSyncStatusMeter  map[CookieSyncStatus]metrics.Meter
+	SChainValidationMeter  map[SChainValidationStatus]metrics.Meter
 	SyncerRequestsMeter    map[string]map[SyncerCookieSyncStatus]metrics.Meter
 	SetUidMeter            metrics.Meter
 	SetUidStatusMeter      map[SetUidStatus]metrics.Meter
@@ -108,6 +111,8 @@ type AdapterMetrics struct {
 	GDPRRequestBlocked metrics.Meter
 	ThrottledMeter     metrics.Meter
 
+	FledgeAuctionConfigMeters map[FledgeAuctionConfigStatus]metrics.Meter
+
 	BidValidationCreativeSizeErrorMeter metrics.Meter
 	BidValidationCreativeSizeWarnMeter  metrics.Meter
 
@@ -193,6 +198,7 @@ func NewBlankMetrics(registry metrics.Registry, exchanges []string, disabledMetr
 		RequestsQueueTimer:             make(map[RequestType]map[bool]metrics.Timer),
 		PrebidCacheRequestTimerSuccess: blankTimer,
 		PrebidCacheRequestTimerError:   blankTimer,
+		PrebidCacheWriteErrorMeter:     make(map[PrebidCacheType]metrics.Meter),
 		StoredDataFetchTimer:           make(map[StoredDataType]map[StoredDataFetchType]metrics.Timer),
 		StoredDataErrorMeter:           make(map[StoredDataType]map[StoredDataError]metrics.Meter),
 		StoredReqCacheMeter:            make(map[CacheResult]metrics.Meter),
@@ -201,12 +207,14 @@ func NewBlankMetrics(registry metrics.Registry, exchanges []string, disabledMetr
 		AmpNoCookieMeter:               blankMeter,
 		CookieSyncMeter:                blankMeter,
 		CookieSyncStatusMeter:          make(map[CookieSyncStatus]metrics.Meter),
+		SChainValidationMeter:          make(map[SChainValidationStatus]metrics.Meter),
 		SyncerRequestsMeter:            make(map[string]map[SyncerCookieSyncStatus]metrics.Meter),
 		SetUidMeter:                    blankMeter,
 		SetUidStatusMeter:              make(map[SetUidStatus]metrics.Meter),
 		SyncerSetsMeter:                make(map[string]map[SyncerSetUidStatus]metrics.Meter),
 		StoredResponsesMeter:           blankMeter,
 		GvlListRequestsMeter:           blankMeter,
+		StoredReqVersionFallbackMeter:  blankMeter,
 		FloorRejectedBidsMeter:         make(map[openrtb_ext.BidderName]metrics.Meter),
 
 		ImpsTypeBanner: blankMeter,
@@ -261,6 +269,10 @@ func NewBlankMetrics(registry metrics.Registry, exchanges []string, disabledMetr
 		}
 	}
 
+	for _, t := range PrebidCacheTypes() {
+		newMetrics.PrebidCacheWriteErrorMeter[t] = blankMeter
+	}
+
 	for _, c := range CacheResults() {
 		newMetrics.StoredReqCacheMeter[c] = blankMeter
 		newMetrics.StoredImpCacheMeter[c] = blankMeter
@@ -333,6 +345,7 @@ func NewMetrics(registry metrics.Registry, exchanges []openrtb_ext.BidderName, d
 	newMetrics.PrebidCacheRequestTimerError = metrics.GetOrRegisterTimer("prebid_cache_request_time.err", registry)
 	newMetrics.StoredResponsesMeter = metrics.GetOrRegisterMeter("stored_responses", registry)
 	newMetrics.GvlListRequestsMeter = metrics.GetOrRegisterMeter("gvl_requests", registry)
+	newMetrics.StoredReqVersionFallbackMeter = metrics.GetOrRegisterMeter("stored_request_version_fallbacks", registry)
 	newMetrics.OverheadTimer = makeOverheadTimerMetrics(registry)
 	newMetrics.BidderServerResponseTimer = metrics.GetOrRegisterTimer("bidder_server_response_time_seconds", registry)
 
@@ -354,6 +367,10 @@ func NewMetrics(registry metrics.Registry, exchanges []openrtb_ext.BidderName, d
 		newMetrics.CookieSyncStatusMeter[s] = metrics.GetOrRegisterMeter(fmt.Sprintf("cookie_sync_requests.%s", s), registry)
 	}
 
+	for _, s := range SChainValidationStatuses() {
+		newMetrics.SChainValidationMeter[s] = metrics.GetOrRegisterMeter(fmt.Sprintf("schain_validation.%s", s), registry)
+	}
+
 	newMetrics.SetUidMeter = metrics.GetOrRegisterMeter("setuid_requests", registry)
 	for _, s := range SetUidStatuses() {
 		newMetrics.SetUidStatusMeter[s] = metrics.GetOrRegisterMeter(fmt.Sprintf("setuid_requests.%s", s), registry)
@@ -386,6 +403,10 @@ func NewMetrics(registry metrics.Registry, exchanges []openrtb_ext.BidderName, d
 		newMetrics.RequestSizeByEndpoint[endpoint] = metrics.GetOrRegisterHistogram("requests.size."+string(endpoint), registry, metrics.NewUniformSample(1024))
 	}
 
+	for _, cacheType := range PrebidCacheTypes() {
+		newMetrics.PrebidCacheWriteErrorMeter[cacheType] = metrics.GetOrRegisterMeter(fmt.Sprintf("prebid_cache_write_error.%s", string(cacheType)), registry)
+	}
+
 	for _, cacheRes := range CacheResults() {
 		newMetrics.StoredReqCacheMeter[cacheRes] = metrics.GetOrRegisterMeter(fmt.Sprintf("stored_request_cache_%s", string(cacheRes)), registry)
 		newMetrics.StoredImpCacheMeter[cacheRes] = metrics.GetOrRegisterMeter(fmt.Sprintf("stored_imp_cache_%s", string(cacheRes)), registry)
@@ -440,6 +461,8 @@ func makeBlankAdapterMetrics(disabledMetrics config.DisabledMetrics) *AdapterMet
 		PanicMeter:        blankMeter,
 		MarkupMetrics:     makeBlankBidMarkupMetrics(),
 		ThrottledMeter:    blankMeter,
+
+		FledgeAuctionConfigMeters: make(map[FledgeAuctionConfigStatus]metrics.Meter),
 	}
 	if !disabledMetrics.AdapterConnectionMetrics {
 		newAdapter.ConnCreated = metrics.NilCounter{}
@@ -458,6 +481,9 @@ func makeBlankAdapterMetrics(disabledMetrics config.DisabledMetrics) *AdapterMet
 	for _, err := range AdapterErrors() {
 		newAdapter.ErrorMeters[err] = blankMeter
 	}
+	for _, status := range FledgeAuctionConfigStatuses() {
+		newAdapter.FledgeAuctionConfigMeters[status] = blankMeter
+	}
 	return newAdapter
 }
 
@@ -537,6 +563,9 @@ func registerAdapterMetrics(registry metrics.Registry, adapterOrAccount string,
 	am.BuyerUIDScrubbed = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.buyeruid_scrubbed", adapterOrAccount, exchange), registry)
 	am.GDPRRequestBlocked = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.gdpr_request_blocked", adapterOrAccount, exchange), registry)
 	am.ThrottledMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.requests.throttled", adapterOrAccount, exchange), registry)
+	for status := range am.FledgeAuctionConfigMeters {
+		am.FledgeAuctionConfigMeters[status] = metrics.GetOrRegisterMeter(fmt.Sprintf("%s.%s.fledge_auction_configs.%s", adapterOrAccount, exchange, status), registry)
+	}
 
 	am.BidValidationCreativeSizeErrorMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.response.validation.size.err", adapterOrAccount, exchange), registry)
 	am.BidValidationCreativeSizeWarnMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.response.validation.size.warn", adapterOrAccount, exchange), registry)
@@ -682,6 +711,11 @@ func (me *Metrics) RecordStoredResponse(pubId string) {
 func (me *Metrics) RecordGvlListRequest() {
 	me.GvlListRequestsMeter.Mark(1)
 }
+
+// RecordStoredRequestVersionFallback implements a part of the MetricsEngine interface
+func (me *Metrics) RecordStoredRequestVersionFallback() {
+	me.StoredReqVersionFallbackMeter.Mark(1)
+}
 func (me *Metrics) RecordRejectedBidsForAccount(pubId string) {
 	if pubId != PublisherUnknown {
 		me.getAccountMetrics(pubId).rejecteBidMeter.Mark(1)
@@ -938,6 +972,14 @@ func (me *Metrics) RecordCookieSync(status CookieSyncStatus) {
 	}
 }
 
+// RecordSChainValidation implements a part of the MetricsEngine interface. Records the outcome of the validation
+// of a supply chain
+func (me *Metrics) RecordSChainValidation(status SChainValidationStatus) {
+	if meter, exists := me.SChainValidationMeter[status]; exists {
+		meter.Mark(1)
+	}
+}
+
 // RecordSyncerRequest implements a part of the MetricsEngine interface. Records a cookie sync syncer request and status
 func (me *Metrics) RecordSyncerRequest(key string, status SyncerCookieSyncStatus) {
 	if keyMeter, exists := me.SyncerRequestsMeter[key]; exists {
@@ -992,6 +1034,14 @@ func (me *Metrics) RecordPrebidCacheRequestTime(success bool, length time.Durati
 	}
 }
 
+// RecordPrebidCacheWriteError implements a part of the MetricsEngine interface. Records the values
+// which couldn't be stored in Prebid Cache.
+func (me *Metrics) RecordPrebidCacheWriteError(cacheType PrebidCacheType) {
+	if meter, ok := me.PrebidCacheWriteErrorMeter[cacheType]; ok {
+		meter.Mark(1)
+	}
+}
+
 func (me *Metrics) RecordRequestQueueTime(success bool, requestType RequestType, length time.Duration) {
 	if requestType == ReqTypeVideo { //remove this check when other request types are supported
 		me.RequestsQueueTimer[requestType][success].Update(length)
@@ -1047,6 +1097,21 @@ func (me *Metrics) RecordAdapterBuyerUIDScrubbed(adapterName openrtb_ext.BidderN
 	am.BuyerUIDScrubbed.Mark(1)
 }
 
+// RecordAdapterFledgeAuctionConfigs implements a part of the MetricsEngine interface. Records the auction configs
+// returned by the adapter, by validation outcome.
+func (me *Metrics) RecordAdapterFledgeAuctionConfigs(adapterName openrtb_ext.BidderName, status FledgeAuctionConfigStatus, count int) {
+	adapterStr := adapterName.String()
+	am, ok := me.AdapterMetrics[strings.ToLower(adapterStr)]
+	if !ok {
+		logger.Errorf("Trying to log adapter fledge auction configs metric for %s: adapter not found", adapterStr)
+		return
+	}
+
+	if meter, ok := am.FledgeAuctionConfigMeters[status]; ok {
+		meter.Mark(int64(count))
+	}
+}
+
 func (me *Metrics) RecordAdapterGDPRRequestBlocked(adapterName openrtb_ext.BidderName) {
 	adapterStr := string(adapterName)
 	if me.MetricsDisabled.AdapterGDPRRequestBlocked {
@@ -38,9 +38,12 @@ func TestNewMetrics(t *testing.T) {
 	ensureContains(t, registry, "setuid_requests.syncer_unknown", m.SetUidStatusMeter[SetUidSyncerUnknown])
 	ensureContains(t, registry, "stored_responses", m.StoredResponsesMeter)
 	ensureContains(t, registry, "gvl_requests", m.GvlListRequestsMeter)
+	ensureContains(t, registry, "stored_request_version_fallbacks", m.StoredReqVersionFallbackMeter)
 
 	ensureContains(t, registry, "prebid_cache_request_time.ok", m.PrebidCacheRequestTimerSuccess)
 	ensureContains(t, registry, "prebid_cache_request_time.err", m.PrebidCacheRequestTimerError)
+	ensureContains(t, registry, "prebid_cache_write_error.json", m.PrebidCacheWriteErrorMeter[PrebidCacheJSON])
+	ensureContains(t, registry, "prebid_cache_write_error.xml", m.PrebidCacheWriteErrorMeter[PrebidCacheXML])
 
 	ensureContains(t, registry, "requests.ok.openrtb2-web", m.RequestStatuses[ReqTypeORTB2Web][RequestStatusOK])
 	ensureContains(t, registry, "requests.badinput.openrtb2-web", m.RequestStatuses[ReqTypeORTB2Web][RequestStatusBadInput])
@@ -628,6 +631,18 @@ func TestRecordPrebidCacheRequestTimeWithNotSuccess(t *testing.T) {
 	assert.Equal(t, m.PrebidCacheRequestTimerError.Count(), int64(1))
 }
 
+func TestRecordPrebidCacheWriteError(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderName("Foo")}, config.DisabledMetrics{AccountAdapterDetails: true}, nil, nil)
+
+	m.RecordPrebidCacheWriteError(PrebidCacheXML)
+	m.RecordPrebidCacheWriteError(PrebidCacheXML)
+	m.RecordPrebidCacheWriteError(PrebidCacheType("unknown"))
+
+	assert.Equal(t, int64(0), m.PrebidCacheWriteErrorMeter[PrebidCacheJSON].Count())
+	assert.Equal(t, int64(2), m.PrebidCacheWriteErrorMeter[PrebidCacheXML].Count())
+}
+
 func TestRecordStoredDataFetchTime(t *testing.T) {
 	tests := []struct {
 		description string
@@ -819,6 +834,19 @@ func TestRecordRequestPrivacy(t *testing.T) {
 	assert.Equal(t, m.PrivacyTCFRequestVersion[TCFVersionV2].Count(), int64(1), "TCF V2")
 }
 
+func TestRecordAdapterFledgeAuctionConfigs(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderName("AnyName")}, config.DisabledMetrics{}, nil, nil)
+
+	m.RecordAdapterFledgeAuctionConfigs(openrtb_ext.BidderName("AnyName"), FledgeAuctionConfigAccepted, 2)
+	m.RecordAdapterFledgeAuctionConfigs(openrtb_ext.BidderName("AnyName"), FledgeAuctionConfigRejected, 1)
+	m.RecordAdapterFledgeAuctionConfigs(openrtb_ext.BidderName("fooAdvertising"), FledgeAuctionConfigRejected, 1)
+
+	ensureContains(t, registry, "adapter.anyname.fledge_auction_configs.accepted", m.AdapterMetrics["anyname"].FledgeAuctionConfigMeters[FledgeAuctionConfigAccepted])
+	assert.Equal(t, int64(2), m.AdapterMetrics["anyname"].FledgeAuctionConfigMeters[FledgeAuctionConfigAccepted].Count())
+	assert.Equal(t, int64(1), m.AdapterMetrics["anyname"].FledgeAuctionConfigMeters[FledgeAuctionConfigRejected].Count())
+}
+
 func TestRecordAdapterBuyerUIDScrubbed(t *testing.T) {
 	var fakeBidder openrtb_ext.BidderName = "fooAdvertising"
 	adapter := "AnyName"
@@ -920,6 +948,21 @@ func TestRecordCookieSync(t *testing.T) {
 	assert.Equal(t, m.CookieSyncStatusMeter[CookieSyncGDPRHostCookieBlocked].Count(), int64(0))
 }
 
+func TestRecordSChainValidation(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderName("Foo")}, config.DisabledMetrics{}, nil, nil)
+
+	m.RecordSChainValidation(SChainRepaired)
+	m.RecordSChainValidation(SChainUnknownSeller)
+	m.RecordSChainValidation(SChainUnknownSeller)
+	m.RecordSChainValidation(SChainValidationStatus("unknown status"))
+
+	ensureContains(t, registry, "schain_validation.repaired", m.SChainValidationMeter[SChainRepaired])
+	assert.Equal(t, int64(0), m.SChainValidationMeter[SChainValid].Count())
+	assert.Equal(t, int64(1), m.SChainValidationMeter[SChainRepaired].Count())
+	assert.Equal(t, int64(2), m.SChainValidationMeter[SChainUnknownSeller].Count())
+}
+
 func TestRecordSyncerRequest(t *testing.T) {
 	registry := metrics.NewRegistry()
 	syncerKeys := []string{"foo"}
@@ -349,6 +349,60 @@ func CacheResults() []CacheResult {
 	}
 }
 
+// PrebidCacheType : The types of the values written to Prebid Cache
+type PrebidCacheType string
+
+const (
+	PrebidCacheJSON PrebidCacheType = "json"
+	PrebidCacheXML  PrebidCacheType = "xml"
+)
+
+// PrebidCacheTypes returns the types of the values written to Prebid Cache
+func PrebidCacheTypes() []PrebidCacheType {
+	return []PrebidCacheType{
+		PrebidCacheJSON,
+		PrebidCacheXML,
+	}
+}
+
+// FledgeAuctionConfigStatus : The outcome of the validation of the auction configs returned by the bidders
+type FledgeAuctionConfigStatus string
+
+const (
+	FledgeAuctionConfigAccepted FledgeAuctionConfigStatus = "accepted"
+	FledgeAuctionConfigRejected FledgeAuctionConfigStatus = "rejected"
+)
+
+// FledgeAuctionConfigStatuses returns the outcomes of the validation of the auction configs
+func FledgeAuctionConfigStatuses() []FledgeAuctionConfigStatus {
+	return []FledgeAuctionConfigStatus{
+		FledgeAuctionConfigAccepted,
+		FledgeAuctionConfigRejected,
+	}
+}
+
+// SChainValidationStatus : The outcome of the validation of the supply chains of the requests
+type SChainValidationStatus string
+
+const (
+	SChainValid            SChainValidationStatus = "valid"
+	SChainRepaired         SChainValidationStatus = "repaired"
+	SChainRejected         SChainValidationStatus = "rejected"
+	SChainUnknownSeller    SChainValidationStatus = "unknown_seller"
+	SChainMismatchedSeller SChainValidationStatus = "mismatched_seller"
+)
+
+// SChainValidationStatuses returns the outcomes of the validation of the supply chains
+func SChainValidationStatuses() []SChainValidationStatus {
+	return []SChainValidationStatus{
+		SChainValid,
+		SChainRepaired,
+		SChainRejected,
+		SChainUnknownSeller,
+		SChainMismatchedSeller,
+	}
+}
+
 // TCFVersionValue : The possible values for TCF versions
 type TCFVersionValue string
 
@@ -502,14 +556,23 @@ type MetricsEngine interface {
 	RecordStoredDataFetchTime(labels StoredDataLabels, length time.Duration)
 	RecordStoredDataError(labels StoredDataLabels)
 	RecordPrebidCacheRequestTime(success bool, length time.Duration)
+	// RecordPrebidCacheWriteError records a value which couldn't be cached, after the retries
+	RecordPrebidCacheWriteError(cacheType PrebidCacheType)
 	RecordRequestQueueTime(success bool, requestType RequestType, length time.Duration)
 	RecordTimeoutNotice(success bool)
 	RecordRequestPrivacy(privacy PrivacyLabels)
 	RecordAdapterBuyerUIDScrubbed(adapterName openrtb_ext.BidderName)
 	RecordAdapterGDPRRequestBlocked(adapterName openrtb_ext.BidderName)
+	// RecordAdapterFledgeAuctionConfigs records the auction configs returned by the adapter, by validation outcome
+	RecordAdapterFledgeAuctionConfigs(adapterName openrtb_ext.BidderName, status FledgeAuctionConfigStatus, count int)
+	// RecordSChainValidation records the outcome of the validation of a supply chain of a request
+	RecordSChainValidation(status SChainValidationStatus)
 	RecordDebugRequest(debugEnabled bool, pubId string)
 	RecordStoredResponse(pubId string)
 	RecordGvlListRequest()
+	// RecordStoredRequestVersionFallback records an auction served the current Stored Request data because the
+	// version selected by the staged rollout could not be fetched
+	RecordStoredRequestVersionFallback()
 	RecordAdsCertReq(success bool)
 	RecordAdsCertSignTime(adsCertSignTime time.Duration)
 	RecordBidValidationCreativeSizeError(adapter openrtb_ext.BidderName, account string)
@@ -145,6 +145,11 @@ func (me *MetricsEngineMock) RecordPrebidCacheRequestTime(success bool, length t
 	me.Called(success, length)
 }
 
+// RecordPrebidCacheWriteError mock
+func (me *MetricsEngineMock) RecordPrebidCacheWriteError(cacheType PrebidCacheType) {
+	me.Called(cacheType)
+}
+
 // RecordRequestQueueTime mock
 func (me *MetricsEngineMock) RecordRequestQueueTime(success bool, requestType RequestType, length time.Duration) {
 	me.Called(success, requestType, length)
@@ -165,6 +170,16 @@ func (me *MetricsEngineMock) RecordAdapterBuyerUIDScrubbed(adapterName openrtb_e
 	me.Called(adapterName)
 }
 
+// RecordAdapterFledgeAuctionConfigs mock
+func (me *MetricsEngineMock) RecordAdapterFledgeAuctionConfigs(adapterName openrtb_ext.BidderName, status FledgeAuctionConfigStatus, count int) {
+	me.Called(adapterName, status, count)
+}
+
+// RecordSChainValidation mock
+func (me *MetricsEngineMock) RecordSChainValidation(status SChainValidationStatus) {
+	me.Called(status)
+}
+
 // RecordAdapterGDPRRequestBlocked mock
 func (me *MetricsEngineMock) RecordAdapterGDPRRequestBlocked(adapterName openrtb_ext.BidderName) {
 	me.Called(adapterName)
@@ -183,6 +198,11 @@ func (me *MetricsEngineMock) RecordGvlListRequest() {
 	me.Called()
 }
 
+// RecordStoredRequestVersionFallback mock
+func (me *MetricsEngineMock) RecordStoredRequestVersionFallback() {
+	me.Called()
+}
+
 func (me *MetricsEngineMock) RecordRejectedBidsForAccount(pubId string) {
 	me.Called(pubId)
 }
@@ -13,8 +13,10 @@ func preloadLabelValues(m *Metrics, syncerKeys []string, moduleStageNames map[st
 		bidTypeValues             = []string{markupDeliveryAdm, markupDeliveryNurl}
 		boolValues                = boolValuesAsString()
 		cacheResultValues         = enumAsString(metrics.CacheResults())
+		cacheTypeValues           = enumAsString(metrics.PrebidCacheTypes())
 		connectionErrorValues     = []string{connectionAcceptError, connectionCloseError}
 		cookieSyncStatusValues    = enumAsString(metrics.CookieSyncStatuses())
+		sChainStatusValues        = enumAsString(metrics.SChainValidationStatuses())
 		cookieValues              = enumAsString(metrics.CookieTypes())
 		overheadTypes             = enumAsString(metrics.OverheadTypes())
 		requestStatusValues       = enumAsString(metrics.RequestStatuses())
@@ -36,6 +38,10 @@ func preloadLabelValues(m *Metrics, syncerKeys []string, moduleStageNames map[st
 		statusLabel: cookieSyncStatusValues,
 	})
 
+	preloadLabelValuesForCounter(m.sChainValidation, map[string][]string{
+		statusLabel: sChainStatusValues,
+	})
+
 	preloadLabelValuesForCounter(m.setUid, map[string][]string{
 		statusLabel: setUidStatusValues,
 	})
@@ -51,6 +57,10 @@ func preloadLabelValues(m *Metrics, syncerKeys []string, moduleStageNames map[st
 		successLabel: boolValues,
 	})
 
+	preloadLabelValuesForCounter(m.prebidCacheWriteErrors, map[string][]string{
+		cacheTypeLabel: cacheTypeValues,
+	})
+
 	preloadLabelValuesForCounter(m.requests, map[string][]string{
 		requestTypeLabel:   requestTypeValues,
 		requestStatusLabel: requestStatusValues,
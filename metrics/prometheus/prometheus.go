@@ -25,9 +25,11 @@ type Metrics struct {
 	connectionsError             *prometheus.CounterVec
 	connectionsOpened            prometheus.Counter
 	cookieSync                   *prometheus.CounterVec
+	sChainValidation             *prometheus.CounterVec
 	setUid                       *prometheus.CounterVec
 	impressions                  *prometheus.CounterVec
 	prebidCacheWriteTimer        *prometheus.HistogramVec
+	prebidCacheWriteErrors       *prometheus.CounterVec
 	requests                     *prometheus.CounterVec
 	requestsSize                 *prometheus.HistogramVec
 	debugRequests                prometheus.Counter
@@ -55,6 +57,7 @@ type Metrics struct {
 	privacyTCF                   *prometheus.CounterVec
 	storedResponses              prometheus.Counter
 	gvlListRequests              prometheus.Counter
+	storedReqVersionFallbacks    prometheus.Counter
 	storedResponsesFetchTimer    *prometheus.HistogramVec
 	storedResponsesErrors        *prometheus.CounterVec
 	adsCertRequests              *prometheus.CounterVec
@@ -73,6 +76,7 @@ type Metrics struct {
 	adapterCreatedConnections             *prometheus.CounterVec
 	adapterConnectionWaitTime             *prometheus.HistogramVec
 	adapterScrubbedBuyerUIDs              *prometheus.CounterVec
+	adapterFledgeAuctionConfigs           *prometheus.CounterVec
 	adapterGDPRBlockedRequests            *prometheus.CounterVec
 	adapterBidResponseValidationSizeError *prometheus.CounterVec
 	adapterBidResponseValidationSizeWarn  *prometheus.CounterVec
@@ -118,6 +122,7 @@ const (
 	adapterLabel         = "adapter"
 	bidTypeLabel         = "bid_type"
 	cacheResultLabel     = "cache_result"
+	cacheTypeLabel       = "cache_type"
 	connectionErrorLabel = "connection_error"
 	cookieLabel          = "cookie"
 	hasBidsLabel         = "has_bids"
@@ -216,6 +221,11 @@ func NewMetrics(cfg config.PrometheusMetrics, reg *prometheus.Registry, disabled
 		"Count of cookie sync requests to Prebid Server.",
 		[]string{statusLabel})
 
+	metrics.sChainValidation = newCounter(cfg, reg,
+		"schain_validations",
+		"Count of supply chains of the requests validated by Prebid Server, labeled by validation status.",
+		[]string{statusLabel})
+
 	metrics.setUid = newCounter(cfg, reg,
 		"setuid_requests",
 		"Count of set uid requests to Prebid Server.",
@@ -232,6 +242,11 @@ func NewMetrics(cfg config.PrometheusMetrics, reg *prometheus.Registry, disabled
 		[]string{successLabel},
 		cacheWriteTimeBuckets)
 
+	metrics.prebidCacheWriteErrors = newCounter(cfg, reg,
+		"prebidcache_write_errors",
+		"Count of values which couldn't be written to Prebid Cache, after the retries, labeled by type.",
+		[]string{cacheTypeLabel})
+
 	metrics.requests = newCounter(cfg, reg,
 		"requests",
 		"Count of total requests to Prebid Server labeled by type and status.",
@@ -368,6 +383,13 @@ func NewMetrics(cfg config.PrometheusMetrics, reg *prometheus.Registry, disabled
 			"Count of total bidder requests with a scrubbed buyeruid due to a privacy policy",
 			[]string{adapterLabel})
 	}
+
+	// not preloaded, only the few bidders supporting Protected Audience return auction configs
+	metrics.adapterFledgeAuctionConfigs = newCounter(cfg, reg,
+		"adapter_fledge_auction_configs",
+		"Count of auction configs returned by the bidders for an interest group auction, labeled by adapter and validation status.",
+		[]string{adapterLabel, statusLabel})
+
 	if !metrics.metricsDisabled.AdapterGDPRRequestBlocked {
 		metrics.adapterGDPRBlockedRequests = newCounter(cfg, reg,
 			"adapter_gdpr_requests_blocked",
@@ -394,6 +416,10 @@ func NewMetrics(cfg config.PrometheusMetrics, reg *prometheus.Registry, disabled
 		"gvl_requests",
 		"Count number of times GVL list is fetched")
 
+	metrics.storedReqVersionFallbacks = newCounterWithoutLabels(cfg, reg,
+		"stored_request_version_fallbacks",
+		"Count of auctions served the current Stored Request data because the rolled out version could not be fetched")
+
 	metrics.adapterBids = newCounter(cfg, reg,
 		"adapter_bids",
 		"Count of bids labeled by adapter and markup delivery type (adm or nurl).",
@@ -758,6 +784,10 @@ func (m *Metrics) RecordGvlListRequest() {
 	m.gvlListRequests.Inc()
 }
 
+func (m *Metrics) RecordStoredRequestVersionFallback() {
+	m.storedReqVersionFallbacks.Inc()
+}
+
 func (m *Metrics) RecordImps(labels metrics.ImpLabels) {
 	m.impressions.With(prometheus.Labels{
 		isBannerLabel: strconv.FormatBool(labels.BannerImps),
@@ -939,6 +969,12 @@ func (m *Metrics) RecordCookieSync(status metrics.CookieSyncStatus) {
 	}).Inc()
 }
 
+func (m *Metrics) RecordSChainValidation(status metrics.SChainValidationStatus) {
+	m.sChainValidation.With(prometheus.Labels{
+		statusLabel: string(status),
+	}).Inc()
+}
+
 func (m *Metrics) RecordSyncerRequest(key string, status metrics.SyncerCookieSyncStatus) {
 	m.syncerRequests.With(prometheus.Labels{
 		syncerLabel: key,
@@ -983,6 +1019,12 @@ func (m *Metrics) RecordPrebidCacheRequestTime(success bool, length time.Duratio
 	}).Observe(length.Seconds())
 }
 
+func (m *Metrics) RecordPrebidCacheWriteError(cacheType metrics.PrebidCacheType) {
+	m.prebidCacheWriteErrors.With(prometheus.Labels{
+		cacheTypeLabel: string(cacheType),
+	}).Inc()
+}
+
 func (m *Metrics) RecordRequestQueueTime(success bool, requestType metrics.RequestType, length time.Duration) {
 	successLabelFormatted := requestRejectLabel
 	if success {
@@ -1044,6 +1086,13 @@ func (m *Metrics) RecordAdapterBuyerUIDScrubbed(adapterName openrtb_ext.BidderNa
 	}).Inc()
 }
 
+func (m *Metrics) RecordAdapterFledgeAuctionConfigs(adapterName openrtb_ext.BidderName, status metrics.FledgeAuctionConfigStatus, count int) {
+	m.adapterFledgeAuctionConfigs.With(prometheus.Labels{
+		adapterLabel: strings.ToLower(string(adapterName)),
+		statusLabel:  string(status),
+	}).Add(float64(count))
+}
+
 func (m *Metrics) RecordAdapterGDPRRequestBlocked(adapterName openrtb_ext.BidderName) {
 	if m.metricsDisabled.AdapterGDPRRequestBlocked {
 		return
@@ -1390,6 +1390,17 @@ func TestPrebidCacheRequestTimeMetric(t *testing.T) {
 	assertHistogram(t, "Error", errorResult, errorExpectedCount, errorExpectedSum)
 }
 
+func TestPrebidCacheWriteErrorMetric(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordPrebidCacheWriteError(metrics.PrebidCacheJSON)
+	m.RecordPrebidCacheWriteError(metrics.PrebidCacheXML)
+	m.RecordPrebidCacheWriteError(metrics.PrebidCacheXML)
+
+	assertCounterVecValue(t, "", "json", m.prebidCacheWriteErrors, float64(1), prometheus.Labels{cacheTypeLabel: string(metrics.PrebidCacheJSON)})
+	assertCounterVecValue(t, "", "xml", m.prebidCacheWriteErrors, float64(2), prometheus.Labels{cacheTypeLabel: string(metrics.PrebidCacheXML)})
+}
+
 func TestRecordRequestQueueTimeMetric(t *testing.T) {
 	performTest := func(m *Metrics, requestStatus bool, requestType metrics.RequestType, timeInSec float64) {
 		m.RecordRequestQueueTime(requestStatus, requestType, time.Duration(timeInSec*float64(time.Second)))
@@ -2054,6 +2065,37 @@ func assertHistogram(t *testing.T, name string, histogram dto.Histogram, expecte
 	assert.Equal(t, expectedSum, histogram.GetSampleSum(), name+":sum")
 }
 
+func TestRecordAdapterFledgeAuctionConfigs(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordAdapterFledgeAuctionConfigs(openrtb_ext.BidderName("AnyName"), metrics.FledgeAuctionConfigAccepted, 2)
+	m.RecordAdapterFledgeAuctionConfigs(openrtb_ext.BidderName("AnyName"), metrics.FledgeAuctionConfigRejected, 1)
+
+	assertCounterVecValue(t, "", "accepted", m.adapterFledgeAuctionConfigs, float64(2), prometheus.Labels{
+		adapterLabel: "anyname",
+		statusLabel:  string(metrics.FledgeAuctionConfigAccepted),
+	})
+	assertCounterVecValue(t, "", "rejected", m.adapterFledgeAuctionConfigs, float64(1), prometheus.Labels{
+		adapterLabel: "anyname",
+		statusLabel:  string(metrics.FledgeAuctionConfigRejected),
+	})
+}
+
+func TestRecordSChainValidation(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordSChainValidation(metrics.SChainRejected)
+	m.RecordSChainValidation(metrics.SChainMismatchedSeller)
+	m.RecordSChainValidation(metrics.SChainMismatchedSeller)
+
+	assertCounterVecValue(t, "", "rejected", m.sChainValidation, float64(1), prometheus.Labels{
+		statusLabel: string(metrics.SChainRejected),
+	})
+	assertCounterVecValue(t, "", "mismatched_seller", m.sChainValidation, float64(2), prometheus.Labels{
+		statusLabel: string(metrics.SChainMismatchedSeller),
+	})
+}
+
 func TestRecordAdapterBuyerUIDScrubbed(t *testing.T) {
 
 	tests := []struct {
@@ -2166,6 +2208,14 @@ func TestRecordGvlListRequest(t *testing.T) {
 	assertCounterValue(t, "Record instance of fetched GVL list", "success", m.gvlListRequests, 1.00)
 }
 
+func TestRecordStoredRequestVersionFallback(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordStoredRequestVersionFallback()
+
+	assertCounterValue(t, "", "stored_request_version_fallbacks", m.storedReqVersionFallbacks, 1.00)
+}
+
 func TestRecordAdsCertReqMetric(t *testing.T) {
 	testCases := []struct {
 		description                  string
@@ -2,6 +2,8 @@ package modules
 
 import (
 	fiftyonedegreesDevicedetection "github.com/prebid/prebid-server/v3/modules/fiftyonedegrees/devicedetection"
+	prebidCreativevalidation "github.com/prebid/prebid-server/v3/modules/prebid/creativevalidation"
+	prebidFpdenrichment "github.com/prebid/prebid-server/v3/modules/prebid/fpdenrichment"
 	prebidOrtb2blocking "github.com/prebid/prebid-server/v3/modules/prebid/ortb2blocking"
 	prebidRulesengine "github.com/prebid/prebid-server/v3/modules/prebid/rulesengine"
 	pubmaticOpenwrap "github.com/prebid/prebid-server/v3/modules/pubmatic/openwrap"
@@ -16,8 +18,10 @@ func builders() ModuleBuilders {
 			"devicedetection": fiftyonedegreesDevicedetection.Builder,
 		},
 		"prebid": {
-			"ortb2blocking": prebidOrtb2blocking.Builder,
-			"rulesengine":   prebidRulesengine.Builder,
+			"creativevalidation": prebidCreativevalidation.Builder,
+			"fpdenrichment":      prebidFpdenrichment.Builder,
+			"ortb2blocking":      prebidOrtb2blocking.Builder,
+			"rulesengine":        prebidRulesengine.Builder,
 		},
 		"scope3": {
 			"rtd": scope3Rtd.Builder,
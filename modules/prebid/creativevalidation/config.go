@@ -0,0 +1,113 @@
+package creativevalidation
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+const (
+	modeEnforce = "enforce"
+	modeWarn    = "warn"
+	modeSkip    = "skip"
+)
+
+// moduleConfig is the module config. The host-level config provides the defaults, accounts may override
+// the check modes and the minimum VAST version through the account-level module config.
+type moduleConfig struct {
+	VAST   vastConfig   `json:"vast"`
+	Native checkConfig  `json:"native"`
+	Markup markupConfig `json:"markup"`
+}
+
+type checkConfig struct {
+	Mode string `json:"mode"`
+}
+
+type vastConfig struct {
+	Mode string `json:"mode"`
+	// MinVersion is the lowest VAST version accepted, e.g. "3.0". Empty accepts any version.
+	MinVersion string `json:"min_version"`
+
+	minVersion vastVersion
+}
+
+type markupConfig struct {
+	Mode string `json:"mode"`
+	// DisallowedPatterns are regular expressions rejected in the markup in addition to the built-in
+	// auto-redirect and document.write patterns. They can only be set in the host-level config.
+	DisallowedPatterns []string `json:"disallowed_patterns"`
+
+	disallowedPatterns []*regexp.Regexp
+}
+
+func newConfig(data json.RawMessage) (moduleConfig, error) {
+	var cfg moduleConfig
+	if len(data) != 0 {
+		if err := jsonutil.UnmarshalValid(data, &cfg); err != nil {
+			return cfg, fmt.Errorf("failed to parse config: %s", err)
+		}
+	}
+
+	for _, pattern := range cfg.Markup.DisallowedPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return cfg, fmt.Errorf("markup.disallowed_patterns has an invalid pattern %q: %s", pattern, err)
+		}
+		cfg.Markup.disallowedPatterns = append(cfg.Markup.disallowedPatterns, re)
+	}
+
+	return cfg, cfg.validate()
+}
+
+// forAccount applies the account-level module config over the host-level config
+func (cfg moduleConfig) forAccount(data json.RawMessage) (moduleConfig, error) {
+	if len(data) == 0 {
+		return cfg, nil
+	}
+
+	accountCfg := cfg
+	if err := jsonutil.UnmarshalValid(data, &accountCfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse account config: %s", err)
+	}
+	accountCfg.Markup.DisallowedPatterns = cfg.Markup.DisallowedPatterns
+	accountCfg.Markup.disallowedPatterns = cfg.Markup.disallowedPatterns
+
+	if err := accountCfg.validate(); err != nil {
+		return cfg, fmt.Errorf("invalid account config: %s", err)
+	}
+	return accountCfg, nil
+}
+
+func (cfg *moduleConfig) validate() error {
+	// the markup checks are heuristic and default to warn, the other checks default to enforce
+	for _, m := range []struct {
+		name        string
+		mode        *string
+		defaultMode string
+	}{
+		{"vast.mode", &cfg.VAST.Mode, modeEnforce},
+		{"native.mode", &cfg.Native.Mode, modeEnforce},
+		{"markup.mode", &cfg.Markup.Mode, modeWarn},
+	} {
+		switch *m.mode {
+		case "":
+			*m.mode = m.defaultMode
+		case modeEnforce, modeWarn, modeSkip:
+		default:
+			return fmt.Errorf("%s must be %q, %q or %q", m.name, modeEnforce, modeWarn, modeSkip)
+		}
+	}
+
+	cfg.VAST.minVersion = vastVersion{}
+	if cfg.VAST.MinVersion != "" {
+		version, err := parseVASTVersion(cfg.VAST.MinVersion)
+		if err != nil {
+			return fmt.Errorf("vast.min_version: %s", err)
+		}
+		cfg.VAST.minVersion = version
+	}
+	return nil
+}
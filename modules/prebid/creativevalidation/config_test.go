@@ -0,0 +1,79 @@
+package creativevalidation
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewConfig(t *testing.T) {
+	testCases := []struct {
+		description   string
+		data          json.RawMessage
+		expectedModes [3]string
+		expectedMin   vastVersion
+		expectedError string
+	}{
+		{
+			description:   "empty config enforces the vast and native checks and warns on the markup",
+			expectedModes: [3]string{modeEnforce, modeEnforce, modeWarn},
+		},
+		{
+			description:   "modes and min version",
+			data:          json.RawMessage(`{"vast":{"mode":"warn","min_version":"3"},"native":{"mode":"skip"},"markup":{"disallowed_patterns":["evil\\.js"]}}`),
+			expectedModes: [3]string{modeWarn, modeSkip, modeWarn},
+			expectedMin:   vastVersion{major: 3},
+		},
+		{
+			description:   "invalid mode",
+			data:          json.RawMessage(`{"native":{"mode":"block"}}`),
+			expectedError: `native.mode must be "enforce", "warn" or "skip"`,
+		},
+		{
+			description:   "invalid min version",
+			data:          json.RawMessage(`{"vast":{"min_version":"three"}}`),
+			expectedError: `vast.min_version: invalid VAST version "three"`,
+		},
+		{
+			description:   "invalid pattern",
+			data:          json.RawMessage(`{"markup":{"disallowed_patterns":["("]}}`),
+			expectedError: `markup.disallowed_patterns has an invalid pattern "("`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			cfg, err := newConfig(test.data)
+			if test.expectedError != "" {
+				assert.ErrorContains(t, err, test.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedModes, [3]string{cfg.VAST.Mode, cfg.Native.Mode, cfg.Markup.Mode})
+			assert.Equal(t, test.expectedMin, cfg.VAST.minVersion)
+			assert.Len(t, cfg.Markup.disallowedPatterns, len(cfg.Markup.DisallowedPatterns))
+		})
+	}
+}
+
+func TestConfigForAccount(t *testing.T) {
+	hostCfg, err := newConfig(json.RawMessage(`{"vast":{"min_version":"2.0"},"markup":{"mode":"warn","disallowed_patterns":["evil\\.js"]}}`))
+	require.NoError(t, err)
+
+	cfg, err := hostCfg.forAccount(nil)
+	require.NoError(t, err)
+	assert.Equal(t, hostCfg, cfg)
+
+	cfg, err = hostCfg.forAccount(json.RawMessage(`{"vast":{"min_version":"4.1"},"native":{"mode":"warn"},"markup":{"disallowed_patterns":[]}}`))
+	require.NoError(t, err)
+	assert.Equal(t, vastVersion{major: 4, minor: 1}, cfg.VAST.minVersion)
+	assert.Equal(t, modeEnforce, cfg.VAST.Mode)
+	assert.Equal(t, modeWarn, cfg.Native.Mode)
+	assert.Equal(t, modeWarn, cfg.Markup.Mode)
+	assert.Equal(t, hostCfg.Markup.disallowedPatterns, cfg.Markup.disallowedPatterns, "disallowed patterns are host-level only")
+
+	_, err = hostCfg.forAccount(json.RawMessage(`{"vast":{"mode":"off"}}`))
+	assert.EqualError(t, err, `invalid account config: vast.mode must be "enforce", "warn" or "skip"`)
+}
@@ -0,0 +1,39 @@
+package creativevalidation
+
+import (
+	nativeRequests "github.com/prebid/openrtb/v20/native1/request"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+)
+
+// impConstraints holds what the bids for an imp are validated against
+type impConstraints struct {
+	video  *openrtb2.Video
+	native *nativeRequests.Request
+}
+
+// handleBidderRequestHook keeps the video objects and native templates of the imps sent to the bidder
+// in the module context, keyed by bidder, for validating the bids at the raw bidder response stage
+func handleBidderRequestHook(
+	payload hookstage.BidderRequestPayload,
+) (result hookstage.HookResult[hookstage.BidderRequestPayload], err error) {
+	if payload.Request == nil || payload.Request.BidRequest == nil {
+		return result, hookexecution.NewFailure("payload contains a nil bid request")
+	}
+
+	constraints := make(map[string]impConstraints, len(payload.Request.Imp))
+	for _, imp := range payload.Request.Imp {
+		c := impConstraints{video: imp.Video}
+		if imp.Native != nil {
+			// an unreadable template is left to the native request validation, assets are not checked then
+			if template, err := parseNativeRequest(imp.Native.Request); err == nil {
+				c.native = template
+			}
+		}
+		constraints[imp.ID] = c
+	}
+
+	result.ModuleContext = hookstage.ModuleContext{payload.Bidder: constraints}
+	return result, nil
+}
@@ -0,0 +1,149 @@
+package creativevalidation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v3/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+)
+
+const (
+	validateCreativesTag = "validate_creatives"
+	reasonsAnalyticKey   = "reasons"
+)
+
+func handleRawBidderResponseHook(
+	cfg moduleConfig,
+	metrics metricsRecorder,
+	payload hookstage.RawBidderResponsePayload,
+	moduleCtx hookstage.ModuleContext,
+) (result hookstage.HookResult[hookstage.RawBidderResponsePayload], err error) {
+	if payload.BidderResponse == nil {
+		return result, nil
+	}
+
+	bidder := payload.Bidder
+	var constraints map[string]impConstraints
+	if val, ok := moduleCtx[bidder]; ok {
+		if constraints, ok = val.(map[string]impConstraints); !ok {
+			return result, hookexecution.NewFailure("could not cast imp constraints for bidder `%s`, module context has incorrect data", bidder)
+		}
+	}
+
+	result.AnalyticsTags = hookanalytics.Analytics{
+		Activities: []hookanalytics.Activity{{Name: validateCreativesTag, Status: hookanalytics.ActivityStatusSuccess}},
+	}
+
+	seatNonBid := openrtb_ext.SeatNonBidBuilder{}
+	allowedBids := make([]*adapters.TypedBid, 0, len(payload.BidderResponse.Bids))
+	for _, bid := range payload.BidderResponse.Bids {
+		if bid == nil || bid.Bid == nil {
+			allowedBids = append(allowedBids, bid)
+			continue
+		}
+
+		rejections, warnings := validateBid(cfg, bid, constraints[bid.Bid.ImpID])
+		for _, f := range rejections {
+			metrics.recordFailure(bidder, f.reason, actionReject)
+		}
+		for _, f := range warnings {
+			metrics.recordFailure(bidder, f.reason, actionWarn)
+		}
+
+		if len(warnings) > 0 {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Bid %s from bidder %s failed creative validation: %s", bid.Bid.ID, bidder, joinMessages(warnings)))
+		}
+
+		if len(rejections) == 0 {
+			if len(warnings) > 0 {
+				addAnalyticResult(&result, hookanalytics.ResultStatusAllow, bidder, bid.Bid.ImpID, warnings)
+			}
+			allowedBids = append(allowedBids, bid)
+			continue
+		}
+
+		addAnalyticResult(&result, hookanalytics.ResultStatusBlock, bidder, bid.Bid.ImpID, append(rejections, warnings...))
+		result.DebugMessages = append(result.DebugMessages, fmt.Sprintf("Bid %s from bidder %s has been rejected, failed checks: %s", bid.Bid.ID, bidder, joinMessages(rejections)))
+		seatNonBid.AddBid(openrtb_ext.NewNonBid(openrtb_ext.NonBidParams{
+			Bid:            bid.Bid,
+			NonBidReason:   int(rejections[0].reason.nonBidReason),
+			DealPriority:   bid.DealPriority,
+			BidMeta:        bid.BidMeta,
+			BidType:        bid.BidType,
+			BidVideo:       bid.BidVideo,
+			OriginalBidCur: payload.BidderResponse.Currency,
+		}), payload.BidderResponse.BidderAlias.String())
+	}
+
+	if len(payload.BidderResponse.Bids) != len(allowedBids) {
+		result.ChangeSet.RawBidderResponse().Bids().UpdateBids(allowedBids)
+		result.SeatNonBid = seatNonBid
+	}
+
+	return result, nil
+}
+
+// validateBid runs the checks applying to the bid, the failures of enforced checks reject the bid
+// while the failures of checks in warn mode are only reported
+func validateBid(cfg moduleConfig, bid *adapters.TypedBid, constraints impConstraints) (rejections, warnings []failure) {
+	collect := func(mode string, failures []failure) {
+		if mode == modeEnforce {
+			rejections = append(rejections, failures...)
+		} else {
+			warnings = append(warnings, failures...)
+		}
+	}
+
+	adm := strings.TrimSpace(bid.Bid.AdM)
+	if adm == "" {
+		// creatives served through the nurl are not available for validation
+		return nil, nil
+	}
+
+	switch bid.BidType {
+	case openrtb_ext.BidTypeVideo:
+		if cfg.VAST.Mode != modeSkip {
+			collect(cfg.VAST.Mode, validateVAST(adm, constraints.video, cfg.VAST.minVersion))
+		}
+	case openrtb_ext.BidTypeNative:
+		if cfg.Native.Mode != modeSkip && constraints.native != nil {
+			collect(cfg.Native.Mode, validateNativeAssets(adm, constraints.native))
+		}
+	}
+
+	// VAST documents are validated as such, the markup patterns only apply to HTML creatives
+	if bid.BidType != openrtb_ext.BidTypeNative && cfg.Markup.Mode != modeSkip && !isVASTMarkup(adm) {
+		collect(cfg.Markup.Mode, validateMarkup(adm, cfg.Markup.disallowedPatterns))
+	}
+	return rejections, warnings
+}
+
+func joinMessages(failures []failure) string {
+	messages := make([]string, 0, len(failures))
+	for _, f := range failures {
+		messages = append(messages, f.message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+func addAnalyticResult(
+	result *hookstage.HookResult[hookstage.RawBidderResponsePayload],
+	status hookanalytics.ResultStatus,
+	bidder, impID string,
+	failures []failure,
+) {
+	reasons := make([]string, 0, len(failures))
+	for _, f := range failures {
+		reasons = append(reasons, f.reason.label)
+	}
+
+	result.AnalyticsTags.Activities[0].Results = append(result.AnalyticsTags.Activities[0].Results, hookanalytics.Result{
+		Status:    status,
+		Values:    map[string]interface{}{reasonsAnalyticKey: reasons},
+		AppliedTo: hookanalytics.AppliedTo{Bidder: bidder, ImpIds: []string{impID}},
+	})
+}
@@ -0,0 +1,34 @@
+package creativevalidation
+
+import (
+	"fmt"
+	"regexp"
+)
+
+type markupPattern struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+// builtinMarkupPatterns flag markup taking over the page instead of rendering in its slot
+var builtinMarkupPatterns = []markupPattern{
+	{name: "auto-redirect", pattern: regexp.MustCompile(`(?i)\b(window|top|self|parent|document)\s*\.\s*location(\s*\.\s*href)?\s*=[^=]`)},
+	{name: "auto-redirect", pattern: regexp.MustCompile(`(?i)\b(window|top|self|parent|document)\s*\.\s*location\s*\.\s*(assign|replace)\s*\(`)},
+	{name: "auto-redirect", pattern: regexp.MustCompile(`(?i)<meta[^>]+http-equiv\s*=\s*["']?refresh`)},
+	{name: "document.write", pattern: regexp.MustCompile(`(?i)\bdocument\s*\.\s*write(ln)?\s*\(`)},
+}
+
+// validateMarkup returns a failure for the first built-in or host-level disallowed pattern found in the markup
+func validateMarkup(adm string, disallowedPatterns []*regexp.Regexp) []failure {
+	for _, p := range builtinMarkupPatterns {
+		if p.pattern.MatchString(adm) {
+			return []failure{{reason: reasonDisallowedMarkup, message: fmt.Sprintf("%s pattern found in the markup", p.name)}}
+		}
+	}
+	for _, pattern := range disallowedPatterns {
+		if pattern.MatchString(adm) {
+			return []failure{{reason: reasonDisallowedMarkup, message: fmt.Sprintf("markup matches the disallowed pattern %s", pattern)}}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package creativevalidation
+
+import (
+	"errors"
+
+	metrics_cfg "github.com/prebid/prebid-server/v3/metrics/config"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	actionReject = "reject"
+	actionWarn   = "warn"
+)
+
+// metricsRecorder counts the failed checks per bidder, reason and action taken
+type metricsRecorder interface {
+	recordFailure(bidder string, reason failureReason, action string)
+}
+
+type nilMetrics struct{}
+
+func (nilMetrics) recordFailure(string, failureReason, string) {}
+
+type prometheusMetrics struct {
+	failures *prometheus.CounterVec
+}
+
+func (m prometheusMetrics) recordFailure(bidder string, reason failureReason, action string) {
+	m.failures.With(prometheus.Labels{
+		"bidder": bidder,
+		"reason": reason.label,
+		"action": action,
+	}).Inc()
+}
+
+// newMetrics registers the module metrics in the Prometheus registry of the host when there is one
+func newMetrics(deps moduledeps.ModuleDeps) (metricsRecorder, error) {
+	if deps.MetricsCfg == nil || deps.MetricsRegistry == nil {
+		return nilMetrics{}, nil
+	}
+	registry, ok := deps.MetricsRegistry[metrics_cfg.PrometheusRegistry].(*prometheus.Registry)
+	if !ok || registry == nil {
+		return nilMetrics{}, nil
+	}
+
+	failures := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: deps.MetricsCfg.Prometheus.Namespace,
+		Subsystem: deps.MetricsCfg.Prometheus.Subsystem,
+		Name:      "creative_validation_failures",
+		Help:      "Count of bids failing a creative validation check by bidder, reason and action taken.",
+	}, []string{"bidder", "reason", "action"})
+
+	if err := registry.Register(failures); err != nil {
+		var alreadyRegistered prometheus.AlreadyRegisteredError
+		if !errors.As(err, &alreadyRegistered) {
+			return nil, err
+		}
+		failures = alreadyRegistered.ExistingCollector.(*prometheus.CounterVec)
+	}
+	return prometheusMetrics{failures: failures}, nil
+}
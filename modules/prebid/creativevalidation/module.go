@@ -0,0 +1,60 @@
+// Package creativevalidation validates the creatives of bids at the raw bidder response stage: VAST markup
+// against imp.video, native assets against the request template and markup against disallowed patterns.
+package creativevalidation
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/prebid/prebid-server/v3/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+)
+
+var (
+	_ hookstage.BidderRequest     = (*Module)(nil)
+	_ hookstage.RawBidderResponse = (*Module)(nil)
+)
+
+func Builder(data json.RawMessage, deps moduledeps.ModuleDeps) (interface{}, error) {
+	cfg, err := newConfig(data)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics, err := newMetrics(deps)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Module{cfg: cfg, metrics: metrics}, nil
+}
+
+type Module struct {
+	cfg     moduleConfig
+	metrics metricsRecorder
+}
+
+// HandleBidderRequestHook keeps the imp constraints the bids of the bidder are validated against.
+func (m *Module) HandleBidderRequestHook(
+	_ context.Context,
+	_ hookstage.ModuleInvocationContext,
+	payload hookstage.BidderRequestPayload,
+) (hookstage.HookResult[hookstage.BidderRequestPayload], error) {
+	return handleBidderRequestHook(payload)
+}
+
+// HandleRawBidderResponseHook rejects the bids failing an enforced creative check and reports
+// the ones failing a check in warn mode.
+func (m *Module) HandleRawBidderResponseHook(
+	_ context.Context,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.RawBidderResponsePayload,
+) (hookstage.HookResult[hookstage.RawBidderResponsePayload], error) {
+	cfg, err := m.cfg.forAccount(miCtx.AccountConfig)
+	if err != nil {
+		return hookstage.HookResult[hookstage.RawBidderResponsePayload]{}, hookexecution.NewFailure("%s", err)
+	}
+
+	return handleRawBidderResponseHook(cfg, m.metrics, payload, miCtx.ModuleContext)
+}
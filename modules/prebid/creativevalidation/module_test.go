@@ -0,0 +1,232 @@
+package creativevalidation
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v3/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	metrics_cfg "github.com/prebid/prebid-server/v3/metrics/config"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+	"github.com/prebid/prebid-server/v3/modules/pubmatic/openwrap/models/nbr"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordedFailure struct {
+	bidder string
+	reason string
+	action string
+}
+
+type fakeMetrics struct {
+	failures []recordedFailure
+}
+
+func (m *fakeMetrics) recordFailure(bidder string, reason failureReason, action string) {
+	m.failures = append(m.failures, recordedFailure{bidder: bidder, reason: reason.label, action: action})
+}
+
+func TestBuilder(t *testing.T) {
+	_, err := Builder(json.RawMessage(`{"vast":{"mode":"block"}}`), moduledeps.ModuleDeps{})
+	assert.EqualError(t, err, `vast.mode must be "enforce", "warn" or "skip"`)
+
+	module, err := Builder(nil, moduledeps.ModuleDeps{})
+	require.NoError(t, err)
+	assert.Equal(t, nilMetrics{}, module.(*Module).metrics)
+
+	registry := prometheus.NewRegistry()
+	deps := moduledeps.ModuleDeps{
+		MetricsCfg:      &config.Metrics{Prometheus: config.PrometheusMetrics{Namespace: "pbs"}},
+		MetricsRegistry: metrics_cfg.MetricsRegistry{metrics_cfg.PrometheusRegistry: registry},
+	}
+	first, err := Builder(nil, deps)
+	require.NoError(t, err)
+	second, err := Builder(nil, deps)
+	require.NoError(t, err, "building the module again must reuse the registered metrics")
+	assert.Same(t, first.(*Module).metrics.(prometheusMetrics).failures, second.(*Module).metrics.(prometheusMetrics).failures)
+
+	first.(*Module).metrics.recordFailure("appnexus", reasonInvalidVAST, actionReject)
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	require.Len(t, families, 1)
+	assert.Equal(t, "pbs_creative_validation_failures", families[0].GetName())
+}
+
+func TestHandleBidderRequestHook(t *testing.T) {
+	video := &openrtb2.Video{MIMEs: []string{"video/mp4"}}
+	payload := hookstage.BidderRequestPayload{
+		Bidder: "appnexus",
+		Request: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{
+			{ID: "video", Video: video},
+			{ID: "native", Native: &openrtb2.Native{Request: `{"assets":[{"id":1,"title":{"len":20}}]}`}},
+			{ID: "invalid-native", Native: &openrtb2.Native{Request: `assets`}},
+		}}},
+	}
+
+	module := &Module{metrics: nilMetrics{}}
+	result, err := module.HandleBidderRequestHook(context.Background(), hookstage.ModuleInvocationContext{}, payload)
+	require.NoError(t, err)
+
+	constraints, ok := result.ModuleContext["appnexus"].(map[string]impConstraints)
+	require.True(t, ok)
+	assert.Len(t, constraints, 3)
+	assert.Same(t, video, constraints["video"].video)
+	require.NotNil(t, constraints["native"].native)
+	assert.Equal(t, int64(20), constraints["native"].native.Assets[0].Title.Len)
+	assert.Nil(t, constraints["invalid-native"].native)
+
+	_, err = module.HandleBidderRequestHook(context.Background(), hookstage.ModuleInvocationContext{}, hookstage.BidderRequestPayload{Bidder: "appnexus"})
+	assert.Error(t, err)
+}
+
+func TestHandleRawBidderResponseHook(t *testing.T) {
+	template, err := parseNativeRequest(`{"assets":[{"id":1,"required":1,"title":{"len":20}}]}`)
+	require.NoError(t, err)
+	moduleCtx := hookstage.ModuleContext{"appnexus": map[string]impConstraints{
+		"video":  {video: &openrtb2.Video{MIMEs: []string{"video/3gpp"}}},
+		"native": {native: template},
+	}}
+
+	validBanner := &adapters.TypedBid{BidType: openrtb_ext.BidTypeBanner, Bid: &openrtb2.Bid{ID: "b1", ImpID: "banner", AdM: `<div>ad</div>`}}
+	redirectBanner := &adapters.TypedBid{BidType: openrtb_ext.BidTypeBanner, Bid: &openrtb2.Bid{ID: "b2", ImpID: "banner", AdM: `<script>window.top.location.href = "https://example.com";</script>`}}
+	writeBanner := &adapters.TypedBid{BidType: openrtb_ext.BidTypeBanner, Bid: &openrtb2.Bid{ID: "b3", ImpID: "banner", AdM: `<script>document.write("<img>")</script>`}}
+	patternBanner := &adapters.TypedBid{BidType: openrtb_ext.BidTypeBanner, Bid: &openrtb2.Bid{ID: "b4", ImpID: "banner", AdM: `<script src="https://evil.js"></script>`}}
+	nurlBanner := &adapters.TypedBid{BidType: openrtb_ext.BidTypeBanner, Bid: &openrtb2.Bid{ID: "b5", ImpID: "banner", NURL: "https://example.com/win"}}
+	invalidVideo := &adapters.TypedBid{BidType: openrtb_ext.BidTypeVideo, Bid: &openrtb2.Bid{ID: "v1", ImpID: "video", AdM: `<VAST version="3.0"><Ad>`}}
+	webmVideo := &adapters.TypedBid{BidType: openrtb_ext.BidTypeVideo, Bid: &openrtb2.Bid{ID: "v2", ImpID: "video", AdM: inlineVAST}}
+	scriptVideo := &adapters.TypedBid{BidType: openrtb_ext.BidTypeVideo, Bid: &openrtb2.Bid{ID: "v3", ImpID: "video", AdM: `<VAST version="3.0"><Ad><InLine><Creatives><Creative><Linear><Duration>00:00:15</Duration><MediaFiles><MediaFile type="video/3gpp"><![CDATA[https://cdn.example.com/ad.3gp]]></MediaFile></MediaFiles></Linear></Creative></Creatives><Extensions><Extension><![CDATA[<script>document.write("<img>")</script>]]></Extension></Extensions></InLine></Ad></VAST>`}}
+	invalidNative := &adapters.TypedBid{BidType: openrtb_ext.BidTypeNative, Bid: &openrtb2.Bid{ID: "n1", ImpID: "native", AdM: `{"assets":[{"id":2,"img":{"url":"https://img"}}]}`}}
+
+	testCases := []struct {
+		description          string
+		hostConfig           json.RawMessage
+		accountConfig        json.RawMessage
+		bids                 []*adapters.TypedBid
+		expectedBids         []*adapters.TypedBid
+		expectedNonBidReason []int
+		expectedWarnings     []string
+		expectedDebug        []string
+		expectedMetrics      []recordedFailure
+		expectedError        bool
+	}{
+		{
+			description:  "valid bids kept",
+			bids:         []*adapters.TypedBid{validBanner, nurlBanner},
+			expectedBids: []*adapters.TypedBid{validBanner, nurlBanner},
+		},
+		{
+			description:          "markup rejected",
+			hostConfig:           json.RawMessage(`{"markup":{"mode":"enforce","disallowed_patterns":["evil\\.js"]}}`),
+			bids:                 []*adapters.TypedBid{validBanner, redirectBanner, writeBanner, patternBanner, scriptVideo},
+			expectedBids:         []*adapters.TypedBid{validBanner, scriptVideo},
+			expectedNonBidReason: []int{int(nbr.ResponseRejectedDisallowedMarkup), int(nbr.ResponseRejectedDisallowedMarkup), int(nbr.ResponseRejectedDisallowedMarkup)},
+			expectedDebug: []string{
+				"Bid b2 from bidder appnexus has been rejected, failed checks: auto-redirect pattern found in the markup",
+				"Bid b3 from bidder appnexus has been rejected, failed checks: document.write pattern found in the markup",
+				`Bid b4 from bidder appnexus has been rejected, failed checks: markup matches the disallowed pattern evil\.js`,
+			},
+			expectedMetrics: []recordedFailure{
+				{bidder: "appnexus", reason: "disallowed_markup", action: actionReject},
+				{bidder: "appnexus", reason: "disallowed_markup", action: actionReject},
+				{bidder: "appnexus", reason: "disallowed_markup", action: actionReject},
+			},
+		},
+		{
+			description:  "markup warned by default",
+			bids:         []*adapters.TypedBid{writeBanner, scriptVideo},
+			expectedBids: []*adapters.TypedBid{writeBanner, scriptVideo},
+			expectedWarnings: []string{
+				"Bid b3 from bidder appnexus failed creative validation: document.write pattern found in the markup",
+			},
+			expectedMetrics: []recordedFailure{{bidder: "appnexus", reason: "disallowed_markup", action: actionWarn}},
+		},
+		{
+			description:          "video and native rejected",
+			bids:                 []*adapters.TypedBid{invalidVideo, webmVideo, invalidNative},
+			expectedBids:         []*adapters.TypedBid{},
+			expectedNonBidReason: []int{int(nbr.ResponseRejectedInvalidVAST), int(nbr.ResponseRejectedMediaFileMIME), int(nbr.ResponseRejectedNativeAssets)},
+			expectedDebug: []string{
+				"Bid v1 from bidder appnexus has been rejected, failed checks: XML syntax error on line 1: unexpected EOF",
+				"Bid v2 from bidder appnexus has been rejected, failed checks: no media file type of video/webm, video/MP4 is in imp.video.mimes",
+				"Bid n1 from bidder appnexus has been rejected, failed checks: img asset 2 is not in the request; required title asset 1 is missing",
+			},
+			expectedMetrics: []recordedFailure{
+				{bidder: "appnexus", reason: "invalid_vast", action: actionReject},
+				{bidder: "appnexus", reason: "media_file_mime", action: actionReject},
+				{bidder: "appnexus", reason: "native_assets", action: actionReject},
+				{bidder: "appnexus", reason: "native_assets", action: actionReject},
+			},
+		},
+		{
+			description:   "account warns and skips",
+			accountConfig: json.RawMessage(`{"vast":{"mode":"warn"},"native":{"mode":"skip"}}`),
+			bids:          []*adapters.TypedBid{webmVideo, invalidNative},
+			expectedBids:  []*adapters.TypedBid{webmVideo, invalidNative},
+			expectedWarnings: []string{
+				"Bid v2 from bidder appnexus failed creative validation: no media file type of video/webm, video/MP4 is in imp.video.mimes",
+			},
+			expectedMetrics: []recordedFailure{{bidder: "appnexus", reason: "media_file_mime", action: actionWarn}},
+		},
+		{
+			description:   "invalid account config",
+			accountConfig: json.RawMessage(`{"vast":{"mode":"block"}}`),
+			bids:          []*adapters.TypedBid{validBanner},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			cfg, err := newConfig(test.hostConfig)
+			require.NoError(t, err)
+			metrics := &fakeMetrics{}
+			module := &Module{cfg: cfg, metrics: metrics}
+
+			payload := hookstage.RawBidderResponsePayload{
+				Bidder:         "appnexus",
+				BidderResponse: &adapters.BidderResponse{Bids: test.bids, Currency: "USD", BidderAlias: "appnexus"},
+			}
+			miCtx := hookstage.ModuleInvocationContext{AccountConfig: test.accountConfig, ModuleContext: moduleCtx}
+
+			result, err := module.HandleRawBidderResponseHook(context.Background(), miCtx, payload)
+			if test.expectedError {
+				assert.IsType(t, hookexecution.FailureError{}, err)
+				return
+			}
+			require.NoError(t, err)
+
+			for _, mut := range result.ChangeSet.Mutations() {
+				payload, err = mut.Apply(payload)
+				require.NoError(t, err)
+			}
+			assert.Equal(t, test.expectedBids, payload.BidderResponse.Bids)
+			assert.Equal(t, test.expectedWarnings, result.Warnings)
+			assert.Equal(t, test.expectedDebug, result.DebugMessages)
+			assert.Equal(t, test.expectedMetrics, metrics.failures)
+			assert.Equal(t, hookanalytics.ActivityStatusSuccess, result.AnalyticsTags.Activities[0].Status)
+
+			var nonBidReasons []int
+			for _, nonBid := range result.SeatNonBid["appnexus"] {
+				nonBidReasons = append(nonBidReasons, nonBid.StatusCode)
+			}
+			assert.Equal(t, test.expectedNonBidReason, nonBidReasons)
+		})
+	}
+}
+
+func TestHandleRawBidderResponseHookInvalidModuleContext(t *testing.T) {
+	module := &Module{metrics: nilMetrics{}}
+	payload := hookstage.RawBidderResponsePayload{Bidder: "appnexus", BidderResponse: &adapters.BidderResponse{}}
+	miCtx := hookstage.ModuleInvocationContext{ModuleContext: hookstage.ModuleContext{"appnexus": "invalid"}}
+
+	_, err := module.HandleRawBidderResponseHook(context.Background(), miCtx, payload)
+	assert.EqualError(t, err, "hook execution failed: could not cast imp constraints for bidder `appnexus`, module context has incorrect data")
+}
@@ -0,0 +1,118 @@
+package creativevalidation
+
+import (
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+
+	nativeRequests "github.com/prebid/openrtb/v20/native1/request"
+	nativeResponse "github.com/prebid/openrtb/v20/native1/response"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// parseNativeRequest parses imp.native.request, which may be wrapped in a "native" object by native 1.0 requests
+func parseNativeRequest(request string) (*nativeRequests.Request, error) {
+	var wrapper struct {
+		Native *nativeRequests.Request `json:"native"`
+	}
+	if err := jsonutil.UnmarshalValid(json.RawMessage(request), &wrapper); err == nil && wrapper.Native != nil {
+		return wrapper.Native, nil
+	}
+
+	var template nativeRequests.Request
+	if err := jsonutil.UnmarshalValid(json.RawMessage(request), &template); err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func parseNativeResponse(adm string) (*nativeResponse.Response, error) {
+	var wrapper struct {
+		Native *nativeResponse.Response `json:"native"`
+	}
+	if err := jsonutil.UnmarshalValid(json.RawMessage(adm), &wrapper); err == nil && wrapper.Native != nil {
+		return wrapper.Native, nil
+	}
+
+	var markup nativeResponse.Response
+	if err := jsonutil.UnmarshalValid(json.RawMessage(adm), &markup); err != nil {
+		return nil, err
+	}
+	return &markup, nil
+}
+
+// validateNativeAssets checks the assets of the native markup against the request template: every asset
+// must be requested with the same type, titles must fit the requested length and required assets must be present
+func validateNativeAssets(adm string, template *nativeRequests.Request) []failure {
+	markup, err := parseNativeResponse(adm)
+	if err != nil {
+		return []failure{{reason: reasonNativeAssets, message: fmt.Sprintf("markup is not a native response: %s", err)}}
+	}
+
+	requested := make(map[int64]nativeRequests.Asset, len(template.Assets))
+	for _, asset := range template.Assets {
+		requested[asset.ID] = asset
+	}
+
+	var failures []failure
+	returned := make(map[int64]struct{}, len(markup.Assets))
+	for _, asset := range markup.Assets {
+		assetType := nativeResponseAssetType(asset)
+		if assetType == "" {
+			continue
+		}
+		if asset.ID == nil {
+			failures = append(failures, failure{reason: reasonNativeAssets, message: fmt.Sprintf("%s asset has no id", assetType)})
+			continue
+		}
+
+		requestedAsset, ok := requested[*asset.ID]
+		if !ok {
+			failures = append(failures, failure{reason: reasonNativeAssets, message: fmt.Sprintf("%s asset %d is not in the request", assetType, *asset.ID)})
+			continue
+		}
+		if requestedType := nativeRequestAssetType(requestedAsset); requestedType != assetType {
+			failures = append(failures, failure{reason: reasonNativeAssets, message: fmt.Sprintf("asset %d is %s in the response but %s in the request", *asset.ID, assetType, requestedType)})
+			continue
+		}
+		if asset.Title != nil && requestedAsset.Title.Len > 0 && int64(utf8.RuneCountInString(asset.Title.Text)) > requestedAsset.Title.Len {
+			failures = append(failures, failure{reason: reasonNativeAssets, message: fmt.Sprintf("title asset %d is longer than %d characters", *asset.ID, requestedAsset.Title.Len)})
+		}
+		returned[*asset.ID] = struct{}{}
+	}
+
+	for _, asset := range template.Assets {
+		if _, ok := returned[asset.ID]; !ok && asset.Required == 1 {
+			failures = append(failures, failure{reason: reasonNativeAssets, message: fmt.Sprintf("required %s asset %d is missing", nativeRequestAssetType(asset), asset.ID)})
+		}
+	}
+	return failures
+}
+
+func nativeResponseAssetType(asset nativeResponse.Asset) string {
+	switch {
+	case asset.Title != nil:
+		return "title"
+	case asset.Img != nil:
+		return "img"
+	case asset.Video != nil:
+		return "video"
+	case asset.Data != nil:
+		return "data"
+	}
+	return ""
+}
+
+func nativeRequestAssetType(asset nativeRequests.Asset) string {
+	switch {
+	case asset.Title != nil:
+		return "title"
+	case asset.Img != nil:
+		return "img"
+	case asset.Video != nil:
+		return "video"
+	case asset.Data != nil:
+		return "data"
+	}
+	return ""
+}
@@ -0,0 +1,54 @@
+package creativevalidation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateNativeAssets(t *testing.T) {
+	template, err := parseNativeRequest(`{"native":{"ver":"1.0","assets":[
+		{"id":1,"required":1,"title":{"len":10}},
+		{"id":2,"required":1,"img":{"type":3}},
+		{"id":3,"data":{"type":2}}
+	]}}`)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		description      string
+		adm              string
+		expectedFailures []failure
+	}{
+		{
+			description: "valid",
+			adm:         `{"assets":[{"id":1,"title":{"text":"Héllo"}},{"id":2,"img":{"url":"https://img"}},{"link":{"url":"https://click"}}]}`,
+		},
+		{
+			description: "valid wrapped",
+			adm:         `{"native":{"assets":[{"id":1,"title":{"text":"Hello"}},{"id":2,"img":{"url":"https://img"}},{"id":3,"data":{"value":"x"}}]}}`,
+		},
+		{
+			description: "asset mismatches",
+			adm:         `{"assets":[{"id":1,"title":{"text":"A very long title"}},{"id":3,"img":{"url":"https://img"}},{"id":4,"data":{"value":"x"}},{"title":{"text":"x"}}]}`,
+			expectedFailures: []failure{
+				{reason: reasonNativeAssets, message: "title asset 1 is longer than 10 characters"},
+				{reason: reasonNativeAssets, message: "asset 3 is img in the response but data in the request"},
+				{reason: reasonNativeAssets, message: "data asset 4 is not in the request"},
+				{reason: reasonNativeAssets, message: "title asset has no id"},
+				{reason: reasonNativeAssets, message: "required img asset 2 is missing"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expectedFailures, validateNativeAssets(test.adm, template))
+		})
+	}
+
+	failures := validateNativeAssets(`<div>ad</div>`, template)
+	require.Len(t, failures, 1)
+	assert.Equal(t, reasonNativeAssets, failures[0].reason)
+	assert.Contains(t, failures[0].message, "markup is not a native response")
+}
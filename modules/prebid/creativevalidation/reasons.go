@@ -0,0 +1,27 @@
+package creativevalidation
+
+import (
+	"github.com/prebid/openrtb/v20/openrtb3"
+	"github.com/prebid/prebid-server/v3/modules/pubmatic/openwrap/models/nbr"
+)
+
+// failureReason identifies a failed check, the label is used for metrics and analytics
+type failureReason struct {
+	label        string
+	nonBidReason openrtb3.NoBidReason
+}
+
+var (
+	reasonInvalidVAST      = failureReason{label: "invalid_vast", nonBidReason: nbr.ResponseRejectedInvalidVAST}
+	reasonVASTVersion      = failureReason{label: "vast_version", nonBidReason: nbr.ResponseRejectedVASTVersion}
+	reasonMediaFileMIME    = failureReason{label: "media_file_mime", nonBidReason: nbr.ResponseRejectedMediaFileMIME}
+	reasonVideoDuration    = failureReason{label: "video_duration", nonBidReason: nbr.ResponseRejectedVideoDuration}
+	reasonNativeAssets     = failureReason{label: "native_assets", nonBidReason: nbr.ResponseRejectedNativeAssets}
+	reasonDisallowedMarkup = failureReason{label: "disallowed_markup", nonBidReason: nbr.ResponseRejectedDisallowedMarkup}
+)
+
+// failure is a check failed by a bid
+type failure struct {
+	reason  failureReason
+	message string
+}
@@ -0,0 +1,260 @@
+package creativevalidation
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+)
+
+type vastVersion struct {
+	major int
+	minor int
+}
+
+func (v vastVersion) less(other vastVersion) bool {
+	return v.major < other.major || (v.major == other.major && v.minor < other.minor)
+}
+
+func (v vastVersion) String() string {
+	return fmt.Sprintf("%d.%d", v.major, v.minor)
+}
+
+// vastProtocols maps each VAST version to its inline and wrapper protocol
+var vastProtocols = map[vastVersion][2]adcom1.MediaCreativeSubtype{
+	{1, 0}: {adcom1.CreativeVAST10, adcom1.CreativeVAST10Wrapper},
+	{2, 0}: {adcom1.CreativeVAST20, adcom1.CreativeVAST20Wrapper},
+	{3, 0}: {adcom1.CreativeVAST30, adcom1.CreativeVAST30Wrapper},
+	{4, 0}: {adcom1.CreativeVAST40, adcom1.CreativeVAST40Wrapper},
+	{4, 1}: {adcom1.CreativeVAST41, adcom1.CreativeVAST41Wrapper},
+	{4, 2}: {adcom1.CreativeVAST42, adcom1.CreativeVAST42Wrapper},
+}
+
+// parseVASTVersion parses versions of the form "3", "3.0" or "4.1"
+func parseVASTVersion(s string) (vastVersion, error) {
+	major, minor, _ := strings.Cut(strings.TrimSpace(s), ".")
+	if minor == "" {
+		minor = "0"
+	}
+
+	var version vastVersion
+	var err error
+	if version.major, err = strconv.Atoi(major); err != nil || version.major < 1 {
+		return version, fmt.Errorf("invalid VAST version %q", s)
+	}
+	if version.minor, err = strconv.Atoi(minor); err != nil || version.minor < 0 {
+		return version, fmt.Errorf("invalid VAST version %q", s)
+	}
+	return version, nil
+}
+
+// isVASTMarkup reports whether the markup is a VAST document, optionally preceded by an XML declaration
+func isVASTMarkup(adm string) bool {
+	if strings.HasPrefix(adm, "<?xml") {
+		if _, rest, found := strings.Cut(adm, "?>"); found {
+			adm = strings.TrimSpace(rest)
+		}
+	}
+	return len(adm) >= len("<vast") && strings.EqualFold(adm[:len("<vast")], "<vast")
+}
+
+type vastDocument struct {
+	XMLName xml.Name `xml:"VAST"`
+	Version string   `xml:"version,attr"`
+	Ads     []vastAd `xml:"Ad"`
+}
+
+type vastAd struct {
+	InLine  *vastAdBody `xml:"InLine"`
+	Wrapper *vastAdBody `xml:"Wrapper"`
+}
+
+type vastAdBody struct {
+	Creatives []vastCreative `xml:"Creatives>Creative"`
+}
+
+type vastCreative struct {
+	Linear *vastLinear `xml:"Linear"`
+}
+
+type vastLinear struct {
+	Duration   string          `xml:"Duration"`
+	MediaFiles []vastMediaFile `xml:"MediaFiles>MediaFile"`
+}
+
+type vastMediaFile struct {
+	Type string `xml:"type,attr"`
+}
+
+// parseVAST decodes the VAST markup, the whole document must be well-formed XML
+func parseVAST(adm string) (vastDocument, error) {
+	var doc vastDocument
+
+	decoder := xml.NewDecoder(strings.NewReader(adm))
+	// only the structure is validated, the declared encoding of the markup is not relevant
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) { return input, nil }
+	if err := decoder.Decode(&doc); err != nil {
+		return doc, err
+	}
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return doc, err
+		}
+		if _, ok := token.(xml.StartElement); ok {
+			return doc, errors.New("markup has more than one root element")
+		}
+	}
+
+	if len(doc.Ads) == 0 {
+		return doc, errors.New("VAST has no Ad element")
+	}
+	for _, ad := range doc.Ads {
+		if ad.InLine == nil && ad.Wrapper == nil {
+			return doc, errors.New("VAST Ad has neither an InLine nor a Wrapper element")
+		}
+	}
+	return doc, nil
+}
+
+// validateVAST checks the VAST markup of a video bid against the video object of the imp
+func validateVAST(adm string, video *openrtb2.Video, minVersion vastVersion) []failure {
+	doc, err := parseVAST(adm)
+	if err != nil {
+		return []failure{{reason: reasonInvalidVAST, message: err.Error()}}
+	}
+
+	version, err := parseVASTVersion(doc.Version)
+	if err != nil {
+		return []failure{{reason: reasonInvalidVAST, message: err.Error()}}
+	}
+
+	var failures []failure
+	if version.less(minVersion) {
+		failures = append(failures, failure{reason: reasonVASTVersion, message: fmt.Sprintf("VAST %s is lower than the required %s", version, minVersion)})
+	}
+	if video == nil {
+		return failures
+	}
+
+	if f, ok := validateVASTProtocols(doc, version, video.Protocols); !ok {
+		failures = append(failures, f)
+	}
+	if f, ok := validateMediaFiles(doc, video.MIMEs); !ok {
+		failures = append(failures, f)
+	}
+	if f, ok := validateDuration(doc, video.MinDuration, video.MaxDuration); !ok {
+		failures = append(failures, f)
+	}
+	return failures
+}
+
+func validateVASTProtocols(doc vastDocument, version vastVersion, allowed []adcom1.MediaCreativeSubtype) (failure, bool) {
+	protocols, known := vastProtocols[version]
+	if len(allowed) == 0 || !known {
+		return failure{}, true
+	}
+
+	for _, ad := range doc.Ads {
+		protocol := protocols[0]
+		if ad.Wrapper != nil {
+			protocol = protocols[1]
+		}
+		if !slices.Contains(allowed, protocol) {
+			return failure{reason: reasonVASTVersion, message: fmt.Sprintf("VAST %s protocol %d is not in imp.video.protocols", version, protocol)}, false
+		}
+	}
+	return failure{}, true
+}
+
+// validateMediaFiles checks that at least one media file of the inline ads has an allowed mime type
+func validateMediaFiles(doc vastDocument, mimes []string) (failure, bool) {
+	if len(mimes) == 0 {
+		return failure{}, true
+	}
+
+	var types []string
+	for _, linear := range inlineLinears(doc) {
+		for _, mediaFile := range linear.MediaFiles {
+			mediaType := strings.TrimSpace(mediaFile.Type)
+			for _, mime := range mimes {
+				if strings.EqualFold(mediaType, mime) {
+					return failure{}, true
+				}
+			}
+			types = append(types, mediaType)
+		}
+	}
+
+	if len(types) == 0 {
+		// wrappers and non-linear ads carry no media files
+		return failure{}, true
+	}
+	return failure{reason: reasonMediaFileMIME, message: fmt.Sprintf("no media file type of %s is in imp.video.mimes", strings.Join(types, ", "))}, false
+}
+
+func validateDuration(doc vastDocument, minDuration, maxDuration int64) (failure, bool) {
+	if minDuration <= 0 && maxDuration <= 0 {
+		return failure{}, true
+	}
+
+	for _, linear := range inlineLinears(doc) {
+		if linear.Duration == "" {
+			continue
+		}
+		seconds, err := parseVASTDuration(linear.Duration)
+		if err != nil {
+			return failure{reason: reasonInvalidVAST, message: err.Error()}, false
+		}
+		if (minDuration > 0 && seconds < float64(minDuration)) || (maxDuration > 0 && seconds > float64(maxDuration)) {
+			return failure{reason: reasonVideoDuration, message: fmt.Sprintf("duration %s is outside of imp.video.minduration %d and maxduration %d", strings.TrimSpace(linear.Duration), minDuration, maxDuration)}, false
+		}
+	}
+	return failure{}, true
+}
+
+func inlineLinears(doc vastDocument) []*vastLinear {
+	var linears []*vastLinear
+	for _, ad := range doc.Ads {
+		if ad.InLine == nil {
+			continue
+		}
+		for _, creative := range ad.InLine.Creatives {
+			if creative.Linear != nil {
+				linears = append(linears, creative.Linear)
+			}
+		}
+	}
+	return linears
+}
+
+// parseVASTDuration parses a duration of the form HH:MM:SS or HH:MM:SS.mmm into seconds
+func parseVASTDuration(s string) (float64, error) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid VAST duration %q", s)
+	}
+
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil || hours < 0 {
+		return 0, fmt.Errorf("invalid VAST duration %q", s)
+	}
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil || minutes < 0 || minutes > 59 {
+		return 0, fmt.Errorf("invalid VAST duration %q", s)
+	}
+	seconds, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil || !(seconds >= 0 && seconds < 60) {
+		return 0, fmt.Errorf("invalid VAST duration %q", s)
+	}
+	return float64(hours*3600+minutes*60) + seconds, nil
+}
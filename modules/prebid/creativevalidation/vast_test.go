@@ -0,0 +1,154 @@
+package creativevalidation
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/stretchr/testify/assert"
+)
+
+const inlineVAST = `<?xml version="1.0" encoding="ISO-8859-1"?>
+<VAST version="3.0">
+  <Ad id="1">
+    <InLine>
+      <AdSystem>test</AdSystem>
+      <Creatives>
+        <Creative>
+          <Linear>
+            <Duration>00:00:15.500</Duration>
+            <MediaFiles>
+              <MediaFile delivery="progressive" type="video/webm"><![CDATA[https://cdn.example.com/ad.webm]]></MediaFile>
+              <MediaFile delivery="progressive" type=" video/MP4 "><![CDATA[https://cdn.example.com/ad.mp4]]></MediaFile>
+            </MediaFiles>
+          </Linear>
+        </Creative>
+      </Creatives>
+    </InLine>
+  </Ad>
+</VAST>`
+
+const wrapperVAST = `<VAST version="4.0"><Ad><Wrapper><VASTAdTagURI><![CDATA[https://ads.example.com/vast]]></VASTAdTagURI></Wrapper></Ad></VAST>`
+
+func TestValidateVAST(t *testing.T) {
+	video := &openrtb2.Video{
+		MIMEs:       []string{"video/mp4"},
+		MinDuration: 5,
+		MaxDuration: 30,
+		Protocols:   []adcom1.MediaCreativeSubtype{adcom1.CreativeVAST30, adcom1.CreativeVAST40Wrapper},
+	}
+
+	testCases := []struct {
+		description      string
+		adm              string
+		video            *openrtb2.Video
+		minVersion       vastVersion
+		expectedFailures []failure
+	}{
+		{
+			description: "valid inline",
+			adm:         inlineVAST,
+			video:       video,
+			minVersion:  vastVersion{major: 3},
+		},
+		{
+			description: "valid wrapper",
+			adm:         wrapperVAST,
+			video:       video,
+		},
+		{
+			description:      "not well-formed",
+			adm:              `<VAST version="3.0"><Ad><InLine></Ad></VAST>`,
+			video:            video,
+			expectedFailures: []failure{{reason: reasonInvalidVAST, message: "XML syntax error on line 1: element <InLine> closed by </Ad>"}},
+		},
+		{
+			description:      "not VAST",
+			adm:              `<div>ad</div>`,
+			expectedFailures: []failure{{reason: reasonInvalidVAST, message: "expected element type <VAST> but have <div>"}},
+		},
+		{
+			description:      "second root element",
+			adm:              wrapperVAST + `<VAST/>`,
+			expectedFailures: []failure{{reason: reasonInvalidVAST, message: "markup has more than one root element"}},
+		},
+		{
+			description:      "no ad",
+			adm:              `<VAST version="3.0"></VAST>`,
+			expectedFailures: []failure{{reason: reasonInvalidVAST, message: "VAST has no Ad element"}},
+		},
+		{
+			description:      "missing version",
+			adm:              `<VAST><Ad><InLine></InLine></Ad></VAST>`,
+			expectedFailures: []failure{{reason: reasonInvalidVAST, message: `invalid VAST version ""`}},
+		},
+		{
+			description:      "version below minimum",
+			adm:              inlineVAST,
+			minVersion:       vastVersion{major: 4},
+			expectedFailures: []failure{{reason: reasonVASTVersion, message: "VAST 3.0 is lower than the required 4.0"}},
+		},
+		{
+			description: "protocol not allowed, mime and duration mismatch",
+			adm:         inlineVAST,
+			video: &openrtb2.Video{
+				MIMEs:       []string{"video/ogg"},
+				MaxDuration: 10,
+				Protocols:   []adcom1.MediaCreativeSubtype{adcom1.CreativeVAST30Wrapper},
+			},
+			expectedFailures: []failure{
+				{reason: reasonVASTVersion, message: "VAST 3.0 protocol 3 is not in imp.video.protocols"},
+				{reason: reasonMediaFileMIME, message: "no media file type of video/webm, video/MP4 is in imp.video.mimes"},
+				{reason: reasonVideoDuration, message: "duration 00:00:15.500 is outside of imp.video.minduration 0 and maxduration 10"},
+			},
+		},
+		{
+			description:      "wrapper protocol not allowed",
+			adm:              wrapperVAST,
+			video:            &openrtb2.Video{Protocols: []adcom1.MediaCreativeSubtype{adcom1.CreativeVAST40}},
+			expectedFailures: []failure{{reason: reasonVASTVersion, message: "VAST 4.0 protocol 8 is not in imp.video.protocols"}},
+		},
+		{
+			description:      "invalid duration",
+			adm:              `<VAST version="3.0"><Ad><InLine><Creatives><Creative><Linear><Duration>15s</Duration></Linear></Creative></Creatives></InLine></Ad></VAST>`,
+			video:            video,
+			expectedFailures: []failure{{reason: reasonInvalidVAST, message: `invalid VAST duration "15s"`}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expectedFailures, validateVAST(test.adm, test.video, test.minVersion))
+		})
+	}
+}
+
+func TestParseVASTDuration(t *testing.T) {
+	for duration, expected := range map[string]float64{
+		"00:00:30":     30,
+		"01:02:03.250": 3723.25,
+		" 00:01:00 ":   60,
+	} {
+		seconds, err := parseVASTDuration(duration)
+		assert.NoError(t, err, duration)
+		assert.Equal(t, expected, seconds, duration)
+	}
+
+	for _, duration := range []string{"", "30", "00:60:00", "00:00:60", "00:00:NaN", "-1:00:00"} {
+		_, err := parseVASTDuration(duration)
+		assert.Error(t, err, duration)
+	}
+}
+
+func TestIsVASTMarkup(t *testing.T) {
+	for adm, expected := range map[string]bool{
+		inlineVAST:              true,
+		`<vast version="4.0"/>`: true,
+		`<?xml version="1.0"?>`: false,
+		`<div>ad</div>`:         false,
+		`<script>VAST</script>`: false,
+		`<VA`:                   false,
+	} {
+		assert.Equal(t, expected, isVASTMarkup(adm), adm)
+	}
+}
@@ -0,0 +1,127 @@
+package fpdenrichment
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+const (
+	storeTypeFile  = "file"
+	storeTypeRedis = "redis"
+
+	defaultDataName     = "publisher-segments"
+	defaultRedisTimeout = 20
+	allBiddersKey       = "*"
+)
+
+// moduleConfig is the host-level module config
+type moduleConfig struct {
+	Store      storeConfig      `json:"store"`
+	Enrichment enrichmentConfig `json:"enrichment"`
+}
+
+type storeConfig struct {
+	Type  string           `json:"type"`
+	File  fileStoreConfig  `json:"file"`
+	Redis redisStoreConfig `json:"redis"`
+}
+
+type fileStoreConfig struct {
+	Path                   string `json:"path"`
+	RefreshIntervalSeconds int    `json:"refresh_interval_seconds"`
+}
+
+type redisStoreConfig struct {
+	Address   string `json:"address"`
+	Password  string `json:"password"`
+	DB        int    `json:"db"`
+	TimeoutMs int    `json:"timeout_ms"`
+	KeyPrefix string `json:"key_prefix"`
+}
+
+// enrichmentConfig controls how segments are added to requests. Accounts may override it
+// through the account-level module config.
+type enrichmentConfig struct {
+	Enabled  *bool  `json:"enabled"`
+	DataName string `json:"data_name"`
+	SegTax   int    `json:"segtax"`
+	// BidderSegments lists the segments each bidder may receive, "*" applies to bidders not listed.
+	// When empty, every bidder receives all segments.
+	BidderSegments map[string][]string `json:"bidder_segments"`
+}
+
+func newConfig(data json.RawMessage) (moduleConfig, error) {
+	var cfg moduleConfig
+	if err := jsonutil.UnmarshalValid(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse config: %s", err)
+	}
+
+	switch cfg.Store.Type {
+	case storeTypeFile:
+		if cfg.Store.File.Path == "" {
+			return cfg, errors.New("store.file.path is required for the file store")
+		}
+		if cfg.Store.File.RefreshIntervalSeconds < 0 {
+			return cfg, errors.New("store.file.refresh_interval_seconds must not be negative")
+		}
+	case storeTypeRedis:
+		if cfg.Store.Redis.Address == "" {
+			return cfg, errors.New("store.redis.address is required for the redis store")
+		}
+		if cfg.Store.Redis.TimeoutMs <= 0 {
+			cfg.Store.Redis.TimeoutMs = defaultRedisTimeout
+		}
+	default:
+		return cfg, fmt.Errorf("store.type must be %q or %q", storeTypeFile, storeTypeRedis)
+	}
+
+	if cfg.Enrichment.DataName == "" {
+		cfg.Enrichment.DataName = defaultDataName
+	}
+	return cfg, nil
+}
+
+// forAccount applies the account-level module config over the host-level enrichment config
+func (cfg enrichmentConfig) forAccount(data json.RawMessage) (enrichmentConfig, error) {
+	if len(data) == 0 {
+		return cfg, nil
+	}
+
+	accountCfg := cfg
+	accountCfg.BidderSegments = nil
+	if err := jsonutil.UnmarshalValid(data, &accountCfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse account config: %s", err)
+	}
+	if accountCfg.DataName == "" {
+		accountCfg.DataName = cfg.DataName
+	}
+	if accountCfg.BidderSegments == nil {
+		accountCfg.BidderSegments = cfg.BidderSegments
+	}
+	return accountCfg, nil
+}
+
+func (cfg enrichmentConfig) enabled() bool {
+	return cfg.Enabled == nil || *cfg.Enabled
+}
+
+// allowedSegments returns the segments the bidder may receive, nil when it may receive all of them
+func (cfg enrichmentConfig) allowedSegments(bidder string) map[string]struct{} {
+	if len(cfg.BidderSegments) == 0 {
+		return nil
+	}
+
+	segments, ok := cfg.BidderSegments[bidder]
+	if !ok {
+		segments = cfg.BidderSegments[allBiddersKey]
+	}
+
+	allowed := make(map[string]struct{}, len(segments))
+	for _, segment := range segments {
+		allowed[segment] = struct{}{}
+	}
+	return allowed
+}
@@ -0,0 +1,160 @@
+package fpdenrichment
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewConfig(t *testing.T) {
+	testCases := []struct {
+		description    string
+		data           json.RawMessage
+		expectedConfig moduleConfig
+		expectedError  string
+	}{
+		{
+			description: "file store with defaults",
+			data:        json.RawMessage(`{"store":{"type":"file","file":{"path":"segments.json"}}}`),
+			expectedConfig: moduleConfig{
+				Store:      storeConfig{Type: storeTypeFile, File: fileStoreConfig{Path: "segments.json"}},
+				Enrichment: enrichmentConfig{DataName: defaultDataName},
+			},
+		},
+		{
+			description: "redis store with defaults",
+			data:        json.RawMessage(`{"store":{"type":"redis","redis":{"address":"localhost:6379"}},"enrichment":{"data_name":"pub","segtax":600}}`),
+			expectedConfig: moduleConfig{
+				Store:      storeConfig{Type: storeTypeRedis, Redis: redisStoreConfig{Address: "localhost:6379", TimeoutMs: defaultRedisTimeout}},
+				Enrichment: enrichmentConfig{DataName: "pub", SegTax: 600},
+			},
+		},
+		{
+			description:   "unknown store type",
+			data:          json.RawMessage(`{"store":{"type":"memcached"}}`),
+			expectedError: `store.type must be "file" or "redis"`,
+		},
+		{
+			description:   "file store without path",
+			data:          json.RawMessage(`{"store":{"type":"file"}}`),
+			expectedError: "store.file.path is required for the file store",
+		},
+		{
+			description:   "file store with negative refresh interval",
+			data:          json.RawMessage(`{"store":{"type":"file","file":{"path":"segments.json","refresh_interval_seconds":-1}}}`),
+			expectedError: "store.file.refresh_interval_seconds must not be negative",
+		},
+		{
+			description:   "redis store without address",
+			data:          json.RawMessage(`{"store":{"type":"redis"}}`),
+			expectedError: "store.redis.address is required for the redis store",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			cfg, err := newConfig(test.data)
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedConfig, cfg)
+		})
+	}
+}
+
+func TestEnrichmentConfigForAccount(t *testing.T) {
+	disabled := false
+	hostCfg := enrichmentConfig{
+		DataName:       "host",
+		SegTax:         600,
+		BidderSegments: map[string][]string{"bidderA": {"seg1"}},
+	}
+
+	testCases := []struct {
+		description    string
+		data           json.RawMessage
+		expectedConfig enrichmentConfig
+		expectedError  string
+	}{
+		{
+			description:    "no account config",
+			expectedConfig: hostCfg,
+		},
+		{
+			description:    "account keeps host values not overridden",
+			data:           json.RawMessage(`{"segtax":601}`),
+			expectedConfig: enrichmentConfig{DataName: "host", SegTax: 601, BidderSegments: map[string][]string{"bidderA": {"seg1"}}},
+		},
+		{
+			description: "account replaces bidder segments",
+			data:        json.RawMessage(`{"enabled":false,"bidder_segments":{"bidderB":["seg2"]}}`),
+			expectedConfig: enrichmentConfig{
+				Enabled:        &disabled,
+				DataName:       "host",
+				SegTax:         600,
+				BidderSegments: map[string][]string{"bidderB": {"seg2"}},
+			},
+		},
+		{
+			description:    "invalid account config",
+			data:           json.RawMessage(`{"segtax":"600"}`),
+			expectedConfig: hostCfg,
+			expectedError:  "failed to parse account config",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			cfg, err := hostCfg.forAccount(test.data)
+			if test.expectedError != "" {
+				assert.ErrorContains(t, err, test.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.expectedConfig, cfg)
+		})
+	}
+}
+
+func TestEnrichmentConfigAllowedSegments(t *testing.T) {
+	testCases := []struct {
+		description     string
+		bidderSegments  map[string][]string
+		bidder          string
+		expectedAllowed map[string]struct{}
+	}{
+		{
+			description:     "no allowlists",
+			bidder:          "bidderA",
+			expectedAllowed: nil,
+		},
+		{
+			description:     "bidder allowlist",
+			bidderSegments:  map[string][]string{"bidderA": {"seg1", "seg2"}, "*": {"seg3"}},
+			bidder:          "bidderA",
+			expectedAllowed: map[string]struct{}{"seg1": {}, "seg2": {}},
+		},
+		{
+			description:     "wildcard allowlist",
+			bidderSegments:  map[string][]string{"bidderA": {"seg1", "seg2"}, "*": {"seg3"}},
+			bidder:          "bidderB",
+			expectedAllowed: map[string]struct{}{"seg3": {}},
+		},
+		{
+			description:     "bidder not listed without wildcard",
+			bidderSegments:  map[string][]string{"bidderA": {"seg1"}},
+			bidder:          "bidderB",
+			expectedAllowed: map[string]struct{}{},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			cfg := enrichmentConfig{BidderSegments: test.bidderSegments}
+			assert.Equal(t, test.expectedAllowed, cfg.allowedSegments(test.bidder))
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package fpdenrichment
+
+import (
+	"slices"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+)
+
+func handleBidderRequestHook(
+	hostCfg enrichmentConfig,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.BidderRequestPayload,
+) (result hookstage.HookResult[hookstage.BidderRequestPayload], err error) {
+	if payload.Request == nil || payload.Request.BidRequest == nil {
+		return result, hookexecution.NewFailure("payload contains a nil bid request")
+	}
+
+	enriched, ok := miCtx.ModuleContext[segmentsContextKey].([]string)
+	if !ok || len(enriched) == 0 {
+		return result, nil
+	}
+
+	cfg, err := hostCfg.forAccount(miCtx.AccountConfig)
+	if err != nil {
+		return result, hookexecution.NewFailure("%s", err)
+	}
+
+	allowed := cfg.allowedSegments(payload.Bidder)
+	if allowed == nil {
+		return result, nil
+	}
+
+	denied := make(map[string]struct{}, len(enriched))
+	for _, segment := range enriched {
+		if _, ok := allowed[segment]; !ok {
+			denied[segment] = struct{}{}
+		}
+	}
+	if len(denied) == 0 {
+		return result, nil
+	}
+
+	result.ChangeSet.AddMutation(func(payload hookstage.BidderRequestPayload) (hookstage.BidderRequestPayload, error) {
+		if payload.Request.User == nil {
+			return payload, nil
+		}
+		user := *payload.Request.User
+		user.Data = filterUserData(user.Data, cfg.DataName, enriched, denied)
+		payload.Request.User = &user
+		return payload, nil
+	}, hookstage.MutationUpdate, "bidrequest", "user", "data")
+
+	return result, nil
+}
+
+// filterUserData removes the denied segments from the data entry added by the module, dropping it when left empty.
+// The other entries are kept as is, even when they have the same name. The slices are copied as user.data may be
+// shared with the requests of other bidders.
+func filterUserData(data []openrtb2.Data, name string, enriched []string, denied map[string]struct{}) []openrtb2.Data {
+	index := enrichedDataIndex(data, name, enriched)
+	if index < 0 {
+		return data
+	}
+
+	segments := make([]openrtb2.Segment, 0, len(data[index].Segment))
+	for _, segment := range data[index].Segment {
+		if _, ok := denied[segment.ID]; !ok {
+			segments = append(segments, segment)
+		}
+	}
+
+	filtered := slices.Clone(data)
+	if len(segments) == 0 {
+		return slices.Delete(filtered, index, index+1)
+	}
+	filtered[index].Segment = segments
+	return filtered
+}
+
+// enrichedDataIndex returns the index of the data entry added by the module, the last one with its name and the
+// enriched segments, or -1 when there is none
+func enrichedDataIndex(data []openrtb2.Data, name string, enriched []string) int {
+	for i := len(data) - 1; i >= 0; i-- {
+		if data[i].Name != name || len(data[i].Segment) != len(enriched) {
+			continue
+		}
+		if slices.EqualFunc(data[i].Segment, enriched, func(segment openrtb2.Segment, id string) bool { return segment.ID == id }) {
+			return i
+		}
+	}
+	return -1
+}
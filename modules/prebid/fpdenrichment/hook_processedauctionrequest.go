@@ -0,0 +1,122 @@
+package fpdenrichment
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v3/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/privacy"
+)
+
+const (
+	enrichActivityName = "enrich_user_data"
+	segmentsContextKey = "segments"
+)
+
+// store keys of the identifiers looked up in the segment store
+const (
+	userIDKeyPrefix = "uid:"
+	eidKeyPrefix    = "eid:"
+	ifaKeyPrefix    = "ifa:"
+)
+
+func handleProcessedAuctionHook(
+	ctx context.Context,
+	hostCfg enrichmentConfig,
+	store segmentStore,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.ProcessedAuctionRequestPayload,
+) (result hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload], err error) {
+	if payload.Request == nil || payload.Request.BidRequest == nil {
+		return result, hookexecution.NewFailure("payload contains a nil bid request")
+	}
+
+	cfg, err := hostCfg.forAccount(miCtx.AccountConfig)
+	if err != nil {
+		return result, hookexecution.NewFailure("%s", err)
+	}
+	if !cfg.enabled() {
+		return result, nil
+	}
+
+	if !enrichAllowed(miCtx, payload) {
+		result.DebugMessages = append(result.DebugMessages, "user data enrichment skipped: enrichUfpd activity is not allowed")
+		return result, nil
+	}
+
+	keys := lookupKeys(payload.Request.BidRequest)
+	if len(keys) == 0 {
+		return result, nil
+	}
+
+	segments, err := store.Segments(ctx, keys)
+	if err != nil {
+		result.Warnings = append(result.Warnings, err.Error())
+	}
+	if len(segments) == 0 {
+		return result, nil
+	}
+
+	data := openrtb2.Data{
+		Name:    cfg.DataName,
+		Segment: make([]openrtb2.Segment, len(segments)),
+	}
+	for i, segment := range segments {
+		data.Segment[i] = openrtb2.Segment{ID: segment}
+	}
+	if cfg.SegTax > 0 {
+		data.Ext = []byte(fmt.Sprintf(`{"segtax":%d}`, cfg.SegTax))
+	}
+
+	result.ChangeSet.AddMutation(func(payload hookstage.ProcessedAuctionRequestPayload) (hookstage.ProcessedAuctionRequestPayload, error) {
+		if payload.Request.User == nil {
+			payload.Request.User = &openrtb2.User{}
+		}
+		payload.Request.User.Data = append(payload.Request.User.Data, data)
+		return payload, nil
+	}, hookstage.MutationAdd, "bidrequest", "user", "data")
+
+	result.ModuleContext = hookstage.ModuleContext{segmentsContextKey: segments}
+	result.AnalyticsTags = hookanalytics.Analytics{
+		Activities: []hookanalytics.Activity{{
+			Name:   enrichActivityName,
+			Status: hookanalytics.ActivityStatusSuccess,
+			Results: []hookanalytics.Result{{
+				Status:    hookanalytics.ResultStatusModify,
+				Values:    map[string]interface{}{segmentsContextKey: segments},
+				AppliedTo: hookanalytics.AppliedTo{Request: true},
+			}},
+		}},
+	}
+	return result, nil
+}
+
+// enrichAllowed evaluates the enrichUfpd activity for the module with the activity controls of the request
+func enrichAllowed(miCtx hookstage.ModuleInvocationContext, payload hookstage.ProcessedAuctionRequestPayload) bool {
+	component := privacy.Component{Type: privacy.ComponentTypeRealTimeData, Name: miCtx.HookImplCode, Module: moduleCode}
+	return miCtx.ActivityControl.Allow(privacy.ActivityEnrichUserFPD, component, privacy.NewRequestFromBidRequest(*payload.Request))
+}
+
+// lookupKeys lists the store keys of the user id, the ids of all EIDs and the device advertising id
+func lookupKeys(request *openrtb2.BidRequest) []string {
+	var keys []string
+	if request.User != nil {
+		if request.User.ID != "" {
+			keys = append(keys, userIDKeyPrefix+request.User.ID)
+		}
+		for _, eid := range request.User.EIDs {
+			for _, uid := range eid.UIDs {
+				if eid.Source != "" && uid.ID != "" {
+					keys = append(keys, eidKeyPrefix+eid.Source+":"+uid.ID)
+				}
+			}
+		}
+	}
+	if request.Device != nil && request.Device.IFA != "" {
+		keys = append(keys, ifaKeyPrefix+request.Device.IFA)
+	}
+	return keys
+}
@@ -0,0 +1,60 @@
+// Package fpdenrichment appends publisher-owned audience segments, looked up in a local
+// key-value segment store, to user.data of auction requests.
+package fpdenrichment
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+)
+
+const moduleCode = "prebid.fpdenrichment"
+
+var (
+	_ hookstage.ProcessedAuctionRequest = (*Module)(nil)
+	_ hookstage.BidderRequest           = (*Module)(nil)
+)
+
+func Builder(data json.RawMessage, _ moduledeps.ModuleDeps) (interface{}, error) {
+	cfg, err := newConfig(data)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := newSegmentStore(cfg.Store)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Module{cfg: cfg.Enrichment, store: store}, nil
+}
+
+type Module struct {
+	cfg   enrichmentConfig
+	store segmentStore
+}
+
+// HandleProcessedAuctionHook looks up the user identifiers of the request in the segment store
+// and appends the segments found to user.data.
+func (m *Module) HandleProcessedAuctionHook(
+	ctx context.Context,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.ProcessedAuctionRequestPayload,
+) (hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload], error) {
+	return handleProcessedAuctionHook(ctx, m.cfg, m.store, miCtx, payload)
+}
+
+// HandleBidderRequestHook removes the enriched segments the bidder is not allowed to receive.
+func (m *Module) HandleBidderRequestHook(
+	_ context.Context,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.BidderRequestPayload,
+) (hookstage.HookResult[hookstage.BidderRequestPayload], error) {
+	return handleBidderRequestHook(m.cfg, miCtx, payload)
+}
+
+func (m *Module) Shutdown() error {
+	return m.store.Close()
+}
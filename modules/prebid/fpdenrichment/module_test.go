@@ -0,0 +1,242 @@
+package fpdenrichment
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v3/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/privacy"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestModule(t *testing.T, enrichment string) *Module {
+	path := filepath.Join(t.TempDir(), "segments.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"uid:user-1": ["seg1", "seg2"],
+		"eid:liveramp.com:XY123": ["seg2", "seg3"],
+		"ifa:ifa-1": ["seg4"]
+	}`), 0644))
+
+	data := json.RawMessage(`{"store":{"type":"file","file":{"path":"` + path + `"}},"enrichment":` + enrichment + `}`)
+	module, err := Builder(data, moduledeps.ModuleDeps{})
+	require.NoError(t, err)
+	t.Cleanup(func() { module.(*Module).Shutdown() })
+
+	return module.(*Module)
+}
+
+func TestBuilder(t *testing.T) {
+	_, err := Builder(json.RawMessage(`{"store":{"type":"file"}}`), moduledeps.ModuleDeps{})
+	assert.EqualError(t, err, "store.file.path is required for the file store")
+
+	_, err = Builder(json.RawMessage(`{"store":{"type":"file","file":{"path":"missing.json"}}}`), moduledeps.ModuleDeps{})
+	assert.ErrorContains(t, err, "failed to read segment snapshot")
+}
+
+func TestHandleProcessedAuctionHook(t *testing.T) {
+	denyEnrichment := privacy.NewActivityControl(&config.AccountPrivacy{
+		AllowActivities: &config.AllowActivities{
+			EnrichUserFPD: config.Activity{Default: ptrutil.ToPtr(false)},
+		},
+	})
+
+	testCases := []struct {
+		description           string
+		enrichment            string
+		accountConfig         json.RawMessage
+		activityControl       privacy.ActivityControl
+		bidRequest            *openrtb2.BidRequest
+		expectedBidRequest    *openrtb2.BidRequest
+		expectedModuleContext hookstage.ModuleContext
+		expectedDebugMessages []string
+		expectedError         bool
+	}{
+		{
+			description: "segments of all identifiers appended to user.data",
+			enrichment:  `{"segtax":600}`,
+			bidRequest: &openrtb2.BidRequest{
+				User: &openrtb2.User{
+					ID:   "user-1",
+					Data: []openrtb2.Data{{Name: "other", Segment: []openrtb2.Segment{{ID: "x"}}}},
+					EIDs: []openrtb2.EID{{Source: "liveramp.com", UIDs: []openrtb2.UID{{ID: "XY123"}}}},
+				},
+				Device: &openrtb2.Device{IFA: "ifa-1"},
+			},
+			expectedBidRequest: &openrtb2.BidRequest{
+				User: &openrtb2.User{
+					ID: "user-1",
+					Data: []openrtb2.Data{
+						{Name: "other", Segment: []openrtb2.Segment{{ID: "x"}}},
+						{
+							Name:    defaultDataName,
+							Segment: []openrtb2.Segment{{ID: "seg1"}, {ID: "seg2"}, {ID: "seg3"}, {ID: "seg4"}},
+							Ext:     json.RawMessage(`{"segtax":600}`),
+						},
+					},
+					EIDs: []openrtb2.EID{{Source: "liveramp.com", UIDs: []openrtb2.UID{{ID: "XY123"}}}},
+				},
+				Device: &openrtb2.Device{IFA: "ifa-1"},
+			},
+			expectedModuleContext: hookstage.ModuleContext{segmentsContextKey: []string{"seg1", "seg2", "seg3", "seg4"}},
+		},
+		{
+			description: "user created for device identifier",
+			enrichment:  `{"data_name":"pub"}`,
+			bidRequest:  &openrtb2.BidRequest{Device: &openrtb2.Device{IFA: "ifa-1"}},
+			expectedBidRequest: &openrtb2.BidRequest{
+				User:   &openrtb2.User{Data: []openrtb2.Data{{Name: "pub", Segment: []openrtb2.Segment{{ID: "seg4"}}}}},
+				Device: &openrtb2.Device{IFA: "ifa-1"},
+			},
+			expectedModuleContext: hookstage.ModuleContext{segmentsContextKey: []string{"seg4"}},
+		},
+		{
+			description:        "no segments found",
+			enrichment:         `{}`,
+			bidRequest:         &openrtb2.BidRequest{User: &openrtb2.User{ID: "user-2"}},
+			expectedBidRequest: &openrtb2.BidRequest{User: &openrtb2.User{ID: "user-2"}},
+		},
+		{
+			description:        "disabled for account",
+			enrichment:         `{}`,
+			accountConfig:      json.RawMessage(`{"enabled":false}`),
+			bidRequest:         &openrtb2.BidRequest{User: &openrtb2.User{ID: "user-1"}},
+			expectedBidRequest: &openrtb2.BidRequest{User: &openrtb2.User{ID: "user-1"}},
+		},
+		{
+			description:           "enrichUfpd activity denied",
+			enrichment:            `{}`,
+			activityControl:       denyEnrichment,
+			bidRequest:            &openrtb2.BidRequest{User: &openrtb2.User{ID: "user-1"}},
+			expectedBidRequest:    &openrtb2.BidRequest{User: &openrtb2.User{ID: "user-1"}},
+			expectedDebugMessages: []string{"user data enrichment skipped: enrichUfpd activity is not allowed"},
+		},
+		{
+			description:   "invalid account config",
+			enrichment:    `{}`,
+			accountConfig: json.RawMessage(`{"segtax":"600"}`),
+			bidRequest:    &openrtb2.BidRequest{User: &openrtb2.User{ID: "user-1"}},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			module := newTestModule(t, test.enrichment)
+			payload := hookstage.ProcessedAuctionRequestPayload{Request: &openrtb_ext.RequestWrapper{BidRequest: test.bidRequest}}
+			miCtx := hookstage.ModuleInvocationContext{
+				AccountConfig:   test.accountConfig,
+				HookImplCode:    "HandleProcessedAuctionHook",
+				ActivityControl: test.activityControl,
+			}
+
+			result, err := module.HandleProcessedAuctionHook(context.Background(), miCtx, payload)
+			if test.expectedError {
+				assert.Error(t, err)
+				assert.IsType(t, hookexecution.FailureError{}, err)
+				return
+			}
+			require.NoError(t, err)
+
+			for _, mut := range result.ChangeSet.Mutations() {
+				payload, err = mut.Apply(payload)
+				require.NoError(t, err)
+			}
+			assert.Equal(t, test.expectedBidRequest, payload.Request.BidRequest)
+			assert.Equal(t, test.expectedModuleContext, result.ModuleContext)
+			assert.Equal(t, test.expectedDebugMessages, result.DebugMessages)
+			if test.expectedModuleContext != nil {
+				assert.Equal(t, hookanalytics.ActivityStatusSuccess, result.AnalyticsTags.Activities[0].Status)
+			}
+		})
+	}
+}
+
+func TestHandleBidderRequestHook(t *testing.T) {
+	newRequest := func() *openrtb2.BidRequest {
+		return &openrtb2.BidRequest{
+			User: &openrtb2.User{
+				Data: []openrtb2.Data{
+					{Name: "other", Segment: []openrtb2.Segment{{ID: "seg1"}}},
+					{Name: defaultDataName, Segment: []openrtb2.Segment{{ID: "seg1"}, {ID: "seg2"}}},
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		description   string
+		enrichment    string
+		bidder        string
+		moduleContext hookstage.ModuleContext
+		expectedData  []openrtb2.Data
+	}{
+		{
+			description:   "no allowlists",
+			enrichment:    `{}`,
+			bidder:        "bidderA",
+			moduleContext: hookstage.ModuleContext{segmentsContextKey: []string{"seg1", "seg2"}},
+			expectedData:  newRequest().User.Data,
+		},
+		{
+			description:   "denied segments removed",
+			enrichment:    `{"bidder_segments":{"bidderA":["seg2"]}}`,
+			bidder:        "bidderA",
+			moduleContext: hookstage.ModuleContext{segmentsContextKey: []string{"seg1", "seg2"}},
+			expectedData: []openrtb2.Data{
+				{Name: "other", Segment: []openrtb2.Segment{{ID: "seg1"}}},
+				{Name: defaultDataName, Segment: []openrtb2.Segment{{ID: "seg2"}}},
+			},
+		},
+		{
+			description:   "entry dropped when all segments are denied",
+			enrichment:    `{"bidder_segments":{"bidderA":["seg2"]}}`,
+			bidder:        "bidderB",
+			moduleContext: hookstage.ModuleContext{segmentsContextKey: []string{"seg1", "seg2"}},
+			expectedData:  []openrtb2.Data{{Name: "other", Segment: []openrtb2.Segment{{ID: "seg1"}}}},
+		},
+		{
+			description:   "publisher entry with the same name kept",
+			enrichment:    `{"bidder_segments":{"bidderA":["seg2"]}}`,
+			bidder:        "bidderA",
+			moduleContext: hookstage.ModuleContext{segmentsContextKey: []string{"seg1"}},
+			expectedData:  newRequest().User.Data,
+		},
+		{
+			description:  "request not enriched",
+			enrichment:   `{"bidder_segments":{"bidderA":["seg2"]}}`,
+			bidder:       "bidderB",
+			expectedData: newRequest().User.Data,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			module := newTestModule(t, test.enrichment)
+			request := newRequest()
+			originalUser := request.User
+			payload := hookstage.BidderRequestPayload{Request: &openrtb_ext.RequestWrapper{BidRequest: request}, Bidder: test.bidder}
+			miCtx := hookstage.ModuleInvocationContext{ModuleContext: test.moduleContext}
+
+			result, err := module.HandleBidderRequestHook(context.Background(), miCtx, payload)
+			require.NoError(t, err)
+
+			for _, mut := range result.ChangeSet.Mutations() {
+				payload, err = mut.Apply(payload)
+				require.NoError(t, err)
+			}
+			assert.Equal(t, test.expectedData, payload.Request.User.Data)
+			assert.Equal(t, newRequest().User.Data, originalUser.Data, "original user.data must not be modified")
+		})
+	}
+}
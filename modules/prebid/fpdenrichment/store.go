@@ -0,0 +1,164 @@
+package fpdenrichment
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/logger"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/prebid/prebid-server/v3/util/redisutil"
+	"github.com/prebid/prebid-server/v3/util/task"
+)
+
+// segmentStore maps user identifiers to the publisher's audience segments
+type segmentStore interface {
+	// Segments returns the segments of all keys found in the store, in key order and without duplicates
+	Segments(ctx context.Context, keys []string) ([]string, error)
+	Close() error
+}
+
+func newSegmentStore(cfg storeConfig) (segmentStore, error) {
+	if cfg.Type == storeTypeRedis {
+		return newRedisStore(cfg.Redis), nil
+	}
+	return newFileStore(cfg.File)
+}
+
+// fileStore serves segments from a JSON snapshot mapping keys to segment ids,
+// optionally reloaded at a fixed interval.
+type fileStore struct {
+	path     string
+	segments atomic.Pointer[map[string][]string]
+	ticker   *task.TickerTask
+}
+
+func newFileStore(cfg fileStoreConfig) (*fileStore, error) {
+	store := &fileStore{path: cfg.Path}
+	if err := store.load(); err != nil {
+		return nil, err
+	}
+
+	if cfg.RefreshIntervalSeconds > 0 {
+		interval := time.Duration(cfg.RefreshIntervalSeconds) * time.Second
+		store.ticker = task.NewTickerTaskFromFunc(interval, func() error {
+			if err := store.load(); err != nil {
+				// keep serving the previous snapshot
+				logger.Errorf("fpdenrichment: %s", err)
+			}
+			return nil
+		})
+		store.ticker.Start()
+	}
+	return store, nil
+}
+
+func (s *fileStore) load() error {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to read segment snapshot %s: %s", s.path, err)
+	}
+
+	var segments map[string][]string
+	if err := jsonutil.UnmarshalValid(data, &segments); err != nil {
+		return fmt.Errorf("failed to parse segment snapshot %s: %s", s.path, err)
+	}
+	s.segments.Store(&segments)
+	return nil
+}
+
+func (s *fileStore) Segments(_ context.Context, keys []string) ([]string, error) {
+	snapshot := *s.segments.Load()
+
+	var found segmentSet
+	for _, key := range keys {
+		found.add(snapshot[key]...)
+	}
+	return found.list, nil
+}
+
+func (s *fileStore) Close() error {
+	if s.ticker != nil {
+		s.ticker.Stop()
+	}
+	return nil
+}
+
+// redisStore reads segments from a server speaking the Redis protocol. Each key holds a JSON array of segment ids.
+type redisStore struct {
+	client    *redisutil.Client
+	keyPrefix string
+}
+
+func newRedisStore(cfg redisStoreConfig) *redisStore {
+	return &redisStore{
+		client: redisutil.NewClient(redisutil.Config{
+			Address:  cfg.Address,
+			Password: cfg.Password,
+			DB:       cfg.DB,
+			Timeout:  time.Duration(cfg.TimeoutMs) * time.Millisecond,
+		}),
+		keyPrefix: cfg.KeyPrefix,
+	}
+}
+
+func (s *redisStore) Segments(ctx context.Context, keys []string) ([]string, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	prefixedKeys := make([]string, len(keys))
+	for i, key := range keys {
+		prefixedKeys[i] = s.keyPrefix + key
+	}
+
+	values, err := s.client.MGet(ctx, prefixedKeys...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch segments: %s", err)
+	}
+
+	var (
+		found    segmentSet
+		parseErr error
+	)
+	for i, value := range values {
+		if value == nil {
+			continue
+		}
+		var segments []string
+		if err := jsonutil.UnmarshalValid(value, &segments); err != nil {
+			parseErr = fmt.Errorf("failed to parse segments of key %s: %s", prefixedKeys[i], err)
+			continue
+		}
+		found.add(segments...)
+	}
+	return found.list, parseErr
+}
+
+func (s *redisStore) Close() error {
+	return s.client.Close()
+}
+
+// segmentSet collects segment ids in insertion order, ignoring duplicates
+type segmentSet struct {
+	list []string
+	seen map[string]struct{}
+}
+
+func (s *segmentSet) add(segments ...string) {
+	for _, segment := range segments {
+		if segment == "" {
+			continue
+		}
+		if s.seen == nil {
+			s.seen = make(map[string]struct{})
+		}
+		if _, ok := s.seen[segment]; ok {
+			continue
+		}
+		s.seen[segment] = struct{}{}
+		s.list = append(s.list, segment)
+	}
+}
@@ -0,0 +1,83 @@
+package fpdenrichment
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/util/redisutil/redistest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "segments.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"uid:1":["seg1","seg2"],"ifa:2":["seg2","seg3"]}`), 0644))
+
+	store, err := newFileStore(fileStoreConfig{Path: path})
+	require.NoError(t, err)
+	defer store.Close()
+
+	segments, err := store.Segments(context.Background(), []string{"uid:1", "eid:src:1", "ifa:2"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"seg1", "seg2", "seg3"}, segments)
+
+	segments, err = store.Segments(context.Background(), []string{"uid:3"})
+	assert.NoError(t, err)
+	assert.Empty(t, segments)
+
+	// a snapshot failing to load keeps the previous one
+	require.NoError(t, os.WriteFile(path, []byte(`{"uid:1":`), 0644))
+	assert.Error(t, store.load())
+
+	segments, err = store.Segments(context.Background(), []string{"uid:1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"seg1", "seg2"}, segments)
+}
+
+func TestFileStoreInvalidSnapshot(t *testing.T) {
+	_, err := newFileStore(fileStoreConfig{Path: filepath.Join(t.TempDir(), "missing.json")})
+	assert.ErrorContains(t, err, "failed to read segment snapshot")
+
+	path := filepath.Join(t.TempDir(), "segments.json")
+	require.NoError(t, os.WriteFile(path, []byte(`["seg1"]`), 0644))
+
+	_, err = newFileStore(fileStoreConfig{Path: path})
+	assert.ErrorContains(t, err, "failed to parse segment snapshot")
+}
+
+func TestRedisStore(t *testing.T) {
+	server := redistest.NewServer()
+	defer server.Close()
+
+	server.Set("pfx:uid:1", `["seg1","seg2"]`)
+	server.Set("pfx:eid:src:1", `["seg2","seg3"]`)
+	server.Set("pfx:ifa:2", `"seg4"`)
+
+	store := newRedisStore(redisStoreConfig{Address: server.Addr, TimeoutMs: 1000, KeyPrefix: "pfx:"})
+	defer store.Close()
+
+	segments, err := store.Segments(context.Background(), []string{"uid:1", "uid:3", "eid:src:1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"seg1", "seg2", "seg3"}, segments)
+
+	segments, err = store.Segments(context.Background(), []string{"uid:1", "ifa:2"})
+	assert.ErrorContains(t, err, "failed to parse segments of key pfx:ifa:2")
+	assert.Equal(t, []string{"seg1", "seg2"}, segments)
+
+	segments, err = store.Segments(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Empty(t, segments)
+}
+
+func TestRedisStoreUnavailable(t *testing.T) {
+	server := redistest.NewServer()
+	server.Close()
+
+	store := newRedisStore(redisStoreConfig{Address: server.Addr, TimeoutMs: 100})
+	defer store.Close()
+
+	_, err := store.Segments(context.Background(), []string{"uid:1"})
+	assert.ErrorContains(t, err, "failed to fetch segments")
+}
@@ -52,12 +52,13 @@ func addBlockedAnalyticTag(
 	values := make(map[string]interface{})
 
 	values[attributesAnalyticKey] = failedAttributes
-	for _, attribute := range [5]string{
+	for _, attribute := range [6]string{
 		"badv",
 		"bcat",
 		"cattax",
 		"bapp",
 		"battr",
+		"block_lists",
 	} {
 		if _, ok := data[attribute]; ok {
 			analyticKey := getAnalyticKeyForAttribute(attribute)
@@ -0,0 +1,294 @@
+package ortb2blocking
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/logger"
+	"github.com/prebid/prebid-server/v3/stored_requests"
+	"github.com/prebid/prebid-server/v3/stored_requests/backends/http_fetcher"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/prebid/prebid-server/v3/util/task"
+)
+
+const (
+	blockListBadv   = "badv"
+	blockListBcat   = "bcat"
+	blockListBapp   = "bapp"
+	blockListBattr  = "battr"
+	blockListCrid   = "crid"
+	blockListDealID = "deal_id"
+
+	blockListWildcardPrefix = "*."
+	blockListFetchTimeout   = 10 * time.Second
+)
+
+// blockListData is the stored format of a shared block list
+type blockListData struct {
+	Badv   []string `json:"badv"`
+	Bcat   []string `json:"bcat"`
+	Bapp   []string `json:"bapp"`
+	Battr  []int    `json:"battr"`
+	Crid   []string `json:"crid"`
+	DealID []string `json:"deal_id"`
+}
+
+// blockList is a named shared block list. Advertiser domain entries of the form "*.example.com" match
+// example.com and all of its subdomains.
+type blockList struct {
+	name string
+	data blockListData
+
+	badv   map[string]struct{}
+	bcat   map[string]struct{}
+	bapp   map[string]struct{}
+	battr  map[int]struct{}
+	crid   map[string]struct{}
+	dealID map[string]struct{}
+}
+
+// blockListHit is a list entry matched by a bid
+type blockListHit struct {
+	list      string
+	attribute string
+	// entry is the matched entry of the list, e.g. *.evil.com for the cdn.evil.com domain of a bid
+	entry string
+}
+
+func (h blockListHit) String() string {
+	return h.list + "." + h.attribute + ":" + h.entry
+}
+
+func newBlockList(name string, raw json.RawMessage) (*blockList, error) {
+	var data blockListData
+	if err := jsonutil.UnmarshalValid(raw, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse block list %s: %s", name, err)
+	}
+
+	for _, domain := range data.Badv {
+		if strings.Contains(strings.TrimPrefix(domain, blockListWildcardPrefix), "*") {
+			return nil, fmt.Errorf("block list %s has an invalid badv entry %q, only a leading *. wildcard is supported", name, domain)
+		}
+	}
+
+	list := &blockList{
+		name:   name,
+		data:   data,
+		badv:   toLowerSet(data.Badv),
+		bcat:   toSet(data.Bcat),
+		bapp:   toSet(data.Bapp),
+		battr:  make(map[int]struct{}, len(data.Battr)),
+		crid:   toSet(data.Crid),
+		dealID: toSet(data.DealID),
+	}
+	for _, attr := range data.Battr {
+		list.battr[attr] = struct{}{}
+	}
+	return list, nil
+}
+
+// matchDomain returns the entry matching the advertiser domain, the domain itself or one
+// of its parent domains with the wildcard prefix
+func (l *blockList) matchDomain(domain string) (string, bool) {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	if _, ok := l.badv[domain]; ok {
+		return domain, true
+	}
+	for parent := domain; parent != ""; {
+		entry := blockListWildcardPrefix + parent
+		if _, ok := l.badv[entry]; ok {
+			return entry, true
+		}
+		_, parent, _ = strings.Cut(parent, ".")
+	}
+	return "", false
+}
+
+// match returns the entries of the list matched by the bid
+func (l *blockList) match(bid *openrtb2.Bid) []blockListHit {
+	var hits []blockListHit
+	add := func(attribute, entry string) {
+		hits = append(hits, blockListHit{list: l.name, attribute: attribute, entry: entry})
+	}
+
+	for _, domain := range bid.ADomain {
+		if entry, ok := l.matchDomain(domain); ok {
+			add(blockListBadv, entry)
+		}
+	}
+	for _, cat := range bid.Cat {
+		if _, ok := l.bcat[cat]; ok {
+			add(blockListBcat, cat)
+		}
+	}
+	if _, ok := l.bapp[bid.Bundle]; ok && bid.Bundle != "" {
+		add(blockListBapp, bid.Bundle)
+	}
+	for _, attr := range bid.Attr {
+		if _, ok := l.battr[int(attr)]; ok {
+			add(blockListBattr, strconv.Itoa(int(attr)))
+		}
+	}
+	if _, ok := l.crid[bid.CrID]; ok && bid.CrID != "" {
+		add(blockListCrid, bid.CrID)
+	}
+	if _, ok := l.dealID[bid.DealID]; ok && bid.DealID != "" {
+		add(blockListDealID, bid.DealID)
+	}
+	return hits
+}
+
+func toSet(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		set[value] = struct{}{}
+	}
+	return set
+}
+
+func toLowerSet(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		set[strings.ToLower(value)] = struct{}{}
+	}
+	return set
+}
+
+// blockListLoader loads all shared block lists by name
+type blockListLoader func() (map[string]json.RawMessage, error)
+
+// blockListStore serves the shared block lists, optionally reloaded at a fixed interval
+type blockListStore struct {
+	load   blockListLoader
+	lists  atomic.Pointer[map[string]*blockList]
+	ticker *task.TickerTask
+}
+
+func newBlockListStore(cfg blockListsConfig, client *http.Client) (*blockListStore, error) {
+	var load blockListLoader
+	switch cfg.Source {
+	case "":
+		return nil, nil
+	case blockListSourceFile:
+		load = fileBlockListLoader(cfg.File.Directory)
+	case blockListSourceStoredRequest:
+		if client == nil {
+			client = http.DefaultClient
+		}
+		load = storedRequestBlockListLoader(http_fetcher.NewFetcher(client, cfg.StoredRequest.Endpoint, false), cfg.StoredRequest.Names)
+	}
+
+	store := &blockListStore{load: load}
+	if err := store.reload(); err != nil {
+		return nil, err
+	}
+
+	if cfg.RefreshIntervalSeconds > 0 {
+		interval := time.Duration(cfg.RefreshIntervalSeconds) * time.Second
+		store.ticker = task.NewTickerTaskFromFunc(interval, func() error {
+			if err := store.reload(); err != nil {
+				// keep serving the previous lists
+				logger.Errorf("ortb2blocking: %s", err)
+			}
+			return nil
+		})
+		store.ticker.Start()
+	}
+	return store, nil
+}
+
+func (s *blockListStore) reload() error {
+	data, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	lists := make(map[string]*blockList, len(data))
+	for name, raw := range data {
+		list, err := newBlockList(name, raw)
+		if err != nil {
+			return err
+		}
+		lists[name] = list
+	}
+	s.lists.Store(&lists)
+	return nil
+}
+
+// get returns the lists with the given names and a warning for each list that is not loaded
+func (s *blockListStore) get(names []string) ([]*blockList, []string) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	var available map[string]*blockList
+	if s != nil {
+		available = *s.lists.Load()
+	}
+
+	var lists []*blockList
+	var warnings []string
+	for _, name := range names {
+		if list, ok := available[name]; ok {
+			lists = append(lists, list)
+		} else {
+			warnings = append(warnings, fmt.Sprintf("Block list %s is not loaded", name))
+		}
+	}
+	return lists, warnings
+}
+
+func (s *blockListStore) Close() error {
+	if s != nil && s.ticker != nil {
+		s.ticker.Stop()
+	}
+	return nil
+}
+
+// fileBlockListLoader loads every {name}.json file of the directory as the list name
+func fileBlockListLoader(directory string) blockListLoader {
+	return func() (map[string]json.RawMessage, error) {
+		entries, err := os.ReadDir(directory)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read block list directory %s: %s", directory, err)
+		}
+
+		data := make(map[string]json.RawMessage, len(entries))
+		for _, entry := range entries {
+			if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+				continue
+			}
+			raw, err := os.ReadFile(filepath.Join(directory, entry.Name()))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read block list file %s: %s", entry.Name(), err)
+			}
+			data[strings.TrimSuffix(entry.Name(), ".json")] = raw
+		}
+		return data, nil
+	}
+}
+
+// storedRequestBlockListLoader fetches the named lists from a stored request backend, each list is the
+// stored request with the list name as id
+func storedRequestBlockListLoader(fetcher stored_requests.Fetcher, names []string) blockListLoader {
+	return func() (map[string]json.RawMessage, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), blockListFetchTimeout)
+		defer cancel()
+
+		data, _, errs := fetcher.FetchRequests(ctx, names, nil)
+		if len(errs) > 0 {
+			return nil, fmt.Errorf("failed to fetch block lists: %w", errors.Join(errs...))
+		}
+		return data, nil
+	}
+}
@@ -0,0 +1,120 @@
+package ortb2blocking
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockListMatch(t *testing.T) {
+	list, err := newBlockList("malware", json.RawMessage(`{
+		"badv": ["bad.com", "*.Evil.com"],
+		"bcat": ["IAB7-39"],
+		"bapp": ["com.bad.app"],
+		"battr": [1, 8],
+		"crid": ["cr-1"],
+		"deal_id": ["deal-1"]
+	}`))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		description  string
+		bid          *openrtb2.Bid
+		expectedHits []string
+	}{
+		{
+			description: "no match",
+			bid:         &openrtb2.Bid{ADomain: []string{"good.com", "notbad.com", "evil.com.example"}, Cat: []string{"IAB1"}, Bundle: "com.good.app", Attr: []adcom1.CreativeAttribute{2}, CrID: "cr-2", DealID: "deal-2"},
+		},
+		{
+			description:  "exact and wildcard domains",
+			bid:          &openrtb2.Bid{ADomain: []string{"BAD.com", "evil.com", "ads.cdn.evil.com", "sub.bad.com"}},
+			expectedHits: []string{"malware.badv:bad.com", "malware.badv:*.evil.com", "malware.badv:*.evil.com"},
+		},
+		{
+			description:  "all attributes",
+			bid:          &openrtb2.Bid{Cat: []string{"IAB1", "IAB7-39"}, Bundle: "com.bad.app", Attr: []adcom1.CreativeAttribute{2, 8}, CrID: "cr-1", DealID: "deal-1"},
+			expectedHits: []string{"malware.bcat:IAB7-39", "malware.bapp:com.bad.app", "malware.battr:8", "malware.crid:cr-1", "malware.deal_id:deal-1"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			var hits []string
+			for _, hit := range list.match(test.bid) {
+				hits = append(hits, hit.String())
+			}
+			assert.Equal(t, test.expectedHits, hits)
+		})
+	}
+}
+
+func TestNewBlockListErrors(t *testing.T) {
+	_, err := newBlockList("list", json.RawMessage(`{"badv": ["ads.*.com"]}`))
+	assert.EqualError(t, err, `block list list has an invalid badv entry "ads.*.com", only a leading *. wildcard is supported`)
+
+	_, err = newBlockList("list", json.RawMessage(`{"battr": ["1"]}`))
+	assert.ErrorContains(t, err, "failed to parse block list list")
+}
+
+func TestBlockListStoreFile(t *testing.T) {
+	directory := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(directory, "malware.json"), []byte(`{"badv": ["bad.com"]}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(directory, "adult.json"), []byte(`{"bcat": ["IAB25"]}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(directory, "README.md"), []byte(`not a list`), 0644))
+
+	store, err := newBlockListStore(blockListsConfig{Source: blockListSourceFile, File: fileBlockListsConfig{Directory: directory}}, nil)
+	require.NoError(t, err)
+	defer store.Close()
+
+	lists, warnings := store.get([]string{"malware", "missing", "adult"})
+	require.Len(t, lists, 2)
+	assert.Equal(t, "malware", lists[0].name)
+	assert.Equal(t, "adult", lists[1].name)
+	assert.Equal(t, []string{"Block list missing is not loaded"}, warnings)
+
+	require.NoError(t, os.WriteFile(filepath.Join(directory, "adult.json"), []byte(`{"bcat": "IAB25"}`), 0644))
+	assert.Error(t, store.reload())
+	lists, _ = store.get([]string{"adult"})
+	assert.Len(t, lists, 1, "the previous lists are kept when a reload fails")
+
+	_, err = newBlockListStore(blockListsConfig{Source: blockListSourceFile, File: fileBlockListsConfig{Directory: filepath.Join(directory, "missing")}}, nil)
+	assert.ErrorContains(t, err, "failed to read block list directory")
+}
+
+func TestBlockListStoreStoredRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, `["malware","adult"]`, r.URL.Query().Get("request-ids"))
+		w.Write([]byte(`{"requests": {"malware": {"badv": ["*.bad.com"]}, "adult": {"bcat": ["IAB25"]}}}`))
+	}))
+	defer server.Close()
+
+	cfg := blockListsConfig{
+		Source:        blockListSourceStoredRequest,
+		StoredRequest: storedRequestListsConfig{Endpoint: server.URL, Names: []string{"malware", "adult"}},
+	}
+	store, err := newBlockListStore(cfg, server.Client())
+	require.NoError(t, err)
+	defer store.Close()
+
+	lists, warnings := store.get([]string{"adult", "malware"})
+	require.Len(t, lists, 2)
+	assert.Empty(t, warnings)
+	assert.Equal(t, []string{"*.bad.com"}, lists[1].data.Badv)
+}
+
+func TestNilBlockListStore(t *testing.T) {
+	var store *blockListStore
+	lists, warnings := store.get([]string{"malware"})
+	assert.Empty(t, lists)
+	assert.Equal(t, []string{"Block list malware is not loaded"}, warnings)
+	assert.NoError(t, store.Close())
+}
@@ -2,6 +2,7 @@ package ortb2blocking
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/prebid/openrtb/v20/adcom1"
@@ -18,6 +19,70 @@ func newConfig(data json.RawMessage) (config, error) {
 
 type config struct {
 	Attributes Attributes `json:"attributes"`
+	// BlockLists names the shared block lists of the host enforced for the account
+	BlockLists []string `json:"block_lists"`
+}
+
+const (
+	blockListSourceFile          = "file"
+	blockListSourceStoredRequest = "stored_request"
+)
+
+// hostConfig is the host-level module config
+type hostConfig struct {
+	BlockLists blockListsConfig `json:"block_lists"`
+}
+
+// blockListsConfig configures where the shared block lists are loaded from. Lists are not loaded when
+// the source is empty.
+type blockListsConfig struct {
+	Source                 string                   `json:"source"`
+	File                   fileBlockListsConfig     `json:"file"`
+	StoredRequest          storedRequestListsConfig `json:"stored_request"`
+	RefreshIntervalSeconds int                      `json:"refresh_interval_seconds"`
+}
+
+type fileBlockListsConfig struct {
+	// Directory holds one {name}.json file per list
+	Directory string `json:"directory"`
+}
+
+type storedRequestListsConfig struct {
+	// Endpoint is an HTTP stored request backend serving each list as the stored request with the list name as id
+	Endpoint string   `json:"endpoint"`
+	Names    []string `json:"names"`
+}
+
+func newHostConfig(data json.RawMessage) (hostConfig, error) {
+	var cfg hostConfig
+	if len(data) == 0 {
+		return cfg, nil
+	}
+	if err := jsonutil.UnmarshalValid(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse config: %s", err)
+	}
+
+	lists := cfg.BlockLists
+	switch lists.Source {
+	case "":
+	case blockListSourceFile:
+		if lists.File.Directory == "" {
+			return cfg, errors.New("block_lists.file.directory is required for the file source")
+		}
+	case blockListSourceStoredRequest:
+		if lists.StoredRequest.Endpoint == "" {
+			return cfg, errors.New("block_lists.stored_request.endpoint is required for the stored_request source")
+		}
+		if len(lists.StoredRequest.Names) == 0 {
+			return cfg, errors.New("block_lists.stored_request.names is required for the stored_request source")
+		}
+	default:
+		return cfg, fmt.Errorf("block_lists.source must be %q or %q", blockListSourceFile, blockListSourceStoredRequest)
+	}
+	if lists.RefreshIntervalSeconds < 0 {
+		return cfg, errors.New("block_lists.refresh_interval_seconds must not be negative")
+	}
+	return cfg, nil
 }
 
 type Attributes struct {
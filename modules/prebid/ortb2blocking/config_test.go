@@ -1,6 +1,7 @@
 package ortb2blocking
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/prebid/openrtb/v20/adcom1"
@@ -337,3 +338,54 @@ func TestOverride_UnmarshalJSON(t *testing.T) {
 	assert.NoError(t, override.UnmarshalJSON([]byte(`"string"`)), "Failed to unmarshal override with ignored value.")
 	assert.Equal(t, Override{}, override, "Empty override expected.")
 }
+
+func TestNewHostConfig(t *testing.T) {
+	testCases := []struct {
+		description   string
+		data          json.RawMessage
+		expectedError string
+	}{
+		{
+			description: "no config",
+		},
+		{
+			description: "file source",
+			data:        json.RawMessage(`{"block_lists": {"source": "file", "file": {"directory": "/etc/lists"}, "refresh_interval_seconds": 60}}`),
+		},
+		{
+			description: "stored_request source",
+			data:        json.RawMessage(`{"block_lists": {"source": "stored_request", "stored_request": {"endpoint": "http://lists", "names": ["malware"]}}}`),
+		},
+		{
+			description:   "file source without directory",
+			data:          json.RawMessage(`{"block_lists": {"source": "file"}}`),
+			expectedError: "block_lists.file.directory is required for the file source",
+		},
+		{
+			description:   "stored_request source without names",
+			data:          json.RawMessage(`{"block_lists": {"source": "stored_request", "stored_request": {"endpoint": "http://lists"}}}`),
+			expectedError: "block_lists.stored_request.names is required for the stored_request source",
+		},
+		{
+			description:   "unknown source",
+			data:          json.RawMessage(`{"block_lists": {"source": "redis"}}`),
+			expectedError: `block_lists.source must be "file" or "stored_request"`,
+		},
+		{
+			description:   "negative refresh interval",
+			data:          json.RawMessage(`{"block_lists": {"source": "file", "file": {"directory": "/etc/lists"}, "refresh_interval_seconds": -1}}`),
+			expectedError: "block_lists.refresh_interval_seconds must not be negative",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			_, err := newHostConfig(test.data)
+			if test.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.expectedError)
+			}
+		})
+	}
+}
@@ -14,6 +14,7 @@ import (
 
 func handleBidderRequestHook(
 	cfg config,
+	blockLists []*blockList,
 	payload hookstage.BidderRequestPayload,
 ) (result hookstage.HookResult[hookstage.BidderRequestPayload], err error) {
 	if payload.Request == nil || payload.Request.BidRequest == nil {
@@ -45,6 +46,7 @@ func handleBidderRequestHook(
 	}
 
 	updateCatTax(cfg, payload, &blockingAttributes, &changeSet)
+	updateFromBlockLists(blockLists, payload, blockingAttributes, &changeSet)
 
 	result.ChangeSet = changeSet
 	result.ModuleContext = hookstage.ModuleContext{payload.Bidder: blockingAttributes}
@@ -199,6 +201,39 @@ func updateCatTax(
 	changeSet.BidderRequest().CatTax().Update(attributes.catTax)
 }
 
+// updateFromBlockLists adds the advertiser domains, categories and apps of the shared block lists to the
+// values sent to the bidder. Wildcard domains are sent as the domain they apply to.
+func updateFromBlockLists(
+	blockLists []*blockList,
+	payload hookstage.BidderRequestPayload,
+	attributes blockingAttributes,
+	changeSet *hookstage.ChangeSet[hookstage.BidderRequestPayload],
+) {
+	if len(blockLists) == 0 {
+		return
+	}
+
+	var badv, bcat, bapp []string
+	for _, list := range blockLists {
+		for _, domain := range list.data.Badv {
+			badv = append(badv, strings.TrimPrefix(domain, blockListWildcardPrefix))
+		}
+		bcat = append(bcat, list.data.Bcat...)
+		bapp = append(bapp, list.data.Bapp...)
+	}
+
+	// the attributes only hold config values when the request has none, so at most one of both is set
+	if len(badv) > 0 {
+		changeSet.BidderRequest().BAdv().Update(mergeUnique(payload.Request.BAdv, attributes.bAdv, badv))
+	}
+	if len(bcat) > 0 {
+		changeSet.BidderRequest().BCat().Update(mergeUnique(payload.Request.BCat, attributes.bCat, bcat))
+	}
+	if len(bapp) > 0 {
+		changeSet.BidderRequest().BApp().Update(mergeUnique(payload.Request.BApp, attributes.bApp, bapp))
+	}
+}
+
 func bTypeMutation(bTypeByImp map[string][]int) hookstage.MutationFunc[hookstage.BidderRequestPayload] {
 	return mutationForImp(bTypeByImp, func(imp openrtb2.Imp, btype []int) openrtb2.Imp {
 		imp.Banner.BType = make([]openrtb2.BannerAdType, len(btype))
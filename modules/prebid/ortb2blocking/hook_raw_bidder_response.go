@@ -14,6 +14,8 @@ import (
 
 func handleRawBidderResponseHook(
 	cfg config,
+	blockLists []*blockList,
+	metrics metricsRecorder,
 	payload hookstage.RawBidderResponsePayload,
 	moduleCtx hookstage.ModuleContext,
 ) (result hookstage.HookResult[hookstage.RawBidderResponsePayload], err error) {
@@ -66,6 +68,8 @@ func handleRawBidderResponseHook(
 			return result, hookexecution.NewFailure("failed to process battr block checking: %s", err)
 		}
 
+		failedChecksData = shouldBeBlockedDueToBlockLists(blockLists, metrics, bid.Bid, bidder, failedChecksData)
+
 		if len(failedChecksData) == 0 {
 			addAllowedAnalyticTag(&result, bidder, bid.Bid.ImpID)
 			allowedBids = append(allowedBids, bid)
@@ -282,6 +286,29 @@ func shouldBeBlockedDueToBattr(
 	return failedChecksData, nil
 }
 
+// shouldBeBlockedDueToBlockLists checks the bid against the shared block lists of the account,
+// which are enforced regardless of the enforce_blocks and deal exception settings of the attributes
+func shouldBeBlockedDueToBlockLists(
+	blockLists []*blockList,
+	metrics metricsRecorder,
+	bid *openrtb2.Bid,
+	bidder string,
+	failedChecksData map[string]interface{},
+) map[string]interface{} {
+	var hits []string
+	for _, list := range blockLists {
+		for _, hit := range list.match(bid) {
+			metrics.recordBlockListHit(bidder, hit)
+			hits = append(hits, hit.String())
+		}
+	}
+
+	if len(hits) > 0 {
+		failedChecksData["block_lists"] = hits
+	}
+	return failedChecksData
+}
+
 func blockAttribute[Attribute comparable](attributes, blockedAttributes, dealExceptions []Attribute, blockUnknown bool) (bool, []Attribute) {
 	if len(attributes) == 0 {
 		if blockUnknown {
@@ -366,12 +393,13 @@ func addDebugMessage(
 // returns a slice with the names of failed attributes
 func getFailedAttributes(data map[string]interface{}) []string {
 	var builder []string
-	for _, attribute := range [5]string{
+	for _, attribute := range [6]string{
 		"badv",
 		"bcat",
 		"cattax",
 		"bapp",
 		"battr",
+		"block_lists",
 	} {
 		if _, ok := data[attribute]; ok {
 			builder = append(builder, attribute)
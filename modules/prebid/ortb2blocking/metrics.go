@@ -0,0 +1,59 @@
+package ortb2blocking
+
+import (
+	"errors"
+
+	metrics_cfg "github.com/prebid/prebid-server/v3/metrics/config"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// metricsRecorder counts the shared block list entries matched by bids. The entry label is the configured entry,
+// e.g. the *.evil.com pattern rather than the domain of the bid, so its values are bounded by the lists.
+type metricsRecorder interface {
+	recordBlockListHit(bidder string, hit blockListHit)
+}
+
+type nilMetrics struct{}
+
+func (nilMetrics) recordBlockListHit(string, blockListHit) {}
+
+type prometheusMetrics struct {
+	blockListHits *prometheus.CounterVec
+}
+
+func (m prometheusMetrics) recordBlockListHit(bidder string, hit blockListHit) {
+	m.blockListHits.With(prometheus.Labels{
+		"bidder":    bidder,
+		"list":      hit.list,
+		"attribute": hit.attribute,
+		"entry":     hit.entry,
+	}).Inc()
+}
+
+// newMetrics registers the module metrics in the Prometheus registry of the host when there is one
+func newMetrics(deps moduledeps.ModuleDeps) (metricsRecorder, error) {
+	if deps.MetricsCfg == nil || deps.MetricsRegistry == nil {
+		return nilMetrics{}, nil
+	}
+	registry, ok := deps.MetricsRegistry[metrics_cfg.PrometheusRegistry].(*prometheus.Registry)
+	if !ok || registry == nil {
+		return nilMetrics{}, nil
+	}
+
+	blockListHits := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: deps.MetricsCfg.Prometheus.Namespace,
+		Subsystem: deps.MetricsCfg.Prometheus.Subsystem,
+		Name:      "ortb2blocking_block_list_hits",
+		Help:      "Count of bids matching a shared block list entry by bidder, list, attribute and entry.",
+	}, []string{"bidder", "list", "attribute", "entry"})
+
+	if err := registry.Register(blockListHits); err != nil {
+		var alreadyRegistered prometheus.AlreadyRegisteredError
+		if !errors.As(err, &alreadyRegistered) {
+			return nil, err
+		}
+		blockListHits = alreadyRegistered.ExistingCollector.(*prometheus.CounterVec)
+	}
+	return prometheusMetrics{blockListHits: blockListHits}, nil
+}
@@ -9,11 +9,30 @@ import (
 	"github.com/prebid/prebid-server/v3/modules/moduledeps"
 )
 
-func Builder(_ json.RawMessage, _ moduledeps.ModuleDeps) (interface{}, error) {
-	return Module{}, nil
+func Builder(data json.RawMessage, deps moduledeps.ModuleDeps) (interface{}, error) {
+	cfg, err := newHostConfig(data)
+	if err != nil {
+		return nil, err
+	}
+
+	blockLists, err := newBlockListStore(cfg.BlockLists, deps.HTTPClient)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics, err := newMetrics(deps)
+	if err != nil {
+		blockLists.Close()
+		return nil, err
+	}
+
+	return Module{blockLists: blockLists, metrics: metrics}, nil
 }
 
-type Module struct{}
+type Module struct {
+	blockLists *blockListStore
+	metrics    metricsRecorder
+}
 
 // HandleBidderRequestHook updates blocking fields on the openrtb2.BidRequest.
 // Fields are updated only if request satisfies conditions provided by the module config.
@@ -32,7 +51,10 @@ func (m Module) HandleBidderRequestHook(
 		return result, err
 	}
 
-	return handleBidderRequestHook(cfg, payload)
+	blockLists, warnings := m.blockLists.get(cfg.BlockLists)
+	result, err = handleBidderRequestHook(cfg, blockLists, payload)
+	result.Warnings = mergeStrings(result.Warnings, warnings...)
+	return result, err
 }
 
 // HandleRawBidderResponseHook rejects bids for a specific bidder if they fail the attribute check.
@@ -51,7 +73,12 @@ func (m Module) HandleRawBidderResponseHook(
 		cfg = ncfg
 	}
 
-	return handleRawBidderResponseHook(cfg, payload, miCtx.ModuleContext)
+	blockLists, _ := m.blockLists.get(cfg.BlockLists)
+	return handleRawBidderResponseHook(cfg, blockLists, m.metrics, payload, miCtx.ModuleContext)
+}
+
+func (m Module) Shutdown() error {
+	return m.blockLists.Close()
 }
 
 type blockingAttributes struct {
@@ -4,17 +4,23 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/prebid/openrtb/v20/adcom1"
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v3/adapters"
+	mainConfig "github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
 	"github.com/prebid/prebid-server/v3/hooks/hookexecution"
 	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	metrics_cfg "github.com/prebid/prebid-server/v3/metrics/config"
 	"github.com/prebid/prebid-server/v3/modules/moduledeps"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var testConfig = json.RawMessage(`
@@ -1572,3 +1578,112 @@ func TestHandleRawBidderResponseHook(t *testing.T) {
 		})
 	}
 }
+
+type fakeMetrics struct {
+	hits []string
+}
+
+func (m *fakeMetrics) recordBlockListHit(bidder string, hit blockListHit) {
+	m.hits = append(m.hits, bidder+" "+hit.String())
+}
+
+func TestBlockLists(t *testing.T) {
+	directory := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(directory, "malware.json"), []byte(`{"badv": ["bad.com", "*.evil.com"], "bapp": ["com.bad.app"], "crid": ["cr-1"]}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(directory, "adult.json"), []byte(`{"bcat": ["IAB25"], "deal_id": ["deal-1"]}`), 0644))
+
+	data := json.RawMessage(`{"block_lists": {"source": "file", "file": {"directory": "` + directory + `"}}}`)
+	built, err := Builder(data, moduledeps.ModuleDeps{})
+	require.NoError(t, err)
+	module := built.(Module)
+	defer module.Shutdown()
+
+	metrics := &fakeMetrics{}
+	module.metrics = metrics
+	accountConfig := json.RawMessage(`{"attributes": {"badv": {"blocked_adomain": ["a.com", "bad.com"]}}, "block_lists": ["malware", "adult", "missing"]}`)
+
+	t.Run("bidder_request", func(t *testing.T) {
+		payload := hookstage.BidderRequestPayload{
+			Bidder:  bidder,
+			Request: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{BCat: []string{"IAB1"}}},
+		}
+		result, err := module.HandleBidderRequestHook(context.Background(), hookstage.ModuleInvocationContext{AccountConfig: accountConfig}, payload)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Block list missing is not loaded"}, result.Warnings)
+
+		for _, mut := range result.ChangeSet.Mutations() {
+			payload, err = mut.Apply(payload)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, []string{"a.com", "bad.com", "evil.com"}, payload.Request.BAdv)
+		assert.Equal(t, []string{"IAB1", "IAB25"}, payload.Request.BCat)
+		assert.Equal(t, []string{"com.bad.app"}, payload.Request.BApp)
+	})
+
+	t.Run("raw_bidder_response", func(t *testing.T) {
+		allowed := &adapters.TypedBid{Bid: &openrtb2.Bid{ID: "1", ImpID: impID1, ADomain: []string{"good.com"}, CrID: "cr-2"}}
+		payload := hookstage.RawBidderResponsePayload{
+			Bidder: bidder,
+			BidderResponse: &adapters.BidderResponse{Bids: []*adapters.TypedBid{
+				allowed,
+				{Bid: &openrtb2.Bid{ID: "2", ImpID: impID1, ADomain: []string{"cdn.evil.com"}, CrID: "cr-1"}},
+				{Bid: &openrtb2.Bid{ID: "3", ImpID: impID1, ADomain: []string{"good.com"}, DealID: "deal-1"}},
+			}},
+		}
+		result, err := module.HandleRawBidderResponseHook(context.Background(), hookstage.ModuleInvocationContext{AccountConfig: accountConfig}, payload)
+		require.NoError(t, err)
+
+		for _, mut := range result.ChangeSet.Mutations() {
+			payload, err = mut.Apply(payload)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, []*adapters.TypedBid{allowed}, payload.BidderResponse.Bids)
+		assert.Equal(t, []string{
+			"Bid 2 from bidder appnexus has been rejected, failed checks: block_lists",
+			"Bid 3 from bidder appnexus has been rejected, failed checks: block_lists",
+		}, result.DebugMessages)
+		assert.Equal(t, []string{"malware.badv:*.evil.com", "malware.crid:cr-1"}, result.AnalyticsTags.Activities[0].Results[1].Values["block_lists"])
+		assert.Equal(t, []string{
+			"appnexus malware.badv:*.evil.com",
+			"appnexus malware.crid:cr-1",
+			"appnexus adult.deal_id:deal-1",
+		}, metrics.hits)
+	})
+}
+
+func TestNewMetrics(t *testing.T) {
+	metrics, err := newMetrics(moduledeps.ModuleDeps{})
+	require.NoError(t, err)
+	assert.Equal(t, nilMetrics{}, metrics)
+
+	registry := prometheus.NewRegistry()
+	deps := moduledeps.ModuleDeps{
+		MetricsCfg:      &mainConfig.Metrics{Prometheus: mainConfig.PrometheusMetrics{Namespace: "pbs"}},
+		MetricsRegistry: metrics_cfg.MetricsRegistry{metrics_cfg.PrometheusRegistry: registry},
+	}
+	first, err := newMetrics(deps)
+	require.NoError(t, err)
+	second, err := newMetrics(deps)
+	require.NoError(t, err, "creating the metrics again must reuse the registered counter")
+	assert.Same(t, first.(prometheusMetrics).blockListHits, second.(prometheusMetrics).blockListHits)
+
+	first.recordBlockListHit("appnexus", blockListHit{list: "malware", attribute: blockListBadv, entry: "*.evil.com"})
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	require.Len(t, families, 1)
+	assert.Equal(t, "pbs_ortb2blocking_block_list_hits", families[0].GetName())
+
+	labels := map[string]string{}
+	for _, label := range families[0].GetMetric()[0].GetLabel() {
+		labels[label.GetName()] = label.GetValue()
+	}
+	assert.Equal(t, map[string]string{"bidder": "appnexus", "list": "malware", "attribute": "badv", "entry": "*.evil.com"}, labels)
+}
+
+func TestBuilderBlockListErrors(t *testing.T) {
+	_, err := Builder(json.RawMessage(`{"block_lists": {"source": "file"}}`), moduledeps.ModuleDeps{})
+	assert.EqualError(t, err, "block_lists.file.directory is required for the file source")
+
+	_, err = Builder(json.RawMessage(`{"block_lists": {"source": "file", "file": {"directory": "missing"}}}`), moduledeps.ModuleDeps{})
+	assert.ErrorContains(t, err, "failed to read block list directory")
+}
@@ -17,6 +17,21 @@ func mergeStrings(messages []string, newMessages ...string) []string {
 	return messages
 }
 
+// mergeUnique concatenates the lists, dropping duplicate values
+func mergeUnique(lists ...[]string) []string {
+	var merged []string
+	seen := make(map[string]struct{})
+	for _, list := range lists {
+		for _, value := range list {
+			if _, ok := seen[value]; !ok {
+				seen[value] = struct{}{}
+				merged = append(merged, value)
+			}
+		}
+	}
+	return merged
+}
+
 func hasMatches(list []string, s string) bool {
 	for _, val := range list {
 		if strings.EqualFold(val, s) {
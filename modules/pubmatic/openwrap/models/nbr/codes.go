@@ -38,4 +38,19 @@ const (
 	ResponseRejectedDSA            openrtb3.NoBidReason = 620 // Response Rejected - DSA
 	ResponseRejectedMissingParam   openrtb3.NoBidReason = 621 // Response rejected due to missing required parameter
 	APSSlotUUIDNotMapped           openrtb3.NoBidReason = 622 // APS entrypoint: slot UUID not mapped to OW ad unit / profile
+
+	// Response rejected as the DSA object of the bid violates the DSA transparency rules of the request
+	ResponseRejectedDSABehalfTooLong       openrtb3.NoBidReason = 623 // Response Rejected - DSA behalf too long
+	ResponseRejectedDSAPaidTooLong         openrtb3.NoBidReason = 624 // Response Rejected - DSA paid too long
+	ResponseRejectedDSARenderConflict      openrtb3.NoBidReason = 625 // Response Rejected - DSA publisher and buyer rendering conflict
+	ResponseRejectedDSATransparencyInvalid openrtb3.NoBidReason = 626 // Response Rejected - DSA transparency domain or dsaparams invalid
+	ResponseRejectedDSAParamsNotDeclared   openrtb3.NoBidReason = 627 // Response Rejected - DSA transparency dsaparams not declared by the publisher
+
+	// Response rejected by the creative validation module
+	ResponseRejectedInvalidVAST      openrtb3.NoBidReason = 628 // Response Rejected - VAST markup is not well-formed
+	ResponseRejectedVASTVersion      openrtb3.NoBidReason = 629 // Response Rejected - VAST version below the minimum or not in imp.video.protocols
+	ResponseRejectedMediaFileMIME    openrtb3.NoBidReason = 630 // Response Rejected - no media file matches imp.video.mimes
+	ResponseRejectedVideoDuration    openrtb3.NoBidReason = 631 // Response Rejected - duration outside imp.video.minduration/maxduration
+	ResponseRejectedNativeAssets     openrtb3.NoBidReason = 632 // Response Rejected - native assets do not match the request template
+	ResponseRejectedDisallowedMarkup openrtb3.NoBidReason = 633 // Response Rejected - markup contains an auto-redirect, document.write or a disallowed pattern
 )
@@ -15,18 +15,26 @@ type ExtBid struct {
 // DealPriority represents priority of deal bid. If its non deal bid then value will be 0
 // DealTierSatisfied true represents corresponding bid has satisfied the deal tier
 type ExtBidPrebid struct {
-	Cache             *ExtBidPrebidCache  `json:"cache,omitempty"`
-	DealPriority      int                 `json:"dealpriority,omitempty"`
-	DealTierSatisfied bool                `json:"dealtiersatisfied,omitempty"`
-	Meta              *ExtBidPrebidMeta   `json:"meta,omitempty"`
-	Targeting         map[string]string   `json:"targeting,omitempty"`
-	TargetBidderCode  string              `json:"targetbiddercode,omitempty"`
-	Type              BidType             `json:"type,omitempty"`
-	Video             *ExtBidPrebidVideo  `json:"video,omitempty"`
-	Events            *ExtBidPrebidEvents `json:"events,omitempty"`
-	BidId             string              `json:"bidid,omitempty"`
-	Passthrough       json.RawMessage     `json:"passthrough,omitempty"`
-	Floors            *ExtBidPrebidFloors `json:"floors,omitempty"`
+	Cache             *ExtBidPrebidCache    `json:"cache,omitempty"`
+	DealPriority      int                   `json:"dealpriority,omitempty"`
+	DealTierSatisfied bool                  `json:"dealtiersatisfied,omitempty"`
+	Meta              *ExtBidPrebidMeta     `json:"meta,omitempty"`
+	Targeting         map[string]string     `json:"targeting,omitempty"`
+	TargetBidderCode  string                `json:"targetbiddercode,omitempty"`
+	Type              BidType               `json:"type,omitempty"`
+	Video             *ExtBidPrebidVideo    `json:"video,omitempty"`
+	Events            *ExtBidPrebidEvents   `json:"events,omitempty"`
+	BidId             string                `json:"bidid,omitempty"`
+	Passthrough       json.RawMessage       `json:"passthrough,omitempty"`
+	Floors            *ExtBidPrebidFloors   `json:"floors,omitempty"`
+	Clearing          *ExtBidPrebidClearing `json:"clearing,omitempty"`
+}
+
+// ExtBidPrebidClearing defines the contract for bidresponse.seatbid.bid[i].ext.prebid.clearing
+type ExtBidPrebidClearing struct {
+	Mode       string  `json:"mode"`
+	BidPrice   float64 `json:"bidprice"`
+	ClearPrice float64 `json:"clearprice"`
 }
 
 // ExtBidPrebidFloors defines the contract for bidresponse.seatbid.bid[i].ext.prebid.floors
@@ -174,6 +182,13 @@ const (
 	EnvAmpValue string = "amp"
 
 	CategoryDurationKey TargetingKey = "_pb_cat_dur"
+
+	// DSAAdRenderKey, DSABehalfKey and DSAPaidKey hold the DSA transparency information of the bid, so the ad server
+	// can render the "about this ad" disclosure. They exist only if the bid carries a DSA object. The behalf and paid
+	// values are free text of the bidder, they are stripped of the characters unsafe for the ad server and truncated.
+	DSAAdRenderKey TargetingKey = "_dsa_adrender"
+	DSABehalfKey   TargetingKey = "_dsa_behalf"
+	DSAPaidKey     TargetingKey = "_dsa_paid"
 )
 
 func (key TargetingKey) BidderKey(prefix string, bidder BidderName, maxLength int) string {
@@ -0,0 +1,10 @@
+package openrtb_ext
+
+// DSARecord describes the DSA transparency information of a bid returned by the auction, it is forwarded to the
+// analytics modules
+type DSARecord struct {
+	Seat  string    `json:"seat"`
+	ImpID string    `json:"impid"`
+	BidID string    `json:"bidid"`
+	DSA   ExtBidDSA `json:"dsa"`
+}
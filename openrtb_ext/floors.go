@@ -100,6 +100,18 @@ type PriceFloorModelGroup struct {
 	Schema       PriceFloorSchema   `json:"schema,omitempty"`
 	Values       map[string]float64 `json:"values,omitempty"`
 	Default      float64            `json:"default,omitempty"`
+	// ValueCurrencies holds the currency of the floor rules whose value is not in the model group currency
+	ValueCurrencies map[string]string `json:"valuecurrencies,omitempty"`
+	// RuleIndex, if set, is the rule index compiled when the model group was fetched, it is shared by the requests
+	// using the fetched floors data and must not be modified
+	RuleIndex PriceFloorRuleIndex `json:"-"`
+}
+
+// PriceFloorRuleIndex selects the most specific floor rule of a model group matching the rule key derived from a request
+type PriceFloorRuleIndex interface {
+	FindRule(desiredRuleKey []string) (string, bool)
+	// UserSegments returns the lower cased userSegment values of the rules, nil if the schema has no userSegment field
+	UserSegments() map[string]struct{}
 }
 
 func (mg PriceFloorModelGroup) Copy() PriceFloorModelGroup {
@@ -120,6 +132,10 @@ func (mg PriceFloorModelGroup) Copy() PriceFloorModelGroup {
 	for key, val := range mg.Values {
 		newMg.Values[key] = val
 	}
+	if mg.ValueCurrencies != nil {
+		newMg.ValueCurrencies = maps.Clone(mg.ValueCurrencies)
+	}
+	newMg.RuleIndex = mg.RuleIndex
 	return *newMg
 }
 
@@ -185,6 +201,8 @@ func (data *PriceFloorData) DeepCopy() *PriceFloorData {
 		eachGroup.SkipRate = data.ModelGroups[i].SkipRate
 		eachGroup.Values = maps.Clone(data.ModelGroups[i].Values)
 		eachGroup.Default = data.ModelGroups[i].Default
+		eachGroup.ValueCurrencies = maps.Clone(data.ModelGroups[i].ValueCurrencies)
+		eachGroup.RuleIndex = data.ModelGroups[i].RuleIndex
 		eachGroup.Schema = PriceFloorSchema{
 			Fields:    slices.Clone(data.ModelGroups[i].Schema.Fields),
 			Delimiter: data.ModelGroups[i].Schema.Delimiter,
@@ -18,6 +18,15 @@ const (
 	ServerSideWithIGSimulation AuctionEnvironmentType = 2
 )
 
+// ExtImpInterestGroupSeller defines the contract for bidrequest.imp[i].ext.igs
+type ExtImpInterestGroupSeller struct {
+	// AE is the auction environment of the imp, it takes precedence over imp[i].ext.ae
+	AE       *AuctionEnvironmentType `json:"ae,omitempty"`
+	Biddable *int8                   `json:"biddable,omitempty"`
+	// SellerSignals are merged into the sellerSignals of the auction configs returned for the imp
+	SellerSignals json.RawMessage `json:"sellersignals,omitempty"`
+}
+
 // IsRewardedInventoryKey is the json key for ExtImpPrebid.IsRewardedInventory
 const IsRewardedInventoryKey = "is_rewarded_inventory"
 
@@ -69,6 +78,10 @@ type ExtImpPrebidFloors struct {
 	FloorValue     float64 `json:"floorvalue,omitempty"`
 	FloorMin       float64 `json:"floormin,omitempty"`
 	FloorMinCur    string  `json:"floorminCur,omitempty"`
+	// FloorRuleOrigValue and FloorRuleOrigCur are set when the matched rule has its own currency
+	// and its value was converted to the floors currency
+	FloorRuleOrigValue float64 `json:"floorruleorigvalue,omitempty"`
+	FloorRuleOrigCur   string  `json:"floorruleorigcur,omitempty"`
 }
 
 // ExtStoredRequest defines the contract for bidrequest.imp[i].ext.prebid.storedrequest
@@ -0,0 +1,17 @@
+package openrtb_ext
+
+// Results of a privacy audit record
+const (
+	PrivacyAuditResultAllow = "allow"
+	PrivacyAuditResultDeny  = "deny"
+)
+
+// PrivacyAuditRecord defines the contract for bidresponse.ext.prebid.privacyaudit[], it describes the privacy
+// decision taken for an activity of a bidder and the policy which took it
+type PrivacyAuditRecord struct {
+	Bidder    string `json:"bidder"`
+	Activity  string `json:"activity"`
+	Policy    string `json:"policy"`
+	RuleIndex *int   `json:"ruleindex,omitempty"`
+	Result    string `json:"result"`
+}
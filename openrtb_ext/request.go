@@ -29,6 +29,9 @@ const TIDKey = "tid"
 // AuctionEnvironmentKey is the json key under imp[].ext for ExtImp.AuctionEnvironment
 const AuctionEnvironmentKey = string(BidderReservedAE)
 
+// InterestGroupSellerKey is the json key under imp[].ext for ExtImpInterestGroupSeller
+const InterestGroupSellerKey = string(BidderReservedIGS)
+
 // NativeExchangeSpecificLowerBound defines the lower threshold of exchange specific types for native ads. There is no upper bound.
 const NativeExchangeSpecificLowerBound = 500
 
@@ -216,6 +219,12 @@ type ExtRequestTargeting struct {
 	AppendBidderNames         bool                       `json:"appendbiddernames,omitempty"`
 	AlwaysIncludeDeals        bool                       `json:"alwaysincludedeals,omitempty"`
 	Prefix                    string                     `json:"prefix,omitempty"`
+
+	// BidderPriceGranularity, DealPriceGranularity and CurrencyPriceGranularity hold bucket tables
+	// keyed by bidder code, deal id and auction currency respectively.
+	BidderPriceGranularity   map[string]*PriceGranularity `json:"bidderpricegranularity,omitempty"`
+	DealPriceGranularity     map[string]*PriceGranularity `json:"dealpricegranularity,omitempty"`
+	CurrencyPriceGranularity map[string]*PriceGranularity `json:"currencypricegranularity,omitempty"`
 }
 
 type ExtIncludeBrandCategory struct {
@@ -239,15 +248,56 @@ type PriceGranularity struct {
 	Test      bool               `json:"test,omitempty"`
 	Precision *int               `json:"precision,omitempty"`
 	Ranges    []GranularityRange `json:"ranges,omitempty"`
+	// Rounding overrides the account bid rounding mode for this granularity
+	Rounding string `json:"rounding,omitempty"`
 }
 
 type PriceGranularityRaw PriceGranularity
 
+// Rounding modes supported by PriceGranularity.Rounding
+const (
+	PriceGranularityRoundingDown      = "down"
+	PriceGranularityRoundingUp        = "up"
+	PriceGranularityRoundingNearest   = "nearest"
+	PriceGranularityRoundingTimeSplit = "timesplit"
+)
+
 // GranularityRange struct defines a range of prices used by PriceGranularity
 type GranularityRange struct {
 	Min       float64 `json:"min"`
 	Max       float64 `json:"max"`
 	Increment float64 `json:"increment"`
+	// Scale is either linear, the default, or log. On a log scale the increment is the factor
+	// between consecutive buckets, counting down from max.
+	Scale string `json:"scale,omitempty"`
+}
+
+// Scales supported by GranularityRange.Scale
+const (
+	GranularityScaleLinear = "linear"
+	GranularityScaleLog    = "log"
+)
+
+// Clone returns a deep copy of the price granularity
+func (pg *PriceGranularity) Clone() *PriceGranularity {
+	if pg == nil {
+		return nil
+	}
+	clone := *pg
+	clone.Precision = ptrutil.Clone(pg.Precision)
+	clone.Ranges = slices.Clone(pg.Ranges)
+	return &clone
+}
+
+func clonePriceGranularityMap(m map[string]*PriceGranularity) map[string]*PriceGranularity {
+	if m == nil {
+		return nil
+	}
+	clone := make(map[string]*PriceGranularity, len(m))
+	for key, pg := range m {
+		clone[key] = pg.Clone()
+	}
+	return clone
 }
 
 func (pg *PriceGranularity) UnmarshalJSON(b []byte) error {
@@ -526,13 +576,10 @@ func (erp *ExtRequestPrebid) Clone() *ExtRequestPrebid {
 			AppendBidderNames: erp.Targeting.AppendBidderNames,
 			Prefix:            erp.Targeting.Prefix,
 		}
-		if erp.Targeting.PriceGranularity != nil {
-			newPriceGranularity := &PriceGranularity{
-				Ranges: slices.Clone(erp.Targeting.PriceGranularity.Ranges),
-			}
-			newPriceGranularity.Precision = ptrutil.Clone(erp.Targeting.PriceGranularity.Precision)
-			newTargeting.PriceGranularity = newPriceGranularity
-		}
+		newTargeting.PriceGranularity = erp.Targeting.PriceGranularity.Clone()
+		newTargeting.BidderPriceGranularity = clonePriceGranularityMap(erp.Targeting.BidderPriceGranularity)
+		newTargeting.DealPriceGranularity = clonePriceGranularityMap(erp.Targeting.DealPriceGranularity)
+		newTargeting.CurrencyPriceGranularity = clonePriceGranularityMap(erp.Targeting.CurrencyPriceGranularity)
 		newTargeting.IncludeWinners = ptrutil.Clone(erp.Targeting.IncludeWinners)
 		newTargeting.IncludeBidderKeys = ptrutil.Clone(erp.Targeting.IncludeBidderKeys)
 		if erp.Targeting.IncludeBrandCategory != nil {
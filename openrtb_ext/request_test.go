@@ -521,6 +521,13 @@ func TestCloneExtRequestPrebid(t *testing.T) {
 						TranslateCategories: ptrutil.ToPtr(true),
 					},
 					DurationRangeSec: []int{1, 2, 3},
+					DealPriceGranularity: map[string]*PriceGranularity{
+						"deal-1": {
+							Precision: ptrutil.ToPtr(2),
+							Ranges:    []GranularityRange{{Max: 20.0, Increment: 2.0, Scale: GranularityScaleLog}},
+							Rounding:  PriceGranularityRoundingUp,
+						},
+					},
 				},
 			},
 			prebidCopy: &ExtRequestPrebid{
@@ -540,6 +547,13 @@ func TestCloneExtRequestPrebid(t *testing.T) {
 						TranslateCategories: ptrutil.ToPtr(true),
 					},
 					DurationRangeSec: []int{1, 2, 3},
+					DealPriceGranularity: map[string]*PriceGranularity{
+						"deal-1": {
+							Precision: ptrutil.ToPtr(2),
+							Ranges:    []GranularityRange{{Max: 20.0, Increment: 2.0, Scale: GranularityScaleLog}},
+							Rounding:  PriceGranularityRoundingUp,
+						},
+					},
 				},
 			},
 			mutator: func(t *testing.T, prebid *ExtRequestPrebid) {
@@ -553,6 +567,10 @@ func TestCloneExtRequestPrebid(t *testing.T) {
 				prebid.Targeting.DurationRangeSec[1] = 5
 				prebid.Targeting.DurationRangeSec = append(prebid.Targeting.DurationRangeSec, 1)
 				prebid.Targeting.AppendBidderNames = true
+				prebid.Targeting.DealPriceGranularity["deal-1"].Ranges[0].Increment = 4.0
+				*prebid.Targeting.DealPriceGranularity["deal-1"].Precision = 3
+				prebid.Targeting.DealPriceGranularity["deal-1"].Rounding = PriceGranularityRoundingDown
+				prebid.Targeting.DealPriceGranularity["deal-2"] = &PriceGranularity{}
 			},
 		},
 		{
@@ -45,6 +45,18 @@ type ExtResponseDebug struct {
 	HttpCalls map[BidderName][]*ExtHttpCall `json:"httpcalls,omitempty"`
 	// Request after resolution of stored requests and debug overrides
 	ResolvedRequest json.RawMessage `json:"resolvedrequest,omitempty"`
+	// BidderTransforms defines the contract for bidresponse.ext.debug.biddertransforms
+	BidderTransforms map[BidderName][]ExtBidderTransformChange `json:"biddertransforms,omitempty"`
+}
+
+// ExtBidderTransformChange describes a change made to the request of a bidder by an account transformation rule.
+// Paths reference the request as it was before the rule applied.
+type ExtBidderTransformChange struct {
+	Op   string          `json:"op"`
+	Path string          `json:"path"`
+	From string          `json:"from,omitempty"`
+	Old  json.RawMessage `json:"old,omitempty"`
+	New  json.RawMessage `json:"new,omitempty"`
 }
 
 // ExtResponseSyncData defines the contract for bidresponse.ext.usersync.{bidder}
@@ -64,6 +76,8 @@ type ExtResponsePrebid struct {
 	// SeatNonBid holds the array of Bids which are either rejected, no bids inside bidresponse.ext.prebid.seatnonbid
 	SeatNonBid []SeatNonBid     `json:"seatnonbid,omitempty"`
 	Floors     *PriceFloorRules `json:"floors,omitempty"`
+	// PrivacyAudit holds the privacy decisions taken for each bidder, it is only set for debug requests
+	PrivacyAudit []PrivacyAuditRecord `json:"privacyaudit,omitempty"`
 }
 
 // FledgeResponse defines the contract for bidresponse.ext.fledge
@@ -80,6 +80,22 @@ func setDefaultsTargeting(targeting *openrtb_ext.ExtRequestTargeting) bool {
 		}
 	}
 
+	for _, pgs := range []map[string]*openrtb_ext.PriceGranularity{
+		targeting.BidderPriceGranularity,
+		targeting.DealPriceGranularity,
+		targeting.CurrencyPriceGranularity,
+	} {
+		for key, pg := range pgs {
+			if pg == nil {
+				continue
+			}
+			if newPG, updated := setDefaultsPriceGranularity(pg); updated {
+				modified = true
+				pgs[key] = newPG
+			}
+		}
+	}
+
 	if targeting.IncludeWinners == nil {
 		targeting.IncludeWinners = ptrutil.ToPtr(DefaultTargetingIncludeWinners)
 		modified = true
@@ -95,8 +111,13 @@ func setDefaultsTargeting(targeting *openrtb_ext.ExtRequestTargeting) bool {
 
 func setDefaultsPriceGranularity(pg *openrtb_ext.PriceGranularity) (*openrtb_ext.PriceGranularity, bool) {
 	if pg == nil || len(pg.Ranges) == 0 {
-		pg = ptrutil.ToPtr(openrtb_ext.NewPriceGranularityDefault())
-		return pg, true
+		defaultPG := openrtb_ext.NewPriceGranularityDefault()
+		// the default buckets replace the missing ones, the other settings of the granularity are kept
+		if pg != nil {
+			defaultPG.Test = pg.Test
+			defaultPG.Rounding = pg.Rounding
+		}
+		return &defaultPG, true
 	}
 
 	modified := false
@@ -219,6 +219,41 @@ func TestSetDefaultsTargeting(t *testing.T) {
 			},
 			expectedModified: true,
 		},
+		{
+			name: "populated-bidder-deal-currency-pricegranularity",
+			givenTargeting: &openrtb_ext.ExtRequestTargeting{
+				PriceGranularity: &defaultGranularity,
+				BidderPriceGranularity: map[string]*openrtb_ext.PriceGranularity{
+					"appnexus": {Ranges: []openrtb_ext.GranularityRange{{Max: 5, Increment: 0.5}, {Max: 10, Increment: 1}}},
+				},
+				DealPriceGranularity: map[string]*openrtb_ext.PriceGranularity{
+					"deal-1": {},
+				},
+				CurrencyPriceGranularity: map[string]*openrtb_ext.PriceGranularity{
+					"EUR": nil,
+				},
+				IncludeWinners:    ptrutil.ToPtr(false),
+				IncludeBidderKeys: ptrutil.ToPtr(false),
+			},
+			expectedTargeting: &openrtb_ext.ExtRequestTargeting{
+				PriceGranularity: &defaultGranularity,
+				BidderPriceGranularity: map[string]*openrtb_ext.PriceGranularity{
+					"appnexus": {
+						Precision: ptrutil.ToPtr(DefaultPriceGranularityPrecision),
+						Ranges:    []openrtb_ext.GranularityRange{{Min: 0, Max: 5, Increment: 0.5}, {Min: 5, Max: 10, Increment: 1}},
+					},
+				},
+				DealPriceGranularity: map[string]*openrtb_ext.PriceGranularity{
+					"deal-1": &defaultGranularity,
+				},
+				CurrencyPriceGranularity: map[string]*openrtb_ext.PriceGranularity{
+					"EUR": nil,
+				},
+				IncludeWinners:    ptrutil.ToPtr(false),
+				IncludeBidderKeys: ptrutil.ToPtr(false),
+			},
+			expectedModified: true,
+		},
 		{
 			name: "populated-ranges-empty",
 			givenTargeting: &openrtb_ext.ExtRequestTargeting{
@@ -419,6 +454,20 @@ func TestSetDefaultsPriceGranularity(t *testing.T) {
 			},
 			expectedModified: false,
 		},
+		{
+			name: "no-ranges-with-rounding-and-test",
+			givenGranularity: &openrtb_ext.PriceGranularity{
+				Test:     true,
+				Rounding: openrtb_ext.PriceGranularityRoundingUp,
+			},
+			expectedGranularity: &openrtb_ext.PriceGranularity{
+				Test:      true,
+				Precision: ptrutil.ToPtr(2),
+				Ranges:    openrtb_ext.NewPriceGranularityDefault().Ranges,
+				Rounding:  openrtb_ext.PriceGranularityRoundingUp,
+			},
+			expectedModified: true,
+		},
 	}
 
 	for _, test := range testCases {
@@ -0,0 +1,133 @@
+package prebid_cache_client
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type putFunc func(ctx context.Context, values []Cacheable) ([]string, []error)
+
+// writeBatcher coalesces the writes of concurrent auctions into shared requests. A batch is sent when the
+// window has elapsed since its first write, or as soon as it holds maxValues values.
+type writeBatcher struct {
+	putValues putFunc
+	window    time.Duration
+	maxValues int
+
+	mu        sync.Mutex
+	pending   []*batchedWrite
+	numValues int
+	timer     *time.Timer
+}
+
+type batchedWrite struct {
+	ctx    context.Context
+	values []Cacheable
+	done   chan batchResult
+}
+
+type batchResult struct {
+	uuids []string
+	errs  []error
+}
+
+func newWriteBatcher(put putFunc, window time.Duration, maxValues int) *writeBatcher {
+	return &writeBatcher{
+		putValues: put,
+		window:    window,
+		maxValues: maxValues,
+	}
+}
+
+// put adds the values to the pending batch and waits for the batch to be sent. The values are reported as
+// not cached if the context is done first.
+func (b *writeBatcher) put(ctx context.Context, values []Cacheable) ([]string, []error) {
+	write := &batchedWrite{
+		ctx:    ctx,
+		values: values,
+		done:   make(chan batchResult, 1),
+	}
+
+	b.mu.Lock()
+	b.pending = append(b.pending, write)
+	b.numValues += len(values)
+	var full []*batchedWrite
+	if b.numValues >= b.maxValues {
+		full = b.takePending()
+	} else if b.timer == nil {
+		b.timer = time.AfterFunc(b.window, b.flush)
+	}
+	b.mu.Unlock()
+
+	if full != nil {
+		b.send(full)
+	}
+
+	select {
+	case result := <-write.done:
+		return result.uuids, result.errs
+	case <-ctx.Done():
+		errs := make([]error, 0, 1)
+		logError(&errs, "Error sending the values to Prebid Cache: %v while waiting for the batch; Items=%v", ctx.Err(), len(values))
+		return make([]string, len(values)), errs
+	}
+}
+
+func (b *writeBatcher) flush() {
+	b.mu.Lock()
+	batch := b.takePending()
+	b.mu.Unlock()
+
+	b.send(batch)
+}
+
+// takePending must be called with the lock held
+func (b *writeBatcher) takePending() []*batchedWrite {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	batch := b.pending
+	b.pending = nil
+	b.numValues = 0
+	return batch
+}
+
+// send writes the values of the batch in one request, bounded by the earliest deadline of its writes, and
+// hands every write its own UUIDs. The errors are shared by the writes of the batch.
+func (b *writeBatcher) send(batch []*batchedWrite) {
+	var values []Cacheable
+	var deadline time.Time
+	writes := batch[:0]
+	for _, write := range batch {
+		if write.ctx.Err() != nil {
+			continue
+		}
+		if d, ok := write.ctx.Deadline(); ok && (deadline.IsZero() || d.Before(deadline)) {
+			deadline = d
+		}
+		values = append(values, write.values...)
+		writes = append(writes, write)
+	}
+	if len(writes) == 0 {
+		return
+	}
+
+	ctx := context.Background()
+	if !deadline.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+
+	uuids, errs := b.putValues(ctx, values)
+	offset := 0
+	for _, write := range writes {
+		write.done <- batchResult{
+			uuids: uuids[offset : offset+len(write.values)],
+			errs:  errs,
+		}
+		offset += len(write.values)
+	}
+}
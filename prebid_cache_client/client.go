@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
 	"strconv"
 	"strings"
@@ -52,14 +53,19 @@ type Cacheable struct {
 }
 
 func NewClient(httpClient *http.Client, conf *config.Cache, extCache *config.ExternalCache, metrics metrics.MetricsEngine) Client {
-	return &clientImpl{
+	client := &clientImpl{
 		httpClient:          httpClient,
 		putUrl:              conf.GetBaseURL() + "/cache",
 		externalCacheScheme: extCache.Scheme,
 		externalCacheHost:   extCache.Host,
 		externalCachePath:   extCache.Path,
+		retries:             conf.WriteRetries,
 		metrics:             metrics,
 	}
+	if conf.WriteBatching.Enabled {
+		client.batcher = newWriteBatcher(client.putWithRetries, time.Duration(conf.WriteBatching.WindowMS)*time.Millisecond, conf.WriteBatching.MaxValues)
+	}
+	return client
 }
 
 type clientImpl struct {
@@ -68,40 +74,88 @@ type clientImpl struct {
 	externalCacheScheme string
 	externalCacheHost   string
 	externalCachePath   string
+	retries             config.CacheWriteRetries
+	batcher             *writeBatcher
 	metrics             metrics.MetricsEngine
 }
 
 func (c *clientImpl) GetExtCacheData() (string, string, string) {
-	path := c.externalCachePath
+	return c.externalCacheScheme, c.externalCacheHost, normalizeExtCachePath(c.externalCachePath)
+}
+
+func normalizeExtCachePath(path string) string {
 	if path == "/" {
 		// Only the slash for the path, remove it to empty
-		path = ""
+		return ""
 	} else if len(path) > 0 && !strings.HasPrefix(path, "/") {
 		// Path defined but does not start with "/", prepend it
-		path = "/" + path
+		return "/" + path
 	}
-
-	return c.externalCacheScheme, c.externalCacheHost, path
+	return path
 }
 
 func (c *clientImpl) PutJson(ctx context.Context, values []Cacheable) (uuids []string, errs []error) {
-	errs = make([]error, 0, 1)
 	if len(values) < 1 {
-		return nil, errs
+		return nil, make([]error, 0, 1)
+	}
+
+	if c.batcher != nil {
+		uuids, errs = c.batcher.put(ctx, values)
+	} else {
+		uuids, errs = c.putWithRetries(ctx, values)
+	}
+	recordWriteErrors(c.metrics, values, uuids)
+	return uuids, errs
+}
+
+// putWithRetries sends the values to Prebid Cache, retrying the failed requests while the context allows it.
+// Only the errors of the last attempt are returned.
+func (c *clientImpl) putWithRetries(ctx context.Context, values []Cacheable) ([]string, []error) {
+	for attempt := 0; ; attempt++ {
+		uuids, errs, retryable := c.put(ctx, values)
+		if !retryable || attempt >= c.retries.MaxRetries {
+			return uuids, errs
+		}
+		delay := c.retryDelay(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return uuids, errs
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return uuids, errs
+		}
+	}
+}
+
+// retryDelay returns the exponential backoff of the attempt with a random jitter of up to half of it
+func (c *clientImpl) retryDelay(attempt int) time.Duration {
+	backoff := time.Duration(c.retries.BackoffMS) * time.Millisecond << attempt
+	if maxBackoff := time.Duration(c.retries.MaxBackoffMS) * time.Millisecond; backoff > maxBackoff || backoff <= 0 {
+		backoff = maxBackoff
+	}
+	if backoff <= 0 {
+		return 0
 	}
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
 
+// put sends one request to Prebid Cache. It reports whether the request may succeed if retried, which is the
+// case when Prebid Cache couldn't be reached or returned a server error.
+func (c *clientImpl) put(ctx context.Context, values []Cacheable) (uuids []string, errs []error, retryable bool) {
+	errs = make([]error, 0, 1)
 	uuidsToReturn := make([]string, len(values))
 
 	postBody, err := encodeValues(values)
 	if err != nil {
 		logError(&errs, "Error creating JSON for prebid cache: %v", err)
-		return uuidsToReturn, errs
+		return uuidsToReturn, errs, false
 	}
 
 	httpReq, err := http.NewRequest("POST", c.putUrl, bytes.NewReader(postBody))
 	if err != nil {
 		logError(&errs, "Error creating POST request to prebid cache: %v", err)
-		return uuidsToReturn, errs
+		return uuidsToReturn, errs, false
 	}
 
 	httpReq.Header.Add("Content-Type", "application/json;charset=utf-8")
@@ -113,7 +167,7 @@ func (c *clientImpl) PutJson(ctx context.Context, values []Cacheable) (uuids []s
 	if err != nil {
 		c.metrics.RecordPrebidCacheRequestTime(false, elapsedTime)
 		logError(&errs, "Error sending the request to Prebid Cache: %v; Duration=%v, Items=%v, Payload Size=%v", err, elapsedTime, len(values), len(postBody))
-		return uuidsToReturn, errs
+		return uuidsToReturn, errs, ctx.Err() == nil
 	}
 	defer anResp.Body.Close()
 
@@ -121,7 +175,7 @@ func (c *clientImpl) PutJson(ctx context.Context, values []Cacheable) (uuids []s
 	if anResp.StatusCode != 200 || err != nil {
 		c.metrics.RecordPrebidCacheRequestTime(false, elapsedTime)
 		logError(&errs, "Prebid Cache call to %s returned %d: %s", c.putUrl, anResp.StatusCode, responseBody)
-		return uuidsToReturn, errs
+		return uuidsToReturn, errs, anResp.StatusCode >= http.StatusInternalServerError && ctx.Err() == nil
 	}
 	c.metrics.RecordPrebidCacheRequestTime(true, elapsedTime)
 
@@ -142,10 +196,19 @@ func (c *clientImpl) PutJson(ctx context.Context, values []Cacheable) (uuids []s
 
 	if _, err := jsonparser.ArrayEach(responseBody, processResponse, "responses"); err != nil {
 		logError(&errs, "Error interpreting Prebid Cache response: %v\nResponse was: %s", err, string(responseBody))
-		return uuidsToReturn, errs
+		return uuidsToReturn, errs, false
 	}
 
-	return uuidsToReturn, errs
+	return uuidsToReturn, errs, false
+}
+
+// recordWriteErrors records the values which couldn't be cached by their type
+func recordWriteErrors(metricsEngine metrics.MetricsEngine, values []Cacheable, uuids []string) {
+	for i, uuid := range uuids {
+		if len(uuid) == 0 {
+			metricsEngine.RecordPrebidCacheWriteError(metrics.PrebidCacheType(values[i].Type))
+		}
+	}
 }
 
 func logError(errs *[]error, format string, a ...interface{}) {
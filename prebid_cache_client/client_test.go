@@ -5,10 +5,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/metrics"
@@ -50,6 +53,7 @@ func TestBadResponse(t *testing.T) {
 
 	metricsMock := &metrics.MetricsEngineMock{}
 	metricsMock.On("RecordPrebidCacheRequestTime", false, mock.Anything).Once()
+	metricsMock.On("RecordPrebidCacheWriteError", metrics.PrebidCacheJSON).Twice()
 
 	client := &clientImpl{
 		httpClient: server.Client(),
@@ -118,6 +122,7 @@ func TestCancelledContext(t *testing.T) {
 	for _, testCase := range testCases {
 		metricsMock := &metrics.MetricsEngineMock{}
 		metricsMock.On("RecordPrebidCacheRequestTime", false, mock.Anything).Once()
+		metricsMock.On("RecordPrebidCacheWriteError", metrics.PrebidCacheJSON).Times(testCase.expectedItems)
 
 		client := &clientImpl{
 			httpClient: stubServer.Client(),
@@ -169,6 +174,191 @@ func TestSuccessfulPut(t *testing.T) {
 	metricsMock.AssertExpectations(t)
 }
 
+func TestPutRetries(t *testing.T) {
+	testCases := []struct {
+		name             string
+		statusCodes      []int
+		maxRetries       int
+		expectedRequests int
+		expectedIDs      []string
+	}{
+		{
+			name:             "retried_server_error",
+			statusCodes:      []int{http.StatusServiceUnavailable, http.StatusOK},
+			maxRetries:       2,
+			expectedRequests: 2,
+			expectedIDs:      []string{"0"},
+		},
+		{
+			name:             "retries_exhausted",
+			statusCodes:      []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusOK},
+			maxRetries:       1,
+			expectedRequests: 2,
+			expectedIDs:      []string{""},
+		},
+		{
+			name:             "client_error_not_retried",
+			statusCodes:      []int{http.StatusBadRequest, http.StatusOK},
+			maxRetries:       2,
+			expectedRequests: 1,
+			expectedIDs:      []string{""},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				statusCode := tc.statusCodes[requests]
+				requests++
+				if statusCode != http.StatusOK {
+					w.WriteHeader(statusCode)
+					return
+				}
+				newHandler(1)(w, r)
+			}))
+			defer server.Close()
+
+			metricsMock := &metrics.MetricsEngineMock{}
+			metricsMock.On("RecordPrebidCacheRequestTime", mock.Anything, mock.Anything)
+			metricsMock.On("RecordPrebidCacheWriteError", metrics.PrebidCacheXML)
+
+			client := &clientImpl{
+				httpClient: server.Client(),
+				putUrl:     server.URL,
+				retries:    config.CacheWriteRetries{MaxRetries: tc.maxRetries, BackoffMS: 1, MaxBackoffMS: 2},
+				metrics:    metricsMock,
+			}
+			ids, _ := client.PutJson(context.Background(), []Cacheable{{Type: TypeXML, Data: json.RawMessage(`"<VAST></VAST>"`)}})
+
+			assert.Equal(t, tc.expectedIDs, ids)
+			assert.Equal(t, tc.expectedRequests, requests)
+			if tc.expectedIDs[0] == "" {
+				metricsMock.AssertCalled(t, "RecordPrebidCacheWriteError", metrics.PrebidCacheXML)
+			} else {
+				metricsMock.AssertNotCalled(t, "RecordPrebidCacheWriteError", metrics.PrebidCacheXML)
+			}
+		})
+	}
+}
+
+func TestPutRetriesWithinDeadline(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	metricsMock := &metrics.MetricsEngineMock{}
+	metricsMock.On("RecordPrebidCacheRequestTime", false, mock.Anything)
+	metricsMock.On("RecordPrebidCacheWriteError", metrics.PrebidCacheJSON).Once()
+
+	client := &clientImpl{
+		httpClient: server.Client(),
+		putUrl:     server.URL,
+		retries:    config.CacheWriteRetries{MaxRetries: 3, BackoffMS: 1000, MaxBackoffMS: 1000},
+		metrics:    metricsMock,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	ids, errs := client.PutJson(ctx, []Cacheable{{Type: TypeJSON, Data: json.RawMessage("true")}})
+
+	assert.Equal(t, []string{""}, ids)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, 1, requests, "the retry can't complete before the deadline")
+	metricsMock.AssertExpectations(t)
+}
+
+func TestRetryDelay(t *testing.T) {
+	client := &clientImpl{retries: config.CacheWriteRetries{MaxRetries: 5, BackoffMS: 10, MaxBackoffMS: 30}}
+
+	for attempt, expectedBackoff := range []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 30 * time.Millisecond, 30 * time.Millisecond} {
+		delay := client.retryDelay(attempt)
+		assert.GreaterOrEqual(t, delay, expectedBackoff/2)
+		assert.LessOrEqual(t, delay, expectedBackoff)
+	}
+}
+
+func TestBatchedPut(t *testing.T) {
+	var requests []int
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Puts []json.RawMessage `json:"puts"`
+		}
+		jsonutil.UnmarshalValid(readBody(r), &body)
+		mu.Lock()
+		requests = append(requests, len(body.Puts))
+		mu.Unlock()
+		newHandler(len(body.Puts))(w, r)
+	}))
+	defer server.Close()
+
+	metricsMock := &metrics.MetricsEngineMock{}
+	metricsMock.On("RecordPrebidCacheRequestTime", true, mock.Anything).Once()
+
+	client := NewClient(server.Client(), &config.Cache{
+		WriteBatching: config.CacheWriteBatching{Enabled: true, WindowMS: 1000, MaxValues: 3},
+	}, &config.ExternalCache{}, metricsMock).(*clientImpl)
+	client.putUrl = server.URL
+
+	results := make([][]string, 2)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[0], _ = client.PutJson(context.Background(), []Cacheable{{Type: TypeJSON, Data: json.RawMessage("true")}})
+	}()
+	assert.Eventually(t, func() bool {
+		client.batcher.mu.Lock()
+		defer client.batcher.mu.Unlock()
+		return len(client.batcher.pending) == 1
+	}, time.Second, time.Millisecond)
+
+	// the second write fills the batch, which is sent before the window has elapsed
+	results[1], _ = client.PutJson(context.Background(), []Cacheable{{Type: TypeJSON, Data: json.RawMessage("1")}, {Type: TypeJSON, Data: json.RawMessage("2")}})
+	wg.Wait()
+
+	assert.Equal(t, []int{3}, requests)
+	assert.Equal(t, []string{"0"}, results[0])
+	assert.Equal(t, []string{"1", "2"}, results[1])
+	metricsMock.AssertExpectations(t)
+}
+
+func TestBatchedPutWindow(t *testing.T) {
+	var puts [][]Cacheable
+	batcher := newWriteBatcher(func(ctx context.Context, values []Cacheable) ([]string, []error) {
+		puts = append(puts, values)
+		_, hasDeadline := ctx.Deadline()
+		assert.True(t, hasDeadline, "the request is bounded by the deadline of the writes")
+		return []string{"id"}, nil
+	}, 5*time.Millisecond, 10)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	ids, errs := batcher.put(ctx, []Cacheable{{Type: TypeJSON, Data: json.RawMessage("true")}})
+
+	assert.Equal(t, []string{"id"}, ids)
+	assert.Empty(t, errs)
+	assert.Len(t, puts, 1)
+}
+
+func TestBatchedPutCancelled(t *testing.T) {
+	batcher := newWriteBatcher(func(ctx context.Context, values []Cacheable) ([]string, []error) {
+		t.Error("The cancelled write should not be sent.")
+		return nil, nil
+	}, 50*time.Millisecond, 10)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	ids, errs := batcher.put(ctx, []Cacheable{{Type: TypeJSON, Data: json.RawMessage("true")}})
+
+	assert.Equal(t, []string{""}, ids)
+	assert.Len(t, errs, 1)
+	time.Sleep(100 * time.Millisecond)
+}
+
 func TestEncodeValueToBuffer(t *testing.T) {
 	buf := new(bytes.Buffer)
 	testCache := Cacheable{
@@ -289,6 +479,11 @@ type handlerResponse struct {
 	Responses []handlerResponseObject `json:"responses"`
 }
 
+func readBody(r *http.Request) []byte {
+	body, _ := io.ReadAll(r.Body)
+	return body
+}
+
 func newHandler(numResponses int) http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		resp := handlerResponse{
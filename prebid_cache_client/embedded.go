@@ -0,0 +1,181 @@
+package prebid_cache_client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/logger"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/prebid/prebid-server/v3/util/uuidutil"
+)
+
+// EmbeddedCachePath is the route on which Prebid Server serves the values of the embedded cache
+const EmbeddedCachePath = "/cache"
+
+// NewEmbeddedClient returns a Client which keeps the values in the storage instead of Prebid Cache. The values
+// are served by the client itself, as an http.Handler of GET /cache?uuid=<uuid> compatible with Prebid Cache.
+//
+// The cache URLs point to the external cache when its host is configured, or else to the external URL of
+// Prebid Server.
+func NewEmbeddedClient(storage EmbeddedStorage, conf *config.EmbeddedCache, extCache *config.ExternalCache, externalURL string, metrics metrics.MetricsEngine) *EmbeddedClient {
+	client := &EmbeddedClient{
+		storage:       storage,
+		uuidGenerator: uuidutil.UUIDRandomGenerator{},
+		defaultTTL:    time.Duration(conf.DefaultTTLSeconds) * time.Second,
+		maxTTL:        time.Duration(conf.MaxTTLSeconds) * time.Second,
+		metrics:       metrics,
+	}
+
+	if len(extCache.Host) > 0 {
+		client.externalCacheScheme = extCache.Scheme
+		client.externalCacheHost = extCache.Host
+		client.externalCachePath = normalizeExtCachePath(extCache.Path)
+	} else if parsedURL, err := url.Parse(externalURL); err == nil {
+		client.externalCacheScheme = parsedURL.Scheme
+		client.externalCacheHost = parsedURL.Host
+		client.externalCachePath = strings.TrimSuffix(parsedURL.Path, "/") + EmbeddedCachePath
+	} else {
+		logger.Errorf("The embedded cache URLs can't be built from the external URL %s: %v", externalURL, err)
+	}
+	return client
+}
+
+type EmbeddedClient struct {
+	storage             EmbeddedStorage
+	uuidGenerator       uuidutil.UUIDGenerator
+	defaultTTL          time.Duration
+	maxTTL              time.Duration
+	externalCacheScheme string
+	externalCacheHost   string
+	externalCachePath   string
+	metrics             metrics.MetricsEngine
+}
+
+func (c *EmbeddedClient) GetExtCacheData() (string, string, string) {
+	return c.externalCacheScheme, c.externalCacheHost, c.externalCachePath
+}
+
+// PutJson stores the values with their "/vtrack" metadata. The values with a key are only stored if the key
+// is not in use, as Prebid Cache does.
+func (c *EmbeddedClient) PutJson(ctx context.Context, values []Cacheable) (uuids []string, errs []error) {
+	errs = make([]error, 0, 1)
+	if len(values) < 1 {
+		return nil, errs
+	}
+
+	uuidsToReturn := make([]string, len(values))
+	startTime := time.Now()
+	for i, value := range values {
+		uuid, err := c.put(ctx, value)
+		if err != nil {
+			logError(&errs, "Error storing the value at index %d in the embedded cache: %v", i, err)
+			continue
+		}
+		uuidsToReturn[i] = uuid
+	}
+	c.metrics.RecordPrebidCacheRequestTime(len(errs) == 0, time.Since(startTime))
+	recordWriteErrors(c.metrics, values, uuidsToReturn)
+
+	return uuidsToReturn, errs
+}
+
+func (c *EmbeddedClient) put(ctx context.Context, value Cacheable) (string, error) {
+	if err := validateCacheable(value); err != nil {
+		return "", err
+	}
+	data, err := jsonutil.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	uuid := value.Key
+	if len(uuid) == 0 {
+		if uuid, err = c.uuidGenerator.Generate(); err != nil {
+			return "", err
+		}
+	}
+
+	stored, err := c.storage.Put(ctx, uuid, data, c.ttl(value.TTLSeconds), len(value.Key) > 0)
+	if err != nil {
+		return "", err
+	}
+	if !stored {
+		return "", fmt.Errorf("key %s is already in use", value.Key)
+	}
+	return uuid, nil
+}
+
+func (c *EmbeddedClient) ttl(ttlSeconds int64) time.Duration {
+	if ttlSeconds <= 0 {
+		return c.defaultTTL
+	}
+	ttl := time.Duration(ttlSeconds) * time.Second
+	if ttl > c.maxTTL {
+		return c.maxTTL
+	}
+	return ttl
+}
+
+func validateCacheable(value Cacheable) error {
+	switch value.Type {
+	case TypeJSON:
+		var data any
+		if err := jsonutil.UnmarshalValid(value.Data, &data); err != nil || data == nil {
+			return fmt.Errorf("json values must be valid JSON, got %s", value.Data)
+		}
+	case TypeXML:
+		var data string
+		if err := jsonutil.UnmarshalValid(value.Data, &data); err != nil {
+			return fmt.Errorf("xml values must be JSON strings, got %s", value.Data)
+		}
+	default:
+		return fmt.Errorf("type must be %s or %s, got %s", TypeJSON, TypeXML, value.Type)
+	}
+	return nil
+}
+
+// ServeHTTP returns the value stored at the uuid query parameter, as XML or JSON depending on its type
+func (c *EmbeddedClient) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	uuid := r.URL.Query().Get("uuid")
+	if len(uuid) == 0 {
+		http.Error(w, "GET /cache requests must contain a uuid query parameter", http.StatusBadRequest)
+		return
+	}
+
+	data, err := c.storage.Get(r.Context(), uuid)
+	if err != nil {
+		logger.Errorf("Error reading %s from the embedded cache: %v", uuid, err)
+		http.Error(w, "Error reading the cached value", http.StatusInternalServerError)
+		return
+	}
+	if data == nil {
+		http.Error(w, fmt.Sprintf("uuid %s not found", uuid), http.StatusNotFound)
+		return
+	}
+
+	var value Cacheable
+	if err := jsonutil.UnmarshalValid(data, &value); err != nil {
+		logger.Errorf("Error decoding %s from the embedded cache: %v", uuid, err)
+		http.Error(w, "Error reading the cached value", http.StatusInternalServerError)
+		return
+	}
+	if value.Type == TypeXML {
+		var xml string
+		if err := jsonutil.UnmarshalValid(value.Data, &xml); err != nil {
+			logger.Errorf("Error decoding %s from the embedded cache: %v", uuid, err)
+			http.Error(w, "Error reading the cached value", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		w.Write([]byte(xml))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(value.Data)
+}
@@ -0,0 +1,173 @@
+package prebid_cache_client
+
+import (
+	"container/heap"
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/util/redisutil"
+)
+
+// EmbeddedStorage keeps the values of the embedded cache until their TTL expires.
+//
+// Implementations must be safe for concurrent access by multiple goroutines.
+type EmbeddedStorage interface {
+	// Put stores the value at the key for the TTL. When onlyIfAbsent is set, an existing value is kept and
+	// Put returns false.
+	Put(ctx context.Context, key string, value []byte, ttl time.Duration, onlyIfAbsent bool) (bool, error)
+	// Get returns the value stored at the key, or nil if it doesn't exist or has expired.
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+// NewEmbeddedStorage returns the storage configured for the embedded cache, and a function which should be
+// called on shutdown.
+func NewEmbeddedStorage(conf *config.EmbeddedCache) (EmbeddedStorage, func()) {
+	if conf.Storage == config.EmbeddedCacheStorageRedis {
+		client := redisutil.NewClient(redisutil.Config{
+			Address:  conf.Redis.Address,
+			Password: conf.Redis.Password,
+			DB:       conf.Redis.DB,
+			Timeout:  conf.Redis.TimeoutDuration(),
+			PoolSize: conf.Redis.PoolSize,
+		})
+		return NewRedisStorage(client, conf.Redis.KeyPrefix), func() { client.Close() }
+	}
+	return NewMemoryStorage(conf.MaxEntries), func() {}
+}
+
+// NewMemoryStorage returns a storage local to the instance which keeps at most maxEntries values. The expired
+// values are removed as they expire, and the values expiring first are evicted while the storage is full.
+func NewMemoryStorage(maxEntries int) EmbeddedStorage {
+	return &memoryStorage{
+		entries:    make(map[string]*memoryEntry),
+		maxEntries: maxEntries,
+		now:        time.Now,
+	}
+}
+
+type memoryEntry struct {
+	key    string
+	value  []byte
+	expiry time.Time
+	// index is the position of the entry in the expiry heap
+	index int
+}
+
+type memoryStorage struct {
+	mu         sync.Mutex
+	entries    map[string]*memoryEntry
+	expiries   expiryHeap
+	maxEntries int
+	now        func() time.Time
+}
+
+func (s *memoryStorage) Put(ctx context.Context, key string, value []byte, ttl time.Duration, onlyIfAbsent bool) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.removeExpired(now)
+	if entry, exists := s.entries[key]; exists {
+		if onlyIfAbsent {
+			return false, nil
+		}
+		entry.value = value
+		entry.expiry = now.Add(ttl)
+		heap.Fix(&s.expiries, entry.index)
+		return true, nil
+	}
+	if len(s.entries) >= s.maxEntries {
+		s.remove(s.expiries[0])
+	}
+	entry := &memoryEntry{key: key, value: value, expiry: now.Add(ttl)}
+	heap.Push(&s.expiries, entry)
+	s.entries[key] = entry
+	return true, nil
+}
+
+func (s *memoryStorage) Get(ctx context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, nil
+	}
+	if !s.now().Before(entry.expiry) {
+		s.remove(entry)
+		return nil, nil
+	}
+	return entry.value, nil
+}
+
+func (s *memoryStorage) removeExpired(now time.Time) {
+	for len(s.expiries) > 0 && !now.Before(s.expiries[0].expiry) {
+		s.remove(s.expiries[0])
+	}
+}
+
+func (s *memoryStorage) remove(entry *memoryEntry) {
+	heap.Remove(&s.expiries, entry.index)
+	delete(s.entries, entry.key)
+}
+
+// expiryHeap orders the entries of the memory storage by expiry, the first to expire at the top
+type expiryHeap []*memoryEntry
+
+func (h expiryHeap) Len() int { return len(h) }
+
+func (h expiryHeap) Less(i, j int) bool { return h[i].expiry.Before(h[j].expiry) }
+
+func (h expiryHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *expiryHeap) Push(x any) {
+	entry := x.(*memoryEntry)
+	entry.index = len(*h)
+	*h = append(*h, entry)
+}
+
+func (h *expiryHeap) Pop() any {
+	old := *h
+	entry := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return entry
+}
+
+// NewRedisStorage returns a storage kept in a server speaking the Redis protocol, shared by the instances
+// using the same server. The values are stored at <keyPrefix><key> and expire with the server TTLs.
+func NewRedisStorage(client *redisutil.Client, keyPrefix string) EmbeddedStorage {
+	return &redisStorage{
+		client:    client,
+		keyPrefix: keyPrefix,
+	}
+}
+
+type redisStorage struct {
+	client    *redisutil.Client
+	keyPrefix string
+}
+
+func (s *redisStorage) Put(ctx context.Context, key string, value []byte, ttl time.Duration, onlyIfAbsent bool) (bool, error) {
+	args := []string{"SET", s.keyPrefix + key, string(value), "PX", strconv.FormatInt(ttl.Milliseconds(), 10)}
+	if onlyIfAbsent {
+		args = append(args, "NX")
+	}
+	reply, err := s.client.Do(ctx, args...)
+	if err != nil {
+		return false, err
+	}
+	// SET replies nil when NX prevents the write
+	return reply != nil, nil
+}
+
+func (s *redisStorage) Get(ctx context.Context, key string) ([]byte, error) {
+	return s.client.Get(ctx, s.keyPrefix+key)
+}
@@ -0,0 +1,268 @@
+package prebid_cache_client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/util/redisutil"
+	"github.com/prebid/prebid-server/v3/util/redisutil/redistest"
+	"github.com/prebid/prebid-server/v3/util/uuidutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testEmbeddedCacheConfig = config.EmbeddedCache{
+	Enabled:           true,
+	Storage:           config.EmbeddedCacheStorageMemory,
+	DefaultTTLSeconds: 300,
+	MaxTTLSeconds:     3600,
+	MaxEntries:        10,
+}
+
+func newTestEmbeddedClient(storage EmbeddedStorage, metricsEngine metrics.MetricsEngine) *EmbeddedClient {
+	client := NewEmbeddedClient(storage, &testEmbeddedCacheConfig, &config.ExternalCache{}, "https://pbs.example.com", metricsEngine)
+	client.uuidGenerator = uuidutil.NewFakeUUIDGenerator("generated-uuid", nil)
+	return client
+}
+
+func getCached(client *EmbeddedClient, uuid string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	client.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/cache?uuid="+uuid, nil))
+	return w
+}
+
+func TestEmbeddedClientPutAndGet(t *testing.T) {
+	metricsMock := &metrics.MetricsEngineMock{}
+	metricsMock.On("RecordPrebidCacheRequestTime", true, mock.Anything).Once()
+	client := newTestEmbeddedClient(NewMemoryStorage(10), metricsMock)
+
+	uuids, errs := client.PutJson(context.Background(), []Cacheable{
+		{Type: TypeJSON, Data: json.RawMessage(`{"id":"bid-1","price":1.5}`)},
+		{Type: TypeXML, Data: json.RawMessage(`"<VAST version=\"3.0\"></VAST>"`), Key: "custom-key", BidID: "bid-2", Bidder: "appnexus", Timestamp: 1700000000},
+	})
+	assert.Empty(t, errs)
+	assert.Equal(t, []string{"generated-uuid", "custom-key"}, uuids)
+	metricsMock.AssertExpectations(t)
+
+	w := getCached(client, "generated-uuid")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"id":"bid-1","price":1.5}`, w.Body.String())
+
+	w = getCached(client, "custom-key")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/xml", w.Header().Get("Content-Type"))
+	assert.Equal(t, `<VAST version="3.0"></VAST>`, w.Body.String())
+
+	stored, _ := client.storage.Get(context.Background(), "custom-key")
+	assert.JSONEq(t, `{"type":"xml","value":"<VAST version=\"3.0\"></VAST>","key":"custom-key","bidid":"bid-2","bidder":"appnexus","timestamp":1700000000}`, string(stored))
+}
+
+func TestEmbeddedClientPutErrors(t *testing.T) {
+	metricsMock := &metrics.MetricsEngineMock{}
+	metricsMock.On("RecordPrebidCacheRequestTime", false, mock.Anything).Once()
+	metricsMock.On("RecordPrebidCacheWriteError", metrics.PrebidCacheXML).Once()
+	metricsMock.On("RecordPrebidCacheWriteError", metrics.PrebidCacheJSON).Twice()
+	metricsMock.On("RecordPrebidCacheWriteError", metrics.PrebidCacheType("html")).Once()
+	client := newTestEmbeddedClient(NewMemoryStorage(10), metricsMock)
+	client.storage.Put(context.Background(), "used-key", []byte(`{}`), time.Minute, false)
+
+	uuids, errs := client.PutJson(context.Background(), []Cacheable{
+		{Type: TypeXML, Data: json.RawMessage(`{"not":"a string"}`)},
+		{Type: TypeJSON, Data: json.RawMessage(`null`)},
+		{Type: "html", Data: json.RawMessage(`"<div></div>"`)},
+		{Type: TypeJSON, Data: json.RawMessage(`{}`), Key: "used-key"},
+		{Type: TypeJSON, Data: json.RawMessage(`{}`)},
+	})
+	assert.Equal(t, []string{"", "", "", "", "generated-uuid"}, uuids)
+	assert.Equal(t, []error{
+		errors.New(`Error storing the value at index 0 in the embedded cache: xml values must be JSON strings, got {"not":"a string"}`),
+		errors.New(`Error storing the value at index 1 in the embedded cache: json values must be valid JSON, got null`),
+		errors.New(`Error storing the value at index 2 in the embedded cache: type must be json or xml, got html`),
+		errors.New(`Error storing the value at index 3 in the embedded cache: key used-key is already in use`),
+	}, errs)
+	metricsMock.AssertExpectations(t)
+}
+
+func TestEmbeddedClientTTL(t *testing.T) {
+	storage := &mockEmbeddedStorage{}
+	client := newTestEmbeddedClient(storage, &metrics.MetricsEngineMock{})
+	client.metrics.(*metrics.MetricsEngineMock).On("RecordPrebidCacheRequestTime", true, mock.Anything)
+
+	client.PutJson(context.Background(), []Cacheable{
+		{Type: TypeJSON, Data: json.RawMessage(`{}`)},
+		{Type: TypeJSON, Data: json.RawMessage(`{}`), TTLSeconds: 60},
+		{Type: TypeJSON, Data: json.RawMessage(`{}`), TTLSeconds: 7200},
+	})
+	assert.Equal(t, []time.Duration{300 * time.Second, 60 * time.Second, 3600 * time.Second}, storage.ttls)
+}
+
+func TestEmbeddedClientGetErrors(t *testing.T) {
+	client := newTestEmbeddedClient(NewMemoryStorage(10), &metrics.MetricsEngineMock{})
+
+	w := getCached(client, "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = getCached(client, "unknown")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	client.storage = &mockEmbeddedStorage{err: errors.New("connection refused")}
+	w = getCached(client, "any")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestEmbeddedClientGetExtCacheData(t *testing.T) {
+	testCases := []struct {
+		name           string
+		extCache       config.ExternalCache
+		externalURL    string
+		expectedScheme string
+		expectedHost   string
+		expectedPath   string
+	}{
+		{
+			name:           "external_url",
+			externalURL:    "https://pbs.example.com",
+			expectedScheme: "https",
+			expectedHost:   "pbs.example.com",
+			expectedPath:   "/cache",
+		},
+		{
+			name:           "external_url_with_path",
+			externalURL:    "http://example.com/pbs/",
+			expectedScheme: "http",
+			expectedHost:   "example.com",
+			expectedPath:   "/pbs/cache",
+		},
+		{
+			name:           "external_cache",
+			extCache:       config.ExternalCache{Scheme: "https", Host: "cache.example.com", Path: "pbs/cache"},
+			externalURL:    "https://pbs.example.com",
+			expectedScheme: "https",
+			expectedHost:   "cache.example.com",
+			expectedPath:   "/pbs/cache",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := NewEmbeddedClient(NewMemoryStorage(10), &testEmbeddedCacheConfig, &tc.extCache, tc.externalURL, &metrics.MetricsEngineMock{})
+			scheme, host, path := client.GetExtCacheData()
+			assert.Equal(t, tc.expectedScheme, scheme)
+			assert.Equal(t, tc.expectedHost, host)
+			assert.Equal(t, tc.expectedPath, path)
+		})
+	}
+}
+
+func TestMemoryStorage(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	storage := NewMemoryStorage(2).(*memoryStorage)
+	storage.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	stored, err := storage.Put(ctx, "a", []byte("1"), time.Minute, false)
+	assert.True(t, stored)
+	assert.NoError(t, err)
+
+	stored, err = storage.Put(ctx, "a", []byte("2"), time.Minute, true)
+	assert.False(t, stored, "the existing value is kept")
+	assert.NoError(t, err)
+
+	storage.Put(ctx, "b", []byte("3"), 2*time.Minute, false)
+	value, _ := storage.Get(ctx, "a")
+	assert.Equal(t, []byte("1"), value)
+
+	now = now.Add(time.Minute)
+	value, _ = storage.Get(ctx, "a")
+	assert.Nil(t, value, "the value has expired")
+
+	stored, err = storage.Put(ctx, "c", []byte("4"), time.Minute, true)
+	assert.True(t, stored)
+	assert.NoError(t, err)
+	value, _ = storage.Get(ctx, "c")
+	assert.Equal(t, []byte("4"), value)
+}
+
+func TestMemoryStorageFull(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	storage := NewMemoryStorage(2).(*memoryStorage)
+	storage.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	storage.Put(ctx, "a", []byte("1"), 3*time.Minute, false)
+	storage.Put(ctx, "b", []byte("2"), time.Minute, false)
+	stored, err := storage.Put(ctx, "c", []byte("3"), 2*time.Minute, false)
+	assert.True(t, stored)
+	assert.NoError(t, err)
+
+	value, _ := storage.Get(ctx, "b")
+	assert.Nil(t, value, "the value expiring first is evicted")
+	value, _ = storage.Get(ctx, "a")
+	assert.Equal(t, []byte("1"), value)
+	value, _ = storage.Get(ctx, "c")
+	assert.Equal(t, []byte("3"), value)
+
+	storage.Put(ctx, "a", []byte("4"), time.Minute, false)
+	storage.Put(ctx, "d", []byte("5"), 2*time.Minute, false)
+	value, _ = storage.Get(ctx, "a")
+	assert.Nil(t, value, "the value expiring first after its update is evicted")
+
+	now = now.Add(2 * time.Minute)
+	storage.Put(ctx, "e", []byte("6"), time.Minute, false)
+	assert.Len(t, storage.entries, 1, "the expired values are removed")
+	assert.Len(t, storage.expiries, 1)
+}
+
+func TestRedisStorage(t *testing.T) {
+	server := redistest.NewServer()
+	client := redisutil.NewClient(redisutil.Config{Address: server.Addr})
+	t.Cleanup(func() {
+		client.Close()
+		server.Close()
+	})
+	storage := NewRedisStorage(client, "pbc:")
+	ctx := context.Background()
+
+	stored, err := storage.Put(ctx, "a", []byte("1"), time.Minute, false)
+	assert.True(t, stored)
+	assert.NoError(t, err)
+	value, _ := server.Value("pbc:a")
+	assert.Equal(t, "1", value)
+	assert.InDelta(t, time.Minute, server.TTL("pbc:a"), float64(time.Second))
+
+	stored, err = storage.Put(ctx, "a", []byte("2"), time.Minute, true)
+	assert.False(t, stored, "the existing value is kept")
+	assert.NoError(t, err)
+
+	data, err := storage.Get(ctx, "a")
+	assert.Equal(t, []byte("1"), data)
+	assert.NoError(t, err)
+
+	data, err = storage.Get(ctx, "b")
+	assert.Nil(t, data)
+	assert.NoError(t, err)
+}
+
+// mockEmbeddedStorage records the TTLs of the stored values
+type mockEmbeddedStorage struct {
+	ttls []time.Duration
+	err  error
+}
+
+func (s *mockEmbeddedStorage) Put(ctx context.Context, key string, value []byte, ttl time.Duration, onlyIfAbsent bool) (bool, error) {
+	s.ttls = append(s.ttls, ttl)
+	return s.err == nil, s.err
+}
+
+func (s *mockEmbeddedStorage) Get(ctx context.Context, key string) ([]byte, error) {
+	return nil, s.err
+}
@@ -13,6 +13,9 @@ const (
 	ActivityTransmitPreciseGeo
 	ActivityTransmitUniqueRequestIDs
 	ActivityTransmitTIDs
+	ActivityTransmitEIDs
+	ActivityTransmitDeviceIDs
+	ActivityTransmitPAAPI
 )
 
 func (a Activity) String() string {
@@ -33,6 +36,12 @@ func (a Activity) String() string {
 		return "transmitUniqueRequestIds"
 	case ActivityTransmitTIDs:
 		return "transmitTid"
+	case ActivityTransmitEIDs:
+		return "transmitEids"
+	case ActivityTransmitDeviceIDs:
+		return "transmitDeviceIds"
+	case ActivityTransmitPAAPI:
+		return "transmitPaapi"
 	}
 
 	return ""
@@ -45,19 +45,38 @@ type ActivityControl struct {
 func NewActivityControl(cfg *config.AccountPrivacy) ActivityControl {
 	ac := ActivityControl{}
 
-	if cfg == nil || cfg.AllowActivities == nil {
+	if cfg == nil || (cfg.AllowActivities == nil && !cfg.USPrivacy.Enabled) {
 		return ac
 	}
 
-	plans := make(map[Activity]ActivityPlan, 8)
-	plans[ActivitySyncUser] = buildPlan(cfg.AllowActivities.SyncUser)
-	plans[ActivityFetchBids] = buildPlan(cfg.AllowActivities.FetchBids)
-	plans[ActivityEnrichUserFPD] = buildPlan(cfg.AllowActivities.EnrichUserFPD)
-	plans[ActivityReportAnalytics] = buildPlan(cfg.AllowActivities.ReportAnalytics)
-	plans[ActivityTransmitUserFPD] = buildPlan(cfg.AllowActivities.TransmitUserFPD)
-	plans[ActivityTransmitPreciseGeo] = buildPlan(cfg.AllowActivities.TransmitPreciseGeo)
-	plans[ActivityTransmitUniqueRequestIDs] = buildPlan(cfg.AllowActivities.TransmitUniqueRequestIds)
-	plans[ActivityTransmitTIDs] = buildPlan(cfg.AllowActivities.TransmitTids)
+	allowActivities := cfg.AllowActivities
+	if allowActivities == nil {
+		allowActivities = &config.AllowActivities{}
+	}
+
+	plans := make(map[Activity]ActivityPlan, 11)
+	plans[ActivitySyncUser] = buildPlan(allowActivities.SyncUser)
+	plans[ActivityFetchBids] = buildPlan(allowActivities.FetchBids)
+	plans[ActivityEnrichUserFPD] = buildPlan(allowActivities.EnrichUserFPD)
+	plans[ActivityReportAnalytics] = buildPlan(allowActivities.ReportAnalytics)
+	plans[ActivityTransmitUserFPD] = buildPlan(allowActivities.TransmitUserFPD)
+	plans[ActivityTransmitPreciseGeo] = buildPlan(allowActivities.TransmitPreciseGeo)
+	plans[ActivityTransmitUniqueRequestIDs] = buildPlan(allowActivities.TransmitUniqueRequestIds)
+	plans[ActivityTransmitTIDs] = buildPlan(allowActivities.TransmitTids)
+	plans[ActivityTransmitEIDs] = buildPlan(allowActivities.TransmitEids)
+	plans[ActivityTransmitDeviceIDs] = buildPlan(allowActivities.TransmitDeviceIds)
+	plans[ActivityTransmitPAAPI] = buildPlan(allowActivities.TransmitPAAPI)
+
+	// rules of the allowed activities take precedence over the US privacy sections
+	if cfg.USPrivacy.Enabled {
+		signals := &usPrivacySignalsCache{}
+		for activity, plan := range plans {
+			if rule, ok := newUSPrivacyRule(activity, cfg.USPrivacy, signals); ok {
+				plan.rules = append(plan.rules, rule)
+				plans[activity] = plan
+			}
+		}
+	}
 	ac.plans = plans
 
 	ac.IPv4Config = cfg.IPv4Config
@@ -86,6 +105,7 @@ func cfgToRules(rules []config.ActivityRule) []Rule {
 			result:        result,
 			componentName: r.Condition.ComponentName,
 			componentType: r.Condition.ComponentType,
+			eidSource:     r.Condition.EIDSource,
 		}
 		enfRules = append(enfRules, er)
 	}
@@ -100,13 +120,39 @@ func cfgToDefaultResult(activityDefault *bool) bool {
 }
 
 func (e ActivityControl) Allow(activity Activity, target Component, request ActivityRequest) bool {
+	return e.Decide(activity, target, request).Allowed
+}
+
+// Decide evaluates the activity plan like Allow and reports which rule of the plan decided
+func (e ActivityControl) Decide(activity Activity, target Component, request ActivityRequest) ActivityDecision {
 	plan, planDefined := e.plans[activity]
 
 	if !planDefined {
-		return defaultActivityResult
+		return ActivityDecision{Allowed: defaultActivityResult, Policy: AuditPolicyDefault, RuleIndex: -1}
+	}
+
+	return plan.decide(target, request)
+}
+
+// DeniedIDs evaluates the transmitDeviceIds and transmitEids activities of the component. The transmitEids activity
+// is evaluated for the source of each EID of the request.
+func (e ActivityControl) DeniedIDs(reqWrapper *openrtb_ext.RequestWrapper, target Component, request ActivityRequest) DeniedIDs {
+	denied := DeniedIDs{
+		DeviceIDs: !e.Allow(ActivityTransmitDeviceIDs, target, request),
+		EIDs:      !e.Allow(ActivityTransmitEIDs, target, request),
 	}
 
-	return plan.Evaluate(target, request)
+	if reqWrapper == nil || reqWrapper.BidRequest == nil || reqWrapper.User == nil {
+		return denied
+	}
+	for _, eid := range reqWrapper.User.EIDs {
+		sourceTarget := target
+		sourceTarget.EIDSource = eid.Source
+		if !e.Allow(ActivityTransmitEIDs, sourceTarget, request) {
+			denied.EIDSources = append(denied.EIDSources, eid.Source)
+		}
+	}
+	return denied
 }
 
 type ActivityPlan struct {
@@ -115,11 +161,20 @@ type ActivityPlan struct {
 }
 
 func (p ActivityPlan) Evaluate(target Component, request ActivityRequest) bool {
-	for _, rule := range p.rules {
+	return p.decide(target, request).Allowed
+}
+
+func (p ActivityPlan) decide(target Component, request ActivityRequest) ActivityDecision {
+	for i, rule := range p.rules {
 		result := rule.Evaluate(target, request)
 		if result == ActivityDeny || result == ActivityAllow {
-			return result == ActivityAllow
+			decision := ActivityDecision{Allowed: result == ActivityAllow, Policy: rulePolicy(rule), RuleIndex: i}
+			if decision.Policy != AuditPolicyActivityControl {
+				// the rule index only refers to the account activity control rules
+				decision.RuleIndex = -1
+			}
+			return decision
 		}
 	}
-	return p.defaultResult
+	return ActivityDecision{Allowed: p.defaultResult, Policy: AuditPolicyActivityControl, RuleIndex: -1}
 }
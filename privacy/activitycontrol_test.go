@@ -3,6 +3,7 @@ package privacy
 import (
 	"testing"
 
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/util/ptrutil"
@@ -32,6 +33,9 @@ func TestNewActivityControl(t *testing.T) {
 					TransmitPreciseGeo:       getTestActivityConfig(false),
 					TransmitUniqueRequestIds: getTestActivityConfig(true),
 					TransmitTids:             getTestActivityConfig(true),
+					TransmitEids:             getTestActivityConfig(false),
+					TransmitDeviceIds:        getTestActivityConfig(true),
+					TransmitPAAPI:            getTestActivityConfig(false),
 				},
 				IPv6Config: config.IPv6{AnonKeepBits: 32},
 				IPv4Config: config.IPv4{AnonKeepBits: 16},
@@ -46,6 +50,9 @@ func TestNewActivityControl(t *testing.T) {
 					ActivityTransmitPreciseGeo:       getTestActivityPlan(ActivityDeny),
 					ActivityTransmitUniqueRequestIDs: getTestActivityPlan(ActivityAllow),
 					ActivityTransmitTIDs:             getTestActivityPlan(ActivityAllow),
+					ActivityTransmitEIDs:             getTestActivityPlan(ActivityDeny),
+					ActivityTransmitDeviceIDs:        getTestActivityPlan(ActivityAllow),
+					ActivityTransmitPAAPI:            getTestActivityPlan(ActivityDeny),
 				},
 				IPv6Config: config.IPv6{AnonKeepBits: 32},
 				IPv4Config: config.IPv4{AnonKeepBits: 16},
@@ -142,6 +149,90 @@ func TestActivityControlAllow(t *testing.T) {
 	}
 }
 
+func TestActivityControlDecide(t *testing.T) {
+	testCases := []struct {
+		name            string
+		activityControl ActivityControl
+		target          Component
+		request         ActivityRequest
+		expected        ActivityDecision
+	}{
+		{
+			name:            "activity_not_defined",
+			activityControl: ActivityControl{plans: nil},
+			target:          Component{Type: "bidder", Name: "bidderA"},
+			expected:        ActivityDecision{Allowed: true, Policy: AuditPolicyDefault, RuleIndex: -1},
+		},
+		{
+			name: "plan_default",
+			activityControl: ActivityControl{plans: map[Activity]ActivityPlan{
+				ActivityFetchBids: getTestActivityPlan(ActivityDeny)}},
+			target:   Component{Type: "bidder", Name: "bidderB"},
+			expected: ActivityDecision{Allowed: true, Policy: AuditPolicyActivityControl, RuleIndex: -1},
+		},
+		{
+			name: "rule_denies",
+			activityControl: ActivityControl{plans: map[Activity]ActivityPlan{
+				ActivityFetchBids: {
+					defaultResult: true,
+					rules: []Rule{
+						ConditionRule{result: ActivityAllow, componentName: []string{"bidderB"}},
+						ConditionRule{result: ActivityDeny, componentName: []string{"bidderA"}},
+					},
+				}}},
+			target:   Component{Type: "bidder", Name: "bidderA"},
+			expected: ActivityDecision{Allowed: false, Policy: AuditPolicyActivityControl, RuleIndex: 1},
+		},
+		{
+			name: "us_privacy_rule_denies",
+			activityControl: ActivityControl{plans: map[Activity]ActivityPlan{
+				ActivityFetchBids: {
+					defaultResult: true,
+					rules: []Rule{
+						ConditionRule{result: ActivityAllow, componentName: []string{"bidderB"}},
+						getTestUSPrivacyRule(ActivityFetchBids, config.AccountUSPrivacy{Enabled: true, Sections: map[string]config.AccountUSPrivacySection{
+							"usca": {Rules: []config.USPrivacyRule{{Activities: []string{"fetchBids"}}}},
+						}}),
+					},
+				}}},
+			target:   Component{Type: "bidder", Name: "bidderA"},
+			request:  NewRequestFromPolicies(Policies{GPPSID: []int8{8}, GPP: uscaGPP}),
+			expected: ActivityDecision{Allowed: false, Policy: AuditPolicyUSPrivacy, RuleIndex: -1},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.activityControl.Decide(ActivityFetchBids, test.target, test.request))
+		})
+	}
+}
+
+func TestActivityControlDeniedIDs(t *testing.T) {
+	cfg := &config.AccountPrivacy{
+		AllowActivities: &config.AllowActivities{
+			TransmitEids: config.Activity{
+				Default: ptrutil.ToPtr(true),
+				Rules: []config.ActivityRule{
+					{Allow: true, Condition: config.ActivityCondition{ComponentName: []string{"bidderA"}}},
+					{Allow: false, Condition: config.ActivityCondition{EIDSource: []string{"liveramp.com"}}},
+				},
+			},
+			TransmitDeviceIds: getTestActivityConfig(false),
+		},
+	}
+	ac := NewActivityControl(cfg)
+	reqWrapper := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+		User: &openrtb2.User{EIDs: []openrtb2.EID{{Source: "liveramp.com"}, {Source: "id5-sync.com"}}},
+	}}
+
+	assert.Equal(t, DeniedIDs{DeviceIDs: true}, ac.DeniedIDs(reqWrapper, Component{Type: "bidder", Name: "bidderA"}, ActivityRequest{}))
+	assert.Equal(t, DeniedIDs{EIDSources: []string{"liveramp.com"}}, ac.DeniedIDs(reqWrapper, Component{Type: "bidder", Name: "bidderB"}, ActivityRequest{}))
+	assert.Equal(t, DeniedIDs{}, ActivityControl{}.DeniedIDs(reqWrapper, Component{Type: "bidder", Name: "bidderB"}, ActivityRequest{}))
+	assert.Equal(t, DeniedIDs{DeviceIDs: true}, ac.DeniedIDs(nil, Component{Type: "bidder", Name: "bidderA"}, ActivityRequest{}))
+	assert.Equal(t, DeniedIDs{DeviceIDs: true}, ac.DeniedIDs(&openrtb_ext.RequestWrapper{}, Component{Type: "bidder", Name: "bidderA"}, ActivityRequest{}), "request wrapper without bid request must not panic")
+}
+
 func TestActivityRequest(t *testing.T) {
 	t.Run("empty", func(t *testing.T) {
 		r := ActivityRequest{}
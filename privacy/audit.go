@@ -0,0 +1,79 @@
+package privacy
+
+import (
+	"sort"
+
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+)
+
+// Policies which can decide whether an activity is allowed
+const (
+	AuditPolicyDefault         = "default"
+	AuditPolicyActivityControl = "activityControl"
+	AuditPolicyUSPrivacy       = "usPrivacy"
+	AuditPolicyTCF2            = "tcf2"
+	AuditPolicyCCPA            = "ccpa"
+	AuditPolicyCOPPA           = "coppa"
+	AuditPolicyLMT             = "lmt"
+	// AuditPolicyAccount is a setting of the account config, like privacysandbox.paapi.enabled
+	AuditPolicyAccount = "account"
+)
+
+// ActivityDecision describes the outcome of an activity and the policy which decided it. RuleIndex is the index of
+// the deciding activity control rule, -1 when no rule decided.
+type ActivityDecision struct {
+	Allowed   bool
+	Policy    string
+	RuleIndex int
+}
+
+// Deny returns a decision denying the activity on behalf of a policy other than the activity control
+func Deny(policy string) ActivityDecision {
+	return ActivityDecision{Allowed: false, Policy: policy, RuleIndex: -1}
+}
+
+func rulePolicy(rule Rule) string {
+	if _, ok := rule.(USPrivacyRule); ok {
+		return AuditPolicyUSPrivacy
+	}
+	return AuditPolicyActivityControl
+}
+
+// AuditTrail collects the privacy decisions taken for each bidder of an auction. A nil AuditTrail records nothing.
+type AuditTrail struct {
+	records []openrtb_ext.PrivacyAuditRecord
+}
+
+// Record adds the decision taken for the activity of the bidder
+func (t *AuditTrail) Record(bidder string, activity Activity, decision ActivityDecision) {
+	if t == nil {
+		return
+	}
+
+	record := openrtb_ext.PrivacyAuditRecord{
+		Bidder:   bidder,
+		Activity: activity.String(),
+		Policy:   decision.Policy,
+		Result:   openrtb_ext.PrivacyAuditResultDeny,
+	}
+	if decision.Allowed {
+		record.Result = openrtb_ext.PrivacyAuditResultAllow
+	}
+	if decision.RuleIndex >= 0 {
+		ruleIndex := decision.RuleIndex
+		record.RuleIndex = &ruleIndex
+	}
+	t.records = append(t.records, record)
+}
+
+// Records returns the recorded decisions by bidder, the decisions of a bidder are in the order they were taken.
+// Bidder requests are built in no particular order so the records are sorted to keep responses stable.
+func (t *AuditTrail) Records() []openrtb_ext.PrivacyAuditRecord {
+	if t == nil {
+		return nil
+	}
+	sort.SliceStable(t.records, func(i, j int) bool {
+		return t.records[i].Bidder < t.records[j].Bidder
+	})
+	return t.records
+}
@@ -0,0 +1,29 @@
+package privacy
+
+import (
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditTrailRecord(t *testing.T) {
+	trail := &AuditTrail{}
+	trail.Record("bidderB", ActivityTransmitTIDs, ActivityDecision{Allowed: true, Policy: AuditPolicyDefault, RuleIndex: -1})
+	trail.Record("bidderA", ActivityFetchBids, ActivityDecision{Allowed: false, Policy: AuditPolicyActivityControl, RuleIndex: 0})
+	trail.Record("bidderA", ActivityTransmitUserFPD, Deny(AuditPolicyTCF2))
+
+	expected := []openrtb_ext.PrivacyAuditRecord{
+		{Bidder: "bidderA", Activity: "fetchBids", Policy: "activityControl", RuleIndex: ptrutil.ToPtr(0), Result: "deny"},
+		{Bidder: "bidderA", Activity: "transmitUfpd", Policy: "tcf2", Result: "deny"},
+		{Bidder: "bidderB", Activity: "transmitTid", Policy: "default", Result: "allow"},
+	}
+	assert.Equal(t, expected, trail.Records())
+}
+
+func TestAuditTrailNil(t *testing.T) {
+	var trail *AuditTrail
+	trail.Record("bidderA", ActivityFetchBids, Deny(AuditPolicyCCPA))
+	assert.Nil(t, trail.Records())
+}
@@ -14,10 +14,18 @@ const (
 type Component struct {
 	Type string
 	Name string
+	// Module is the code of the module a hook belongs to, rules naming the module apply to all of its hooks
+	Module string
+	// EIDSource is the source of the EID the transmitEids activity is evaluated for
+	EIDSource string
 }
 
 func (c Component) MatchesName(v string) bool {
-	return strings.EqualFold(c.Name, v)
+	return strings.EqualFold(c.Name, v) || (c.Module != "" && strings.EqualFold(c.Module, v))
+}
+
+func (c Component) MatchesEIDSource(v string) bool {
+	return c.EIDSource != "" && strings.EqualFold(c.EIDSource, v)
 }
 
 func (c Component) MatchesType(v string) bool {
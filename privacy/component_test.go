@@ -37,6 +37,12 @@ func TestComponentMatchesName(t *testing.T) {
 			target: "B",
 			result: true,
 		},
+		{
+			name:   "module",
+			given:  Component{Type: "a", Name: "b", Module: "c"},
+			target: "C",
+			result: true,
+		},
 	}
 
 	for _, test := range testCases {
@@ -85,3 +91,9 @@ func TestComponentMatchesType(t *testing.T) {
 		})
 	}
 }
+
+func TestComponentMatchesEIDSource(t *testing.T) {
+	assert.True(t, Component{EIDSource: "liveramp.com"}.MatchesEIDSource("LiveRamp.com"))
+	assert.False(t, Component{EIDSource: "liveramp.com"}.MatchesEIDSource("id5-sync.com"))
+	assert.False(t, Component{}.MatchesEIDSource("liveramp.com"), "a component without source must not match a source clause")
+}
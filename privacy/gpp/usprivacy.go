@@ -0,0 +1,183 @@
+package gpp
+
+import (
+	gpplib "github.com/prebid/go-gpp"
+	gppConstants "github.com/prebid/go-gpp/constants"
+	"github.com/prebid/go-gpp/sections"
+	"github.com/prebid/go-gpp/sections/uspca"
+	"github.com/prebid/go-gpp/sections/uspco"
+	"github.com/prebid/go-gpp/sections/uspct"
+	"github.com/prebid/go-gpp/sections/uspnat"
+	"github.com/prebid/go-gpp/sections/usput"
+	"github.com/prebid/go-gpp/sections/uspva"
+)
+
+// Values of the opt-out and consent fields of the US sections, 0 means not applicable.
+const (
+	usOptedOut     byte = 1
+	usNoConsent    byte = 1
+	usProviderMode byte = 1
+)
+
+// USSectionNames maps the US National and US state section ids to their names
+var USSectionNames = map[gppConstants.SectionID]string{
+	gppConstants.SectionUSPNAT: "usnat",
+	gppConstants.SectionUSPCA:  "usca",
+	gppConstants.SectionUSPVA:  "usva",
+	gppConstants.SectionUSPCO:  "usco",
+	gppConstants.SectionUSPUT:  "usut",
+	gppConstants.SectionUSPCT:  "usct",
+}
+
+// usPreciseGeoIndex is the index of the precise geolocation category in the sensitive data fields of each US section,
+// the Colorado section has no such category
+var usPreciseGeoIndex = map[gppConstants.SectionID]int{
+	gppConstants.SectionUSPNAT: 7,
+	gppConstants.SectionUSPCA:  2,
+	gppConstants.SectionUSPVA:  7,
+	gppConstants.SectionUSPCO:  -1,
+	gppConstants.SectionUSPUT:  7,
+	gppConstants.SectionUSPCT:  7,
+}
+
+// USPrivacySignals holds the opt-outs and consents of a US National or US state section relevant to activity
+// enforcement. A section which could not be parsed is marked invalid and must be enforced as if the user opted out.
+type USPrivacySignals struct {
+	SectionID                 gppConstants.SectionID
+	Invalid                   bool
+	SaleOptOut                bool
+	SharingOptOut             bool
+	TargetedAdvertisingOptOut bool
+	SensitiveDataOptOut       bool
+	PreciseGeoOptOut          bool
+	KnownChildNoConsent       bool
+	PersonalDataNoConsent     bool
+	ServiceProviderMode       bool
+	GPC                       bool
+}
+
+// IsUSPrivacySIDInList returns true if any of the gppSIDs is a US National or US state section
+func IsUSPrivacySIDInList(gppSIDs []int8) bool {
+	for _, id := range gppSIDs {
+		if _, ok := USSectionNames[gppConstants.SectionID(id)]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseUSPrivacySignals returns the signals of the US National and US state sections of the GPP string which are
+// listed in gppSIDs. Applicable sections missing from the GPP string are reported as invalid.
+func ParseUSPrivacySignals(gppString string, gppSIDs []int8) []USPrivacySignals {
+	if !IsUSPrivacySIDInList(gppSIDs) {
+		return nil
+	}
+
+	gpp, _ := gpplib.Parse(gppString)
+
+	var signals []USPrivacySignals
+	for _, id := range gppSIDs {
+		sid := gppConstants.SectionID(id)
+		if _, ok := USSectionNames[sid]; !ok {
+			continue
+		}
+
+		i := IndexOfSID(gpp, sid)
+		if i < 0 || i >= len(gpp.Sections) {
+			signals = append(signals, USPrivacySignals{SectionID: sid, Invalid: true})
+			continue
+		}
+		signals = append(signals, newUSPrivacySignals(sid, gpp.Sections[i]))
+	}
+	return signals
+}
+
+// newUSPrivacySignals extracts the signals from a parsed US section, sections which failed to parse have no section id
+func newUSPrivacySignals(sid gppConstants.SectionID, section gpplib.Section) USPrivacySignals {
+	signals := USPrivacySignals{SectionID: sid}
+
+	switch s := section.(type) {
+	case uspnat.USPNAT:
+		if s.SectionID != sid {
+			break
+		}
+		core := s.CoreSegment
+		signals.SaleOptOut = core.SaleOptOut == usOptedOut
+		signals.SharingOptOut = core.SharingOptOut == usOptedOut
+		signals.TargetedAdvertisingOptOut = core.TargetedAdvertisingOptOut == usOptedOut
+		signals.PersonalDataNoConsent = core.PersonalDataConsents == usNoConsent
+		signals.setSensitiveData(core.SensitiveDataProcessing, core.KnownChildSensitiveDataConsents)
+		signals.ServiceProviderMode = core.MspaServiceProviderMode == usProviderMode
+		signals.GPC = s.GPCSegment.Gpc
+		return signals
+	case uspca.USPCA:
+		if s.SectionID != sid {
+			break
+		}
+		core := s.CoreSegment
+		signals.SaleOptOut = core.SaleOptOut == usOptedOut
+		signals.SharingOptOut = core.SharingOptOut == usOptedOut
+		signals.PersonalDataNoConsent = core.PersonalDataConsents == usNoConsent
+		signals.setSensitiveData(core.SensitiveDataProcessing, core.KnownChildSensitiveDataConsents)
+		signals.ServiceProviderMode = core.MspaServiceProviderMode == usProviderMode
+		signals.GPC = s.GPCSegment.Gpc
+		return signals
+	case uspva.USPVA:
+		if s.SectionID == sid {
+			signals.setCommonCore(s.CoreSegment, sections.CommonUSGPCSegment{})
+			return signals
+		}
+	case uspco.USPCO:
+		if s.SectionID == sid {
+			signals.setCommonCore(s.CoreSegment, s.GPCSegment)
+			return signals
+		}
+	case uspct.USPCT:
+		if s.SectionID == sid {
+			signals.setCommonCore(s.CoreSegment, s.GPCSegment)
+			return signals
+		}
+	case usput.USPUT:
+		if s.SectionID != sid {
+			break
+		}
+		core := s.CoreSegment
+		signals.SaleOptOut = core.SaleOptOut == usOptedOut
+		signals.TargetedAdvertisingOptOut = core.TargetedAdvertisingOptOut == usOptedOut
+		signals.setSensitiveData(core.SensitiveDataProcessing, []byte{core.KnownChildSensitiveDataConsents})
+		signals.ServiceProviderMode = core.MspaServiceProviderMode == usProviderMode
+		return signals
+	}
+
+	signals.Invalid = true
+	return signals
+}
+
+func (signals *USPrivacySignals) setCommonCore(core sections.CommonUSCoreSegment, gpc sections.CommonUSGPCSegment) {
+	signals.SaleOptOut = core.SaleOptOut == usOptedOut
+	signals.TargetedAdvertisingOptOut = core.TargetedAdvertisingOptOut == usOptedOut
+	signals.setSensitiveData(core.SensitiveDataProcessing, core.KnownChildSensitiveDataConsents)
+	signals.ServiceProviderMode = core.MspaServiceProviderMode == usProviderMode
+	signals.GPC = gpc.Gpc
+}
+
+// setSensitiveData sets the sensitive data signals, a sensitive data category is restricted when the user opted out
+// of its processing or did not consent to it, both being encoded as 1 depending on the section
+func (signals *USPrivacySignals) setSensitiveData(sensitiveData []byte, knownChildConsents []byte) {
+	geoIndex := usPreciseGeoIndex[signals.SectionID]
+	for i, value := range sensitiveData {
+		if value != usOptedOut {
+			continue
+		}
+		signals.SensitiveDataOptOut = true
+		if i == geoIndex {
+			signals.PreciseGeoOptOut = true
+		}
+	}
+
+	for _, value := range knownChildConsents {
+		if value == usNoConsent {
+			signals.KnownChildNoConsent = true
+		}
+	}
+}
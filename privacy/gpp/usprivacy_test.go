@@ -0,0 +1,189 @@
+package gpp
+
+import (
+	"testing"
+
+	gpplib "github.com/prebid/go-gpp"
+	gppConstants "github.com/prebid/go-gpp/constants"
+	"github.com/prebid/go-gpp/sections"
+	"github.com/prebid/go-gpp/sections/uspco"
+	"github.com/prebid/go-gpp/sections/uspct"
+	"github.com/prebid/go-gpp/sections/uspnat"
+	"github.com/prebid/go-gpp/sections/usput"
+	"github.com/stretchr/testify/assert"
+)
+
+// usAllSectionsGPP holds the usnat, usca, usva, usco, usut and usct sections
+const usAllSectionsGPP = "DBABrGA~DSJgmkoZJSA.YA~BlgWEYCY.QA~BSFgmiU~bSFgmJQ.YA~BWJYJllA~bSFgmSZQ.YA"
+
+func TestIsUSPrivacySIDInList(t *testing.T) {
+	assert.False(t, IsUSPrivacySIDInList(nil))
+	assert.False(t, IsUSPrivacySIDInList([]int8{2, 6}))
+	assert.True(t, IsUSPrivacySIDInList([]int8{2, 8}))
+}
+
+func TestParseUSPrivacySignals(t *testing.T) {
+	usnatSignals := USPrivacySignals{
+		SectionID:             gppConstants.SectionUSPNAT,
+		SensitiveDataOptOut:   true,
+		PersonalDataNoConsent: true,
+		GPC:                   true,
+	}
+	usvaSignals := USPrivacySignals{
+		SectionID:                 gppConstants.SectionUSPVA,
+		TargetedAdvertisingOptOut: true,
+		SensitiveDataOptOut:       true,
+		ServiceProviderMode:       true,
+	}
+
+	testCases := []struct {
+		name     string
+		gpp      string
+		gppSIDs  []int8
+		expected []USPrivacySignals
+	}{
+		{
+			name:     "no_us_sid",
+			gpp:      usAllSectionsGPP,
+			gppSIDs:  []int8{2, 6},
+			expected: nil,
+		},
+		{
+			name:     "applicable_sections_in_sid_order",
+			gpp:      usAllSectionsGPP,
+			gppSIDs:  []int8{9, 2, 7},
+			expected: []USPrivacySignals{usvaSignals, usnatSignals},
+		},
+		{
+			name:    "usca",
+			gpp:     "DBABBgA~xlgWEYCZAA",
+			gppSIDs: []int8{8},
+			expected: []USPrivacySignals{{
+				SectionID:           gppConstants.SectionUSPCA,
+				SensitiveDataOptOut: true,
+				PreciseGeoOptOut:    true,
+			}},
+		},
+		{
+			name:     "section_missing_from_gpp_string",
+			gpp:      "DBABBgA~xlgWEYCZAA",
+			gppSIDs:  []int8{7},
+			expected: []USPrivacySignals{{SectionID: gppConstants.SectionUSPNAT, Invalid: true}},
+		},
+		{
+			name:     "malformed_gpp_string",
+			gpp:      "malformed",
+			gppSIDs:  []int8{8},
+			expected: []USPrivacySignals{{SectionID: gppConstants.SectionUSPCA, Invalid: true}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ParseUSPrivacySignals(tc.gpp, tc.gppSIDs))
+		})
+	}
+}
+
+func TestNewUSPrivacySignals(t *testing.T) {
+	testCases := []struct {
+		name     string
+		sid      gppConstants.SectionID
+		section  gpplib.Section
+		expected USPrivacySignals
+	}{
+		{
+			name: "usnat_opt_outs",
+			sid:  gppConstants.SectionUSPNAT,
+			section: uspnat.USPNAT{
+				SectionID: gppConstants.SectionUSPNAT,
+				CoreSegment: uspnat.USPNATCoreSegment{
+					SaleOptOut:                      1,
+					SharingOptOut:                   1,
+					TargetedAdvertisingOptOut:       1,
+					SensitiveDataProcessing:         []byte{0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0},
+					KnownChildSensitiveDataConsents: []byte{0, 1},
+					MspaServiceProviderMode:         1,
+				},
+			},
+			expected: USPrivacySignals{
+				SectionID:                 gppConstants.SectionUSPNAT,
+				SaleOptOut:                true,
+				SharingOptOut:             true,
+				TargetedAdvertisingOptOut: true,
+				SensitiveDataOptOut:       true,
+				PreciseGeoOptOut:          true,
+				KnownChildNoConsent:       true,
+				ServiceProviderMode:       true,
+			},
+		},
+		{
+			name: "usco_has_no_precise_geo_category",
+			sid:  gppConstants.SectionUSPCO,
+			section: uspco.USPCO{
+				SectionID: gppConstants.SectionUSPCO,
+				CoreSegment: sections.CommonUSCoreSegment{
+					SaleOptOut:              1,
+					SensitiveDataProcessing: []byte{0, 0, 0, 0, 0, 0, 1},
+				},
+				GPCSegment: sections.CommonUSGPCSegment{SubsectionType: 1, Gpc: true},
+			},
+			expected: USPrivacySignals{
+				SectionID:           gppConstants.SectionUSPCO,
+				SaleOptOut:          true,
+				SensitiveDataOptOut: true,
+				GPC:                 true,
+			},
+		},
+		{
+			name: "usct_consents_given",
+			sid:  gppConstants.SectionUSPCT,
+			section: uspct.USPCT{
+				SectionID: gppConstants.SectionUSPCT,
+				CoreSegment: sections.CommonUSCoreSegment{
+					SaleOptOut:                      2,
+					TargetedAdvertisingOptOut:       2,
+					SensitiveDataProcessing:         []byte{2, 2, 2, 2, 2, 2, 2, 2},
+					KnownChildSensitiveDataConsents: []byte{2, 2, 2},
+					MspaServiceProviderMode:         2,
+				},
+			},
+			expected: USPrivacySignals{SectionID: gppConstants.SectionUSPCT},
+		},
+		{
+			name: "usut_known_child",
+			sid:  gppConstants.SectionUSPUT,
+			section: usput.USPUT{
+				SectionID: gppConstants.SectionUSPUT,
+				CoreSegment: usput.USPUTCoreSegment{
+					SensitiveDataProcessing:         []byte{0, 0, 0, 0, 0, 0, 0, 1},
+					KnownChildSensitiveDataConsents: 1,
+				},
+			},
+			expected: USPrivacySignals{
+				SectionID:           gppConstants.SectionUSPUT,
+				SensitiveDataOptOut: true,
+				PreciseGeoOptOut:    true,
+				KnownChildNoConsent: true,
+			},
+		},
+		{
+			name:     "section_failed_to_parse",
+			sid:      gppConstants.SectionUSPNAT,
+			section:  uspnat.USPNAT{},
+			expected: USPrivacySignals{SectionID: gppConstants.SectionUSPNAT, Invalid: true},
+		},
+		{
+			name:     "unexpected_section_type",
+			sid:      gppConstants.SectionUSPVA,
+			section:  uspnat.USPNAT{SectionID: gppConstants.SectionUSPNAT},
+			expected: USPrivacySignals{SectionID: gppConstants.SectionUSPVA, Invalid: true},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, newUSPrivacySignals(tc.sid, tc.section))
+		})
+	}
+}
@@ -7,6 +7,7 @@ type ConditionRule struct {
 	result        ActivityResult
 	componentName []string
 	componentType []string
+	eidSource     []string
 	gppSID        []int8
 }
 
@@ -19,6 +20,10 @@ func (r ConditionRule) Evaluate(target Component, request ActivityRequest) Activ
 		return ActivityAbstain
 	}
 
+	if matched := evaluateEIDSource(target, r.eidSource); !matched {
+		return ActivityAbstain
+	}
+
 	if matched := evaluateGPPSID(r.gppSID, request); !matched {
 		return ActivityAbstain
 	}
@@ -56,6 +61,21 @@ func evaluateComponentType(target Component, componentTypes []string) bool {
 	return false
 }
 
+// evaluateEIDSource only matches clauses against a component evaluated for an EID source
+func evaluateEIDSource(target Component, eidSources []string) bool {
+	if len(eidSources) == 0 {
+		return noClausesDefinedResult
+	}
+
+	for _, s := range eidSources {
+		if target.MatchesEIDSource(s) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func evaluateGPPSID(sid []int8, request ActivityRequest) bool {
 	if len(sid) == 0 {
 		return noClausesDefinedResult
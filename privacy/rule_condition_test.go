@@ -35,6 +35,24 @@ func TestComponentEnforcementRuleEvaluate(t *testing.T) {
 			target:         Component{Type: "bidder", Name: "bidderA"},
 			activityResult: ActivityDeny,
 		},
+		{
+			name: "eid_source_matches",
+			componentRule: ConditionRule{
+				result:    ActivityDeny,
+				eidSource: []string{"liveramp.com"},
+			},
+			target:         Component{Type: "bidder", Name: "bidderA", EIDSource: "liveramp.com"},
+			activityResult: ActivityDeny,
+		},
+		{
+			name: "abstain_eid_source_does_not_match",
+			componentRule: ConditionRule{
+				result:    ActivityDeny,
+				eidSource: []string{"liveramp.com"},
+			},
+			target:         Component{Type: "bidder", Name: "bidderA"},
+			activityResult: ActivityAbstain,
+		},
 		{
 			name: "abstain_both_clauses_do_not_match",
 			componentRule: ConditionRule{
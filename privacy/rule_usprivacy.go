@@ -0,0 +1,194 @@
+package privacy
+
+import (
+	"slices"
+	"sync"
+
+	gppConstants "github.com/prebid/go-gpp/constants"
+	"github.com/prebid/prebid-server/v3/config"
+	gppPolicy "github.com/prebid/prebid-server/v3/privacy/gpp"
+)
+
+// usPrivacyRestrictions maps the activities restricted by the US National and US state sections to the signals
+// restricting them. Activities not listed, such as fetchBids, are only restricted by the section override rules.
+var usPrivacyRestrictions = map[Activity]func(s gppPolicy.USPrivacySignals) bool{
+	ActivitySyncUser:                 restrictsUserData,
+	ActivityTransmitUniqueRequestIDs: restrictsUserData,
+	ActivityTransmitEIDs:             restrictsUserData,
+	ActivityTransmitDeviceIDs:        restrictsUserData,
+	ActivityEnrichUserFPD:            restrictsUserFPD,
+	ActivityTransmitUserFPD:          restrictsUserFPD,
+	ActivityTransmitPreciseGeo:       restrictsPreciseGeo,
+	ActivityTransmitPAAPI:            restrictsUserData,
+}
+
+// usPrivacySection is the enforcement of a single US section for an activity
+type usPrivacySection struct {
+	enabled bool
+	rules   []Rule
+}
+
+// USPrivacyRule enforces the US National and US state GPP sections listed in the GPP SID of the request
+// for a single activity
+type USPrivacyRule struct {
+	activity Activity
+	sections map[gppConstants.SectionID]usPrivacySection
+	signals  *usPrivacySignalsCache
+}
+
+// usPrivacySignalsCache holds the US sections parsed from the GPP string of the request, it is shared by the rules
+// of all activities so the GPP string is parsed once per request
+type usPrivacySignalsCache struct {
+	lock    sync.Mutex
+	parsed  bool
+	gpp     string
+	gppSID  []int8
+	signals []gppPolicy.USPrivacySignals
+}
+
+// newUSPrivacyRule returns the rule enforcing the US sections for the activity, it returns false if neither the
+// section signals nor the section override rules can restrict the activity
+func newUSPrivacyRule(activity Activity, cfg config.AccountUSPrivacy, signals *usPrivacySignalsCache) (USPrivacyRule, bool) {
+	rule := USPrivacyRule{
+		activity: activity,
+		sections: make(map[gppConstants.SectionID]usPrivacySection, len(gppPolicy.USSectionNames)),
+		signals:  signals,
+	}
+
+	_, applies := usPrivacyRestrictions[activity]
+	for sid, name := range gppPolicy.USSectionNames {
+		section := usPrivacySection{enabled: true}
+		if override, ok := cfg.Sections[name]; ok {
+			if override.Enabled != nil {
+				section.enabled = *override.Enabled
+			}
+			section.rules = cfgToUSPrivacyRules(activity, override.Rules)
+		}
+		applies = applies || (section.enabled && len(section.rules) > 0)
+		rule.sections[sid] = section
+	}
+	return rule, applies
+}
+
+func cfgToUSPrivacyRules(activity Activity, rules []config.USPrivacyRule) []Rule {
+	var enfRules []Rule
+
+	for _, r := range rules {
+		if !containsActivity(r.Activities, activity) {
+			continue
+		}
+
+		result := ActivityDeny
+		if r.Allow {
+			result = ActivityAllow
+		}
+
+		er := ConditionRule{
+			result:        result,
+			componentName: r.Condition.ComponentName,
+			componentType: r.Condition.ComponentType,
+			eidSource:     r.Condition.EIDSource,
+		}
+		enfRules = append(enfRules, er)
+	}
+	return enfRules
+}
+
+func containsActivity(activities []string, activity Activity) bool {
+	for _, a := range activities {
+		if a == activity.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// Evaluate denies the activity if any applicable section restricts it, a section override rule matching the target
+// takes precedence over the signals of that section
+func (r USPrivacyRule) Evaluate(target Component, request ActivityRequest) ActivityResult {
+	gppSID := getGPPSID(request)
+	if !gppPolicy.IsUSPrivacySIDInList(gppSID) {
+		return ActivityAbstain
+	}
+
+	result := ActivityAbstain
+	for _, signals := range r.signals.get(getGPP(request), gppSID) {
+		section, ok := r.sections[signals.SectionID]
+		if !ok || !section.enabled {
+			continue
+		}
+
+		sectionResult := evaluateRules(section.rules, target, request)
+		if sectionResult == ActivityAbstain && r.restricts(signals) {
+			sectionResult = ActivityDeny
+		}
+
+		if sectionResult == ActivityDeny {
+			return ActivityDeny
+		}
+		if sectionResult == ActivityAllow {
+			result = ActivityAllow
+		}
+	}
+	return result
+}
+
+// get returns the US sections of the GPP string, they are parsed again only if the GPP string or SID changed
+func (c *usPrivacySignalsCache) get(gpp string, gppSID []int8) []gppPolicy.USPrivacySignals {
+	if c == nil {
+		return gppPolicy.ParseUSPrivacySignals(gpp, gppSID)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if !c.parsed || c.gpp != gpp || !slices.Equal(c.gppSID, gppSID) {
+		c.signals = gppPolicy.ParseUSPrivacySignals(gpp, gppSID)
+		c.gpp = gpp
+		c.gppSID = slices.Clone(gppSID)
+		c.parsed = true
+	}
+	return c.signals
+}
+
+func (r USPrivacyRule) restricts(signals gppPolicy.USPrivacySignals) bool {
+	restriction, ok := usPrivacyRestrictions[r.activity]
+	if !ok {
+		return false
+	}
+	return signals.Invalid || restriction(signals)
+}
+
+func evaluateRules(rules []Rule, target Component, request ActivityRequest) ActivityResult {
+	for _, rule := range rules {
+		if result := rule.Evaluate(target, request); result != ActivityAbstain {
+			return result
+		}
+	}
+	return ActivityAbstain
+}
+
+func restrictsUserData(s gppPolicy.USPrivacySignals) bool {
+	return s.SaleOptOut || s.SharingOptOut || s.TargetedAdvertisingOptOut || s.KnownChildNoConsent ||
+		s.PersonalDataNoConsent || s.ServiceProviderMode || s.GPC
+}
+
+func restrictsUserFPD(s gppPolicy.USPrivacySignals) bool {
+	return restrictsUserData(s) || s.SensitiveDataOptOut
+}
+
+func restrictsPreciseGeo(s gppPolicy.USPrivacySignals) bool {
+	return s.PreciseGeoOptOut || s.KnownChildNoConsent || s.ServiceProviderMode || s.GPC
+}
+
+func getGPP(request ActivityRequest) string {
+	if request.IsPolicies() {
+		return request.policies.GPP
+	}
+
+	if request.IsBidRequest() && request.bidRequest.Regs != nil {
+		return request.bidRequest.Regs.GPP
+	}
+
+	return ""
+}
@@ -0,0 +1,240 @@
+package privacy
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	// usAllSectionsGPP holds the usnat, usca, usva, usco, usut and usct sections, the usnat section has no sale,
+	// sharing or targeted advertising opt-out but GPC is set
+	usAllSectionsGPP = "DBABrGA~DSJgmkoZJSA.YA~BlgWEYCY.QA~BSFgmiU~bSFgmJQ.YA~BWJYJllA~bSFgmSZQ.YA"
+	// uscaGPP holds a usca section opting out of the processing of precise geolocation only
+	uscaGPP = "DBABBgA~xlgWEYCZAA"
+)
+
+func getTestUSPrivacyRule(activity Activity, cfg config.AccountUSPrivacy) USPrivacyRule {
+	rule, _ := newUSPrivacyRule(activity, cfg, &usPrivacySignalsCache{})
+	return rule
+}
+
+func TestUSPrivacyRuleEvaluate(t *testing.T) {
+	bidderA := Component{Type: ComponentTypeBidder, Name: "bidderA"}
+	bidderB := Component{Type: ComponentTypeBidder, Name: "bidderB"}
+
+	testCases := []struct {
+		name     string
+		cfg      config.AccountUSPrivacy
+		activity Activity
+		target   Component
+		request  ActivityRequest
+		expected ActivityResult
+	}{
+		{
+			name:     "no_us_section_in_sid",
+			activity: ActivitySyncUser,
+			target:   bidderA,
+			request:  NewRequestFromPolicies(Policies{GPPSID: []int8{2}, GPP: usAllSectionsGPP}),
+			expected: ActivityAbstain,
+		},
+		{
+			name:     "user_data_restricted_by_gpc",
+			activity: ActivitySyncUser,
+			target:   bidderA,
+			request:  NewRequestFromPolicies(Policies{GPPSID: []int8{7}, GPP: usAllSectionsGPP}),
+			expected: ActivityDeny,
+		},
+		{
+			name:     "precise_geo_restricted",
+			activity: ActivityTransmitPreciseGeo,
+			target:   bidderA,
+			request:  NewRequestFromPolicies(Policies{GPPSID: []int8{8}, GPP: uscaGPP}),
+			expected: ActivityDeny,
+		},
+		{
+			name:     "user_data_not_restricted",
+			activity: ActivitySyncUser,
+			target:   bidderA,
+			request:  NewRequestFromPolicies(Policies{GPPSID: []int8{8}, GPP: uscaGPP}),
+			expected: ActivityAbstain,
+		},
+		{
+			name:     "fetch_bids_not_restricted_by_signals",
+			activity: ActivityFetchBids,
+			target:   bidderA,
+			request:  NewRequestFromPolicies(Policies{GPPSID: []int8{7}, GPP: usAllSectionsGPP}),
+			expected: ActivityAbstain,
+		},
+		{
+			name:     "invalid_section_restricts",
+			activity: ActivityTransmitUserFPD,
+			target:   bidderA,
+			request:  NewRequestFromPolicies(Policies{GPPSID: []int8{7}, GPP: uscaGPP}),
+			expected: ActivityDeny,
+		},
+		{
+			name:     "bid_request_signals",
+			activity: ActivityTransmitPreciseGeo,
+			target:   bidderA,
+			request: NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+				Regs: &openrtb2.Regs{GPPSID: []int8{8}, GPP: uscaGPP},
+			}}),
+			expected: ActivityDeny,
+		},
+		{
+			name: "section_disabled",
+			cfg: config.AccountUSPrivacy{Enabled: true, Sections: map[string]config.AccountUSPrivacySection{
+				"usca": {Enabled: ptrutil.ToPtr(false)},
+			}},
+			activity: ActivityTransmitPreciseGeo,
+			target:   bidderA,
+			request:  NewRequestFromPolicies(Policies{GPPSID: []int8{8}, GPP: uscaGPP}),
+			expected: ActivityAbstain,
+		},
+		{
+			name: "override_rule_allows_matching_component",
+			cfg: config.AccountUSPrivacy{Enabled: true, Sections: map[string]config.AccountUSPrivacySection{
+				"usca": {Rules: []config.USPrivacyRule{{
+					Activities: []string{"transmitPreciseGeo"},
+					Condition:  config.ActivityCondition{ComponentName: []string{"bidderA"}},
+					Allow:      true,
+				}}},
+			}},
+			activity: ActivityTransmitPreciseGeo,
+			target:   bidderA,
+			request:  NewRequestFromPolicies(Policies{GPPSID: []int8{8}, GPP: uscaGPP}),
+			expected: ActivityAllow,
+		},
+		{
+			name: "override_rule_does_not_match_component",
+			cfg: config.AccountUSPrivacy{Enabled: true, Sections: map[string]config.AccountUSPrivacySection{
+				"usca": {Rules: []config.USPrivacyRule{{
+					Activities: []string{"transmitPreciseGeo"},
+					Condition:  config.ActivityCondition{ComponentName: []string{"bidderA"}},
+					Allow:      true,
+				}}},
+			}},
+			activity: ActivityTransmitPreciseGeo,
+			target:   bidderB,
+			request:  NewRequestFromPolicies(Policies{GPPSID: []int8{8}, GPP: uscaGPP}),
+			expected: ActivityDeny,
+		},
+		{
+			name: "override_rule_denies_activity",
+			cfg: config.AccountUSPrivacy{Enabled: true, Sections: map[string]config.AccountUSPrivacySection{
+				"usca": {Rules: []config.USPrivacyRule{{Activities: []string{"fetchBids"}}}},
+			}},
+			activity: ActivityFetchBids,
+			target:   bidderA,
+			request:  NewRequestFromPolicies(Policies{GPPSID: []int8{8}, GPP: uscaGPP}),
+			expected: ActivityDeny,
+		},
+		{
+			name: "any_restricting_section_denies",
+			cfg: config.AccountUSPrivacy{Enabled: true, Sections: map[string]config.AccountUSPrivacySection{
+				"usca": {Rules: []config.USPrivacyRule{{Activities: []string{"syncUser"}, Allow: true}}},
+			}},
+			activity: ActivitySyncUser,
+			target:   bidderA,
+			request:  NewRequestFromPolicies(Policies{GPPSID: []int8{8, 7}, GPP: usAllSectionsGPP}),
+			expected: ActivityDeny,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule := getTestUSPrivacyRule(tc.activity, tc.cfg)
+			assert.Equal(t, tc.expected, rule.Evaluate(tc.target, tc.request))
+		})
+	}
+}
+
+func TestNewUSPrivacyRuleApplies(t *testing.T) {
+	testCases := []struct {
+		name     string
+		cfg      config.AccountUSPrivacy
+		activity Activity
+		expected bool
+	}{
+		{
+			name:     "restricted_by_signals",
+			activity: ActivitySyncUser,
+			expected: true,
+		},
+		{
+			name:     "not_restricted_by_signals",
+			activity: ActivityFetchBids,
+			expected: false,
+		},
+		{
+			name: "override_rule",
+			cfg: config.AccountUSPrivacy{Enabled: true, Sections: map[string]config.AccountUSPrivacySection{
+				"usca": {Rules: []config.USPrivacyRule{{Activities: []string{"fetchBids"}}}},
+			}},
+			activity: ActivityFetchBids,
+			expected: true,
+		},
+		{
+			name: "override_rule_of_disabled_section",
+			cfg: config.AccountUSPrivacy{Enabled: true, Sections: map[string]config.AccountUSPrivacySection{
+				"usca": {Enabled: ptrutil.ToPtr(false), Rules: []config.USPrivacyRule{{Activities: []string{"fetchBids"}}}},
+			}},
+			activity: ActivityFetchBids,
+			expected: false,
+		},
+		{
+			name: "override_rule_of_other_activity",
+			cfg: config.AccountUSPrivacy{Enabled: true, Sections: map[string]config.AccountUSPrivacySection{
+				"usca": {Rules: []config.USPrivacyRule{{Activities: []string{"syncUser"}}}},
+			}},
+			activity: ActivityReportAnalytics,
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, applies := newUSPrivacyRule(tc.activity, tc.cfg, &usPrivacySignalsCache{})
+			assert.Equal(t, tc.expected, applies)
+		})
+	}
+}
+
+func TestUSPrivacySignalsCache(t *testing.T) {
+	cache := &usPrivacySignalsCache{}
+
+	signals := cache.get(uscaGPP, []int8{8})
+	if assert.Len(t, signals, 1) {
+		assert.True(t, signals[0].PreciseGeoOptOut)
+	}
+	assert.Same(t, &signals[0], &cache.get(uscaGPP, []int8{8})[0], "same GPP string must not be parsed again")
+
+	signals = cache.get(uscaGPP, []int8{7})
+	if assert.Len(t, signals, 1) {
+		assert.True(t, signals[0].Invalid, "GPP SID change must parse the GPP string again")
+	}
+}
+
+func TestNewActivityControlUSPrivacy(t *testing.T) {
+	cfg := &config.AccountPrivacy{
+		AllowActivities: &config.AllowActivities{
+			TransmitPreciseGeo: getTestActivityConfig(true),
+		},
+		USPrivacy: config.AccountUSPrivacy{Enabled: true},
+	}
+	request := NewRequestFromPolicies(Policies{GPPSID: []int8{8}, GPP: uscaGPP})
+
+	ac := NewActivityControl(cfg)
+	assert.True(t, ac.Allow(ActivityTransmitPreciseGeo, Component{Type: "bidder", Name: "bidderA"}, request), "allowed activities rules take precedence")
+	assert.False(t, ac.Allow(ActivityTransmitPreciseGeo, Component{Type: "bidder", Name: "bidderB"}, request))
+	assert.True(t, ac.Allow(ActivitySyncUser, Component{Type: "bidder", Name: "bidderB"}, request))
+
+	ac = NewActivityControl(&config.AccountPrivacy{USPrivacy: config.AccountUSPrivacy{Enabled: true}})
+	assert.False(t, ac.Allow(ActivityTransmitPreciseGeo, Component{Type: "bidder", Name: "bidderB"}, request), "sections must be enforced without allowed activities")
+	assert.Empty(t, ac.plans[ActivityFetchBids].rules, "sections must not be enforced on activities they cannot restrict")
+}
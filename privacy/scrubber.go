@@ -3,7 +3,10 @@ package privacy
 import (
 	"encoding/json"
 	"net"
+	"slices"
+	"strings"
 
+	"github.com/buger/jsonparser"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
 
 	"github.com/prebid/openrtb/v20/openrtb2"
@@ -86,6 +89,33 @@ func ScrubTID(reqWrapper *openrtb_ext.RequestWrapper) {
 	reqWrapper.SetImp(impWrapper)
 }
 
+// ScrubPAAPI removes the Protected Audience signals of the imps, imp.ext.ae and imp.ext.igs. The imps are left
+// untouched when none of them carries the signals.
+func ScrubPAAPI(reqWrapper *openrtb_ext.RequestWrapper) {
+	if !slices.ContainsFunc(reqWrapper.Imp, hasPAAPISignals) {
+		return
+	}
+
+	impWrapper := reqWrapper.GetImp()
+	for ind, imp := range impWrapper {
+		if !hasPAAPISignals(*imp.Imp) {
+			continue
+		}
+		impExt := scrubExtIDs(imp.Ext, openrtb_ext.AuctionEnvironmentKey)
+		impWrapper[ind].Ext = scrubExtIDs(impExt, openrtb_ext.InterestGroupSellerKey)
+	}
+	reqWrapper.SetImp(impWrapper)
+}
+
+func hasPAAPISignals(imp openrtb2.Imp) bool {
+	for _, key := range []string{openrtb_ext.AuctionEnvironmentKey, openrtb_ext.InterestGroupSellerKey} {
+		if _, _, _, err := jsonparser.Get(imp.Ext, key); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
 func scrubGEO(reqWrapper *openrtb_ext.RequestWrapper) {
 	//round user's geographic location by rounding off IP address and lat/lng data.
 	//this applies to both device.geo and user.geo
@@ -145,6 +175,44 @@ func ScrubGdprID(reqWrapper *openrtb_ext.RequestWrapper) {
 	scrubUserExt(reqWrapper, "eids")
 }
 
+// DeniedIDs are the identifiers a component may not receive under the transmitDeviceIds and transmitEids activities
+type DeniedIDs struct {
+	DeviceIDs bool
+	// EIDs denies the EIDs whose source is unknown, those of user.ext.eids
+	EIDs       bool
+	EIDSources []string
+}
+
+func (d DeniedIDs) Any() bool {
+	return d.DeviceIDs || d.EIDs || len(d.EIDSources) > 0
+}
+
+// ScrubDeniedIDs removes the device IDs and the EIDs denied to a component
+func ScrubDeniedIDs(reqWrapper *openrtb_ext.RequestWrapper, denied DeniedIDs) {
+	if denied.DeviceIDs {
+		scrubDeviceIDs(reqWrapper)
+	}
+	if denied.EIDs {
+		scrubUserExt(reqWrapper, "eids")
+	}
+	if len(denied.EIDSources) > 0 && reqWrapper.User != nil {
+		reqWrapper.User.EIDs = scrubEIDSources(reqWrapper.User.EIDs, denied.EIDSources)
+	}
+}
+
+func scrubEIDSources(eids []openrtb2.EID, sources []string) []openrtb2.EID {
+	var kept []openrtb2.EID
+	for _, eid := range eids {
+		denied := slices.ContainsFunc(sources, func(source string) bool {
+			return strings.EqualFold(source, eid.Source)
+		})
+		if !denied {
+			kept = append(kept, eid)
+		}
+	}
+	return kept
+}
+
 func ScrubGeoAndDeviceIP(reqWrapper *openrtb_ext.RequestWrapper, ipConf IPConf) {
 	scrubDeviceIP(reqWrapper, ipConf)
 	scrubGEO(reqWrapper)
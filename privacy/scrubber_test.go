@@ -174,6 +174,58 @@ func TestScrubEids(t *testing.T) {
 	}
 }
 
+func TestScrubDeniedIDs(t *testing.T) {
+	testCases := []struct {
+		name           string
+		denied         DeniedIDs
+		deviceIn       *openrtb2.Device
+		userIn         *openrtb2.User
+		expectedDevice *openrtb2.Device
+		expectedUser   *openrtb2.User
+	}{
+		{
+			name:           "nothing_denied",
+			deviceIn:       &openrtb2.Device{IFA: "ifa"},
+			userIn:         &openrtb2.User{EIDs: []openrtb2.EID{{Source: "a.com"}}},
+			expectedDevice: &openrtb2.Device{IFA: "ifa"},
+			expectedUser:   &openrtb2.User{EIDs: []openrtb2.EID{{Source: "a.com"}}},
+		},
+		{
+			name:           "device_ids",
+			denied:         DeniedIDs{DeviceIDs: true},
+			deviceIn:       &openrtb2.Device{IFA: "ifa", MACSHA1: "mac", IP: "1.2.3.4"},
+			expectedDevice: &openrtb2.Device{IP: "1.2.3.4"},
+		},
+		{
+			name:         "eid_sources",
+			denied:       DeniedIDs{EIDSources: []string{"A.com"}},
+			userIn:       &openrtb2.User{EIDs: []openrtb2.EID{{Source: "a.com"}, {Source: "b.com"}}},
+			expectedUser: &openrtb2.User{EIDs: []openrtb2.EID{{Source: "b.com"}}},
+		},
+		{
+			name:         "all_eid_sources",
+			denied:       DeniedIDs{EIDSources: []string{"a.com"}},
+			userIn:       &openrtb2.User{ID: "ID", EIDs: []openrtb2.EID{{Source: "a.com"}}},
+			expectedUser: &openrtb2.User{ID: "ID"},
+		},
+		{
+			name:         "ext_eids",
+			denied:       DeniedIDs{EIDs: true},
+			userIn:       &openrtb2.User{Ext: json.RawMessage(`{"eids":[{"source":"a.com"}],"data":1}`)},
+			expectedUser: &openrtb2.User{Ext: json.RawMessage(`{"data":1}`)},
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			brw := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Device: test.deviceIn, User: test.userIn}}
+			ScrubDeniedIDs(brw, test.denied)
+			brw.RebuildRequest()
+			assert.Equal(t, test.expectedDevice, brw.Device)
+			assert.Equal(t, test.expectedUser, brw.User)
+		})
+	}
+}
+
 func TestScrubTID(t *testing.T) {
 	testCases := []struct {
 		name           string
@@ -227,6 +279,52 @@ func TestScrubTID(t *testing.T) {
 	}
 }
 
+func TestScrubPAAPI(t *testing.T) {
+	testCases := []struct {
+		name        string
+		impIn       []openrtb2.Imp
+		expectedImp []openrtb2.Imp
+	}{
+		{
+			name:        "nil_imp_ext",
+			impIn:       []openrtb2.Imp{{ID: "impID", Ext: nil}},
+			expectedImp: []openrtb2.Imp{{ID: "impID", Ext: nil}},
+		},
+		{
+			name:        "ext_with_ae_and_igs",
+			impIn:       []openrtb2.Imp{{ID: "impID", Ext: json.RawMessage(`{"ae":1,"igs":{"ae":1,"biddable":1},"test":1}`)}},
+			expectedImp: []openrtb2.Imp{{ID: "impID", Ext: json.RawMessage(`{"test":1}`)}},
+		},
+		{
+			name:        "ext_with_igs",
+			impIn:       []openrtb2.Imp{{ID: "impID", Ext: json.RawMessage(`{"igs":{"ae":1},"tid":"123"}`)}},
+			expectedImp: []openrtb2.Imp{{ID: "impID", Ext: json.RawMessage(`{"tid":"123"}`)}},
+		},
+		{
+			name:        "ext_without_paapi",
+			impIn:       []openrtb2.Imp{{ID: "impID", Ext: json.RawMessage(`{"data":"123","test":1}`)}},
+			expectedImp: []openrtb2.Imp{{ID: "impID", Ext: json.RawMessage(`{"data":"123","test":1}`)}},
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			brw := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Imp: test.impIn}}
+			ScrubPAAPI(brw)
+			brw.RebuildRequest()
+			assert.Equal(t, test.expectedImp, brw.Imp)
+		})
+	}
+}
+
+func TestScrubPAAPIWithoutSignals(t *testing.T) {
+	imps := []openrtb2.Imp{{ID: "impID", Ext: json.RawMessage(`{"data":"123","test":1}`)}}
+	brw := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Imp: imps}}
+
+	ScrubPAAPI(brw)
+	assert.NoError(t, brw.RebuildRequest())
+	assert.Same(t, &imps[0], &brw.Imp[0], "imps shouldn't be rebuilt")
+}
+
 func TestScrubGEO(t *testing.T) {
 	testCases := []struct {
 		name           string
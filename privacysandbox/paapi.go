@@ -0,0 +1,163 @@
+package privacysandbox
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	jsonpatch "gopkg.in/evanphx/json-patch.v5"
+)
+
+// PAAPIImp holds the Protected Audience signals of an imp eligible to an on-device interest group auction
+type PAAPIImp struct {
+	SellerSignals json.RawMessage
+}
+
+type paapiImpExt struct {
+	AE  openrtb_ext.AuctionEnvironmentType     `json:"ae"`
+	IGS *openrtb_ext.ExtImpInterestGroupSeller `json:"igs"`
+}
+
+// PAAPIImps returns the imps eligible to an on-device interest group auction, keyed by ID. An imp is eligible when
+// imp.ext.igs.ae, or else imp.ext.ae, is 1.
+func PAAPIImps(imps []openrtb2.Imp) map[string]PAAPIImp {
+	var paapiImps map[string]PAAPIImp
+	for _, imp := range imps {
+		if len(imp.Ext) == 0 {
+			continue
+		}
+		var ext paapiImpExt
+		if err := jsonutil.Unmarshal(imp.Ext, &ext); err != nil {
+			continue
+		}
+
+		ae := ext.AE
+		var sellerSignals json.RawMessage
+		if ext.IGS != nil {
+			if ext.IGS.AE != nil {
+				ae = *ext.IGS.AE
+			}
+			sellerSignals = ext.IGS.SellerSignals
+		}
+		if ae != openrtb_ext.OnDeviceIGAuctionFledge {
+			continue
+		}
+
+		if paapiImps == nil {
+			paapiImps = make(map[string]PAAPIImp)
+		}
+		paapiImps[imp.ID] = PAAPIImp{SellerSignals: sellerSignals}
+	}
+	return paapiImps
+}
+
+// ValidateAuctionConfigs returns the auction configs which can run as a component auction of their imp, with the
+// seller signals of the imp merged in, and a warning for every rejected config.
+//
+// A config is rejected when its imp isn't eligible to an interest group auction, or when its seller, decision logic
+// URL and interest group buyers aren't https origins, the decision logic URL being on the origin of the seller.
+func ValidateAuctionConfigs(configs []*openrtb_ext.FledgeAuctionConfig, imps map[string]PAAPIImp) ([]*openrtb_ext.FledgeAuctionConfig, []error) {
+	var validConfigs []*openrtb_ext.FledgeAuctionConfig
+	var warnings []error
+	for _, config := range configs {
+		if config == nil {
+			continue
+		}
+		mergedConfig, err := validateAuctionConfig(config, imps)
+		if err != nil {
+			warnings = append(warnings, &errortypes.Warning{
+				Message:     fmt.Sprintf("auction config of imp %s rejected: %v", config.ImpId, err),
+				WarningCode: errortypes.InvalidFledgeAuctionConfigWarningCode,
+			})
+			continue
+		}
+		validConfigs = append(validConfigs, mergedConfig)
+	}
+	return validConfigs, warnings
+}
+
+func validateAuctionConfig(config *openrtb_ext.FledgeAuctionConfig, imps map[string]PAAPIImp) (*openrtb_ext.FledgeAuctionConfig, error) {
+	imp, ok := imps[config.ImpId]
+	if !ok {
+		return nil, errors.New("the imp is not eligible to an interest group auction")
+	}
+
+	var fields map[string]json.RawMessage
+	if err := jsonutil.Unmarshal(config.Config, &fields); err != nil || fields == nil {
+		return nil, errors.New("config must be a JSON object")
+	}
+
+	var seller string
+	if err := jsonutil.Unmarshal(fields["seller"], &seller); err != nil {
+		return nil, errors.New("seller must be set")
+	}
+	sellerOrigin, err := parseOrigin(seller)
+	if err != nil {
+		return nil, fmt.Errorf("seller %v", err)
+	}
+
+	decisionLogicURL, ok := fields["decisionLogicURL"]
+	if !ok {
+		decisionLogicURL = fields["decisionLogicUrl"]
+	}
+	var decisionLogic string
+	if err := jsonutil.Unmarshal(decisionLogicURL, &decisionLogic); err != nil || len(decisionLogic) == 0 {
+		return nil, errors.New("decisionLogicURL must be set")
+	}
+	if parsedURL, err := url.Parse(decisionLogic); err != nil || parsedURL.Scheme+"://"+parsedURL.Host != sellerOrigin {
+		return nil, fmt.Errorf("decisionLogicURL %s must be on the origin of the seller %s", decisionLogic, sellerOrigin)
+	}
+
+	if buyersJSON, ok := fields["interestGroupBuyers"]; ok {
+		var buyers []string
+		if err := jsonutil.Unmarshal(buyersJSON, &buyers); err != nil {
+			return nil, errors.New("interestGroupBuyers must be an array of strings")
+		}
+		for _, buyer := range buyers {
+			if _, err := parseOrigin(buyer); err != nil {
+				return nil, fmt.Errorf("interestGroupBuyers %v", err)
+			}
+		}
+	}
+
+	if len(imp.SellerSignals) == 0 {
+		return config, nil
+	}
+	mergedConfig := *config
+	if mergedConfig.Config, err = mergeSellerSignals(fields, imp.SellerSignals); err != nil {
+		return nil, err
+	}
+	return &mergedConfig, nil
+}
+
+// parseOrigin returns the origin if the string is an https origin, without path, query or fragment
+func parseOrigin(origin string) (string, error) {
+	parsedURL, err := url.Parse(origin)
+	if err != nil || parsedURL.Scheme != "https" || len(parsedURL.Host) == 0 {
+		return "", fmt.Errorf("%s must be an https origin", origin)
+	}
+	if (len(parsedURL.Path) > 0 && parsedURL.Path != "/") || len(parsedURL.RawQuery) > 0 || len(parsedURL.Fragment) > 0 {
+		return "", fmt.Errorf("%s must be an https origin", origin)
+	}
+	return parsedURL.Scheme + "://" + parsedURL.Host, nil
+}
+
+// mergeSellerSignals merges the seller signals of the imp into the ones of the config, the signals of the imp take
+// precedence
+func mergeSellerSignals(fields map[string]json.RawMessage, impSellerSignals json.RawMessage) (json.RawMessage, error) {
+	sellerSignals := impSellerSignals
+	if configSellerSignals, ok := fields["sellerSignals"]; ok {
+		merged, err := jsonpatch.MergePatch(configSellerSignals, impSellerSignals)
+		if err != nil {
+			return nil, fmt.Errorf("sellerSignals can't be merged: %v", err)
+		}
+		sellerSignals = merged
+	}
+	fields["sellerSignals"] = sellerSignals
+	return jsonutil.Marshal(fields)
+}
@@ -0,0 +1,167 @@
+package privacysandbox
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPAAPIImps(t *testing.T) {
+	tests := []struct {
+		name     string
+		imps     []openrtb2.Imp
+		expected map[string]PAAPIImp
+	}{
+		{
+			name:     "no_ext",
+			imps:     []openrtb2.Imp{{ID: "imp-1"}},
+			expected: nil,
+		},
+		{
+			name: "ae",
+			imps: []openrtb2.Imp{
+				{ID: "imp-1", Ext: json.RawMessage(`{"ae":1}`)},
+				{ID: "imp-2", Ext: json.RawMessage(`{"ae":0}`)},
+				{ID: "imp-3", Ext: json.RawMessage(`{"ae":2}`)},
+			},
+			expected: map[string]PAAPIImp{"imp-1": {}},
+		},
+		{
+			name: "igs_ae_takes_precedence",
+			imps: []openrtb2.Imp{
+				{ID: "imp-1", Ext: json.RawMessage(`{"ae":0,"igs":{"ae":1,"biddable":1,"sellersignals":{"floor":1}}}`)},
+				{ID: "imp-2", Ext: json.RawMessage(`{"ae":1,"igs":{"ae":0}}`)},
+				{ID: "imp-3", Ext: json.RawMessage(`{"ae":1,"igs":{"biddable":1}}`)},
+			},
+			expected: map[string]PAAPIImp{
+				"imp-1": {SellerSignals: json.RawMessage(`{"floor":1}`)},
+				"imp-3": {},
+			},
+		},
+		{
+			name:     "malformed_ext",
+			imps:     []openrtb2.Imp{{ID: "imp-1", Ext: json.RawMessage(`{"ae":"1"}`)}},
+			expected: nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, PAAPIImps(test.imps))
+		})
+	}
+}
+
+func TestValidateAuctionConfigs(t *testing.T) {
+	imps := map[string]PAAPIImp{
+		"imp-1": {},
+		"imp-2": {SellerSignals: json.RawMessage(`{"floor":1.5,"currency":null}`)},
+	}
+
+	tests := []struct {
+		name             string
+		config           *openrtb_ext.FledgeAuctionConfig
+		expectedConfig   *openrtb_ext.FledgeAuctionConfig
+		expectedWarnings []string
+	}{
+		{
+			name:           "valid",
+			config:         &openrtb_ext.FledgeAuctionConfig{ImpId: "imp-1", Config: json.RawMessage(`{"seller":"https://seller.com","decisionLogicURL":"https://seller.com/decision.js","interestGroupBuyers":["https://buyer.com/"]}`)},
+			expectedConfig: &openrtb_ext.FledgeAuctionConfig{ImpId: "imp-1", Config: json.RawMessage(`{"seller":"https://seller.com","decisionLogicURL":"https://seller.com/decision.js","interestGroupBuyers":["https://buyer.com/"]}`)},
+		},
+		{
+			name:           "legacy_decision_logic_url",
+			config:         &openrtb_ext.FledgeAuctionConfig{ImpId: "imp-1", Config: json.RawMessage(`{"seller":"https://seller.com","decisionLogicUrl":"https://seller.com/decision.js"}`)},
+			expectedConfig: &openrtb_ext.FledgeAuctionConfig{ImpId: "imp-1", Config: json.RawMessage(`{"seller":"https://seller.com","decisionLogicUrl":"https://seller.com/decision.js"}`)},
+		},
+		{
+			name:           "seller_signals_merged",
+			config:         &openrtb_ext.FledgeAuctionConfig{ImpId: "imp-2", Bidder: "openx", Config: json.RawMessage(`{"seller":"https://seller.com","decisionLogicURL":"https://seller.com/decision.js","sellerSignals":{"currency":"USD","floor":1}}`)},
+			expectedConfig: &openrtb_ext.FledgeAuctionConfig{ImpId: "imp-2", Bidder: "openx", Config: json.RawMessage(`{"decisionLogicURL":"https://seller.com/decision.js","seller":"https://seller.com","sellerSignals":{"floor":1.5}}`)},
+		},
+		{
+			name:           "seller_signals_added",
+			config:         &openrtb_ext.FledgeAuctionConfig{ImpId: "imp-2", Config: json.RawMessage(`{"seller":"https://seller.com","decisionLogicURL":"https://seller.com/decision.js"}`)},
+			expectedConfig: &openrtb_ext.FledgeAuctionConfig{ImpId: "imp-2", Config: json.RawMessage(`{"decisionLogicURL":"https://seller.com/decision.js","seller":"https://seller.com","sellerSignals":{"floor":1.5,"currency":null}}`)},
+		},
+		{
+			name:             "imp_not_eligible",
+			config:           &openrtb_ext.FledgeAuctionConfig{ImpId: "imp-3", Config: json.RawMessage(`{"seller":"https://seller.com","decisionLogicURL":"https://seller.com/decision.js"}`)},
+			expectedWarnings: []string{"auction config of imp imp-3 rejected: the imp is not eligible to an interest group auction"},
+		},
+		{
+			name:             "not_an_object",
+			config:           &openrtb_ext.FledgeAuctionConfig{ImpId: "imp-1", Config: json.RawMessage(`[1,2,3]`)},
+			expectedWarnings: []string{"auction config of imp imp-1 rejected: config must be a JSON object"},
+		},
+		{
+			name:             "no_seller",
+			config:           &openrtb_ext.FledgeAuctionConfig{ImpId: "imp-1", Config: json.RawMessage(`{"decisionLogicURL":"https://seller.com/decision.js"}`)},
+			expectedWarnings: []string{"auction config of imp imp-1 rejected: seller must be set"},
+		},
+		{
+			name:             "seller_not_https",
+			config:           &openrtb_ext.FledgeAuctionConfig{ImpId: "imp-1", Config: json.RawMessage(`{"seller":"http://seller.com","decisionLogicURL":"http://seller.com/decision.js"}`)},
+			expectedWarnings: []string{"auction config of imp imp-1 rejected: seller http://seller.com must be an https origin"},
+		},
+		{
+			name:             "seller_with_path",
+			config:           &openrtb_ext.FledgeAuctionConfig{ImpId: "imp-1", Config: json.RawMessage(`{"seller":"https://seller.com/auction","decisionLogicURL":"https://seller.com/decision.js"}`)},
+			expectedWarnings: []string{"auction config of imp imp-1 rejected: seller https://seller.com/auction must be an https origin"},
+		},
+		{
+			name:             "no_decision_logic_url",
+			config:           &openrtb_ext.FledgeAuctionConfig{ImpId: "imp-1", Config: json.RawMessage(`{"seller":"https://seller.com"}`)},
+			expectedWarnings: []string{"auction config of imp imp-1 rejected: decisionLogicURL must be set"},
+		},
+		{
+			name:             "decision_logic_url_on_another_origin",
+			config:           &openrtb_ext.FledgeAuctionConfig{ImpId: "imp-1", Config: json.RawMessage(`{"seller":"https://seller.com","decisionLogicURL":"https://cdn.com/decision.js"}`)},
+			expectedWarnings: []string{"auction config of imp imp-1 rejected: decisionLogicURL https://cdn.com/decision.js must be on the origin of the seller https://seller.com"},
+		},
+		{
+			name:             "buyer_not_an_origin",
+			config:           &openrtb_ext.FledgeAuctionConfig{ImpId: "imp-1", Config: json.RawMessage(`{"seller":"https://seller.com","decisionLogicURL":"https://seller.com/decision.js","interestGroupBuyers":["buyer.com"]}`)},
+			expectedWarnings: []string{"auction config of imp imp-1 rejected: interestGroupBuyers buyer.com must be an https origin"},
+		},
+		{
+			name:             "buyers_not_an_array",
+			config:           &openrtb_ext.FledgeAuctionConfig{ImpId: "imp-1", Config: json.RawMessage(`{"seller":"https://seller.com","decisionLogicURL":"https://seller.com/decision.js","interestGroupBuyers":"https://buyer.com"}`)},
+			expectedWarnings: []string{"auction config of imp imp-1 rejected: interestGroupBuyers must be an array of strings"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configs, warnings := ValidateAuctionConfigs([]*openrtb_ext.FledgeAuctionConfig{test.config}, imps)
+
+			if test.expectedConfig == nil {
+				assert.Empty(t, configs)
+			} else if assert.Len(t, configs, 1) {
+				assert.Equal(t, test.expectedConfig.ImpId, configs[0].ImpId)
+				assert.Equal(t, test.expectedConfig.Bidder, configs[0].Bidder)
+				assert.JSONEq(t, string(test.expectedConfig.Config), string(configs[0].Config))
+			}
+
+			var warningMessages []string
+			for _, warning := range warnings {
+				assert.Equal(t, errortypes.InvalidFledgeAuctionConfigWarningCode, errortypes.ReadCode(warning))
+				warningMessages = append(warningMessages, warning.Error())
+			}
+			assert.Equal(t, test.expectedWarnings, warningMessages)
+		})
+	}
+}
+
+func TestValidateAuctionConfigsKeepsBidderConfig(t *testing.T) {
+	config := &openrtb_ext.FledgeAuctionConfig{ImpId: "imp-1", Config: json.RawMessage(`{"seller":"https://seller.com","decisionLogicURL":"https://seller.com/decision.js"}`)}
+	imps := map[string]PAAPIImp{"imp-1": {SellerSignals: json.RawMessage(`{"floor":1}`)}}
+
+	configs, _ := ValidateAuctionConfigs([]*openrtb_ext.FledgeAuctionConfig{config}, imps)
+	assert.Len(t, configs, 1)
+	assert.JSONEq(t, `{"seller":"https://seller.com","decisionLogicURL":"https://seller.com/decision.js"}`, string(config.Config), "the config returned by the bidder must not be modified")
+}
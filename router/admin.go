@@ -7,10 +7,11 @@ import (
 
 	"github.com/prebid/prebid-server/v3/currency"
 	"github.com/prebid/prebid-server/v3/endpoints"
+	"github.com/prebid/prebid-server/v3/gdpr"
 	"github.com/prebid/prebid-server/v3/version"
 )
 
-func Admin(rateConverter *currency.RateConverter, rateConverterFetchingInterval time.Duration) *http.ServeMux {
+func Admin(rateConverter *currency.RateConverter, rateConverterFetchingInterval time.Duration, storedDataAPI, floorSuggestionsAPI http.HandlerFunc) *http.ServeMux {
 	// Add endpoints to the admin server
 	// Making sure to add pprof routes
 	mux := http.NewServeMux()
@@ -22,6 +23,13 @@ func Admin(rateConverter *currency.RateConverter, rateConverterFetchingInterval
 	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
 	// Register prebid-server defined admin handlers
 	mux.HandleFunc("/currency/rates", endpoints.NewCurrencyRatesEndpoint(rateConverter, rateConverterFetchingInterval))
+	mux.HandleFunc("/gdpr/vendorlists", endpoints.NewVendorListsEndpoint(gdpr.LoadedVendorListVersions))
 	mux.HandleFunc("/version", endpoints.NewVersionEndpoint(version.Ver, version.Rev))
+	if storedDataAPI != nil {
+		mux.HandleFunc(endpoints.StoredDataEndpoint, storedDataAPI)
+	}
+	if floorSuggestionsAPI != nil {
+		mux.HandleFunc(endpoints.FloorSuggestionsEndpoint, floorSuggestionsAPI)
+	}
 	return mux
 }
@@ -119,10 +119,33 @@ type Router struct {
 	*httprouter.Router
 	MetricsEngine   *metricsConf.DetailedMetricsEngine
 	ParamsValidator openrtb_ext.BidderParamValidator
+	// StoredDataAPI is the admin endpoint managing the stored data, nil when disabled
+	StoredDataAPI http.HandlerFunc
+	// FloorSuggestionsAPI is the admin endpoint serving the suggested floors data, nil when disabled
+	FloorSuggestionsAPI http.HandlerFunc
 
 	shutdowns []func()
 }
 
+// priceEncoders are the host encoders of the ${AUCTION_PRICE} macro registered with RegisterPriceEncoder
+var priceEncoders = macros.PriceEncoders{}
+
+// RegisterPriceEncoder registers a host encoder of the ${AUCTION_PRICE} macro, e.g. an encrypted price, which the
+// accounts select by name with auction_macros.price_encoding. It must be called before New.
+func RegisterPriceEncoder(name string, encoder macros.PriceEncoder) error {
+	if name == "" || name == macros.PriceEncodingClear {
+		return fmt.Errorf("price encoder name %q is reserved", name)
+	}
+	if encoder == nil {
+		return fmt.Errorf("price encoder %s is nil", name)
+	}
+	if _, ok := priceEncoders[name]; ok {
+		return fmt.Errorf("price encoder %s is already registered", name)
+	}
+	priceEncoders[name] = encoder
+	return nil
+}
+
 func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *Router, err error) {
 	const schemaDirectory = "./static/bidder-params"
 
@@ -253,7 +276,16 @@ func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *R
 		vendorListScheduler.Start()
 	}
 
-	cacheClient := pbc.NewClient(cacheHttpClient, &cfg.CacheURL, &cfg.ExtCacheURL, r.MetricsEngine)
+	var cacheClient pbc.Client
+	if cfg.CacheURL.Embedded.Enabled {
+		cacheStorage, shutdownCacheStorage := pbc.NewEmbeddedStorage(&cfg.CacheURL.Embedded)
+		r.shutdowns = append(r.shutdowns, shutdownCacheStorage)
+		embeddedCache := pbc.NewEmbeddedClient(cacheStorage, &cfg.CacheURL.Embedded, &cfg.ExtCacheURL, cfg.ExternalURL, r.MetricsEngine)
+		r.Handler(http.MethodGet, pbc.EmbeddedCachePath, embeddedCache)
+		cacheClient = embeddedCache
+	} else {
+		cacheClient = pbc.NewClient(cacheHttpClient, &cfg.CacheURL, &cfg.ExtCacheURL, r.MetricsEngine)
+	}
 
 	adapters, singleFormatAdapters, adaptersErrs := exchange.BuildAdapters(generalHttpClient, cfg, cfg.BidderInfos, r.MetricsEngine)
 	if len(adaptersErrs) > 0 {
@@ -266,12 +298,18 @@ func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *R
 	}
 
 	requestValidator := ortb.NewRequestValidator(activeBidders, disabledBidders, paramsValidator)
+	if cfg.StoredDataAPI.Enabled {
+		storedDataWriters, shutdownWriters := storedRequestsConf.NewStoredDataWriters(cfg)
+		r.shutdowns = append(r.shutdowns, shutdownWriters)
+		r.StoredDataAPI = endpoints.NewStoredDataEndpoint(storedDataWriters, requestValidator, cfg.MaxRequestSize, cfg.StoredDataAPI.TimeoutDuration())
+	}
 	priceFloorFetcher := floors.NewPriceFloorFetcher(cfg.PriceFloors, floorFechterHttpClient, r.MetricsEngine)
+	floorSuggester := floors.NewPriceFloorSuggester(cfg.PriceFloors.Suggestions)
 
 	tmaxAdjustments := exchange.ProcessTMaxAdjustments(cfg.TmaxAdjustments)
 	planBuilder := hooks.NewExecutionPlanBuilder(cfg.Hooks, repo)
 	macroReplacer := macros.NewStringIndexBasedReplacer()
-	theExchange := exchange.NewExchange(adapters, cacheClient, cfg, requestValidator, syncersByBidder, r.MetricsEngine, cfg.BidderInfos, gdprPermsBuilder, rateConvertor, categoriesFetcher, adsCertSigner, macroReplacer, priceFloorFetcher, singleFormatAdapters)
+	theExchange := exchange.NewExchange(adapters, cacheClient, cfg, requestValidator, syncersByBidder, r.MetricsEngine, cfg.BidderInfos, gdprPermsBuilder, rateConvertor, categoriesFetcher, adsCertSigner, macroReplacer, priceFloorFetcher, floorSuggester, priceEncoders, singleFormatAdapters)
 	var uuidGenerator uuidutil.UUIDRandomGenerator
 	openrtbEndpoint, err := openrtb2.NewEndpoint(uuidGenerator, theExchange, requestValidator, fetcher, accounts, cfg, r.MetricsEngine, analyticsRunner, disabledBidders, defReqJSON, activeBidders, storedRespFetcher, planBuilder, tmaxAdjustments)
 	if err != nil {
@@ -315,6 +353,11 @@ func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *R
 	eventEndpoint := events.NewEventEndpoint(cfg, accounts, analyticsRunner, r.MetricsEngine)
 	r.GET("/event", eventEndpoint)
 
+	if floorSuggester != nil {
+		r.FloorSuggestionsAPI = endpoints.NewFloorSuggestionsEndpoint(floorSuggester)
+		r.shutdowns = append(r.shutdowns, floorSuggester.Stop)
+	}
+
 	userSyncDeps := &pbs.UserSyncDeps{
 		HostCookieConfig: &(cfg.HostCookie),
 		ExternalUrl:      cfg.ExternalURL,
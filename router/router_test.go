@@ -9,10 +9,12 @@ import (
 
 	jsoniter "github.com/json-iterator/go"
 	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/macros"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const adapterDirectory = "../adapters"
@@ -318,3 +320,23 @@ func TestGetNormalizedGeoscopes(t *testing.T) {
 		})
 	}
 }
+
+type testPriceEncoder struct{}
+
+func (testPriceEncoder) EncodePrice(price float64, currency string, seat string) (string, error) {
+	return "encrypted", nil
+}
+
+func TestRegisterPriceEncoder(t *testing.T) {
+	defer func() { priceEncoders = macros.PriceEncoders{} }()
+
+	require.NoError(t, RegisterPriceEncoder("encrypted", testPriceEncoder{}))
+	encoder, err := priceEncoders.Get("encrypted")
+	require.NoError(t, err)
+	assert.Equal(t, testPriceEncoder{}, encoder)
+
+	assert.EqualError(t, RegisterPriceEncoder("encrypted", testPriceEncoder{}), "price encoder encrypted is already registered")
+	assert.EqualError(t, RegisterPriceEncoder(macros.PriceEncodingClear, testPriceEncoder{}), `price encoder name "clear" is reserved`)
+	assert.EqualError(t, RegisterPriceEncoder("", testPriceEncoder{}), `price encoder name "" is reserved`)
+	assert.EqualError(t, RegisterPriceEncoder("other", nil), "price encoder other is nil")
+}
@@ -0,0 +1,80 @@
+package schain
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// Seller types of a sellers.json file
+const (
+	SellerTypePublisher    = "PUBLISHER"
+	SellerTypeIntermediary = "INTERMEDIARY"
+	SellerTypeBoth         = "BOTH"
+)
+
+// Seller is an entry of a sellers.json file, see https://iabtechlab.com/sellers-json/
+type Seller struct {
+	SellerID   string `json:"seller_id"`
+	Name       string `json:"name"`
+	Domain     string `json:"domain"`
+	SellerType string `json:"seller_type"`
+}
+
+type sellersJSON struct {
+	Sellers []Seller `json:"sellers"`
+}
+
+// Sellers holds the sellers of the advertising systems, keyed by the domain of the system and by seller ID
+type Sellers map[string]map[string]Seller
+
+// LoadSellers reads the sellers.json files of the advertising systems
+func LoadSellers(files []config.SellersJSONFile) (Sellers, error) {
+	sellers := make(Sellers, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file.Path)
+		if err != nil {
+			return nil, fmt.Errorf("the sellers.json of %s could not be read: %v", file.ASI, err)
+		}
+		var parsed sellersJSON
+		if err := jsonutil.UnmarshalValid(data, &parsed); err != nil {
+			return nil, fmt.Errorf("the sellers.json of %s could not be parsed: %v", file.ASI, err)
+		}
+
+		bySellerID := make(map[string]Seller, len(parsed.Sellers))
+		for _, seller := range parsed.Sellers {
+			bySellerID[seller.SellerID] = seller
+		}
+		sellers[strings.ToLower(file.ASI)] = bySellerID
+	}
+	return sellers, nil
+}
+
+// check returns the nodes whose seller ID isn't listed in the sellers.json of their advertising system, and the
+// nodes mismatching their listed seller: another domain, or a publisher which isn't the first node. The nodes of
+// the systems without sellers.json are skipped.
+func (s Sellers) check(nodes []openrtb2.SupplyChainNode) (unknown []string, mismatched []string) {
+	for i, node := range nodes {
+		bySellerID, ok := s[strings.ToLower(node.ASI)]
+		if !ok {
+			continue
+		}
+		seller, ok := bySellerID[node.SID]
+		if !ok {
+			unknown = append(unknown, fmt.Sprintf("nodes[%d] seller %s is unknown to the sellers.json of %s", i, node.SID, node.ASI))
+			continue
+		}
+		if len(node.Domain) > 0 && len(seller.Domain) > 0 && !strings.EqualFold(node.Domain, seller.Domain) {
+			mismatched = append(mismatched, fmt.Sprintf("nodes[%d] domain %s mismatches the domain %s of seller %s in the sellers.json of %s", i, node.Domain, seller.Domain, node.SID, node.ASI))
+			continue
+		}
+		if i > 0 && strings.EqualFold(seller.SellerType, SellerTypePublisher) {
+			mismatched = append(mismatched, fmt.Sprintf("nodes[%d] seller %s is a publisher in the sellers.json of %s, it can only be the first node", i, node.SID, node.ASI))
+		}
+	}
+	return unknown, mismatched
+}
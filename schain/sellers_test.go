@@ -0,0 +1,28 @@
+package schain
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadSellers(t *testing.T) {
+	dir := t.TempDir()
+	validPath := filepath.Join(dir, "sellers.json")
+	assert.NoError(t, os.WriteFile(validPath, []byte(`{"version":"1.0","sellers":[{"seller_id":"1","name":"Publisher","domain":"publisher.com","seller_type":"PUBLISHER"}]}`), 0600))
+	invalidPath := filepath.Join(dir, "invalid.json")
+	assert.NoError(t, os.WriteFile(invalidPath, []byte(`{"sellers":`), 0600))
+
+	sellers, err := LoadSellers([]config.SellersJSONFile{{ASI: "Exchange.com", Path: validPath}})
+	assert.NoError(t, err)
+	assert.Equal(t, Sellers{"exchange.com": {"1": {SellerID: "1", Name: "Publisher", Domain: "publisher.com", SellerType: SellerTypePublisher}}}, sellers)
+
+	_, err = LoadSellers([]config.SellersJSONFile{{ASI: "exchange.com", Path: invalidPath}})
+	assert.ErrorContains(t, err, "the sellers.json of exchange.com could not be parsed")
+
+	_, err = LoadSellers([]config.SellersJSONFile{{ASI: "exchange.com", Path: filepath.Join(dir, "missing.json")}})
+	assert.ErrorContains(t, err, "the sellers.json of exchange.com could not be read")
+}
@@ -0,0 +1,106 @@
+package schain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+)
+
+const sidMaxLength = 64
+
+var asiPattern = regexp.MustCompile(`(?i)^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}$`)
+
+// Validator validates the inbound supply chains and checks their nodes against the sellers.json files of the
+// advertising systems
+type Validator struct {
+	enforcement string
+	maxDepth    int
+	sellers     Sellers
+}
+
+// NewValidator returns the supply chain validator of the config, it is nil when the validation is disabled
+func NewValidator(cfg config.SChainValidation) (*Validator, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	sellers, err := LoadSellers(cfg.SellersJSON)
+	if err != nil {
+		return nil, err
+	}
+	return &Validator{
+		enforcement: cfg.Enforcement,
+		maxDepth:    cfg.MaxDepth,
+		sellers:     sellers,
+	}, nil
+}
+
+// Result is the outcome of the validation of a supply chain
+type Result struct {
+	// SChain is the chain to forward to the bidders, repaired when invalid. It is nil when the chain is rejected.
+	SChain *openrtb2.SupplyChain
+	// Violations are the reasons why the chain is invalid
+	Violations []string
+	// UnknownSellers and MismatchedSellers flag the nodes which don't match the sellers.json of their advertising
+	// system, they don't invalidate the chain
+	UnknownSellers    []string
+	MismatchedSellers []string
+}
+
+// Valid tells whether the chain is forwarded as is
+func (r Result) Valid() bool {
+	return len(r.Violations) == 0
+}
+
+// Validate checks the complete flag of the chain, the asi and sid of its nodes, and that it has no loop and doesn't
+// exceed the max depth. An invalid chain is rejected, or repaired by removing the invalid nodes, the repeated nodes
+// and the nodes beyond the max depth, and is then marked incomplete.
+func (v *Validator) Validate(chain openrtb2.SupplyChain) Result {
+	var violations []string
+	if chain.Complete != 0 && chain.Complete != 1 {
+		violations = append(violations, fmt.Sprintf("complete must be 0 or 1. Got %d", chain.Complete))
+	}
+
+	repaired := chain
+	repaired.Nodes = make([]openrtb2.SupplyChainNode, 0, len(chain.Nodes))
+	seen := make(map[string]int, len(chain.Nodes))
+	for i, node := range chain.Nodes {
+		if err := validateNode(node); err != nil {
+			violations = append(violations, fmt.Sprintf("nodes[%d] %v", i, err))
+			continue
+		}
+		key := strings.ToLower(node.ASI) + "/" + node.SID
+		if first, ok := seen[key]; ok {
+			violations = append(violations, fmt.Sprintf("nodes[%d] repeats nodes[%d], the chain has a loop", i, first))
+			continue
+		}
+		seen[key] = i
+		repaired.Nodes = append(repaired.Nodes, node)
+	}
+	if len(repaired.Nodes) > v.maxDepth {
+		violations = append(violations, fmt.Sprintf("the chain has %d nodes, more than the max depth of %d", len(repaired.Nodes), v.maxDepth))
+		repaired.Nodes = repaired.Nodes[:v.maxDepth]
+	}
+
+	result := Result{Violations: violations}
+	result.UnknownSellers, result.MismatchedSellers = v.sellers.check(chain.Nodes)
+	if len(violations) == 0 {
+		result.SChain = &chain
+	} else if v.enforcement == config.SChainEnforcementRepair {
+		repaired.Complete = 0
+		result.SChain = &repaired
+	}
+	return result
+}
+
+func validateNode(node openrtb2.SupplyChainNode) error {
+	if !asiPattern.MatchString(node.ASI) {
+		return fmt.Errorf("asi %q must be a domain", node.ASI)
+	}
+	if len(node.SID) == 0 || len(node.SID) > sidMaxLength {
+		return fmt.Errorf("sid must be set and not exceed %d chars", sidMaxLength)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package schain
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewValidator(t *testing.T) {
+	validator, err := NewValidator(config.SChainValidation{Enabled: false})
+	assert.NoError(t, err)
+	assert.Nil(t, validator)
+
+	validator, err = NewValidator(config.SChainValidation{Enabled: true, Enforcement: config.SChainEnforcementReject, MaxDepth: 5})
+	assert.NoError(t, err)
+	assert.Equal(t, &Validator{enforcement: config.SChainEnforcementReject, maxDepth: 5, sellers: Sellers{}}, validator)
+
+	_, err = NewValidator(config.SChainValidation{
+		Enabled:     true,
+		Enforcement: config.SChainEnforcementReject,
+		MaxDepth:    5,
+		SellersJSON: []config.SellersJSONFile{{ASI: "exchange.com", Path: filepath.Join(t.TempDir(), "missing.json")}},
+	})
+	assert.ErrorContains(t, err, "the sellers.json of exchange.com could not be read")
+}
+
+func TestValidate(t *testing.T) {
+	node := func(asi, sid string) openrtb2.SupplyChainNode {
+		return openrtb2.SupplyChainNode{ASI: asi, SID: sid, HP: openrtb2.Int8Ptr(1)}
+	}
+
+	tests := []struct {
+		name           string
+		enforcement    string
+		chain          openrtb2.SupplyChain
+		wantChain      *openrtb2.SupplyChain
+		wantViolations []string
+	}{
+		{
+			name:        "valid",
+			enforcement: config.SChainEnforcementReject,
+			chain:       openrtb2.SupplyChain{Complete: 1, Ver: "1.0", Nodes: []openrtb2.SupplyChainNode{node("publisher.com", "1"), node("exchange.co.uk", "abc")}},
+			wantChain:   &openrtb2.SupplyChain{Complete: 1, Ver: "1.0", Nodes: []openrtb2.SupplyChainNode{node("publisher.com", "1"), node("exchange.co.uk", "abc")}},
+		},
+		{
+			name:           "invalid_complete_repaired",
+			enforcement:    config.SChainEnforcementRepair,
+			chain:          openrtb2.SupplyChain{Complete: 2, Ver: "1.0", Nodes: []openrtb2.SupplyChainNode{node("publisher.com", "1")}},
+			wantChain:      &openrtb2.SupplyChain{Complete: 0, Ver: "1.0", Nodes: []openrtb2.SupplyChainNode{node("publisher.com", "1")}},
+			wantViolations: []string{"complete must be 0 or 1. Got 2"},
+		},
+		{
+			name:           "invalid_nodes_repaired",
+			enforcement:    config.SChainEnforcementRepair,
+			chain:          openrtb2.SupplyChain{Complete: 1, Ver: "1.0", Nodes: []openrtb2.SupplyChainNode{node("https://publisher.com", "1"), node("exchange.com", ""), node("ssp.com", "2")}},
+			wantChain:      &openrtb2.SupplyChain{Complete: 0, Ver: "1.0", Nodes: []openrtb2.SupplyChainNode{node("ssp.com", "2")}},
+			wantViolations: []string{`nodes[0] asi "https://publisher.com" must be a domain`, "nodes[1] sid must be set and not exceed 64 chars"},
+		},
+		{
+			name:           "loop_repaired",
+			enforcement:    config.SChainEnforcementRepair,
+			chain:          openrtb2.SupplyChain{Complete: 1, Ver: "1.0", Nodes: []openrtb2.SupplyChainNode{node("publisher.com", "1"), node("exchange.com", "2"), node("Publisher.com", "1")}},
+			wantChain:      &openrtb2.SupplyChain{Complete: 0, Ver: "1.0", Nodes: []openrtb2.SupplyChainNode{node("publisher.com", "1"), node("exchange.com", "2")}},
+			wantViolations: []string{"nodes[2] repeats nodes[0], the chain has a loop"},
+		},
+		{
+			name:           "too_deep_repaired",
+			enforcement:    config.SChainEnforcementRepair,
+			chain:          openrtb2.SupplyChain{Complete: 1, Ver: "1.0", Nodes: []openrtb2.SupplyChainNode{node("a.com", "1"), node("b.com", "1"), node("c.com", "1"), node("d.com", "1")}},
+			wantChain:      &openrtb2.SupplyChain{Complete: 0, Ver: "1.0", Nodes: []openrtb2.SupplyChainNode{node("a.com", "1"), node("b.com", "1"), node("c.com", "1")}},
+			wantViolations: []string{"the chain has 4 nodes, more than the max depth of 3"},
+		},
+		{
+			name:           "loop_rejected",
+			enforcement:    config.SChainEnforcementReject,
+			chain:          openrtb2.SupplyChain{Complete: 1, Ver: "1.0", Nodes: []openrtb2.SupplyChainNode{node("publisher.com", "1"), node("publisher.com", "1")}},
+			wantChain:      nil,
+			wantViolations: []string{"nodes[1] repeats nodes[0], the chain has a loop"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			validator := &Validator{enforcement: test.enforcement, maxDepth: 3}
+
+			result := validator.Validate(test.chain)
+			assert.Equal(t, test.wantChain, result.SChain)
+			assert.Equal(t, test.wantViolations, result.Violations)
+			assert.Equal(t, len(test.wantViolations) == 0, result.Valid())
+		})
+	}
+}
+
+func TestValidateSellers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sellers.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"sellers":[
+		{"seller_id":"1","name":"Publisher","domain":"publisher.com","seller_type":"PUBLISHER"},
+		{"seller_id":"2","name":"Network","domain":"network.com","seller_type":"INTERMEDIARY"},
+		{"seller_id":"4","name":"Other Publisher","domain":"other-publisher.com","seller_type":"PUBLISHER"}
+	]}`), 0600))
+
+	validator, err := NewValidator(config.SChainValidation{
+		Enabled:     true,
+		Enforcement: config.SChainEnforcementReject,
+		MaxDepth:    10,
+		SellersJSON: []config.SellersJSONFile{{ASI: "Exchange.com", Path: path}},
+	})
+	assert.NoError(t, err)
+
+	result := validator.Validate(openrtb2.SupplyChain{Complete: 1, Ver: "1.0", Nodes: []openrtb2.SupplyChainNode{
+		{ASI: "exchange.com", SID: "1", Domain: "publisher.com"},
+		{ASI: "other.com", SID: "1"},
+		{ASI: "exchange.com", SID: "3"},
+		{ASI: "exchange.com", SID: "2", Domain: "other-network.com"},
+		{ASI: "exchange.com", SID: "4"},
+	}})
+	assert.True(t, result.Valid(), "the sellers don't invalidate the chain")
+	assert.NotNil(t, result.SChain)
+	assert.Equal(t, []string{"nodes[2] seller 3 is unknown to the sellers.json of exchange.com"}, result.UnknownSellers)
+	assert.Equal(t, []string{
+		"nodes[3] domain other-network.com mismatches the domain network.com of seller 2 in the sellers.json of exchange.com",
+		"nodes[4] seller 4 is a publisher in the sellers.json of exchange.com, it can only be the first node",
+	}, result.MismatchedSellers)
+}
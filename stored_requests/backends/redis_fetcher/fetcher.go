@@ -0,0 +1,152 @@
+package redis_fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/prebid/prebid-server/v3/logger"
+	"github.com/prebid/prebid-server/v3/stored_requests"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/prebid/prebid-server/v3/util/redisutil"
+)
+
+// Key types of the stored data, the data is stored as JSON strings at <prefix><type>:<id>
+const (
+	RequestKeyType  = "request"
+	ImpKeyType      = "imp"
+	ResponseKeyType = "response"
+	AccountKeyType  = "account"
+	// CategoryKeyType keys are <prefix>category:<primaryAdServer> or <prefix>category:<primaryAdServer>_<publisherId>,
+	// their values have the format of the category mapping files.
+	CategoryKeyType = "category"
+)
+
+// NewFetcher returns a fetcher reading the stored data from a server speaking the Redis protocol
+func NewFetcher(client *redisutil.Client, keyPrefix string) stored_requests.AllFetcher {
+	if client == nil {
+		logger.Fatalf("The Redis Stored Request Fetcher requires a Redis client. Please report this as a bug.")
+	}
+	return &redisFetcher{
+		client:    client,
+		keyPrefix: keyPrefix,
+	}
+}
+
+type redisFetcher struct {
+	client    *redisutil.Client
+	keyPrefix string
+}
+
+// Key returns the key of the stored data
+func Key(keyPrefix, keyType, id string) string {
+	return keyPrefix + keyType + ":" + id
+}
+
+func (fetcher *redisFetcher) FetchRequests(ctx context.Context, requestIDs []string, impIDs []string) (map[string]json.RawMessage, map[string]json.RawMessage, []error) {
+	if len(requestIDs) == 0 && len(impIDs) == 0 {
+		return nil, nil, nil
+	}
+
+	// a single round trip fetches both the requests and the imps
+	keys := make([]string, 0, len(requestIDs)+len(impIDs))
+	keys = fetcher.appendKeys(keys, RequestKeyType, requestIDs)
+	keys = fetcher.appendKeys(keys, ImpKeyType, impIDs)
+
+	values, err := fetcher.client.MGet(ctx, keys...)
+	if err != nil {
+		return nil, nil, []error{fmt.Errorf("Error fetching Stored Requests via Redis: %v", err)}
+	}
+
+	requestData := toDataMap(requestIDs, values[:len(requestIDs)])
+	impData := toDataMap(impIDs, values[len(requestIDs):])
+
+	errs := appendErrors("Request", requestIDs, requestData, nil)
+	errs = appendErrors("Imp", impIDs, impData, errs)
+	return requestData, impData, errs
+}
+
+func (fetcher *redisFetcher) FetchResponses(ctx context.Context, ids []string) (map[string]json.RawMessage, []error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	values, err := fetcher.client.MGet(ctx, fetcher.appendKeys(nil, ResponseKeyType, ids)...)
+	if err != nil {
+		return nil, []error{fmt.Errorf("Error fetching Stored Responses via Redis: %v", err)}
+	}
+
+	data := toDataMap(ids, values)
+	return data, appendErrors("Response", ids, data, nil)
+}
+
+func (fetcher *redisFetcher) FetchAccount(ctx context.Context, accountDefaultsJSON json.RawMessage, accountID string) (json.RawMessage, []error) {
+	if len(accountID) == 0 {
+		return nil, []error{fmt.Errorf("Cannot look up an empty accountID")}
+	}
+
+	value, err := fetcher.client.Get(ctx, Key(fetcher.keyPrefix, AccountKeyType, accountID))
+	if err != nil {
+		return nil, []error{fmt.Errorf("Error fetching account %s via Redis: %v", accountID, err)}
+	}
+	if value == nil {
+		return nil, []error{stored_requests.NotFoundError{
+			ID:       accountID,
+			DataType: "Account",
+		}}
+	}
+	return value, nil
+}
+
+func (fetcher *redisFetcher) FetchCategories(ctx context.Context, primaryAdServer, publisherId, iabCategory string) (string, error) {
+	mappingID := primaryAdServer
+	if len(publisherId) != 0 {
+		mappingID = primaryAdServer + "_" + publisherId
+	}
+
+	value, err := fetcher.client.Get(ctx, Key(fetcher.keyPrefix, CategoryKeyType, mappingID))
+	if err != nil {
+		return "", fmt.Errorf("Error fetching categories for adserver: '%s', publisherId: '%s' via Redis: %v", primaryAdServer, publisherId, err)
+	}
+	if value == nil {
+		return "", fmt.Errorf("Unable to find mapping for adserver: '%s', publisherId: '%s'", primaryAdServer, publisherId)
+	}
+
+	categories := make(map[string]stored_requests.Category)
+	if err := jsonutil.UnmarshalValid(value, &categories); err != nil {
+		return "", fmt.Errorf("Unable to unmarshal categories for adserver: '%s', publisherId: '%s'", primaryAdServer, publisherId)
+	}
+	if category := categories[iabCategory].Id; len(category) != 0 {
+		return category, nil
+	}
+	return "", fmt.Errorf("Unable to find category for adserver '%s', publisherId: '%s', iab category: '%s'", primaryAdServer, publisherId, iabCategory)
+}
+
+func (fetcher *redisFetcher) appendKeys(keys []string, keyType string, ids []string) []string {
+	for _, id := range ids {
+		keys = append(keys, Key(fetcher.keyPrefix, keyType, id))
+	}
+	return keys
+}
+
+func toDataMap(ids []string, values [][]byte) map[string]json.RawMessage {
+	data := make(map[string]json.RawMessage, len(ids))
+	for i, id := range ids {
+		if values[i] != nil {
+			data[id] = values[i]
+		}
+	}
+	return data
+}
+
+func appendErrors(dataType string, ids []string, data map[string]json.RawMessage, errs []error) []error {
+	for _, id := range ids {
+		if _, ok := data[id]; !ok {
+			errs = append(errs, stored_requests.NotFoundError{
+				ID:       id,
+				DataType: dataType,
+			})
+		}
+	}
+	return errs
+}
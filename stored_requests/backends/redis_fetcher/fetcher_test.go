@@ -0,0 +1,122 @@
+package redis_fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/stored_requests"
+	"github.com/prebid/prebid-server/v3/util/redisutil"
+	"github.com/prebid/prebid-server/v3/util/redisutil/redistest"
+	"github.com/stretchr/testify/assert"
+)
+
+const testKeyPrefix = "pbs:"
+
+func newTestFetcher(t *testing.T, data map[string]string) (stored_requests.AllFetcher, *redistest.Server) {
+	server := redistest.NewServer()
+	for key, value := range data {
+		server.Set(key, value)
+	}
+	client := redisutil.NewClient(redisutil.Config{Address: server.Addr})
+	t.Cleanup(func() {
+		client.Close()
+		server.Close()
+	})
+	return NewFetcher(client, testKeyPrefix), server
+}
+
+func TestFetchRequests(t *testing.T) {
+	fetcher, _ := newTestFetcher(t, map[string]string{
+		"pbs:request:req-1": `{"id":"req-1"}`,
+		"pbs:imp:imp-1":     `{"id":"imp-1"}`,
+		"pbs:imp:imp-2":     `{"id":"imp-2"}`,
+		"request:req-2":     `{"id":"unprefixed"}`,
+	})
+
+	reqData, impData, errs := fetcher.FetchRequests(context.Background(), []string{"req-1", "req-2"}, []string{"imp-1", "imp-2"})
+
+	assert.Equal(t, map[string]json.RawMessage{"req-1": json.RawMessage(`{"id":"req-1"}`)}, reqData)
+	assert.Equal(t, map[string]json.RawMessage{
+		"imp-1": json.RawMessage(`{"id":"imp-1"}`),
+		"imp-2": json.RawMessage(`{"id":"imp-2"}`),
+	}, impData)
+	assert.Equal(t, []error{stored_requests.NotFoundError{ID: "req-2", DataType: "Request"}}, errs)
+}
+
+func TestFetchRequestsEmpty(t *testing.T) {
+	fetcher, _ := newTestFetcher(t, nil)
+
+	reqData, impData, errs := fetcher.FetchRequests(context.Background(), nil, nil)
+	assert.Nil(t, reqData)
+	assert.Nil(t, impData)
+	assert.Empty(t, errs)
+}
+
+func TestFetchRequestsUnreachable(t *testing.T) {
+	server := redistest.NewServer()
+	server.Close()
+	client := redisutil.NewClient(redisutil.Config{Address: server.Addr, Timeout: 50 * time.Millisecond})
+	defer client.Close()
+	fetcher := NewFetcher(client, testKeyPrefix)
+
+	reqData, impData, errs := fetcher.FetchRequests(context.Background(), []string{"req-1"}, nil)
+	assert.Nil(t, reqData)
+	assert.Nil(t, impData)
+	assert.Len(t, errs, 1)
+	_, isNotFound := errs[0].(stored_requests.NotFoundError)
+	assert.False(t, isNotFound, "connection errors must not be reported as not found")
+}
+
+func TestFetchResponses(t *testing.T) {
+	fetcher, _ := newTestFetcher(t, map[string]string{
+		"pbs:response:resp-1": `{"seatbid":[]}`,
+	})
+
+	data, errs := fetcher.FetchResponses(context.Background(), []string{"resp-1", "resp-2"})
+	assert.Equal(t, map[string]json.RawMessage{"resp-1": json.RawMessage(`{"seatbid":[]}`)}, data)
+	assert.Equal(t, []error{stored_requests.NotFoundError{ID: "resp-2", DataType: "Response"}}, errs)
+}
+
+func TestFetchAccount(t *testing.T) {
+	fetcher, _ := newTestFetcher(t, map[string]string{
+		"pbs:account:acc-1": `{"id":"acc-1","disabled":false}`,
+	})
+
+	account, errs := fetcher.FetchAccount(context.Background(), nil, "acc-1")
+	assert.Empty(t, errs)
+	assert.JSONEq(t, `{"id":"acc-1","disabled":false}`, string(account))
+
+	account, errs = fetcher.FetchAccount(context.Background(), nil, "acc-2")
+	assert.Nil(t, account)
+	assert.Equal(t, []error{stored_requests.NotFoundError{ID: "acc-2", DataType: "Account"}}, errs)
+
+	_, errs = fetcher.FetchAccount(context.Background(), nil, "")
+	assert.Len(t, errs, 1)
+}
+
+func TestFetchCategories(t *testing.T) {
+	fetcher, _ := newTestFetcher(t, map[string]string{
+		"pbs:category:freewheel":         `{"IAB1-1":{"id":"Cat1","name":"Category 1"}}`,
+		"pbs:category:freewheel_pub-1":   `{"IAB1-1":{"id":"PubCat1","name":"Publisher Category 1"}}`,
+		"pbs:category:freewheel_invalid": `{malformed`,
+	})
+
+	category, err := fetcher.FetchCategories(context.Background(), "freewheel", "", "IAB1-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Cat1", category)
+
+	category, err = fetcher.FetchCategories(context.Background(), "freewheel", "pub-1", "IAB1-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "PubCat1", category)
+
+	_, err = fetcher.FetchCategories(context.Background(), "freewheel", "", "IAB1-2")
+	assert.EqualError(t, err, "Unable to find category for adserver 'freewheel', publisherId: '', iab category: 'IAB1-2'")
+
+	_, err = fetcher.FetchCategories(context.Background(), "dfp", "", "IAB1-1")
+	assert.EqualError(t, err, "Unable to find mapping for adserver: 'dfp', publisherId: ''")
+
+	_, err = fetcher.FetchCategories(context.Background(), "freewheel", "invalid", "IAB1-1")
+	assert.EqualError(t, err, "Unable to unmarshal categories for adserver: 'freewheel', publisherId: 'invalid'")
+}
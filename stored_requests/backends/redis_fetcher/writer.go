@@ -0,0 +1,119 @@
+package redis_fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/prebid/prebid-server/v3/logger"
+	"github.com/prebid/prebid-server/v3/stored_requests"
+	"github.com/prebid/prebid-server/v3/stored_requests/events"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/prebid/prebid-server/v3/util/redisutil"
+)
+
+var keyTypes = map[stored_requests.DataType]string{
+	stored_requests.RequestData:  RequestKeyType,
+	stored_requests.ImpData:      ImpKeyType,
+	stored_requests.ResponseData: ResponseKeyType,
+	stored_requests.AccountData:  AccountKeyType,
+}
+
+// NewWriter returns a writer of the stored data kept in a server speaking the Redis protocol. Every write is
+// published on the invalidation channel, when set, so the caches of the Prebid Servers subscribed to it fetch
+// the written data again.
+func NewWriter(client *redisutil.Client, keyPrefix, invalidationChannel string) stored_requests.Writer {
+	if client == nil {
+		logger.Fatalf("The Redis Stored Request Writer requires a Redis client. Please report this as a bug.")
+	}
+	return &redisWriter{
+		client:              client,
+		keyPrefix:           keyPrefix,
+		invalidationChannel: invalidationChannel,
+	}
+}
+
+type redisWriter struct {
+	client              *redisutil.Client
+	keyPrefix           string
+	invalidationChannel string
+}
+
+func (writer *redisWriter) Read(ctx context.Context, dataType stored_requests.DataType, id string) (json.RawMessage, error) {
+	key, err := writer.key(dataType, id)
+	if err != nil {
+		return nil, err
+	}
+	value, err := writer.client.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading Stored %s %s via Redis: %v", dataType, id, err)
+	}
+	if value == nil {
+		return nil, stored_requests.NotFoundError{ID: id, DataType: string(dataType)}
+	}
+	return value, nil
+}
+
+func (writer *redisWriter) Save(ctx context.Context, dataType stored_requests.DataType, id string, data json.RawMessage) error {
+	key, err := writer.key(dataType, id)
+	if err != nil {
+		return err
+	}
+	if _, err := writer.client.Do(ctx, "SET", key, string(data)); err != nil {
+		return fmt.Errorf("Error saving Stored %s %s via Redis: %v", dataType, id, err)
+	}
+	return writer.invalidate(ctx, dataType, id)
+}
+
+func (writer *redisWriter) Delete(ctx context.Context, dataType stored_requests.DataType, id string) error {
+	key, err := writer.key(dataType, id)
+	if err != nil {
+		return err
+	}
+	deleted, err := writer.client.Do(ctx, "DEL", key)
+	if err != nil {
+		return fmt.Errorf("Error deleting Stored %s %s via Redis: %v", dataType, id, err)
+	}
+	if deleted == int64(0) {
+		return stored_requests.NotFoundError{ID: id, DataType: string(dataType)}
+	}
+	return writer.invalidate(ctx, dataType, id)
+}
+
+func (writer *redisWriter) key(dataType stored_requests.DataType, id string) (string, error) {
+	keyType, ok := keyTypes[dataType]
+	if !ok {
+		return "", fmt.Errorf("Stored %s data is not supported by the Redis writer", dataType)
+	}
+	if len(id) == 0 {
+		return "", fmt.Errorf("Cannot write Stored %s data with an empty ID", dataType)
+	}
+	return Key(writer.keyPrefix, keyType, id), nil
+}
+
+// invalidate publishes the invalidation of the written data, the data is already written when it fails
+func (writer *redisWriter) invalidate(ctx context.Context, dataType stored_requests.DataType, id string) error {
+	if len(writer.invalidationChannel) == 0 {
+		return nil
+	}
+
+	var invalidation events.Invalidation
+	switch dataType {
+	case stored_requests.RequestData:
+		invalidation.Requests = []string{id}
+	case stored_requests.ImpData:
+		invalidation.Imps = []string{id}
+	case stored_requests.ResponseData:
+		invalidation.Responses = []string{id}
+	case stored_requests.AccountData:
+		invalidation.Accounts = []string{id}
+	}
+	message, err := jsonutil.Marshal(invalidation)
+	if err != nil {
+		return err
+	}
+	if _, err := writer.client.Do(ctx, "PUBLISH", writer.invalidationChannel, string(message)); err != nil {
+		return fmt.Errorf("Stored %s %s written but its invalidation was not published via Redis: %v", dataType, id, err)
+	}
+	return nil
+}
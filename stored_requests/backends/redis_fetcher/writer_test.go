@@ -0,0 +1,95 @@
+package redis_fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/stored_requests"
+	"github.com/prebid/prebid-server/v3/util/redisutil"
+	"github.com/prebid/prebid-server/v3/util/redisutil/redistest"
+	"github.com/stretchr/testify/assert"
+)
+
+const testInvalidationChannel = "pbs:invalidations"
+
+func newTestWriter(t *testing.T, invalidationChannel string) (stored_requests.Writer, *redistest.Server, <-chan string) {
+	server := redistest.NewServer()
+	client := redisutil.NewClient(redisutil.Config{Address: server.Addr})
+	t.Cleanup(func() {
+		client.Close()
+		server.Close()
+	})
+
+	messages := make(chan string, 10)
+	if len(invalidationChannel) > 0 {
+		go client.Subscribe(context.Background(), invalidationChannel, func(payload []byte) {
+			messages <- string(payload)
+		})
+		assert.Eventually(t, func() bool { return server.Subscribers(invalidationChannel) == 1 }, time.Second, time.Millisecond)
+	}
+	return NewWriter(client, testKeyPrefix, invalidationChannel), server, messages
+}
+
+func assertInvalidation(t *testing.T, messages <-chan string, expected string) {
+	t.Helper()
+	select {
+	case message := <-messages:
+		assert.JSONEq(t, expected, message)
+	case <-time.After(time.Second):
+		t.Fatal("invalidation not published")
+	}
+}
+
+func TestWriterSave(t *testing.T) {
+	writer, server, messages := newTestWriter(t, testInvalidationChannel)
+
+	err := writer.Save(context.Background(), stored_requests.ImpData, "imp-1", json.RawMessage(`{"id":"imp-1"}`))
+	assert.NoError(t, err)
+	value, _ := server.Value("pbs:imp:imp-1")
+	assert.Equal(t, `{"id":"imp-1"}`, value)
+	assertInvalidation(t, messages, `{"requests":null,"imps":["imp-1"],"accounts":null,"responses":null}`)
+
+	err = writer.Save(context.Background(), stored_requests.AccountData, "acc-1", json.RawMessage(`{"disabled":true}`))
+	assert.NoError(t, err)
+	value, _ = server.Value("pbs:account:acc-1")
+	assert.Equal(t, `{"disabled":true}`, value)
+	assertInvalidation(t, messages, `{"requests":null,"imps":null,"accounts":["acc-1"],"responses":null}`)
+}
+
+func TestWriterRead(t *testing.T) {
+	writer, server, _ := newTestWriter(t, "")
+	server.Set("pbs:response:resp-1", `{"seatbid":[]}`)
+
+	data, err := writer.Read(context.Background(), stored_requests.ResponseData, "resp-1")
+	assert.NoError(t, err)
+	assert.Equal(t, json.RawMessage(`{"seatbid":[]}`), data)
+
+	_, err = writer.Read(context.Background(), stored_requests.ResponseData, "resp-2")
+	assert.Equal(t, stored_requests.NotFoundError{ID: "resp-2", DataType: "Response"}, err)
+}
+
+func TestWriterDelete(t *testing.T) {
+	writer, server, messages := newTestWriter(t, testInvalidationChannel)
+	server.Set("pbs:request:req-1", `{}`)
+
+	err := writer.Delete(context.Background(), stored_requests.RequestData, "req-1")
+	assert.NoError(t, err)
+	_, exists := server.Value("pbs:request:req-1")
+	assert.False(t, exists)
+	assertInvalidation(t, messages, `{"requests":["req-1"],"imps":null,"accounts":null,"responses":null}`)
+
+	err = writer.Delete(context.Background(), stored_requests.RequestData, "req-1")
+	assert.Equal(t, stored_requests.NotFoundError{ID: "req-1", DataType: "Request"}, err)
+}
+
+func TestWriterInvalidArguments(t *testing.T) {
+	writer, _, _ := newTestWriter(t, "")
+
+	err := writer.Save(context.Background(), stored_requests.DataType("Category"), "freewheel", json.RawMessage(`{}`))
+	assert.EqualError(t, err, "Stored Category data is not supported by the Redis writer")
+
+	err = writer.Save(context.Background(), stored_requests.ImpData, "", json.RawMessage(`{}`))
+	assert.EqualError(t, err, "Cannot write Stored Imp data with an empty ID")
+}
@@ -15,12 +15,15 @@ import (
 	"github.com/prebid/prebid-server/v3/stored_requests/backends/empty_fetcher"
 	"github.com/prebid/prebid-server/v3/stored_requests/backends/file_fetcher"
 	"github.com/prebid/prebid-server/v3/stored_requests/backends/http_fetcher"
+	"github.com/prebid/prebid-server/v3/stored_requests/backends/redis_fetcher"
 	"github.com/prebid/prebid-server/v3/stored_requests/caches/memory"
 	"github.com/prebid/prebid-server/v3/stored_requests/caches/nil_cache"
 	"github.com/prebid/prebid-server/v3/stored_requests/events"
 	apiEvents "github.com/prebid/prebid-server/v3/stored_requests/events/api"
 	databaseEvents "github.com/prebid/prebid-server/v3/stored_requests/events/database"
 	httpEvents "github.com/prebid/prebid-server/v3/stored_requests/events/http"
+	redisEvents "github.com/prebid/prebid-server/v3/stored_requests/events/redis"
+	"github.com/prebid/prebid-server/v3/util/redisutil"
 	"github.com/prebid/prebid-server/v3/util/task"
 )
 
@@ -54,8 +57,13 @@ func CreateStoredRequests(cfg *config.StoredRequests, metricsEngine metrics.Metr
 		}
 	}
 
-	eventProducers := newEventProducers(cfg, client, provider, metricsEngine, router)
-	fetcher = newFetcher(cfg, client, provider)
+	var redisClient *redisutil.Client
+	if cfg.Redis.Address != "" {
+		redisClient = newRedisClient(cfg)
+	}
+
+	eventProducers := newEventProducers(cfg, client, provider, redisClient, metricsEngine, router)
+	fetcher = newFetcher(cfg, client, provider, redisClient)
 
 	var shutdown1 func()
 
@@ -70,6 +78,10 @@ func CreateStoredRequests(cfg *config.StoredRequests, metricsEngine metrics.Metr
 			shutdown1()
 		}
 
+		if redisClient != nil {
+			redisClient.Close()
+		}
+
 		if provider == nil {
 			return
 		}
@@ -132,6 +144,36 @@ func NewStoredRequests(cfg *config.Configuration, metricsEngine metrics.MetricsE
 	return
 }
 
+// NewStoredDataWriters returns the writers of the stored data kept in writable backends, by data type.
+// Stored Requests and Imps are written to the stored_requests backend, Stored Responses and Accounts
+// to the stored_responses and accounts backends.
+func NewStoredDataWriters(cfg *config.Configuration) (writers map[stored_requests.DataType]stored_requests.Writer, shutdown func()) {
+	writers = make(map[stored_requests.DataType]stored_requests.Writer, 4)
+	var redisClients []*redisutil.Client
+
+	addWriter := func(srCfg *config.StoredRequests, dataTypes ...stored_requests.DataType) {
+		if srCfg.Redis.Address == "" {
+			return
+		}
+		redisClient := newRedisClient(srCfg)
+		redisClients = append(redisClients, redisClient)
+		writer := redis_fetcher.NewWriter(redisClient, srCfg.Redis.KeyPrefix, srCfg.Redis.InvalidationChannel)
+		for _, dataType := range dataTypes {
+			writers[dataType] = writer
+		}
+	}
+	addWriter(&cfg.StoredRequests, stored_requests.RequestData, stored_requests.ImpData)
+	addWriter(&cfg.StoredResponses, stored_requests.ResponseData)
+	addWriter(&cfg.Accounts, stored_requests.AccountData)
+
+	shutdown = func() {
+		for _, redisClient := range redisClients {
+			redisClient.Close()
+		}
+	}
+	return
+}
+
 func addListeners(cache stored_requests.Cache, eventProducers []events.EventProducer) (shutdown func()) {
 	listeners := make([]*events.EventListener, 0, len(eventProducers))
 
@@ -148,7 +190,7 @@ func addListeners(cache stored_requests.Cache, eventProducers []events.EventProd
 	}
 }
 
-func newFetcher(cfg *config.StoredRequests, client *http.Client, provider db_provider.DbProvider) (fetcher stored_requests.AllFetcher) {
+func newFetcher(cfg *config.StoredRequests, client *http.Client, provider db_provider.DbProvider, redisClient *redisutil.Client) (fetcher stored_requests.AllFetcher) {
 	idList := make(stored_requests.MultiFetcher, 0, 3)
 
 	if cfg.Files.Enabled {
@@ -163,6 +205,10 @@ func newFetcher(cfg *config.StoredRequests, client *http.Client, provider db_pro
 		//in this case data will be loaded to cache via poll for updates event
 		idList = append(idList, empty_fetcher.EmptyFetcher{})
 	}
+	if redisClient != nil {
+		logger.Infof("Loading Stored %s data via Redis. key_prefix=%s", cfg.DataType(), cfg.Redis.KeyPrefix)
+		idList = append(idList, redis_fetcher.NewFetcher(redisClient, cfg.Redis.KeyPrefix))
+	}
 	if cfg.HTTP.Endpoint != "" {
 		logger.Infof("Loading Stored %s data via HTTP. endpoint=%s", cfg.DataType(), cfg.HTTP.Endpoint)
 		idList = append(idList, http_fetcher.NewFetcher(client, cfg.HTTP.Endpoint, cfg.HTTP.UseRfcCompliantBuilder))
@@ -192,7 +238,18 @@ func newCache(cfg *config.StoredRequests) stored_requests.Cache {
 	return cache
 }
 
-func newEventProducers(cfg *config.StoredRequests, client *http.Client, provider db_provider.DbProvider, metricsEngine metrics.MetricsEngine, router *httprouter.Router) (eventProducers []events.EventProducer) {
+func newRedisClient(cfg *config.StoredRequests) *redisutil.Client {
+	logger.Infof("Connecting to Redis for Stored %s. address=%s, db=%d", cfg.DataType(), cfg.Redis.Address, cfg.Redis.DB)
+	return redisutil.NewClient(redisutil.Config{
+		Address:  cfg.Redis.Address,
+		Password: cfg.Redis.Password,
+		DB:       cfg.Redis.DB,
+		Timeout:  cfg.Redis.TimeoutDuration(),
+		PoolSize: cfg.Redis.PoolSize,
+	})
+}
+
+func newEventProducers(cfg *config.StoredRequests, client *http.Client, provider db_provider.DbProvider, redisClient *redisutil.Client, metricsEngine metrics.MetricsEngine, router *httprouter.Router) (eventProducers []events.EventProducer) {
 	if cfg.CacheEvents.Enabled {
 		eventProducers = append(eventProducers, newEventsAPI(router, cfg.CacheEvents.Endpoint))
 	}
@@ -215,6 +272,9 @@ func newEventProducers(cfg *config.StoredRequests, client *http.Client, provider
 		dbEventTickerTask.Start()
 		eventProducers = append(eventProducers, dbEventProducer)
 	}
+	if redisClient != nil && cfg.Redis.InvalidationChannel != "" {
+		eventProducers = append(eventProducers, redisEvents.NewRedisEvents(context.Background(), redisClient, cfg.Redis.InvalidationChannel, cfg.Redis.RetryDelayDuration()))
+	}
 	return
 }
 
@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -21,6 +22,7 @@ import (
 	"github.com/prebid/prebid-server/v3/stored_requests/backends/http_fetcher"
 	"github.com/prebid/prebid-server/v3/stored_requests/events"
 	httpEvents "github.com/prebid/prebid-server/v3/stored_requests/events/http"
+	"github.com/prebid/prebid-server/v3/util/redisutil/redistest"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -117,7 +119,7 @@ func TestNewEmptyFetcher(t *testing.T) {
 	}
 
 	for _, test := range testCases {
-		fetcher := newFetcher(test.config, nil, db_provider.DbProviderMock{})
+		fetcher := newFetcher(test.config, nil, db_provider.DbProviderMock{}, nil)
 		assert.NotNil(t, fetcher, "The fetcher should be non-nil.")
 		if test.emptyFetcher {
 			assert.Equal(t, empty_fetcher.EmptyFetcher{}, fetcher, "Empty fetcher should be returned")
@@ -132,7 +134,7 @@ func TestNewHTTPFetcher(t *testing.T) {
 		HTTP: config.HTTPFetcherConfig{
 			Endpoint: "stored-requests.prebid.com",
 		},
-	}, nil, nil)
+	}, nil, nil, nil)
 	if httpFetcher, ok := fetcher.(*http_fetcher.HttpFetcher); ok {
 		if httpFetcher.EndpointURL.String() != "stored-requests.prebid.com" {
 			t.Errorf("The HTTP fetcher is using the wrong endpoint. Expected %s, got %s", "stored-requests.prebid.com", httpFetcher.EndpointURL)
@@ -158,11 +160,75 @@ func TestNewHTTPEvents(t *testing.T) {
 
 	metricsMock := &metrics.MetricsEngineMock{}
 
-	evProducers := newEventProducers(cfg, server1.Client(), nil, metricsMock, nil)
+	evProducers := newEventProducers(cfg, server1.Client(), nil, nil, metricsMock, nil)
 	assertSliceLength(t, evProducers, 1)
 	assertHttpWithURL(t, evProducers[0], server1.URL)
 }
 
+func TestNewRedisFetcherAndEvents(t *testing.T) {
+	server := redistest.NewServer()
+	defer server.Close()
+	server.Set("pbs:request:req-1", `{"id":"1"}`)
+
+	cfg := typedConfig(config.RequestDataType, &config.StoredRequests{
+		InMemoryCache: config.InMemoryCache{Type: "unbounded"},
+		Redis: config.RedisConfig{
+			Address:             server.Addr,
+			KeyPrefix:           "pbs:",
+			Timeout:             1000,
+			InvalidationChannel: "pbs:invalidations",
+			RetryDelay:          10,
+		},
+	})
+	metricsMock := &metrics.MetricsEngineMock{}
+	metricsMock.On("RecordStoredDataFetchTime", mock.Anything, mock.Anything).Return()
+	metricsMock.On("RecordStoredDataError", mock.Anything).Return()
+	metricsMock.On("RecordStoredReqCacheResult", mock.Anything, mock.Anything).Return()
+	metricsMock.On("RecordStoredImpCacheResult", mock.Anything, mock.Anything).Return()
+
+	fetcher, shutdown := CreateStoredRequests(cfg, metricsMock, nil, httprouter.New(), nil)
+	defer shutdown()
+
+	reqData, _, errs := fetcher.FetchRequests(context.Background(), []string{"req-1"}, nil)
+	assert.Empty(t, errs)
+	assert.JSONEq(t, `{"id":"1"}`, string(reqData["req-1"]))
+
+	// the cached request is served until it is invalidated
+	server.Set("pbs:request:req-1", `{"id":"2"}`)
+	reqData, _, _ = fetcher.FetchRequests(context.Background(), []string{"req-1"}, nil)
+	assert.JSONEq(t, `{"id":"1"}`, string(reqData["req-1"]))
+
+	assert.Eventually(t, func() bool { return server.Subscribers("pbs:invalidations") == 1 }, time.Second, time.Millisecond)
+	server.Publish("pbs:invalidations", `{"requests":["req-1"]}`)
+	assert.Eventually(t, func() bool {
+		reqData, _, _ := fetcher.FetchRequests(context.Background(), []string{"req-1"}, nil)
+		return string(reqData["req-1"]) == `{"id":"2"}`
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestNewStoredDataWriters(t *testing.T) {
+	server := redistest.NewServer()
+	defer server.Close()
+
+	cfg := &config.Configuration{
+		StoredRequests: config.StoredRequests{Redis: config.RedisConfig{Address: server.Addr, KeyPrefix: "pbs:", Timeout: 1000}},
+		Accounts:       config.StoredRequests{Redis: config.RedisConfig{Address: server.Addr, KeyPrefix: "acc:", Timeout: 1000}},
+	}
+	writers, shutdown := NewStoredDataWriters(cfg)
+	defer shutdown()
+
+	assert.Len(t, writers, 3)
+	assert.NotContains(t, writers, stored_requests.ResponseData)
+	assert.Same(t, writers[stored_requests.RequestData], writers[stored_requests.ImpData])
+
+	assert.NoError(t, writers[stored_requests.ImpData].Save(context.Background(), stored_requests.ImpData, "imp-1", json.RawMessage(`{}`)))
+	assert.NoError(t, writers[stored_requests.AccountData].Save(context.Background(), stored_requests.AccountData, "acc-1", json.RawMessage(`{}`)))
+	_, impSaved := server.Value("pbs:imp:imp-1")
+	_, accountSaved := server.Value("acc:account:acc-1")
+	assert.True(t, impSaved)
+	assert.True(t, accountSaved)
+}
+
 func TestNewEmptyCache(t *testing.T) {
 	cache := newCache(&config.StoredRequests{InMemoryCache: config.InMemoryCache{Type: "none"}})
 	assert.True(t, isEmptyCacheType(cache.Requests), "The newCache method should return an empty Request cache")
@@ -224,7 +290,7 @@ func TestNewDatabaseEventProducers(t *testing.T) {
 	}
 	mock.ExpectQuery("^" + regexp.QuoteMeta(cfg.Database.CacheInitialization.Query) + "$").WillReturnError(errors.New("Query failed"))
 
-	evProducers := newEventProducers(cfg, client, provider, metricsMock, nil)
+	evProducers := newEventProducers(cfg, client, provider, nil, metricsMock, nil)
 	assertProducerLength(t, evProducers, 1)
 
 	assertExpectationsMet(t, mock)
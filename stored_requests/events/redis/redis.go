@@ -0,0 +1,82 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/logger"
+	"github.com/prebid/prebid-server/v3/stored_requests/events"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/prebid/prebid-server/v3/util/redisutil"
+)
+
+// NewRedisEvents makes an EventProducer which invalidates the cached data when a message is published on the
+// channel of a server speaking the Redis protocol. The messages list the invalidated IDs:
+//
+//	{
+//	  "requests": ["request1", "request2"],
+//	  "imps": ["imp1"],
+//	  "responses": ["resp1"],
+//	  "accounts": ["acc1"]
+//	}
+//
+// Updated data is fetched again once invalidated, so the producer never sends saves. The subscription is restored
+// after connection failures until the context is done or the client is closed, messages published meanwhile are
+// lost and the in memory cache TTL bounds how long the invalidated data may still be served.
+func NewRedisEvents(ctx context.Context, client *redisutil.Client, channel string, retryDelay time.Duration) *RedisEvents {
+	e := &RedisEvents{
+		client:        client,
+		channel:       channel,
+		retryDelay:    retryDelay,
+		saves:         make(chan events.Save, 1),
+		invalidations: make(chan events.Invalidation, 1),
+	}
+	logger.Infof("Subscribing to stored data invalidations on Redis channel %s", channel)
+	go e.subscribe(ctx)
+	return e
+}
+
+type RedisEvents struct {
+	client        *redisutil.Client
+	channel       string
+	retryDelay    time.Duration
+	saves         chan events.Save
+	invalidations chan events.Invalidation
+}
+
+func (e *RedisEvents) subscribe(ctx context.Context) {
+	for {
+		err := e.client.Subscribe(ctx, e.channel, e.onMessage)
+		if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+			return
+		}
+		logger.Errorf("Redis subscription to channel %s for Stored Requests failed, retrying in %v: %v", e.channel, e.retryDelay, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(e.retryDelay):
+		}
+	}
+}
+
+func (e *RedisEvents) onMessage(payload []byte) {
+	var invalidation events.Invalidation
+	if err := jsonutil.UnmarshalValid(payload, &invalidation); err != nil {
+		logger.Errorf("Failed to unmarshal Stored Requests invalidation from Redis channel %s: %v", e.channel, err)
+		return
+	}
+	if len(invalidation.Requests) > 0 || len(invalidation.Imps) > 0 || len(invalidation.Responses) > 0 || len(invalidation.Accounts) > 0 {
+		e.invalidations <- invalidation
+	}
+}
+
+func (e *RedisEvents) Saves() <-chan events.Save {
+	return e.saves
+}
+
+func (e *RedisEvents) Invalidations() <-chan events.Invalidation {
+	return e.invalidations
+}
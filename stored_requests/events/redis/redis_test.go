@@ -0,0 +1,64 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/stored_requests/events"
+	"github.com/prebid/prebid-server/v3/util/redisutil"
+	"github.com/prebid/prebid-server/v3/util/redisutil/redistest"
+	"github.com/stretchr/testify/assert"
+)
+
+const testChannel = "pbs:invalidations"
+
+func TestInvalidations(t *testing.T) {
+	server := redistest.NewServer()
+	defer server.Close()
+	client := redisutil.NewClient(redisutil.Config{Address: server.Addr})
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	producer := NewRedisEvents(ctx, client, testChannel, time.Millisecond)
+	assert.Eventually(t, func() bool { return server.Subscribers(testChannel) == 1 }, time.Second, time.Millisecond)
+
+	server.Publish(testChannel, `malformed`)
+	server.Publish(testChannel, `{}`)
+	server.Publish(testChannel, `{"requests":["req-1"],"accounts":["acc-1"]}`)
+
+	select {
+	case invalidation := <-producer.Invalidations():
+		assert.Equal(t, events.Invalidation{Requests: []string{"req-1"}, Accounts: []string{"acc-1"}}, invalidation)
+	case <-time.After(time.Second):
+		t.Fatal("invalidation not received")
+	}
+	assert.Empty(t, producer.Saves())
+}
+
+func TestSubscriptionRestored(t *testing.T) {
+	server := redistest.NewServer()
+	client := redisutil.NewClient(redisutil.Config{Address: server.Addr})
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	producer := NewRedisEvents(ctx, client, testChannel, time.Millisecond)
+	assert.Eventually(t, func() bool { return server.Subscribers(testChannel) == 1 }, time.Second, time.Millisecond)
+
+	// the connections of the subscription are dropped, the producer subscribes again
+	server.CloseConnections()
+	assert.Eventually(t, func() bool {
+		return server.Subscriptions(testChannel) == 2 && server.Subscribers(testChannel) == 1
+	}, time.Second, time.Millisecond)
+
+	server.Publish(testChannel, `{"imps":["imp-1"]}`)
+	select {
+	case invalidation := <-producer.Invalidations():
+		assert.Equal(t, events.Invalidation{Imps: []string{"imp-1"}}, invalidation)
+	case <-time.After(time.Second):
+		t.Fatal("invalidation not received after the subscription was restored")
+	}
+	server.Close()
+}
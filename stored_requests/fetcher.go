@@ -1,9 +1,11 @@
 package stored_requests
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/prebid/prebid-server/v3/metrics"
 )
@@ -155,6 +157,8 @@ type fetcherWithCache struct {
 // This can be called multiple times to compose Cache layers onto the backing Fetcher, though
 // it is usually more desirable to first compose caches with Compose, ensuring propagation of updates
 // and invalidations through all cache layers.
+//
+// The versions of Stored Requests and Imps found missing are cached too, see FetchVersion.
 func WithCache(fetcher AllFetcher, cache Cache, metricsEngine metrics.MetricsEngine) AllFetcher {
 	return &fetcherWithCache{
 		cache:         cache,
@@ -171,6 +175,8 @@ func (f *fetcherWithCache) FetchRequests(ctx context.Context, requestIDs []strin
 	// Fixes #311
 	leftoverImps := findLeftovers(impIDs, impData)
 	leftoverReqs := findLeftovers(requestIDs, requestData)
+	requestData, errs = dropMissingVersions(requestData, "Request", errs)
+	impData, errs = dropMissingVersions(impData, "Imp", errs)
 
 	// Record cache hits for stored requests and stored imps
 	f.metricsEngine.RecordStoredReqCacheResult(metrics.CacheHit, len(requestIDs)-len(leftoverReqs))
@@ -181,10 +187,14 @@ func (f *fetcherWithCache) FetchRequests(ctx context.Context, requestIDs []strin
 
 	if len(leftoverReqs) > 0 || len(leftoverImps) > 0 {
 		fetcherReqData, fetcherImpData, fetcherErrs := f.fetcher.FetchRequests(ctx, leftoverReqs, leftoverImps)
-		errs = fetcherErrs
+		errs = append(errs, fetcherErrs...)
 
 		f.cache.Requests.Save(ctx, fetcherReqData)
 		f.cache.Imps.Save(ctx, fetcherImpData)
+		if onlyMissingIDs(fetcherErrs) {
+			saveMissingVersions(ctx, f.cache.Requests, leftoverReqs, fetcherReqData)
+			saveMissingVersions(ctx, f.cache.Imps, leftoverImps, fetcherImpData)
+		}
 
 		requestData = mergeData(requestData, fetcherReqData)
 		impData = mergeData(impData, fetcherImpData)
@@ -230,6 +240,44 @@ func (f *fetcherWithCache) FetchCategories(ctx context.Context, primaryAdServer,
 	return "", nil
 }
 
+// missingVersion is cached for the versions of Stored Requests and Imps which don't exist. Most of the data
+// is left untagged by a version, so without it the rollout of a version would reach the backend for every
+// request. See FetchVersion.
+var missingVersion = json.RawMessage(`null`)
+
+func saveMissingVersions(ctx context.Context, cache CacheJSON, ids []string, data map[string]json.RawMessage) {
+	missing := make(map[string]json.RawMessage)
+	for _, id := range ids {
+		if _, ok := data[id]; !ok && strings.Contains(id, VersionSeparator) {
+			missing[id] = missingVersion
+		}
+	}
+	if len(missing) > 0 {
+		cache.Save(ctx, missing)
+	}
+}
+
+// dropMissingVersions removes the cached missing versions from the data, reporting them as not found
+// like the backend would.
+func dropMissingVersions(data map[string]json.RawMessage, dataType string, errs []error) (map[string]json.RawMessage, []error) {
+	for id, value := range data {
+		if bytes.Equal(value, missingVersion) {
+			delete(data, id)
+			errs = append(errs, NotFoundError{ID: id, DataType: dataType})
+		}
+	}
+	return data, errs
+}
+
+func onlyMissingIDs(errs []error) bool {
+	for _, err := range errs {
+		if _, ok := err.(NotFoundError); !ok {
+			return false
+		}
+	}
+	return true
+}
+
 func findLeftovers(ids []string, data map[string]json.RawMessage) (leftovers []string) {
 	leftovers = make([]string, 0, len(ids)-len(data))
 	for _, id := range ids {
@@ -372,3 +372,68 @@ func (c *mockCache) Save(ctx context.Context, data map[string]json.RawMessage) {
 func (c *mockCache) Invalidate(ctx context.Context, ids []string) {
 	c.Called(ctx, ids)
 }
+
+func TestMissingVersions(t *testing.T) {
+	reqCache, impCache, _, fetcher, aFetcherWithCache, metricsEngine := setupFetcherWithCacheDeps()
+	reqIDs := []string{"req-1@v2"}
+	impIDs := []string{"imp-1@v2", "imp-2@v2"}
+	ctx := context.Background()
+
+	reqCache.On("Get", ctx, reqIDs).Return(map[string]json.RawMessage{})
+	impCache.On("Get", ctx, impIDs).Return(
+		map[string]json.RawMessage{
+			"imp-1@v2": missingVersion,
+		})
+	fetcher.On("FetchRequests", ctx, reqIDs, []string{"imp-2@v2"}).Return(
+		map[string]json.RawMessage{},
+		map[string]json.RawMessage{
+			"imp-2@v2": json.RawMessage(`{"id":"imp-2"}`),
+		},
+		[]error{NotFoundError{"req-1@v2", "Request"}},
+	)
+	reqCache.On("Save", ctx, map[string]json.RawMessage{})
+	reqCache.On("Save", ctx, map[string]json.RawMessage{"req-1@v2": missingVersion})
+	impCache.On("Save", ctx, map[string]json.RawMessage{"imp-2@v2": json.RawMessage(`{"id":"imp-2"}`)})
+	metricsEngine.On("RecordStoredReqCacheResult", metrics.CacheHit, 0)
+	metricsEngine.On("RecordStoredReqCacheResult", metrics.CacheMiss, 1)
+	metricsEngine.On("RecordStoredImpCacheResult", metrics.CacheHit, 1)
+	metricsEngine.On("RecordStoredImpCacheResult", metrics.CacheMiss, 1)
+
+	reqData, impData, errs := aFetcherWithCache.FetchRequests(ctx, reqIDs, impIDs)
+
+	reqCache.AssertExpectations(t)
+	impCache.AssertExpectations(t)
+	fetcher.AssertExpectations(t)
+	metricsEngine.AssertExpectations(t)
+	assert.Empty(t, reqData, "FetchRequests shouldn't return missing versions")
+	assert.Equal(t, map[string]json.RawMessage{"imp-2@v2": json.RawMessage(`{"id":"imp-2"}`)}, impData, "FetchRequests shouldn't return cached missing versions")
+	assert.ElementsMatch(t, []error{NotFoundError{"imp-1@v2", "Imp"}, NotFoundError{"req-1@v2", "Request"}}, errs)
+}
+
+func TestMissingVersionsNotSavedOnError(t *testing.T) {
+	reqCache, impCache, _, fetcher, aFetcherWithCache, metricsEngine := setupFetcherWithCacheDeps()
+	reqIDs := []string{"req-1@v2"}
+	ctx := context.Background()
+	fetchErr := errors.New("timeout")
+
+	reqCache.On("Get", ctx, reqIDs).Return(map[string]json.RawMessage{})
+	impCache.On("Get", ctx, []string(nil)).Return(map[string]json.RawMessage{})
+	fetcher.On("FetchRequests", ctx, reqIDs, []string{}).Return(
+		map[string]json.RawMessage{},
+		map[string]json.RawMessage{},
+		[]error{fetchErr},
+	)
+	reqCache.On("Save", ctx, map[string]json.RawMessage{})
+	impCache.On("Save", ctx, map[string]json.RawMessage{})
+	metricsEngine.On("RecordStoredReqCacheResult", metrics.CacheHit, 0)
+	metricsEngine.On("RecordStoredReqCacheResult", metrics.CacheMiss, 1)
+	metricsEngine.On("RecordStoredImpCacheResult", metrics.CacheHit, 0)
+	metricsEngine.On("RecordStoredImpCacheResult", metrics.CacheMiss, 0)
+
+	_, _, errs := aFetcherWithCache.FetchRequests(ctx, reqIDs, nil)
+
+	reqCache.AssertExpectations(t)
+	reqCache.AssertNumberOfCalls(t, "Save", 1)
+	fetcher.AssertExpectations(t)
+	assert.Equal(t, []error{fetchErr}, errs)
+}
@@ -0,0 +1,77 @@
+package stored_requests
+
+import (
+	"context"
+	"encoding/json"
+	"hash/fnv"
+	"sort"
+)
+
+// VersionSeparator separates the ID of Stored Request data from its version tag.
+// Version "v2" of the Stored Imp "imp-1" is stored with the ID "imp-1@v2".
+const VersionSeparator = "@"
+
+// VersionedID returns the ID the version of the Stored Request data is stored with.
+// The empty version is the untagged data.
+func VersionedID(id, version string) string {
+	if len(version) == 0 {
+		return id
+	}
+	return id + VersionSeparator + version
+}
+
+// VersionBucket places the key of a user in one of the 100 buckets of the staged rollout of a version,
+// see config.AccountStoredRequestVersions. A user is always placed in the same bucket.
+func VersionBucket(key string) int {
+	hash := fnv.New32a()
+	hash.Write([]byte(key))
+	return int(hash.Sum32() % 100)
+}
+
+// FetchVersion fetches the version of the Stored Requests and Imps already fetched by their untagged IDs.
+// Only the data changed by a version needs to be tagged: the untagged data is kept for the IDs without data
+// for the version, so a missing version is never an error. Fetchers with a cache remember the missing versions.
+//
+// The argument maps are not modified, the returned maps replace them.
+func FetchVersion(ctx context.Context, fetcher Fetcher, version string, requestData, impData map[string]json.RawMessage) (map[string]json.RawMessage, map[string]json.RawMessage, []error) {
+	if len(version) == 0 || (len(requestData) == 0 && len(impData) == 0) {
+		return requestData, impData, nil
+	}
+
+	requestIDs := versionedIDs(requestData, version)
+	impIDs := versionedIDs(impData, version)
+	versionedRequests, versionedImps, errs := fetcher.FetchRequests(ctx, requestIDs, impIDs)
+	if errs = dropMissingIDs(errs); len(errs) > 0 {
+		return requestData, impData, errs
+	}
+
+	return withVersion(requestData, versionedRequests, version), withVersion(impData, versionedImps, version), nil
+}
+
+func versionedIDs(data map[string]json.RawMessage, version string) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(data))
+	for id := range data {
+		ids = append(ids, VersionedID(id, version))
+	}
+	// sorted for consistent fetcher calls
+	sort.Strings(ids)
+	return ids
+}
+
+func withVersion(data, versionedData map[string]json.RawMessage, version string) map[string]json.RawMessage {
+	if len(versionedData) == 0 {
+		return data
+	}
+	merged := make(map[string]json.RawMessage, len(data))
+	for id, value := range data {
+		if versionedValue, ok := versionedData[VersionedID(id, version)]; ok {
+			merged[id] = versionedValue
+		} else {
+			merged[id] = value
+		}
+	}
+	return merged
+}
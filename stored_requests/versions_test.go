@@ -0,0 +1,78 @@
+package stored_requests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionedID(t *testing.T) {
+	assert.Equal(t, "imp-1", VersionedID("imp-1", ""))
+	assert.Equal(t, "imp-1@v2", VersionedID("imp-1", "v2"))
+}
+
+func TestFetchVersion(t *testing.T) {
+	ctx := context.Background()
+	requestData := map[string]json.RawMessage{"req-1": json.RawMessage(`{"id":"v1"}`)}
+	impData := map[string]json.RawMessage{
+		"imp-1": json.RawMessage(`{"id":"imp-1-v1"}`),
+		"imp-2": json.RawMessage(`{"id":"imp-2-v1"}`),
+	}
+
+	fetcher := &mockFetcher{}
+	fetcher.On("FetchRequests", ctx, []string{"req-1@v2"}, []string{"imp-1@v2", "imp-2@v2"}).Return(
+		map[string]json.RawMessage{},
+		map[string]json.RawMessage{"imp-2@v2": json.RawMessage(`{"id":"imp-2-v2"}`)},
+		[]error{NotFoundError{"req-1@v2", "Request"}, NotFoundError{"imp-1@v2", "Imp"}},
+	)
+
+	versionedRequests, versionedImps, errs := FetchVersion(ctx, fetcher, "v2", requestData, impData)
+	fetcher.AssertExpectations(t)
+	assert.Empty(t, errs)
+	assert.Equal(t, requestData, versionedRequests)
+	assert.Equal(t, map[string]json.RawMessage{
+		"imp-1": json.RawMessage(`{"id":"imp-1-v1"}`),
+		"imp-2": json.RawMessage(`{"id":"imp-2-v2"}`),
+	}, versionedImps)
+	assert.Equal(t, json.RawMessage(`{"id":"imp-2-v1"}`), impData["imp-2"], "the fetched data must not be modified")
+}
+
+func TestFetchVersionUntagged(t *testing.T) {
+	fetcher := &mockFetcher{}
+	requestData := map[string]json.RawMessage{"req-1": json.RawMessage(`{}`)}
+
+	versionedRequests, versionedImps, errs := FetchVersion(context.Background(), fetcher, "", requestData, nil)
+	fetcher.AssertNotCalled(t, "FetchRequests")
+	assert.Empty(t, errs)
+	assert.Equal(t, requestData, versionedRequests)
+	assert.Nil(t, versionedImps)
+}
+
+func TestFetchVersionError(t *testing.T) {
+	ctx := context.Background()
+	requestData := map[string]json.RawMessage{"req-1": json.RawMessage(`{}`)}
+	fetchErr := errors.New("timeout")
+
+	fetcher := &mockFetcher{}
+	fetcher.On("FetchRequests", ctx, []string{"req-1@v2"}, []string(nil)).Return(
+		map[string]json.RawMessage{},
+		map[string]json.RawMessage{},
+		[]error{NotFoundError{"req-1@v2", "Request"}, fetchErr},
+	)
+
+	versionedRequests, _, errs := FetchVersion(ctx, fetcher, "v2", requestData, nil)
+	assert.Equal(t, []error{fetchErr}, errs)
+	assert.Equal(t, requestData, versionedRequests)
+}
+
+func TestVersionBucket(t *testing.T) {
+	for _, key := range []string{"", "user-1", "user-2", "a-much-longer-user-id-0123456789"} {
+		bucket := VersionBucket(key)
+		assert.GreaterOrEqual(t, bucket, 0)
+		assert.Less(t, bucket, 100)
+		assert.Equal(t, bucket, VersionBucket(key), "a key must always be placed in the same bucket")
+	}
+}
@@ -0,0 +1,31 @@
+package stored_requests
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// DataType identifies the Stored Request data managed by a Writer. The values match the
+// NotFoundError data types.
+type DataType string
+
+const (
+	RequestData  DataType = "Request"
+	ImpData      DataType = "Imp"
+	ResponseData DataType = "Response"
+	AccountData  DataType = "Account"
+)
+
+// Writer manages Stored Request data by id, for the backends which can be written to.
+//
+// Implementations must be safe for concurrent access by multiple goroutines.
+// Writes are not applied to the caches directly: they are expected to reach them
+// through the event producers configured for the backend.
+type Writer interface {
+	// Read returns the data stored at the ID, or a NotFoundError if it doesn't exist.
+	Read(ctx context.Context, dataType DataType, id string) (json.RawMessage, error)
+	// Save creates or replaces the data stored at the ID.
+	Save(ctx context.Context, dataType DataType, id string, data json.RawMessage) error
+	// Delete removes the data stored at the ID, or returns a NotFoundError if it doesn't exist.
+	Delete(ctx context.Context, dataType DataType, id string) error
+}
@@ -0,0 +1,55 @@
+package jsonutil
+
+import (
+	"errors"
+	"strings"
+)
+
+// PointerWildcard is the pointer token matching every element of an array
+const PointerWildcard = "*"
+
+var pointerReplacer = strings.NewReplacer("~", "~0", "/", "~1")
+var pointerUnreplacer = strings.NewReplacer("~1", "/", "~0", "~")
+
+// Pointer is a parsed JSON pointer (RFC 6901) holding the unescaped reference tokens. The "*" token is
+// not part of the RFC, it matches every element of an array.
+type Pointer []string
+
+// ParsePointer parses the JSON pointer, the empty pointer referencing the whole document is rejected
+func ParsePointer(s string) (Pointer, error) {
+	if s == "" {
+		return nil, errors.New("pointer must not be empty")
+	}
+	if s[0] != '/' {
+		return nil, errors.New("pointer must start with /")
+	}
+
+	tokens := strings.Split(s[1:], "/")
+	for i, token := range tokens {
+		if strings.Contains(strings.ReplaceAll(strings.ReplaceAll(token, "~0", ""), "~1", ""), "~") {
+			return nil, errors.New("pointer has an invalid ~ escape in " + token)
+		}
+		tokens[i] = pointerUnreplacer.Replace(token)
+	}
+	return tokens, nil
+}
+
+// Wildcards counts the "*" tokens of the pointer
+func (p Pointer) Wildcards() int {
+	count := 0
+	for _, token := range p {
+		if token == PointerWildcard {
+			count++
+		}
+	}
+	return count
+}
+
+func (p Pointer) String() string {
+	var sb strings.Builder
+	for _, token := range p {
+		sb.WriteByte('/')
+		sb.WriteString(pointerReplacer.Replace(token))
+	}
+	return sb.String()
+}
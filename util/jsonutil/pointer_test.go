@@ -0,0 +1,68 @@
+package jsonutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePointer(t *testing.T) {
+	testCases := []struct {
+		description       string
+		pointer           string
+		expectedPointer   Pointer
+		expectedWildcards int
+		expectedError     string
+	}{
+		{
+			description:     "single token",
+			pointer:         "/tmax",
+			expectedPointer: Pointer{"tmax"},
+		},
+		{
+			description:       "nested tokens with wildcard",
+			pointer:           "/imp/*/ext/bidder/siteId",
+			expectedPointer:   Pointer{"imp", "*", "ext", "bidder", "siteId"},
+			expectedWildcards: 1,
+		},
+		{
+			description:     "escaped tokens",
+			pointer:         "/ext/a~1b/c~0d/~01",
+			expectedPointer: Pointer{"ext", "a/b", "c~d", "~1"},
+		},
+		{
+			description:     "empty token",
+			pointer:         "/ext/",
+			expectedPointer: Pointer{"ext", ""},
+		},
+		{
+			description:   "empty pointer",
+			pointer:       "",
+			expectedError: "pointer must not be empty",
+		},
+		{
+			description:   "missing leading slash",
+			pointer:       "imp/0",
+			expectedError: "pointer must start with /",
+		},
+		{
+			description:   "invalid escape",
+			pointer:       "/ext/a~2",
+			expectedError: "pointer has an invalid ~ escape in a~2",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			pointer, err := ParsePointer(test.pointer)
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedPointer, pointer)
+			assert.Equal(t, test.expectedWildcards, pointer.Wildcards())
+			assert.Equal(t, test.pointer, pointer.String())
+		})
+	}
+}
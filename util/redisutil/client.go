@@ -0,0 +1,341 @@
+package redisutil
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultPoolSize = 10
+	defaultTimeout  = 100 * time.Millisecond
+)
+
+// Config configures the connection to a server speaking the Redis protocol (RESP)
+type Config struct {
+	Address  string
+	Password string
+	DB       int
+	// Timeout bounds dialing and each command when the context has no earlier deadline
+	Timeout time.Duration
+	// PoolSize is the number of idle connections kept for reuse
+	PoolSize int
+}
+
+// Error is an error reply of the server
+type Error string
+
+func (e Error) Error() string {
+	return string(e)
+}
+
+// Client runs commands on a pool of connections. It is safe for concurrent use.
+type Client struct {
+	cfg    Config
+	dialer net.Dialer
+	idle   chan *conn
+	closed chan struct{}
+}
+
+func NewClient(cfg Config) *Client {
+	if cfg.PoolSize <= 0 {
+		cfg.PoolSize = defaultPoolSize
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	return &Client{
+		cfg:    cfg,
+		idle:   make(chan *conn, cfg.PoolSize),
+		closed: make(chan struct{}),
+	}
+}
+
+// Do runs a command and returns its reply. Replies are decoded as string (simple strings), int64 (integers),
+// []byte (bulk strings), []any (arrays) or nil. Error replies are returned as Error.
+func (c *Client) Do(ctx context.Context, args ...string) (any, error) {
+	cn, err := c.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := cn.do(ctx, c.cfg.Timeout, args...)
+	var replyErr Error
+	if err != nil && !errors.As(err, &replyErr) {
+		// the state of the connection is unknown after a network or protocol error
+		cn.Close()
+		return nil, err
+	}
+	c.put(cn)
+	return reply, err
+}
+
+// Get returns the value of the key, nil when the key does not exist
+func (c *Client) Get(ctx context.Context, key string) ([]byte, error) {
+	reply, err := c.Do(ctx, "GET", key)
+	if err != nil {
+		return nil, err
+	}
+	return toBytes(reply)
+}
+
+// MGet returns the values of the keys in order, nil for the keys which do not exist
+func (c *Client) MGet(ctx context.Context, keys ...string) ([][]byte, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	reply, err := c.Do(ctx, append([]string{"MGET"}, keys...)...)
+	if err != nil {
+		return nil, err
+	}
+	items, ok := reply.([]any)
+	if !ok || len(items) != len(keys) {
+		return nil, fmt.Errorf("unexpected MGET reply %T", reply)
+	}
+
+	values := make([][]byte, len(items))
+	for i, item := range items {
+		if values[i], err = toBytes(item); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// Subscribe calls onMessage with the payload of every message published on the channel. It blocks on a dedicated
+// connection until the context is done, the client is closed or the connection fails.
+func (c *Client) Subscribe(ctx context.Context, channel string, onMessage func(payload []byte)) error {
+	select {
+	case <-c.closed:
+		return net.ErrClosed
+	default:
+	}
+
+	cn, err := c.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer cn.Close()
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		// unblock the read of the next message
+		select {
+		case <-ctx.Done():
+		case <-c.closed:
+		case <-stop:
+		}
+		cn.Close()
+	}()
+
+	if err := cn.write(c.cfg.Timeout, "SUBSCRIBE", channel); err != nil {
+		return err
+	}
+	for {
+		cn.SetReadDeadline(time.Time{})
+		reply, err := cn.read()
+		if err != nil {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-c.closed:
+				return net.ErrClosed
+			default:
+				return err
+			}
+		}
+
+		// messages are ["message", channel, payload], the subscription confirmation is ignored
+		items, ok := reply.([]any)
+		if !ok || len(items) != 3 {
+			continue
+		}
+		if kind, _ := toBytes(items[0]); string(kind) == "message" {
+			payload, _ := toBytes(items[2])
+			onMessage(payload)
+		}
+	}
+}
+
+// Close closes the idle connections and stops the subscriptions
+func (c *Client) Close() error {
+	select {
+	case <-c.closed:
+		return nil
+	default:
+		close(c.closed)
+	}
+
+	for {
+		select {
+		case cn := <-c.idle:
+			cn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+func (c *Client) get(ctx context.Context) (*conn, error) {
+	select {
+	case <-c.closed:
+		return nil, net.ErrClosed
+	case cn := <-c.idle:
+		return cn, nil
+	default:
+		return c.dial(ctx)
+	}
+}
+
+func (c *Client) put(cn *conn) {
+	select {
+	case <-c.closed:
+		cn.Close()
+	case c.idle <- cn:
+	default:
+		cn.Close()
+	}
+}
+
+func (c *Client) dial(ctx context.Context) (*conn, error) {
+	dialCtx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+
+	netConn, err := c.dialer.DialContext(dialCtx, "tcp", c.cfg.Address)
+	if err != nil {
+		return nil, err
+	}
+	cn := &conn{Conn: netConn, r: bufio.NewReader(netConn), w: bufio.NewWriter(netConn)}
+
+	if c.cfg.Password != "" {
+		if _, err := cn.do(ctx, c.cfg.Timeout, "AUTH", c.cfg.Password); err != nil {
+			cn.Close()
+			return nil, err
+		}
+	}
+	if c.cfg.DB != 0 {
+		if _, err := cn.do(ctx, c.cfg.Timeout, "SELECT", strconv.Itoa(c.cfg.DB)); err != nil {
+			cn.Close()
+			return nil, err
+		}
+	}
+	return cn, nil
+}
+
+type conn struct {
+	net.Conn
+	r *bufio.Reader
+	w *bufio.Writer
+}
+
+func (cn *conn) do(ctx context.Context, timeout time.Duration, args ...string) (any, error) {
+	deadline := time.Now().Add(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := cn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	if err := cn.write(0, args...); err != nil {
+		return nil, err
+	}
+	return cn.read()
+}
+
+// write sends the command as an array of bulk strings, a zero timeout keeps the current deadline
+func (cn *conn) write(timeout time.Duration, args ...string) error {
+	if timeout > 0 {
+		if err := cn.SetWriteDeadline(time.Now().Add(timeout)); err != nil {
+			return err
+		}
+	}
+
+	cn.w.WriteString("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		cn.w.WriteString("$" + strconv.Itoa(len(arg)) + "\r\n" + arg + "\r\n")
+	}
+	return cn.w.Flush()
+}
+
+func (cn *conn) read() (any, error) {
+	return ReadReply(cn.r)
+}
+
+// ReadReply reads a single RESP value
+func ReadReply(r *bufio.Reader) (any, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, errors.New("empty RESP line")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, Error(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return buf[:size], nil
+	case '*':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 {
+			return nil, err
+		}
+		items := make([]any, size)
+		for i := range items {
+			item, err := ReadReply(r)
+			var replyErr Error
+			if errors.As(err, &replyErr) {
+				item = replyErr
+			} else if err != nil {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("unexpected RESP type %q", line[0])
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return "", errors.New("malformed RESP line")
+	}
+	return line[:len(line)-2], nil
+}
+
+func toBytes(reply any) ([]byte, error) {
+	switch v := reply.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	}
+	return nil, fmt.Errorf("unexpected reply type %T", reply)
+}
@@ -0,0 +1,168 @@
+package redisutil_test
+
+import (
+	"bufio"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/util/redisutil"
+	"github.com/prebid/prebid-server/v3/util/redisutil/redistest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientGet(t *testing.T) {
+	server := redistest.NewServer()
+	defer server.Close()
+	server.Set("key", "value")
+
+	client := redisutil.NewClient(redisutil.Config{Address: server.Addr, Password: "secret", DB: 1})
+	defer client.Close()
+
+	value, err := client.Get(context.Background(), "key")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+
+	value, err = client.Get(context.Background(), "missing")
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+}
+
+func TestClientMGet(t *testing.T) {
+	server := redistest.NewServer()
+	defer server.Close()
+	server.Set("a", "1")
+	server.Set("c", "3")
+
+	client := redisutil.NewClient(redisutil.Config{Address: server.Addr})
+	defer client.Close()
+
+	values, err := client.MGet(context.Background(), "a", "b", "c")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("1"), nil, []byte("3")}, values)
+
+	values, err = client.MGet(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, values)
+}
+
+func TestClientDo(t *testing.T) {
+	server := redistest.NewServer()
+	defer server.Close()
+
+	client := redisutil.NewClient(redisutil.Config{Address: server.Addr})
+	defer client.Close()
+
+	reply, err := client.Do(context.Background(), "SET", "key", "value")
+	assert.NoError(t, err)
+	assert.Equal(t, "OK", reply)
+
+	reply, err = client.Do(context.Background(), "DEL", "key", "missing")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), reply)
+
+	_, err = client.Do(context.Background(), "UNKNOWN")
+	assert.Equal(t, redisutil.Error("ERR unknown command 'UNKNOWN'"), err)
+
+	// the connection is still usable after an error reply
+	reply, err = client.Do(context.Background(), "PING")
+	assert.NoError(t, err)
+	assert.Equal(t, "OK", reply)
+}
+
+func TestClientSetOptions(t *testing.T) {
+	server := redistest.NewServer()
+	defer server.Close()
+
+	client := redisutil.NewClient(redisutil.Config{Address: server.Addr})
+	defer client.Close()
+
+	reply, err := client.Do(context.Background(), "SET", "key", "first", "NX", "PX", "60000")
+	assert.NoError(t, err)
+	assert.Equal(t, "OK", reply)
+	assert.InDelta(t, time.Minute, server.TTL("key"), float64(time.Second))
+
+	// the key exists, NX does not replace it
+	reply, err = client.Do(context.Background(), "SET", "key", "second", "NX")
+	assert.NoError(t, err)
+	assert.Nil(t, reply)
+	value, _ := server.Value("key")
+	assert.Equal(t, "first", value)
+
+	_, err = client.Do(context.Background(), "SET", "key", "second", "PX", "0")
+	assert.Equal(t, redisutil.Error("ERR invalid expire time in 'set' command"), err)
+
+	_, err = client.Do(context.Background(), "SET", "expiring", "value", "PX", "1")
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		_, exists := server.Value("expiring")
+		return !exists
+	}, time.Second, time.Millisecond)
+}
+
+func TestClientUnreachable(t *testing.T) {
+	server := redistest.NewServer()
+	server.Close()
+
+	client := redisutil.NewClient(redisutil.Config{Address: server.Addr, Timeout: 50 * time.Millisecond})
+	defer client.Close()
+
+	_, err := client.Get(context.Background(), "key")
+	assert.Error(t, err)
+}
+
+func TestClientSubscribe(t *testing.T) {
+	server := redistest.NewServer()
+	defer server.Close()
+
+	client := redisutil.NewClient(redisutil.Config{Address: server.Addr})
+	messages := make(chan string, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Subscribe(context.Background(), "channel", func(payload []byte) {
+			messages <- string(payload)
+		})
+	}()
+
+	assert.Eventually(t, func() bool { return server.Subscribers("channel") == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, 1, server.Publish("channel", "hello"))
+	select {
+	case message := <-messages:
+		assert.Equal(t, "hello", message)
+	case <-time.After(time.Second):
+		t.Fatal("message not received")
+	}
+
+	client.Close()
+	select {
+	case err := <-done:
+		assert.Error(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("subscription not stopped by Close")
+	}
+}
+
+func TestReadReply(t *testing.T) {
+	testCases := []struct {
+		name          string
+		input         string
+		expected      any
+		expectedError error
+	}{
+		{name: "simple_string", input: "+OK\r\n", expected: "OK"},
+		{name: "error", input: "-ERR failed\r\n", expectedError: redisutil.Error("ERR failed")},
+		{name: "integer", input: ":42\r\n", expected: int64(42)},
+		{name: "bulk_string", input: "$5\r\nhe\r\no\r\n", expected: []byte("he\r\no")},
+		{name: "nil_bulk_string", input: "$-1\r\n", expected: nil},
+		{name: "array", input: "*3\r\n$1\r\na\r\n$-1\r\n-ERR item\r\n", expected: []any{[]byte("a"), nil, redisutil.Error("ERR item")}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reply, err := redisutil.ReadReply(bufio.NewReader(strings.NewReader(tc.input)))
+			assert.Equal(t, tc.expectedError, err)
+			assert.Equal(t, tc.expected, reply)
+		})
+	}
+}
@@ -0,0 +1,334 @@
+// Package redistest provides an in-process stand-in for a Redis server to test clients of the Redis protocol.
+// It supports the PING, AUTH, SELECT, GET, MGET, SET (with the NX, EX and PX options), DEL, PUBLISH and
+// SUBSCRIBE commands.
+package redistest
+
+import (
+	"bufio"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/util/redisutil"
+)
+
+type Server struct {
+	Addr string
+
+	listener net.Listener
+	mu       sync.Mutex
+	data     map[string]string
+	expiries map[string]time.Time
+	subs     map[string]map[*subscriber]struct{}
+	// subscriptions counts the SUBSCRIBE commands per channel
+	subscriptions map[string]int
+	conns         map[net.Conn]struct{}
+	wg            sync.WaitGroup
+}
+
+type subscriber struct {
+	mu sync.Mutex
+	w  *bufio.Writer
+}
+
+// NewServer starts a server listening on a random local port
+func NewServer() *Server {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic("redistest: failed to listen: " + err.Error())
+	}
+
+	s := &Server{
+		Addr:          listener.Addr().String(),
+		listener:      listener,
+		data:          make(map[string]string),
+		expiries:      make(map[string]time.Time),
+		subs:          make(map[string]map[*subscriber]struct{}),
+		subscriptions: make(map[string]int),
+		conns:         make(map[net.Conn]struct{}),
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return s
+}
+
+// Set stores the value of the key without expiry
+func (s *Server) Set(key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[key] = value
+	delete(s.expiries, key)
+}
+
+// Del removes the key
+func (s *Server) Del(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data, key)
+	delete(s.expiries, key)
+}
+
+// Value returns the value of the key and whether it exists
+func (s *Server) Value(key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.value(key)
+}
+
+// TTL returns the time to live of the key, 0 when it does not exist or has no expiry
+func (s *Server) TTL(key string) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.value(key); !ok {
+		return 0
+	}
+	if expiry, ok := s.expiries[key]; ok {
+		return time.Until(expiry)
+	}
+	return 0
+}
+
+func (s *Server) value(key string) (string, bool) {
+	if expiry, ok := s.expiries[key]; ok && !time.Now().Before(expiry) {
+		delete(s.data, key)
+		delete(s.expiries, key)
+	}
+	value, ok := s.data[key]
+	return value, ok
+}
+
+// set runs a SET command, it returns false when the value was not set because of the NX option
+func (s *Server) set(args []string) (bool, error) {
+	key, value := args[0], args[1]
+	var notExists bool
+	var ttl time.Duration
+	for i := 2; i < len(args); i++ {
+		switch strings.ToUpper(args[i]) {
+		case "NX":
+			notExists = true
+		case "EX", "PX":
+			if i+1 == len(args) {
+				return false, redisutil.Error("ERR syntax error")
+			}
+			n, err := strconv.ParseInt(args[i+1], 10, 64)
+			if err != nil || n <= 0 {
+				return false, redisutil.Error("ERR invalid expire time in 'set' command")
+			}
+			ttl = time.Duration(n) * time.Millisecond
+			if strings.ToUpper(args[i]) == "EX" {
+				ttl = time.Duration(n) * time.Second
+			}
+			i++
+		default:
+			return false, redisutil.Error("ERR syntax error")
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.value(key); exists && notExists {
+		return false, nil
+	}
+	s.data[key] = value
+	delete(s.expiries, key)
+	if ttl > 0 {
+		s.expiries[key] = time.Now().Add(ttl)
+	}
+	return true, nil
+}
+
+// Publish sends the message to the subscribers of the channel and returns their number
+func (s *Server) Publish(channel, message string) int {
+	return s.publishFrom(nil, channel, message)
+}
+
+// Subscribers returns the number of subscribers of the channel
+func (s *Server) Subscribers(channel string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.subs[channel])
+}
+
+// Subscriptions returns the number of subscriptions to the channel since the server started, including the closed ones
+func (s *Server) Subscriptions(channel string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.subscriptions[channel]
+}
+
+// CloseConnections closes the connections of the clients, the server keeps accepting new connections
+func (s *Server) CloseConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for c := range s.conns {
+		c.Close()
+	}
+}
+
+// Close stops the server and closes all connections
+func (s *Server) Close() {
+	s.listener.Close()
+	s.CloseConnections()
+	s.wg.Wait()
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		c, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns[c] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go s.handle(c)
+	}
+}
+
+func (s *Server) handle(c net.Conn) {
+	defer s.wg.Done()
+	sub := &subscriber{w: bufio.NewWriter(c)}
+	defer func() {
+		c.Close()
+		s.mu.Lock()
+		delete(s.conns, c)
+		for _, subs := range s.subs {
+			delete(subs, sub)
+		}
+		s.mu.Unlock()
+	}()
+
+	r := bufio.NewReader(c)
+	for {
+		request, err := redisutil.ReadReply(r)
+		if err != nil {
+			return
+		}
+		items, ok := request.([]any)
+		if !ok || len(items) == 0 {
+			return
+		}
+		args := make([]string, len(items))
+		for i, item := range items {
+			arg, _ := item.([]byte)
+			args[i] = string(arg)
+		}
+
+		sub.mu.Lock()
+		s.exec(sub, args)
+		err = sub.w.Flush()
+		sub.mu.Unlock()
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) exec(sub *subscriber, args []string) {
+	w := sub.w
+	switch strings.ToUpper(args[0]) {
+	case "PING", "AUTH", "SELECT":
+		w.WriteString("+OK\r\n")
+	case "GET":
+		if len(args) != 2 {
+			writeError(w, args[0])
+			return
+		}
+		value, ok := s.Value(args[1])
+		writeBulk(w, value, ok)
+	case "MGET":
+		w.WriteString("*" + strconv.Itoa(len(args)-1) + "\r\n")
+		for _, key := range args[1:] {
+			value, ok := s.Value(key)
+			writeBulk(w, value, ok)
+		}
+	case "SET":
+		if len(args) < 3 {
+			writeError(w, args[0])
+			return
+		}
+		set, err := s.set(args[1:])
+		if err != nil {
+			w.WriteString("-" + err.Error() + "\r\n")
+		} else if set {
+			w.WriteString("+OK\r\n")
+		} else {
+			writeBulk(w, "", false)
+		}
+	case "DEL":
+		deleted := 0
+		for _, key := range args[1:] {
+			if _, ok := s.Value(key); ok {
+				s.Del(key)
+				deleted++
+			}
+		}
+		w.WriteString(":" + strconv.Itoa(deleted) + "\r\n")
+	case "PUBLISH":
+		if len(args) != 3 {
+			writeError(w, args[0])
+			return
+		}
+		// the subscriber lock of this connection is held, publishing to itself is not supported
+		w.WriteString(":" + strconv.Itoa(s.publishFrom(sub, args[1], args[2])) + "\r\n")
+	case "SUBSCRIBE":
+		s.mu.Lock()
+		for i, channel := range args[1:] {
+			if s.subs[channel] == nil {
+				s.subs[channel] = make(map[*subscriber]struct{})
+			}
+			s.subs[channel][sub] = struct{}{}
+			s.subscriptions[channel]++
+			w.WriteString("*3\r\n")
+			writeBulk(w, "subscribe", true)
+			writeBulk(w, channel, true)
+			w.WriteString(":" + strconv.Itoa(i+1) + "\r\n")
+		}
+		s.mu.Unlock()
+	default:
+		w.WriteString("-ERR unknown command '" + args[0] + "'\r\n")
+	}
+}
+
+func (s *Server) publishFrom(from *subscriber, channel, message string) int {
+	s.mu.Lock()
+	subs := make([]*subscriber, 0, len(s.subs[channel]))
+	for sub := range s.subs[channel] {
+		if sub != from {
+			subs = append(subs, sub)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.mu.Lock()
+		writeArray(sub.w, "message", channel, message)
+		sub.w.Flush()
+		sub.mu.Unlock()
+	}
+	return len(subs)
+}
+
+func writeBulk(w *bufio.Writer, value string, ok bool) {
+	if !ok {
+		w.WriteString("$-1\r\n")
+		return
+	}
+	w.WriteString("$" + strconv.Itoa(len(value)) + "\r\n" + value + "\r\n")
+}
+
+func writeArray(w *bufio.Writer, values ...string) {
+	w.WriteString("*" + strconv.Itoa(len(values)) + "\r\n")
+	for _, value := range values {
+		writeBulk(w, value, true)
+	}
+}
+
+func writeError(w *bufio.Writer, command string) {
+	w.WriteString("-ERR wrong number of arguments for '" + command + "' command\r\n")
+}